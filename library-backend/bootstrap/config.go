@@ -33,6 +33,17 @@ func NewConfig() *config.Config {
 		DBURL:         getRequiredString("DB_URL"),
 		DBMaxIdleConn: getEnvAsInt("DB_MAX_IDLE_CONN", 10),
 		DBMaxOpenConn: getEnvAsInt("DB_MAX_OPEN_CONN", 10),
+
+		LoanPeriodDays: getEnvAsInt("LOAN_PERIOD_DAYS", 14),
+		HoldPickupDays: getEnvAsInt("HOLD_PICKUP_DAYS", 3),
+
+		FinePerDay:     getEnvAsInt("FINE_PER_DAY", 25),
+		FineGraceDays:  getEnvAsInt("FINE_GRACE_DAYS", 0),
+		FineMaxPerLoan: getEnvAsInt("FINE_MAX_PER_LOAN", 1000),
+
+		JWTSecret:           getRequiredString("JWT_SECRET"),
+		JWTAccessTTLMinutes: getEnvAsInt("JWT_ACCESS_TTL_MINUTES", 15),
+		JWTRefreshTTLHours:  getEnvAsInt("JWT_REFRESH_TTL_HOURS", 168),
 	}
 
 	return &cfg
@@ -0,0 +1,78 @@
+package book
+
+import (
+	"context"
+	"encoding/json"
+	"library-backend/bootstrap"
+	"library-backend/internal/payload"
+	"library-backend/internal/repository"
+	"library-backend/internal/service"
+	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ImportBooks imports books from a CSV, NDJSON, MARC or MARCXML file, the same way
+// POST /v1/books/import does, and prints the per-row report. The format is taken from the file
+// extension when not given.
+func ImportBooks(path, format string, dryRun, atomic bool) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".csv":
+			format = payload.BookImportFormatCSV
+		case ".ndjson", ".jsonl":
+			format = payload.BookImportFormatNDJSON
+		case ".mrc", ".marc":
+			format = payload.BookImportFormatMARC
+		case ".xml":
+			format = payload.BookImportFormatMARCXML
+		default:
+			log.Fatalf("Cannot tell the format of %s, pass --format csv, ndjson, marc or marcxml", path)
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("Opening import file failed: %v", err)
+	}
+	defer file.Close()
+
+	config := bootstrap.NewConfig()
+
+	db, err := bootstrap.InitiatePostgreSQL(config)
+	if err != nil {
+		log.Fatalf("Database connection failed: %v", err)
+	}
+	defer db.Close()
+
+	repo := repository.InitiateRepository(repository.Option{
+		DB: db,
+	})
+
+	svc := service.InitiateService(service.Option{
+		Config:     config,
+		Repository: repo,
+	})
+
+	request := payload.ImportBooksRequest{
+		Format: format,
+		DryRun: dryRun,
+		Atomic: atomic,
+	}
+
+	res, err := svc.BookService.ImportBooks(context.Background(), request, file)
+	if err != nil {
+		log.Fatalf("Importing books failed: %v", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(res); err != nil {
+		log.Fatalf("Printing import report failed: %v", err)
+	}
+
+	slog.Info("imported books", "total", res.Total, "created", res.Created, "valid", res.Valid,
+		"invalid", res.Invalid, "failed", res.Failed, "skipped", res.Skipped, "dry_run", dryRun)
+}
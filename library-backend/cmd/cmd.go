@@ -1,7 +1,11 @@
 package cmd
 
 import (
+	"library-backend/cmd/book"
+	"library-backend/cmd/hold"
 	"library-backend/cmd/migration"
+	"library-backend/cmd/user"
+	"library-backend/internal/model"
 	"log"
 
 	"library-backend/cmd/http"
@@ -26,7 +30,49 @@ func Start() {
 		},
 	}
 
+	expireHoldsCmd := &cobra.Command{
+		Use:   "holds:expire",
+		Short: "Expire holds that were not picked up in time",
+		Run: func(cmd *cobra.Command, args []string) {
+			hold.ExpireHolds()
+		},
+	}
+
+	var name, email, password, role string
+	createUserCmd := &cobra.Command{
+		Use:   "user:create",
+		Short: "Create a user that can sign in to the API, e.g. the first admin",
+		Run: func(cmd *cobra.Command, args []string) {
+			user.CreateUser(name, email, password, role)
+		},
+	}
+	createUserCmd.Flags().StringVar(&name, "name", "", "name of the user")
+	createUserCmd.Flags().StringVar(&email, "email", "", "email the user signs in with")
+	createUserCmd.Flags().StringVar(&password, "password", "", "password of the user (at least 8 characters)")
+	createUserCmd.Flags().StringVar(&role, "role", model.UserRoleAdmin, "role of the user (member, librarian, admin)")
+	_ = createUserCmd.MarkFlagRequired("name")
+	_ = createUserCmd.MarkFlagRequired("email")
+	_ = createUserCmd.MarkFlagRequired("password")
+
+	var importFile, importFormat string
+	var importDryRun, importAtomic bool
+	importBooksCmd := &cobra.Command{
+		Use:   "books:import",
+		Short: "Import books from a CSV, NDJSON, MARC or MARCXML file and print a per-row report",
+		Run: func(cmd *cobra.Command, args []string) {
+			book.ImportBooks(importFile, importFormat, importDryRun, importAtomic)
+		},
+	}
+	importBooksCmd.Flags().StringVar(&importFile, "file", "", "CSV, NDJSON, MARC or MARCXML file to import")
+	importBooksCmd.Flags().StringVar(&importFormat, "format", "", "csv, ndjson, marc or marcxml, taken from the file extension by default")
+	importBooksCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "check the rows without writing anything")
+	importBooksCmd.Flags().BoolVar(&importAtomic, "atomic", false, "create every row or, when any row is invalid or fails, none")
+	_ = importBooksCmd.MarkFlagRequired("file")
+
 	rootCmd.AddCommand(migrateCmd)
+	rootCmd.AddCommand(expireHoldsCmd)
+	rootCmd.AddCommand(createUserCmd)
+	rootCmd.AddCommand(importBooksCmd)
 	if err := rootCmd.Execute(); err != nil {
 		log.Fatal(err)
 	}
@@ -0,0 +1,37 @@
+package hold
+
+import (
+	"context"
+	"library-backend/bootstrap"
+	"library-backend/internal/repository"
+	"library-backend/internal/service"
+	"log"
+	"log/slog"
+)
+
+// ExpireHolds closes ready holds that were not picked up in time, meant to be run periodically
+// (e.g. from cron).
+func ExpireHolds() {
+	config := bootstrap.NewConfig()
+
+	db, err := bootstrap.InitiatePostgreSQL(config)
+	if err != nil {
+		log.Fatalf("Database connection failed: %v", err)
+	}
+	defer db.Close()
+
+	repo := repository.InitiateRepository(repository.Option{
+		DB: db,
+	})
+
+	svc := service.InitiateService(service.Option{
+		Config:     config,
+		Repository: repo,
+	})
+
+	expired, err := svc.HoldService.ExpireHolds(context.Background())
+	slog.Info("expired holds", "count", expired)
+	if err != nil {
+		log.Fatalf("Expiring holds failed: %v", err)
+	}
+}
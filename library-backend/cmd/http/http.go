@@ -3,6 +3,7 @@ package http
 import (
 	"library-backend/bootstrap"
 	"library-backend/cmd/migration"
+	"library-backend/internal/auth"
 	"library-backend/internal/handler"
 	"library-backend/internal/repository"
 	"library-backend/internal/router"
@@ -40,9 +41,13 @@ func Start() {
 		DB: db,
 	})
 
+	// initialize token manager, shared by the auth service and the auth middleware
+	tokens := auth.NewTokenManager(config)
+
 	// initialize service
 	svc := service.InitiateService(service.Option{
 		Config:     config,
+		Tokens:     tokens,
 		Repository: repo,
 	})
 
@@ -56,7 +61,7 @@ func Start() {
 	// initialize router
 	//==============================================
 
-	app := router.NewRouter(hndler)
+	app := router.NewRouter(hndler, tokens, svc.APIKeyService)
 
 	// start HTTP server
 	router.StartServer(app, config.AppPort)
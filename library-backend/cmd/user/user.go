@@ -0,0 +1,51 @@
+package user
+
+import (
+	"context"
+	"library-backend/bootstrap"
+	"library-backend/internal/payload"
+	"library-backend/internal/repository"
+	"library-backend/internal/service"
+	"library-backend/internal/validator"
+	"log"
+	"log/slog"
+)
+
+// CreateUser creates a user from the command line, which is how the first user gets in before
+// anyone can sign in to create the others.
+func CreateUser(name, email, password, role string) {
+	config := bootstrap.NewConfig()
+
+	db, err := bootstrap.InitiatePostgreSQL(config)
+	if err != nil {
+		log.Fatalf("Database connection failed: %v", err)
+	}
+	defer db.Close()
+
+	request := payload.CreateUserRequest{
+		Name:     name,
+		Email:    email,
+		Password: password,
+		Role:     role,
+	}
+
+	if err := validator.Validate.Struct(request); err != nil {
+		log.Fatalf("Invalid user: %+v", validator.TranslateErrorValidator(err))
+	}
+
+	repo := repository.InitiateRepository(repository.Option{
+		DB: db,
+	})
+
+	svc := service.InitiateService(service.Option{
+		Config:     config,
+		Repository: repo,
+	})
+
+	res, err := svc.UserService.CreateUser(context.Background(), request)
+	if err != nil {
+		log.Fatalf("Creating user failed: %v", err)
+	}
+
+	slog.Info("created user", "id", res.ID, "email", email, "role", role)
+}
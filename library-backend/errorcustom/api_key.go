@@ -0,0 +1,10 @@
+package errorcustom
+
+import "errors"
+
+var (
+	ErrAPIKeyNotFound      = errors.New("API key not found")
+	ErrInvalidAPIKey       = errors.New("invalid, expired or revoked API key")
+	ErrInvalidAPIKeyScope  = errors.New("scopes must be permissions an API key can be granted")
+	ErrInvalidAPIKeyExpiry = errors.New("expires_at must be in the future")
+)
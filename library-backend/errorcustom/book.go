@@ -3,6 +3,16 @@ package errorcustom
 import "errors"
 
 var (
-	ErrBookNotFound      = errors.New("book not found")
-	ErrBookAlreadyExists = errors.New("book with this ISBN already exists")
+	ErrBookNotFound        = errors.New("book not found")
+	ErrBookAlreadyExists   = errors.New("book with this ISBN already exists")
+	ErrInvalidSortField    = errors.New("invalid sort field")
+	ErrInvalidFilter       = errors.New("invalid filter")
+	ErrInvalidFacet        = errors.New("invalid facet")
+	ErrInvalidCursor       = errors.New("invalid cursor")
+	ErrCursorUnsupported   = errors.New("cursor pagination cannot be combined with sort or q")
+	ErrBookPurgeBlocked    = errors.New("book still has loans or holds and cannot be purged")
+	ErrInvalidISBN         = errors.New("invalid ISBN")
+	ErrInvalidImportFormat = errors.New("import format must be csv or ndjson")
+	ErrInvalidImportFile   = errors.New("invalid import file")
+	ErrBookInTrash         = errors.New("a deleted book has this ISBN, create it with restore set to true to restore it")
 )
@@ -0,0 +1,11 @@
+package errorcustom
+
+import "errors"
+
+var (
+	ErrBookCopyNotFound      = errors.New("book copy not found")
+	ErrBookCopyAlreadyExists = errors.New("book copy with this barcode already exists")
+	ErrBookCopyOnLoan        = errors.New("book copy is currently on loan")
+	ErrBookCopyOnHold        = errors.New("book copy is set aside for a hold")
+	ErrBookCopyNotAvailable  = errors.New("book copy is not available")
+)
@@ -0,0 +1,7 @@
+package errorcustom
+
+import "errors"
+
+var (
+	ErrFineExceedsBalance = errors.New("amount exceeds the outstanding fine balance")
+)
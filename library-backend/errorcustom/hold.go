@@ -0,0 +1,10 @@
+package errorcustom
+
+import "errors"
+
+var (
+	ErrHoldNotFound      = errors.New("hold not found")
+	ErrHoldAlreadyExists = errors.New("member already has an active hold on this book")
+	ErrHoldNotNeeded     = errors.New("a copy of this book is available, check it out instead")
+	ErrHoldNotActive     = errors.New("hold is no longer active")
+)
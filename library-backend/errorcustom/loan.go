@@ -0,0 +1,11 @@
+package errorcustom
+
+import "errors"
+
+var (
+	ErrLoanNotFound        = errors.New("loan not found")
+	ErrLoanAlreadyReturned = errors.New("loan has already been returned")
+	ErrBookNotAvailable    = errors.New("no copy of this book is available")
+	ErrMemberNotActive     = errors.New("member is not active")
+	ErrInvalidDueDate      = errors.New("due date must be in the future")
+)
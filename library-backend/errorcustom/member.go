@@ -0,0 +1,8 @@
+package errorcustom
+
+import "errors"
+
+var (
+	ErrMemberNotFound      = errors.New("member not found")
+	ErrMemberAlreadyExists = errors.New("member with this email already exists")
+)
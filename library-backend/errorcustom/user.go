@@ -0,0 +1,13 @@
+package errorcustom
+
+import "errors"
+
+var (
+	ErrUserNotFound        = errors.New("user not found")
+	ErrUserAlreadyExists   = errors.New("user with this email already exists")
+	ErrInvalidCredentials  = errors.New("invalid email or password")
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrUnauthorized        = errors.New("missing or invalid access token")
+	ErrCannotDeleteSelf    = errors.New("you cannot delete your own account")
+	ErrCannotChangeOwnRole = errors.New("you cannot change your own role or disable your own account")
+)
@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+)
+
+// APIKeyPrefix starts every API key so keys are easy to recognise, e.g. by secret scanners.
+const APIKeyPrefix = "lib_"
+
+// apiKeyDisplayLength is how much of a key is kept in the clear to tell keys apart.
+const apiKeyDisplayLength = len(APIKeyPrefix) + 8
+
+// NewAPIKey mints a random API key, it returns the key for the client, the prefix shown to admins
+// and the hash to store.
+func NewAPIKey() (key, prefix, hash string, err error) {
+	raw := make([]byte, 32)
+	if _, err = rand.Read(raw); err != nil {
+		return "", "", "", err
+	}
+
+	key = APIKeyPrefix + base64.RawURLEncoding.EncodeToString(raw)
+
+	return key, key[:apiKeyDisplayLength], HashAPIKey(key), nil
+}
+
+// HashAPIKey returns the hex encoded SHA-256 of an API key, like refresh tokens the keys are
+// random enough that a fast hash is sufficient.
+func HashAPIKey(key string) string {
+	return hashSecret(key)
+}
@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// LocalsKey is the fiber local the auth middleware stores the User under. It is a plain string
+// because fiber exposes its locals through the request context by their string key, which lets
+// services read the user from the context they are handed.
+const LocalsKey = "auth_user"
+
+// User is the authenticated caller of a request. For an API key ID is the key ID and the key
+// scopes take the place of the role permissions.
+type User struct {
+	ID     uuid.UUID
+	Email  string
+	Role   string
+	APIKey bool
+	Scopes []Permission
+}
+
+// HasPermission reports whether the caller is granted the permission, by their role or, for an
+// API key, by its scopes.
+func (u User) HasPermission(permission Permission) bool {
+	if !u.APIKey {
+		return HasPermission(u.Role, permission)
+	}
+
+	for _, scope := range u.Scopes {
+		if scope == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WithUser returns a copy of ctx carrying the authenticated user, for callers outside of fiber.
+func WithUser(ctx context.Context, user User) context.Context {
+	return context.WithValue(ctx, LocalsKey, user)
+}
+
+// UserFromContext returns the authenticated user of the request, if any.
+func UserFromContext(ctx context.Context) (User, bool) {
+	user, ok := ctx.Value(LocalsKey).(User)
+	return user, ok
+}
@@ -0,0 +1,80 @@
+package auth
+
+import "library-backend/internal/model"
+
+// Permission is what a route requires of the caller, roles are granted a fixed set of them.
+type Permission string
+
+const (
+	PermBooksRead   Permission = "books:read"
+	PermBooksWrite  Permission = "books:write"
+	PermBooksDelete Permission = "books:delete"
+
+	PermMembersRead   Permission = "members:read"
+	PermMembersWrite  Permission = "members:write"
+	PermMembersDelete Permission = "members:delete"
+
+	PermLoansRead  Permission = "loans:read"
+	PermLoansWrite Permission = "loans:write"
+
+	PermHoldsRead  Permission = "holds:read"
+	PermHoldsWrite Permission = "holds:write"
+
+	PermFinesRead  Permission = "fines:read"
+	PermFinesWrite Permission = "fines:write"
+
+	PermAuditRead Permission = "audit:read"
+
+	PermUsersManage   Permission = "users:manage"
+	PermAPIKeysManage Permission = "api_keys:manage"
+)
+
+// librarianPermissions cover the day to day desk work, deleting records and managing users is
+// left to admins.
+var librarianPermissions = []Permission{
+	PermBooksRead,
+	PermBooksWrite,
+	PermMembersRead,
+	PermMembersWrite,
+	PermLoansRead,
+	PermLoansWrite,
+	PermHoldsRead,
+	PermHoldsWrite,
+	PermFinesRead,
+	PermFinesWrite,
+	PermAuditRead,
+}
+
+var rolePermissions = map[string][]Permission{
+	model.UserRoleMember:    {PermBooksRead},
+	model.UserRoleLibrarian: librarianPermissions,
+	model.UserRoleAdmin: append([]Permission{
+		PermBooksDelete,
+		PermMembersDelete,
+		PermUsersManage,
+		PermAPIKeysManage,
+	}, librarianPermissions...),
+}
+
+// IsGrantableScope reports whether an API key may be given the permission. Keys get any of the
+// circulation and catalog permissions, but never the ones to manage users or other keys.
+func IsGrantableScope(permission Permission) bool {
+	return permission != PermUsersManage && permission != PermAPIKeysManage &&
+		HasPermission(model.UserRoleAdmin, permission)
+}
+
+// Permissions returns the permissions granted to a role, unknown roles get none.
+func Permissions(role string) []Permission {
+	return rolePermissions[role]
+}
+
+// HasPermission reports whether the role is granted the permission.
+func HasPermission(role string, permission Permission) bool {
+	for _, granted := range rolePermissions[role] {
+		if granted == permission {
+			return true
+		}
+	}
+
+	return false
+}
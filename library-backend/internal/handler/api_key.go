@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"errors"
+	"library-backend/errorcustom"
+	"library-backend/internal/payload"
+	"library-backend/internal/service"
+	"library-backend/internal/util"
+	"library-backend/internal/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type APIKeyHandler interface {
+	CreateAPIKey(c *fiber.Ctx) error
+	GetAPIKeys(c *fiber.Ctx) error
+	GetAPIKeyByID(c *fiber.Ctx) error
+	RevokeAPIKey(c *fiber.Ctx) error
+}
+
+type apiKeyHandler struct {
+	apiKeyService service.APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService service.APIKeyService) APIKeyHandler {
+	return &apiKeyHandler{apiKeyService: apiKeyService}
+}
+
+// CreateAPIKey Creating API Key
+//
+//	@Summary        Create a new API key
+//	@Description    Create an API key for a machine client. The key is only returned in this response, send it as `Authorization: ApiKey <key>`. Scopes are permissions, except users:manage and api_keys:manage.
+//	@Tags           API Keys
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@x-permission   "api_keys:manage"
+//	@Param          api_key  body      payload.CreateAPIKeyRequest  true  "API key data"
+//	@Success        200      {object}  payload.Response{data=payload.CreateAPIKeyResponse}
+//	@Failure        400      {object}  payload.GlobalErrorHandlerResp
+//	@Failure        401      {object}  payload.GlobalErrorHandlerResp
+//	@Failure        403      {object}  payload.GlobalErrorHandlerResp
+//	@Failure        500      {object}  payload.GlobalErrorHandlerResp
+//	@Router         /v1/api-keys [post]
+func (h *apiKeyHandler) CreateAPIKey(c *fiber.Ctx) error {
+	var request payload.CreateAPIKeyRequest
+
+	if err := c.BodyParser(&request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	res, err := h.apiKeyService.CreateAPIKey(c.Context(), request)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrInvalidAPIKeyScope) || errors.Is(err, errorcustom.ErrInvalidAPIKeyExpiry) {
+			return util.ErrBadRequestResponse(c, err.Error())
+		}
+		if errors.Is(err, errorcustom.ErrUnauthorized) {
+			return util.ErrUnauthorizedResponse(c, err.Error())
+		}
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, res)
+}
+
+// GetAPIKeys Getting API Keys
+//
+//	@Summary        Get API keys with pagination
+//	@Description    Get a list of API keys, newest first, with status filter support
+//	@Tags           API Keys
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@x-permission   "api_keys:manage"
+//	@Param          page     query    int     false  "Page number (default: 1)"
+//	@Param          limit    query    int     false  "Items per page (default: 10)"
+//	@Param          status   query    string  false  "Filter by status (active, expired, revoked)"
+//	@Success        200      {object} payload.Response{data=payload.GetAPIKeysResponse}
+//	@Failure        400      {object} payload.GlobalErrorHandlerResp
+//	@Failure        401      {object} payload.GlobalErrorHandlerResp
+//	@Failure        403      {object} payload.GlobalErrorHandlerResp
+//	@Failure        500      {object} payload.GlobalErrorHandlerResp
+//	@Router         /v1/api-keys [get]
+func (h *apiKeyHandler) GetAPIKeys(c *fiber.Ctx) error {
+	var request payload.GetAPIKeysRequest
+
+	if err := c.QueryParser(&request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	if request.Page == 0 {
+		request.Page = 1
+	}
+
+	if request.Limit == 0 {
+		request.Limit = 10 // set default limit is 10
+	}
+
+	res, err := h.apiKeyService.GetAPIKeys(c.Context(), request)
+	if err != nil {
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, res)
+}
+
+// GetAPIKeyByID Getting API Key by ID
+//
+//	@Summary        Get API key by ID
+//	@Description    Get a specific API key by its ID, the key itself is never returned again
+//	@Tags           API Keys
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@x-permission   "api_keys:manage"
+//	@Param          id   path     string  true  "API key ID"
+//	@Success        200  {object} payload.Response{data=payload.GetAPIKeyByIDResponse}
+//	@Failure        400  {object} payload.GlobalErrorHandlerResp
+//	@Failure        401  {object} payload.GlobalErrorHandlerResp
+//	@Failure        403  {object} payload.GlobalErrorHandlerResp
+//	@Failure        404  {object} payload.GlobalErrorHandlerResp
+//	@Failure        500  {object} payload.GlobalErrorHandlerResp
+//	@Router         /v1/api-keys/{id} [get]
+func (h *apiKeyHandler) GetAPIKeyByID(c *fiber.Ctx) error {
+	var request payload.GetAPIKeyByIDRequest
+
+	id := c.Params("id")
+	request.ID = id
+
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	res, err := h.apiKeyService.GetAPIKeyByID(c.Context(), request.ID)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrAPIKeyNotFound) {
+			return util.ErrNotFoundResponse(c)
+		}
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, res)
+}
+
+// RevokeAPIKey Revoking API Key
+//
+//	@Summary        Revoke an API key
+//	@Description    Revoke an API key so it can no longer be used. Revoking a revoked key is not an error.
+//	@Tags           API Keys
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@x-permission   "api_keys:manage"
+//	@Param          id   path      string  true  "API key ID"
+//	@Success        200  {object}  payload.Response{}
+//	@Failure        400  {object}  payload.GlobalErrorHandlerResp
+//	@Failure        401  {object}  payload.GlobalErrorHandlerResp
+//	@Failure        403  {object}  payload.GlobalErrorHandlerResp
+//	@Failure        404  {object}  payload.GlobalErrorHandlerResp
+//	@Failure        500  {object}  payload.GlobalErrorHandlerResp
+//	@Router         /v1/api-keys/{id}/revoke [post]
+func (h *apiKeyHandler) RevokeAPIKey(c *fiber.Ctx) error {
+	var request payload.RevokeAPIKeyRequest
+
+	id := c.Params("id")
+	request.ID = id
+
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	err := h.apiKeyService.RevokeAPIKey(c.Context(), request)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrAPIKeyNotFound) {
+			return util.ErrNotFoundResponse(c)
+		}
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, nil)
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"library-backend/internal/payload"
+	"library-backend/internal/service"
+	"library-backend/internal/util"
+	"library-backend/internal/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AuditHandler interface {
+	GetAuditEvents(c *fiber.Ctx) error
+}
+
+type auditHandler struct {
+	auditService service.AuditService
+}
+
+func NewAuditHandler(auditService service.AuditService) AuditHandler {
+	return &auditHandler{auditService: auditService}
+}
+
+// GetAuditEvents Getting Audit Events
+//
+//	@Summary        Get audit events with pagination
+//	@Description    Get the audit trail, newest first. Every event has the actor, the action, the entity and its state before and after the change, and the ID of the request that made it (also sent back as the X-Request-ID header).
+//	@Tags           Audit
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "audit:read"
+//	@Param          page         query    int     false  "Page number (default: 1)"
+//	@Param          limit        query    int     false  "Items per page (default: 10)"
+//	@Param          actor_type   query    string  false  "Filter by actor type (user, api_key, system)"
+//	@Param          actor_id     query    string  false  "Filter by actor, a user or API key ID"
+//	@Param          action       query    string  false  "Filter by action (create, update, delete, restore, purge)"
+//	@Param          entity_type  query    string  false  "Filter by entity type, e.g. book"
+//	@Param          entity_id    query    string  false  "Filter by entity ID"
+//	@Param          request_id   query    string  false  "Filter by request ID"
+//	@Param          from         query    string  false  "Occurred on or after this day (YYYY-MM-DD)"
+//	@Param          to           query    string  false  "Occurred on or before this day (YYYY-MM-DD)"
+//	@Success        200          {object} payload.Response{data=payload.GetAuditEventsResponse}
+//	@Failure        400          {object} payload.GlobalErrorHandlerResp
+//	@Failure        401          {object} payload.GlobalErrorHandlerResp
+//	@Failure        403          {object} payload.GlobalErrorHandlerResp
+//	@Failure        500          {object} payload.GlobalErrorHandlerResp
+//	@Router         /v1/audit [get]
+func (h *auditHandler) GetAuditEvents(c *fiber.Ctx) error {
+	var request payload.GetAuditEventsRequest
+
+	if err := c.QueryParser(&request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	if request.Page == 0 {
+		request.Page = 1
+	}
+
+	if request.Limit == 0 {
+		request.Limit = 10 // set default limit is 10
+	}
+
+	res, err := h.auditService.GetAuditEvents(c.Context(), request)
+	if err != nil {
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, res)
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"errors"
+	"library-backend/errorcustom"
+	"library-backend/internal/payload"
+	"library-backend/internal/service"
+	"library-backend/internal/util"
+	"library-backend/internal/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AuthHandler interface {
+	Login(c *fiber.Ctx) error
+	Refresh(c *fiber.Ctx) error
+	Logout(c *fiber.Ctx) error
+	Me(c *fiber.Ctx) error
+}
+
+type authHandler struct {
+	authService service.AuthService
+}
+
+func NewAuthHandler(authService service.AuthService) AuthHandler {
+	return &authHandler{authService: authService}
+}
+
+// Login Logging In
+//
+//	@Summary        Log in
+//	@Description    Exchange an email and password for an access token and a refresh token
+//	@Tags           Auth
+//	@Accept         json
+//	@Produce        json
+//	@Param          credentials  body      payload.LoginRequest  true  "Login credentials"
+//	@Success        200          {object}  payload.Response{data=payload.TokenResponse}
+//	@Failure        400          {object}  payload.GlobalErrorHandlerResp
+//	@Failure        401          {object}  payload.GlobalErrorHandlerResp
+//	@Failure        500          {object}  payload.GlobalErrorHandlerResp
+//	@Router         /v1/auth/login [post]
+func (h *authHandler) Login(c *fiber.Ctx) error {
+	var request payload.LoginRequest
+
+	if err := c.BodyParser(&request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	res, err := h.authService.Login(c.Context(), request)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrInvalidCredentials) {
+			return util.ErrUnauthorizedResponse(c, err.Error())
+		}
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, res)
+}
+
+// Refresh Refreshing Tokens
+//
+//	@Summary        Refresh the access token
+//	@Description    Exchange a refresh token for a new access token and refresh token. Every refresh token can be used once.
+//	@Tags           Auth
+//	@Accept         json
+//	@Produce        json
+//	@Param          token  body      payload.RefreshTokenRequest  true  "Refresh token"
+//	@Success        200    {object}  payload.Response{data=payload.TokenResponse}
+//	@Failure        400    {object}  payload.GlobalErrorHandlerResp
+//	@Failure        401    {object}  payload.GlobalErrorHandlerResp
+//	@Failure        500    {object}  payload.GlobalErrorHandlerResp
+//	@Router         /v1/auth/refresh [post]
+func (h *authHandler) Refresh(c *fiber.Ctx) error {
+	var request payload.RefreshTokenRequest
+
+	if err := c.BodyParser(&request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	res, err := h.authService.Refresh(c.Context(), request)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrInvalidRefreshToken) {
+			return util.ErrUnauthorizedResponse(c, err.Error())
+		}
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, res)
+}
+
+// Logout Logging Out
+//
+//	@Summary        Log out
+//	@Description    Revoke a refresh token. The access token stays valid until it expires.
+//	@Tags           Auth
+//	@Accept         json
+//	@Produce        json
+//	@Param          token  body      payload.LogoutRequest  true  "Refresh token"
+//	@Success        200    {object}  payload.Response{}
+//	@Failure        400    {object}  payload.GlobalErrorHandlerResp
+//	@Failure        500    {object}  payload.GlobalErrorHandlerResp
+//	@Router         /v1/auth/logout [post]
+func (h *authHandler) Logout(c *fiber.Ctx) error {
+	var request payload.LogoutRequest
+
+	if err := c.BodyParser(&request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	if err := h.authService.Logout(c.Context(), request); err != nil {
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, nil)
+}
+
+// Me Getting the Current User
+//
+//	@Summary        Get the current user
+//	@Description    Get the account of the user the access token was issued to and the permissions granted by their role
+//	@Tags           Auth
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@Success        200  {object}  payload.Response{data=payload.MeResponse}
+//	@Failure        401  {object}  payload.GlobalErrorHandlerResp
+//	@Failure        404  {object}  payload.GlobalErrorHandlerResp
+//	@Failure        500  {object}  payload.GlobalErrorHandlerResp
+//	@Router         /v1/auth/me [get]
+func (h *authHandler) Me(c *fiber.Ctx) error {
+	res, err := h.authService.Me(c.Context())
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrUnauthorized) {
+			return util.ErrUnauthorizedResponse(c, err.Error())
+		}
+		if errors.Is(err, errorcustom.ErrUserNotFound) {
+			return util.ErrNotFoundResponse(c)
+		}
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, res)
+}
@@ -1,12 +1,18 @@
 package handler
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"errors"
+	"fmt"
 	"library-backend/errorcustom"
 	"library-backend/internal/payload"
+	"library-backend/internal/requestid"
 	"library-backend/internal/service"
 	"library-backend/internal/util"
 	"library-backend/internal/validator"
+	"mime"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -15,8 +21,18 @@ type BookHandler interface {
 	CreateBook(c *fiber.Ctx) error
 	GetBooks(c *fiber.Ctx) error
 	GetBookByID(c *fiber.Ctx) error
+	GetBookByISBN(c *fiber.Ctx) error
+	GetBookByBarcode(c *fiber.Ctx) error
+	ImportBooks(c *fiber.Ctx) error
+	ExportBooks(c *fiber.Ctx) error
+	GetBookMARC(c *fiber.Ctx) error
+	GetBookCitation(c *fiber.Ctx) error
+	GetBooksCitation(c *fiber.Ctx) error
 	UpdateBook(c *fiber.Ctx) error
 	DeleteBook(c *fiber.Ctx) error
+	GetDeletedBooks(c *fiber.Ctx) error
+	RestoreBook(c *fiber.Ctx) error
+	PurgeBook(c *fiber.Ctx) error
 }
 
 type bookHandler struct {
@@ -30,13 +46,18 @@ func NewBookHandler(bookService service.BookService) BookHandler {
 // CreateBook Creating Book
 //
 //	@Summary        Create a new book
-//	@Description    Create a new book with the provided details
+//	@Description    Create a new book with the provided details. When a deleted book has the same ISBN the request fails unless restore is true, then that book is restored with the provided details and restored is set in the response.
 //	@Tags           Books
 //	@Accept         json
 //	@Produce        json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "books:write"
 //	@Param          book  body      payload.CreateBookRequest  true  "Book data"
 //	@Success        200   {object}  payload.Response{data=payload.CreateBookResponse}
 //	@Failure        400   {object}  payload.GlobalErrorHandlerResp
+//	@Failure        401   {object}  payload.GlobalErrorHandlerResp
+//	@Failure        403   {object}  payload.GlobalErrorHandlerResp
 //	@Failure        500   {object}  payload.GlobalErrorHandlerResp
 //	@Router         /v1/books [post]
 func (h *bookHandler) CreateBook(c *fiber.Ctx) error {
@@ -53,7 +74,8 @@ func (h *bookHandler) CreateBook(c *fiber.Ctx) error {
 
 	res, err := h.bookService.CreateBook(c.Context(), request)
 	if err != nil {
-		if errors.Is(err, errorcustom.ErrBookAlreadyExists) {
+		if errors.Is(err, errorcustom.ErrBookAlreadyExists) || errors.Is(err, errorcustom.ErrBookInTrash) ||
+			errors.Is(err, errorcustom.ErrInvalidISBN) {
 			return util.ErrBadRequestResponse(c, err.Error())
 		}
 		return util.ErrInternalResponse(c)
@@ -65,15 +87,34 @@ func (h *bookHandler) CreateBook(c *fiber.Ctx) error {
 // GetBooks Getting Books
 //
 //	@Summary        Get Books with pagination
-//	@Description    Get a list of books with pagination support
+//	@Description    Get a list of books with pagination support. With q the books are searched by title, author, publisher and ISBN, ranked by relevance and returned with a highlighted snippet. With cursor the books are paged by next_cursor instead of page, ordered by most recently updated, without sort, q or totals.
 //	@Tags           Books
 //	@Accept         json
 //	@Produce        json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "books:read"
 //	@Param          page     query    int  false  "Page number (default: 1)"
 //	@Param          limit    query    int  false  "Items per page (default: 10)"
 //	@Param          title    query    string  false  "Search by title"
+//	@Param          q        query    string  false  "Full-text search in web search syntax (quoted phrases, or, -exclude)"
+//	@Param          category      query    []string  false  "Filter by category, repeat for several categories"  collectionFormat(multi)
+//	@Param          author        query    string    false  "Filter by author (partial match)"
+//	@Param          publisher     query    string    false  "Filter by publisher (partial match)"
+//	@Param          year_from     query    int       false  "Published in or after this year"
+//	@Param          year_to       query    int       false  "Published in or before this year"
+//	@Param          created_from  query    string    false  "Created on or after this date (YYYY-MM-DD)"
+//	@Param          created_to    query    string    false  "Created on or before this date (YYYY-MM-DD)"
+//	@Param          updated_from  query    string    false  "Updated on or after this date (YYYY-MM-DD)"
+//	@Param          updated_to    query    string    false  "Updated on or before this date (YYYY-MM-DD)"
+//	@Param          sort          query    string    false  "Comma separated sort fields (title, author, publisher, year_of_publication, category, created_at, updated_at), prefix with - for descending"
+//	@Param          filter        query    string    false  "Filter expression over title, author, publisher, year_of_publication, category, created_at and updated_at, e.g. category in ('fantasy','novel') and year_of_publication >= 2000 or author ~ 'tolkien'"
+//	@Param          facets        query    string    false  "Comma separated facets (category, decade, publisher) to count the matching books by, returned in facets"
+//	@Param          cursor        query    string    false  "Switch to cursor pagination, pass it empty for the first page and then the next_cursor of the previous page"
 //	@Success        200      {object} payload.Response{data=payload.GetBooksResponse}
 //	@Failure        400      {object} payload.GlobalErrorHandlerResp
+//	@Failure        401      {object} payload.GlobalErrorHandlerResp
+//	@Failure        403      {object} payload.GlobalErrorHandlerResp
 //	@Failure        500      {object} payload.GlobalErrorHandlerResp
 //	@Router         /v1/books [get]
 func (h *bookHandler) GetBooks(c *fiber.Ctx) error {
@@ -83,6 +124,10 @@ func (h *bookHandler) GetBooks(c *fiber.Ctx) error {
 		return util.ErrBindResponse(c, err)
 	}
 
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
 	if request.Page == 0 {
 		request.Page = 1
 	}
@@ -91,8 +136,17 @@ func (h *bookHandler) GetBooks(c *fiber.Ctx) error {
 		request.Limit = 10 // set default limit is 10
 	}
 
+	request.CursorMode = c.Context().QueryArgs().Has("cursor")
+
 	res, err := h.bookService.GetBooks(c.Context(), request)
 	if err != nil {
+		if errors.Is(err, errorcustom.ErrInvalidSortField) ||
+			errors.Is(err, errorcustom.ErrInvalidFilter) ||
+			errors.Is(err, errorcustom.ErrInvalidFacet) ||
+			errors.Is(err, errorcustom.ErrInvalidCursor) ||
+			errors.Is(err, errorcustom.ErrCursorUnsupported) {
+			return util.ErrBadRequestResponse(c, err.Error())
+		}
 		return util.ErrInternalResponse(c)
 	}
 
@@ -106,9 +160,14 @@ func (h *bookHandler) GetBooks(c *fiber.Ctx) error {
 //	@Tags           Books
 //	@Accept         json
 //	@Produce        json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "books:read"
 //	@Param          id   path     string  true  "Book ID"
 //	@Success        200  {object} payload.Response{data=payload.GetBookByIDResponse}
 //	@Failure        400  {object} payload.GlobalErrorHandlerResp
+//	@Failure        401  {object} payload.GlobalErrorHandlerResp
+//	@Failure        403  {object} payload.GlobalErrorHandlerResp
 //	@Failure        404  {object} payload.GlobalErrorHandlerResp
 //	@Failure        500  {object} payload.GlobalErrorHandlerResp
 //	@Router         /v1/books/{id} [get]
@@ -133,6 +192,389 @@ func (h *bookHandler) GetBookByID(c *fiber.Ctx) error {
 	return util.SuccessResponse(c, res)
 }
 
+// GetBookByISBN Getting Book by ISBN
+//
+//	@Summary        Get Book by ISBN
+//	@Description    Get a book by its ISBN-10 or ISBN-13, with or without hyphens, or by the EAN-13 barcode printed on it. A price add-on read along with the barcode is ignored.
+//	@Tags           Books
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "books:read"
+//	@Param          isbn  path     string  true  "ISBN or EAN-13 barcode"
+//	@Success        200   {object} payload.Response{data=payload.GetBookByIDResponse}
+//	@Failure        400   {object} payload.GlobalErrorHandlerResp
+//	@Failure        401   {object} payload.GlobalErrorHandlerResp
+//	@Failure        403   {object} payload.GlobalErrorHandlerResp
+//	@Failure        404   {object} payload.GlobalErrorHandlerResp
+//	@Failure        500   {object} payload.GlobalErrorHandlerResp
+//	@Router         /v1/books/isbn/{isbn} [get]
+func (h *bookHandler) GetBookByISBN(c *fiber.Ctx) error {
+	var request payload.GetBookByISBNRequest
+
+	request.ISBN = c.Params("isbn")
+
+	err := validator.Validate.Struct(request)
+	if err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	res, err := h.bookService.GetBookByISBN(c.Context(), request)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrInvalidISBN) {
+			return util.ErrBadRequestResponse(c, err.Error())
+		}
+		if errors.Is(err, errorcustom.ErrBookNotFound) {
+			return util.ErrNotFoundResponse(c)
+		}
+		return util.ErrInternalResponse(c)
+	}
+	return util.SuccessResponse(c, res)
+}
+
+// GetBookByBarcode Getting Book by Copy Barcode
+//
+//	@Summary        Get Book by copy barcode
+//	@Description    Get the book a copy belongs to by the copy's barcode
+//	@Tags           Books
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "books:read"
+//	@Param          barcode  path     string  true  "Copy barcode"
+//	@Success        200      {object} payload.Response{data=payload.GetBookByIDResponse}
+//	@Failure        400      {object} payload.GlobalErrorHandlerResp
+//	@Failure        401      {object} payload.GlobalErrorHandlerResp
+//	@Failure        403      {object} payload.GlobalErrorHandlerResp
+//	@Failure        404      {object} payload.GlobalErrorHandlerResp
+//	@Failure        500      {object} payload.GlobalErrorHandlerResp
+//	@Router         /v1/books/barcode/{barcode} [get]
+func (h *bookHandler) GetBookByBarcode(c *fiber.Ctx) error {
+	var request payload.GetBookByBarcodeRequest
+
+	request.Barcode = c.Params("barcode")
+
+	err := validator.Validate.Struct(request)
+	if err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	res, err := h.bookService.GetBookByBarcode(c.Context(), request)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrBookNotFound) {
+			return util.ErrNotFoundResponse(c)
+		}
+		return util.ErrInternalResponse(c)
+	}
+	return util.SuccessResponse(c, res)
+}
+
+// ImportBooks Importing Books
+//
+//	@Summary        Import books from a CSV, NDJSON or MARC file
+//	@Description    Create a book for every row of the request body, a CSV file with a header row naming the columns (isbn, title, author, publisher, year_of_publication, category, image_url, restore), NDJSON with a create request per line, or MARC 21 records in ISO 2709 or MARCXML (UTF-8 only). A MARC record is mapped from 020 $a (ISBN), 100/110/700 $a (author), 245 $a $b (title), 264/260 $b $c (publisher, year), 650/655 $a (category) and 856 $u (cover image), its row reports the fields that were not imported. Every row is validated like a created book and reported on with its line. With dry_run nothing is written and ISBN conflicts are reported too. With atomic every row is created or, when any row is invalid or fails, none; otherwise the valid rows are created and the others reported. At most 5000 rows per file.
+//	@Tags           Books
+//	@Accept         text/csv,application/x-ndjson,application/marc,application/marcxml+xml
+//	@Produce        json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "books:write"
+//	@Param          format   query    string  false  "csv, ndjson, marc or marcxml, by default taken from the Content-Type (text/csv, application/x-ndjson, application/marc, application/marcxml+xml)"
+//	@Param          dry_run  query    bool    false  "Check the rows without writing anything"
+//	@Param          atomic   query    bool    false  "Create every row or none"
+//	@Param          file     body     string  true   "CSV, NDJSON or MARC file"
+//	@Success        200      {object} payload.Response{data=payload.ImportBooksResponse}
+//	@Failure        400      {object} payload.GlobalErrorHandlerResp
+//	@Failure        401      {object} payload.GlobalErrorHandlerResp
+//	@Failure        403      {object} payload.GlobalErrorHandlerResp
+//	@Failure        500      {object} payload.GlobalErrorHandlerResp
+//	@Router         /v1/books/import [post]
+func (h *bookHandler) ImportBooks(c *fiber.Ctx) error {
+	var request payload.ImportBooksRequest
+
+	if err := c.QueryParser(&request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	if request.Format == "" {
+		request.Format = bookImportFormat(c.Get(fiber.HeaderContentType))
+	}
+
+	res, err := h.bookService.ImportBooks(c.Context(), request, bytes.NewReader(c.Body()))
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrInvalidImportFormat) || errors.Is(err, errorcustom.ErrInvalidImportFile) {
+			return util.ErrBadRequestResponse(c, err.Error())
+		}
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, res)
+}
+
+// bookExportContentTypes are the content types of the export formats.
+var bookExportContentTypes = map[string]string{
+	payload.BookExportFormatCSV:     "text/csv; charset=utf-8",
+	payload.BookExportFormatNDJSON:  "application/x-ndjson",
+	payload.BookExportFormatJSON:    fiber.MIMEApplicationJSONCharsetUTF8,
+	payload.BookExportFormatMARC:    "application/marc",
+	payload.BookExportFormatMARCXML: "application/marcxml+xml; charset=utf-8",
+}
+
+// bookExportExtensions are the file extensions of the export formats named differently from them.
+var bookExportExtensions = map[string]string{
+	payload.BookExportFormatMARC:    "mrc",
+	payload.BookExportFormatMARCXML: "xml",
+}
+
+// ExportBooks Exporting Books
+//
+//	@Summary        Export the catalog
+//	@Description    Stream every book matching the filters of the book list, in its order, as CSV (the default), NDJSON, a JSON array of books, or MARC 21 records in ISO 2709 or MARCXML. page, limit and cursor are ignored. The books are read from the database while the response is written, so an error midway ends the response early.
+//	@Tags           Books
+//	@Produce        text/csv,application/x-ndjson,json,application/marc,application/marcxml+xml
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "books:read"
+//	@Param          format        query    string    false  "csv (default), ndjson, json, marc or marcxml"
+//	@Param          title         query    string    false  "Search by title"
+//	@Param          q             query    string    false  "Full-text search in web search syntax (quoted phrases, or, -exclude)"
+//	@Param          category      query    []string  false  "Filter by category, repeat for several categories"  collectionFormat(multi)
+//	@Param          author        query    string    false  "Filter by author (partial match)"
+//	@Param          publisher     query    string    false  "Filter by publisher (partial match)"
+//	@Param          year_from     query    int       false  "Published in or after this year"
+//	@Param          year_to       query    int       false  "Published in or before this year"
+//	@Param          created_from  query    string    false  "Created on or after this date (YYYY-MM-DD)"
+//	@Param          created_to    query    string    false  "Created on or before this date (YYYY-MM-DD)"
+//	@Param          updated_from  query    string    false  "Updated on or after this date (YYYY-MM-DD)"
+//	@Param          updated_to    query    string    false  "Updated on or before this date (YYYY-MM-DD)"
+//	@Param          sort          query    string    false  "Comma separated sort fields, as for the book list"
+//	@Param          filter        query    string    false  "Filter expression, as for the book list"
+//	@Success        200           {array}  payload.BookResponse
+//	@Failure        400           {object} payload.GlobalErrorHandlerResp
+//	@Failure        401           {object} payload.GlobalErrorHandlerResp
+//	@Failure        403           {object} payload.GlobalErrorHandlerResp
+//	@Failure        500           {object} payload.GlobalErrorHandlerResp
+//	@Router         /v1/books/export [get]
+func (h *bookHandler) ExportBooks(c *fiber.Ctx) error {
+	var request payload.ExportBooksRequest
+
+	if err := c.QueryParser(&request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	if request.Format == "" {
+		request.Format = payload.BookExportFormatCSV
+	}
+
+	// the export is written after the handler returns, when the request context is no longer
+	// ours to use
+	ctx := requestid.WithRequestID(context.Background(), requestid.FromContext(c.Context()))
+
+	export, err := h.bookService.ExportBooks(ctx, request)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrInvalidSortField) || errors.Is(err, errorcustom.ErrInvalidFilter) {
+			return util.ErrBadRequestResponse(c, err.Error())
+		}
+		return util.ErrInternalResponse(c)
+	}
+
+	extension, ok := bookExportExtensions[request.Format]
+	if !ok {
+		extension = request.Format
+	}
+
+	c.Set(fiber.HeaderContentType, bookExportContentTypes[request.Format])
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="books.%s"`, extension))
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		// a failed export was logged by the service, the client is left with a cut off body
+		_ = export(w)
+	})
+
+	return nil
+}
+
+// GetBookMARC Getting Book as MARC
+//
+//	@Summary        Get Book as a MARC record
+//	@Description    Get a book as a MARC 21 bibliographic record, MARCXML by default or ISO 2709
+//	@Tags           Books
+//	@Produce        application/marcxml+xml,application/marc
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "books:read"
+//	@Param          id       path     string  true   "Book ID"
+//	@Param          format   query    string  false  "marcxml (default) or marc"
+//	@Success        200      {string} string
+//	@Failure        400      {object} payload.GlobalErrorHandlerResp
+//	@Failure        401      {object} payload.GlobalErrorHandlerResp
+//	@Failure        403      {object} payload.GlobalErrorHandlerResp
+//	@Failure        404      {object} payload.GlobalErrorHandlerResp
+//	@Failure        500      {object} payload.GlobalErrorHandlerResp
+//	@Router         /v1/books/{id}/marc [get]
+func (h *bookHandler) GetBookMARC(c *fiber.Ctx) error {
+	var request payload.GetBookMARCRequest
+
+	if err := c.QueryParser(&request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	request.ID = c.Params("id")
+
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	if request.Format == "" {
+		request.Format = payload.BookExportFormatMARCXML
+	}
+
+	res, err := h.bookService.GetBookMARC(c.Context(), request)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrBookNotFound) {
+			return util.ErrNotFoundResponse(c)
+		}
+		return util.ErrInternalResponse(c)
+	}
+
+	c.Set(fiber.HeaderContentType, bookExportContentTypes[request.Format])
+	return c.Send(res)
+}
+
+// bookCitationContentTypes are the content types of the citation formats, the ones reference
+// managers register for.
+var bookCitationContentTypes = map[string]string{
+	payload.BookCitationFormatBibTeX:  "application/x-bibtex; charset=utf-8",
+	payload.BookCitationFormatRIS:     "application/x-research-info-systems",
+	payload.BookCitationFormatCSLJSON: "application/vnd.citationstyles.csl+json",
+}
+
+var bookCitationExtensions = map[string]string{
+	payload.BookCitationFormatBibTeX:  "bib",
+	payload.BookCitationFormatRIS:     "ris",
+	payload.BookCitationFormatCSLJSON: "json",
+}
+
+// GetBookCitation Getting Book Citation
+//
+//	@Summary        Get a citation of a book
+//	@Description    Cite a book for a reference manager, in BibTeX (default), RIS or CSL-JSON. Authors are split into family and given names, several authors are read from "and", "&" or ";".
+//	@Tags           Books
+//	@Produce        application/x-bibtex,application/x-research-info-systems,application/vnd.citationstyles.csl+json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "books:read"
+//	@Param          id       path     string  true   "Book ID"
+//	@Param          format   query    string  false  "bibtex (default), ris or csl-json"
+//	@Success        200      {string} string
+//	@Failure        400      {object} payload.GlobalErrorHandlerResp
+//	@Failure        401      {object} payload.GlobalErrorHandlerResp
+//	@Failure        403      {object} payload.GlobalErrorHandlerResp
+//	@Failure        404      {object} payload.GlobalErrorHandlerResp
+//	@Failure        500      {object} payload.GlobalErrorHandlerResp
+//	@Router         /v1/books/{id}/citation [get]
+func (h *bookHandler) GetBookCitation(c *fiber.Ctx) error {
+	var request payload.GetBookCitationRequest
+
+	if err := c.QueryParser(&request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	request.ID = c.Params("id")
+
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	if request.Format == "" {
+		request.Format = payload.BookCitationFormatBibTeX
+	}
+
+	res, err := h.bookService.GetBookCitation(c.Context(), request)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrBookNotFound) {
+			return util.ErrNotFoundResponse(c)
+		}
+		return util.ErrInternalResponse(c)
+	}
+
+	c.Set(fiber.HeaderContentType, bookCitationContentTypes[request.Format])
+	return c.Send(res)
+}
+
+// GetBooksCitation Getting Citations of Books
+//
+//	@Summary        Get citations of several books
+//	@Description    Cite up to 100 books at once, in the order of their IDs, as one BibTeX (default), RIS or CSL-JSON file. BibTeX keys that would clash get a letter appended. The request fails when one of the books does not exist.
+//	@Tags           Books
+//	@Produce        application/x-bibtex,application/x-research-info-systems,application/vnd.citationstyles.csl+json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "books:read"
+//	@Param          ids      query    []string  true   "Book IDs, repeat for several books"  collectionFormat(multi)
+//	@Param          format   query    string    false  "bibtex (default), ris or csl-json"
+//	@Success        200      {string} string
+//	@Failure        400      {object} payload.GlobalErrorHandlerResp
+//	@Failure        401      {object} payload.GlobalErrorHandlerResp
+//	@Failure        403      {object} payload.GlobalErrorHandlerResp
+//	@Failure        500      {object} payload.GlobalErrorHandlerResp
+//	@Router         /v1/books/citation [get]
+func (h *bookHandler) GetBooksCitation(c *fiber.Ctx) error {
+	var request payload.GetBooksCitationRequest
+
+	if err := c.QueryParser(&request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	if request.Format == "" {
+		request.Format = payload.BookCitationFormatBibTeX
+	}
+
+	res, err := h.bookService.GetBooksCitation(c.Context(), request)
+	if err != nil {
+		// the error names the missing book
+		if errors.Is(err, errorcustom.ErrBookNotFound) {
+			return util.ErrBadRequestResponse(c, err.Error())
+		}
+		return util.ErrInternalResponse(c)
+	}
+
+	c.Set(fiber.HeaderContentType, bookCitationContentTypes[request.Format])
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="citations.%s"`, bookCitationExtensions[request.Format]))
+	return c.Send(res)
+}
+
+// bookImportFormat maps the content type of an import onto its format, empty when it is none of them.
+func bookImportFormat(contentType string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	switch mediaType {
+	case "text/csv":
+		return payload.BookImportFormatCSV
+	case "application/x-ndjson", "application/ndjson", "application/jsonl":
+		return payload.BookImportFormatNDJSON
+	case "application/marc":
+		return payload.BookImportFormatMARC
+	case "application/marcxml+xml":
+		return payload.BookImportFormatMARCXML
+	default:
+		return ""
+	}
+}
+
 // UpdateBook Updating Book
 //
 //	@Summary        Update a book
@@ -140,10 +582,15 @@ func (h *bookHandler) GetBookByID(c *fiber.Ctx) error {
 //	@Tags           Books
 //	@Accept         json
 //	@Produce        json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "books:write"
 //	@Param          id    path      string                     true   "Book ID"
 //	@Param          book  body      payload.UpdateBookRequest  true   "Book update data"
 //	@Success        200   {object}  payload.Response{}
 //	@Failure        400   {object}  payload.GlobalErrorHandlerResp
+//	@Failure        401   {object}  payload.GlobalErrorHandlerResp
+//	@Failure        403   {object}  payload.GlobalErrorHandlerResp
 //	@Failure        404   {object}  payload.GlobalErrorHandlerResp
 //	@Failure        500   {object}  payload.GlobalErrorHandlerResp
 //	@Router         /v1/books/{id} [put]
@@ -166,6 +613,9 @@ func (h *bookHandler) UpdateBook(c *fiber.Ctx) error {
 		if errors.Is(err, errorcustom.ErrBookNotFound) {
 			return util.ErrNotFoundResponse(c)
 		}
+		if errors.Is(err, errorcustom.ErrBookAlreadyExists) || errors.Is(err, errorcustom.ErrInvalidISBN) {
+			return util.ErrBadRequestResponse(c, err.Error())
+		}
 		return util.ErrInternalResponse(c)
 	}
 
@@ -175,13 +625,18 @@ func (h *bookHandler) UpdateBook(c *fiber.Ctx) error {
 // DeleteBook Deleting Book
 //
 //	@Summary        Delete a book
-//	@Description    Soft delete a book by ID. Sets the deleted_at timestamp.
+//	@Description    Soft delete a book by ID. Sets the deleted_at timestamp, the book can be restored from the trash.
 //	@Tags           Books
 //	@Accept         json
 //	@Produce        json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "books:delete"
 //	@Param          id   path      string  true  "Book ID"
 //	@Success        200  {object}  payload.Response{}
 //	@Failure        400  {object}  payload.GlobalErrorHandlerResp
+//	@Failure        401  {object}  payload.GlobalErrorHandlerResp
+//	@Failure        403  {object}  payload.GlobalErrorHandlerResp
 //	@Failure        404  {object}  payload.GlobalErrorHandlerResp
 //	@Failure        500  {object}  payload.GlobalErrorHandlerResp
 //	@Router         /v1/books/{id} [delete]
@@ -205,3 +660,133 @@ func (h *bookHandler) DeleteBook(c *fiber.Ctx) error {
 
 	return util.SuccessResponse(c, nil)
 }
+
+// GetDeletedBooks Getting Deleted Books
+//
+//	@Summary        Get the trash
+//	@Description    Get the soft deleted books, most recently deleted first
+//	@Tags           Books
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "books:delete"
+//	@Param          page     query    int     false  "Page number (default: 1)"
+//	@Param          limit    query    int     false  "Items per page (default: 10)"
+//	@Param          title    query    string  false  "Search by title"
+//	@Success        200      {object} payload.Response{data=payload.GetDeletedBooksResponse}
+//	@Failure        400      {object} payload.GlobalErrorHandlerResp
+//	@Failure        401      {object} payload.GlobalErrorHandlerResp
+//	@Failure        403      {object} payload.GlobalErrorHandlerResp
+//	@Failure        500      {object} payload.GlobalErrorHandlerResp
+//	@Router         /v1/books/trash [get]
+func (h *bookHandler) GetDeletedBooks(c *fiber.Ctx) error {
+	var request payload.GetDeletedBooksRequest
+
+	if err := c.QueryParser(&request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	if request.Page == 0 {
+		request.Page = 1
+	}
+
+	if request.Limit == 0 {
+		request.Limit = 10 // set default limit is 10
+	}
+
+	res, err := h.bookService.GetDeletedBooks(c.Context(), request)
+	if err != nil {
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, res)
+}
+
+// RestoreBook Restoring Book
+//
+//	@Summary        Restore a book
+//	@Description    Take a soft deleted book out of the trash
+//	@Tags           Books
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "books:delete"
+//	@Param          id   path      string  true  "Book ID"
+//	@Success        200  {object}  payload.Response{}
+//	@Failure        400  {object}  payload.GlobalErrorHandlerResp
+//	@Failure        401  {object}  payload.GlobalErrorHandlerResp
+//	@Failure        403  {object}  payload.GlobalErrorHandlerResp
+//	@Failure        404  {object}  payload.GlobalErrorHandlerResp
+//	@Failure        500  {object}  payload.GlobalErrorHandlerResp
+//	@Router         /v1/books/trash/{id}/restore [post]
+func (h *bookHandler) RestoreBook(c *fiber.Ctx) error {
+	var request payload.RestoreBookRequest
+
+	id := c.Params("id")
+	request.ID = id
+
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	err := h.bookService.RestoreBook(c.Context(), request)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrBookNotFound) {
+			return util.ErrNotFoundResponse(c)
+		}
+		if errors.Is(err, errorcustom.ErrBookAlreadyExists) {
+			return util.ErrBadRequestResponse(c, err.Error())
+		}
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, nil)
+}
+
+// PurgeBook Purging Book
+//
+//	@Summary        Permanently delete a book
+//	@Description    Permanently delete a book in the trash along with its copies. Books that have loans or holds cannot be purged.
+//	@Tags           Books
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "books:delete"
+//	@Param          id   path      string  true  "Book ID"
+//	@Success        200  {object}  payload.Response{}
+//	@Failure        400  {object}  payload.GlobalErrorHandlerResp
+//	@Failure        401  {object}  payload.GlobalErrorHandlerResp
+//	@Failure        403  {object}  payload.GlobalErrorHandlerResp
+//	@Failure        404  {object}  payload.GlobalErrorHandlerResp
+//	@Failure        500  {object}  payload.GlobalErrorHandlerResp
+//	@Router         /v1/books/trash/{id} [delete]
+func (h *bookHandler) PurgeBook(c *fiber.Ctx) error {
+	var request payload.PurgeBookRequest
+
+	id := c.Params("id")
+	request.ID = id
+
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	err := h.bookService.PurgeBook(c.Context(), request)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrBookNotFound) {
+			return util.ErrNotFoundResponse(c)
+		}
+		if errors.Is(err, errorcustom.ErrBookPurgeBlocked) {
+			return util.ErrBadRequestResponse(c, err.Error())
+		}
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, nil)
+}
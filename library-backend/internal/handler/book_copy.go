@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"errors"
+	"library-backend/errorcustom"
+	"library-backend/internal/payload"
+	"library-backend/internal/service"
+	"library-backend/internal/util"
+	"library-backend/internal/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type BookCopyHandler interface {
+	CreateBookCopy(c *fiber.Ctx) error
+	GetBookCopies(c *fiber.Ctx) error
+	GetBookCopyByID(c *fiber.Ctx) error
+	UpdateBookCopy(c *fiber.Ctx) error
+	DeleteBookCopy(c *fiber.Ctx) error
+}
+
+type bookCopyHandler struct {
+	bookCopyService service.BookCopyService
+}
+
+func NewBookCopyHandler(bookCopyService service.BookCopyService) BookCopyHandler {
+	return &bookCopyHandler{bookCopyService: bookCopyService}
+}
+
+// CreateBookCopy Creating Book Copy
+//
+//	@Summary        Add a physical copy of a book
+//	@Description    Register a new physical copy (item) of a book with its barcode and shelf location
+//	@Tags           Book Copies
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "books:write"
+//	@Param          id    path      string                         true  "Book ID"
+//	@Param          copy  body      payload.CreateBookCopyRequest  true  "Copy data"
+//	@Success        200   {object}  payload.Response{data=payload.CreateBookCopyResponse}
+//	@Failure        400   {object}  payload.GlobalErrorHandlerResp
+//	@Failure        401   {object}  payload.GlobalErrorHandlerResp
+//	@Failure        403   {object}  payload.GlobalErrorHandlerResp
+//	@Failure        404   {object}  payload.GlobalErrorHandlerResp
+//	@Failure        500   {object}  payload.GlobalErrorHandlerResp
+//	@Router         /v1/books/{id}/copies [post]
+func (h *bookCopyHandler) CreateBookCopy(c *fiber.Ctx) error {
+	var request payload.CreateBookCopyRequest
+
+	if err := c.BodyParser(&request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	request.BookID = c.Params("id")
+
+	err := validator.Validate.Struct(request)
+	if err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	res, err := h.bookCopyService.CreateBookCopy(c.Context(), request)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrBookNotFound) {
+			return util.ErrNotFoundResponse(c)
+		}
+		if errors.Is(err, errorcustom.ErrBookCopyAlreadyExists) {
+			return util.ErrBadRequestResponse(c, err.Error())
+		}
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, res)
+}
+
+// GetBookCopies Getting Book Copies
+//
+//	@Summary        Get copies of a book
+//	@Description    Get every physical copy of a book, optionally filtered by status
+//	@Tags           Book Copies
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "books:read"
+//	@Param          id      path     string  true   "Book ID"
+//	@Param          status  query    string  false  "Filter by status (available, on_loan, on_hold, maintenance, lost)"
+//	@Success        200     {object} payload.Response{data=payload.GetBookCopiesResponse}
+//	@Failure        400     {object} payload.GlobalErrorHandlerResp
+//	@Failure        401     {object} payload.GlobalErrorHandlerResp
+//	@Failure        403     {object} payload.GlobalErrorHandlerResp
+//	@Failure        404     {object} payload.GlobalErrorHandlerResp
+//	@Failure        500     {object} payload.GlobalErrorHandlerResp
+//	@Router         /v1/books/{id}/copies [get]
+func (h *bookCopyHandler) GetBookCopies(c *fiber.Ctx) error {
+	var request payload.GetBookCopiesRequest
+
+	if err := c.QueryParser(&request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	request.BookID = c.Params("id")
+
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	res, err := h.bookCopyService.GetBookCopies(c.Context(), request)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrBookNotFound) {
+			return util.ErrNotFoundResponse(c)
+		}
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, res)
+}
+
+// GetBookCopyByID Getting Book Copy by ID
+//
+//	@Summary        Get a copy of a book
+//	@Description    Get a specific physical copy of a book
+//	@Tags           Book Copies
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "books:read"
+//	@Param          id      path     string  true  "Book ID"
+//	@Param          copyId  path     string  true  "Copy ID"
+//	@Success        200     {object} payload.Response{data=payload.GetBookCopyByIDResponse}
+//	@Failure        400     {object} payload.GlobalErrorHandlerResp
+//	@Failure        401     {object} payload.GlobalErrorHandlerResp
+//	@Failure        403     {object} payload.GlobalErrorHandlerResp
+//	@Failure        404     {object} payload.GlobalErrorHandlerResp
+//	@Failure        500     {object} payload.GlobalErrorHandlerResp
+//	@Router         /v1/books/{id}/copies/{copyId} [get]
+func (h *bookCopyHandler) GetBookCopyByID(c *fiber.Ctx) error {
+	var request payload.GetBookCopyByIDRequest
+
+	request.BookID = c.Params("id")
+	request.CopyID = c.Params("copyId")
+
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	res, err := h.bookCopyService.GetBookCopyByID(c.Context(), request)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrBookCopyNotFound) {
+			return util.ErrNotFoundResponse(c)
+		}
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, res)
+}
+
+// UpdateBookCopy Updating Book Copy
+//
+//	@Summary        Update a copy of a book
+//	@Description    Update barcode, shelf location, condition or status of a copy. Only provided fields will be updated.
+//	@Tags           Book Copies
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "books:write"
+//	@Param          id      path      string                         true  "Book ID"
+//	@Param          copyId  path      string                         true  "Copy ID"
+//	@Param          copy    body      payload.UpdateBookCopyRequest  true  "Copy update data"
+//	@Success        200     {object}  payload.Response{}
+//	@Failure        400     {object}  payload.GlobalErrorHandlerResp
+//	@Failure        401     {object}  payload.GlobalErrorHandlerResp
+//	@Failure        403     {object}  payload.GlobalErrorHandlerResp
+//	@Failure        404     {object}  payload.GlobalErrorHandlerResp
+//	@Failure        500     {object}  payload.GlobalErrorHandlerResp
+//	@Router         /v1/books/{id}/copies/{copyId} [put]
+func (h *bookCopyHandler) UpdateBookCopy(c *fiber.Ctx) error {
+	var request payload.UpdateBookCopyRequest
+
+	if err := c.BodyParser(&request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	request.BookID = c.Params("id")
+	request.CopyID = c.Params("copyId")
+
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	err := h.bookCopyService.UpdateBookCopy(c.Context(), request)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrBookCopyNotFound) {
+			return util.ErrNotFoundResponse(c)
+		}
+		if errors.Is(err, errorcustom.ErrBookCopyOnLoan) ||
+			errors.Is(err, errorcustom.ErrBookCopyOnHold) ||
+			errors.Is(err, errorcustom.ErrBookCopyAlreadyExists) {
+			return util.ErrBadRequestResponse(c, err.Error())
+		}
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, nil)
+}
+
+// DeleteBookCopy Deleting Book Copy
+//
+//	@Summary        Delete a copy of a book
+//	@Description    Soft delete a copy that is not currently on loan or set aside for a hold
+//	@Tags           Book Copies
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "books:delete"
+//	@Param          id      path      string  true  "Book ID"
+//	@Param          copyId  path      string  true  "Copy ID"
+//	@Success        200     {object}  payload.Response{}
+//	@Failure        400     {object}  payload.GlobalErrorHandlerResp
+//	@Failure        401     {object}  payload.GlobalErrorHandlerResp
+//	@Failure        403     {object}  payload.GlobalErrorHandlerResp
+//	@Failure        404     {object}  payload.GlobalErrorHandlerResp
+//	@Failure        500     {object}  payload.GlobalErrorHandlerResp
+//	@Router         /v1/books/{id}/copies/{copyId} [delete]
+func (h *bookCopyHandler) DeleteBookCopy(c *fiber.Ctx) error {
+	var request payload.DeleteBookCopyRequest
+
+	request.BookID = c.Params("id")
+	request.CopyID = c.Params("copyId")
+
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	err := h.bookCopyService.DeleteBookCopy(c.Context(), request)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrBookCopyNotFound) {
+			return util.ErrNotFoundResponse(c)
+		}
+		if errors.Is(err, errorcustom.ErrBookCopyOnLoan) || errors.Is(err, errorcustom.ErrBookCopyOnHold) {
+			return util.ErrBadRequestResponse(c, err.Error())
+		}
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, nil)
+}
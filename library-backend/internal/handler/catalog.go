@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"errors"
+	"library-backend/errorcustom"
+	"library-backend/internal/payload"
+	"library-backend/internal/service"
+	"library-backend/internal/util"
+	"library-backend/internal/validator"
+	"library-backend/pkg/opds"
+	"library-backend/pkg/sru"
+	"net"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// opdsPageSize is the number of books per page of an acquisition feed when the client does not ask
+// for a limit.
+const opdsPageSize = 25
+
+type CatalogHandler interface {
+	GetOPDSRoot(c *fiber.Ctx) error
+	GetOPDSBooks(c *fiber.Ctx) error
+	GetOpenSearchDescription(c *fiber.Ctx) error
+	SRU(c *fiber.Ctx) error
+}
+
+type catalogHandler struct {
+	catalogService service.CatalogService
+}
+
+func NewCatalogHandler(catalogService service.CatalogService) CatalogHandler {
+	return &catalogHandler{catalogService: catalogService}
+}
+
+// GetOPDSRoot Getting the OPDS Catalog
+//
+//	@Summary        OPDS catalog root
+//	@Description    The OPDS 1.2 navigation feed e-reader apps start browsing from, with feeds of every book, the newest books and each category, and a link to the OpenSearch description. Apps that only support HTTP Basic auth can send an API key as the password.
+//	@Tags           Catalog
+//	@Produce        xml
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "books:read"
+//	@Success        200      {string} string
+//	@Failure        401      {object} payload.GlobalErrorHandlerResp
+//	@Failure        403      {object} payload.GlobalErrorHandlerResp
+//	@Failure        500      {object} payload.GlobalErrorHandlerResp
+//	@Router         /v1/opds [get]
+func (h *catalogHandler) GetOPDSRoot(c *fiber.Ctx) error {
+	feed := h.catalogService.GetOPDSRoot(c.Context())
+
+	return sendOPDSFeed(c, feed, opds.TypeNavigation)
+}
+
+// GetOPDSBooks Getting an OPDS Acquisition Feed
+//
+//	@Summary        OPDS acquisition feed of books
+//	@Description    A page of the book list as an OPDS 1.2 acquisition feed, with first, previous, next and last page links. It takes the filters and sort of the book list; cursor pagination is not available. Entries link their cover image and carry how many copies are available, with a borrow acquisition link to the copies.
+//	@Tags           Catalog
+//	@Produce        xml
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "books:read"
+//	@Param          page          query    int       false  "Page number (default: 1)"
+//	@Param          limit         query    int       false  "Books per page (default: 25)"
+//	@Param          title         query    string    false  "Search by title"
+//	@Param          q             query    string    false  "Full-text search in web search syntax (quoted phrases, or, -exclude)"
+//	@Param          category      query    []string  false  "Filter by category, repeat for several categories"  collectionFormat(multi)
+//	@Param          author        query    string    false  "Filter by author (partial match)"
+//	@Param          publisher     query    string    false  "Filter by publisher (partial match)"
+//	@Param          year_from     query    int       false  "Published in or after this year"
+//	@Param          year_to       query    int       false  "Published in or before this year"
+//	@Param          sort          query    string    false  "Comma separated sort fields, as for the book list"
+//	@Param          filter        query    string    false  "Filter expression, as for the book list"
+//	@Success        200           {string} string
+//	@Failure        400           {object} payload.GlobalErrorHandlerResp
+//	@Failure        401           {object} payload.GlobalErrorHandlerResp
+//	@Failure        403           {object} payload.GlobalErrorHandlerResp
+//	@Failure        500           {object} payload.GlobalErrorHandlerResp
+//	@Router         /v1/opds/books [get]
+func (h *catalogHandler) GetOPDSBooks(c *fiber.Ctx) error {
+	var request payload.GetBooksRequest
+
+	if err := c.QueryParser(&request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	if request.Page == 0 {
+		request.Page = 1
+	}
+
+	if request.Limit == 0 {
+		request.Limit = opdsPageSize
+	}
+
+	feed, err := h.catalogService.GetOPDSBooks(c.Context(), request)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrInvalidSortField) || errors.Is(err, errorcustom.ErrInvalidFilter) {
+			return util.ErrBadRequestResponse(c, err.Error())
+		}
+		return util.ErrInternalResponse(c)
+	}
+
+	return sendOPDSFeed(c, feed, opds.TypeAcquisition)
+}
+
+// GetOpenSearchDescription Getting the OPDS Search Description
+//
+//	@Summary        OpenSearch description of the catalog
+//	@Description    Tells e-reader apps how to search the catalog, the search runs the full-text book search and returns an acquisition feed
+//	@Tags           Catalog
+//	@Produce        xml
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "books:read"
+//	@Success        200      {string} string
+//	@Failure        401      {object} payload.GlobalErrorHandlerResp
+//	@Failure        403      {object} payload.GlobalErrorHandlerResp
+//	@Failure        500      {object} payload.GlobalErrorHandlerResp
+//	@Router         /v1/opds/search.xml [get]
+func (h *catalogHandler) GetOpenSearchDescription(c *fiber.Ctx) error {
+	raw, err := opds.MarshalOpenSearch(h.catalogService.GetOpenSearchDescription(c.Context()))
+	if err != nil {
+		return util.ErrInternalResponse(c)
+	}
+
+	c.Set(fiber.HeaderContentType, opds.TypeOpenSearch+";charset=utf-8")
+	return c.Send(raw)
+}
+
+// SRU Searching the Catalog over SRU
+//
+//	@Summary        SRU 2.0 search, scan and explain
+//	@Description    Lets library systems search the catalog over SRU 2.0. A query runs searchRetrieve, a CQL query such as dc.title any "go" and dc.date > 2010 over the indexes dc.title, dc.creator, dc.publisher, dc.date, dc.subject, dc.identifier, bath.isbn, rec.identifier and cql.serverChoice, returning Dublin Core or MARCXML records. A scanClause such as dc.creator = "tolkien" runs scan, browsing the values of an index with their number of books. With neither the explain record describes the server. Bad parameters and queries are reported as SRU diagnostics in a 200 response. Apps that only support HTTP Basic auth can send an API key as the password.
+//	@Tags           Catalog
+//	@Produce        xml
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "books:read"
+//	@Param          operation          query    string  false  "Operation for SRU 1.x clients: searchRetrieve, scan or explain"
+//	@Param          version            query    string  false  "SRU version, only 2.0 is supported"
+//	@Param          query              query    string  false  "CQL query (searchRetrieve)"
+//	@Param          startRecord        query    int     false  "Position of the first record, from 1 (default: 1)"
+//	@Param          maximumRecords     query    int     false  "Records per response, 0 to 100 (default: 10)"
+//	@Param          recordSchema       query    string  false  "dc or marcxml, or their schema identifiers (default: dc)"
+//	@Param          recordXMLEscaping  query    string  false  "xml or string (default: xml)"
+//	@Param          scanClause         query    string  false  "Index, relation and term to browse from (scan)"
+//	@Param          responsePosition   query    int     false  "Position of the term in the list of terms (default: 1)"
+//	@Param          maximumTerms       query    int     false  "Terms per response, 1 to 100 (default: 20)"
+//	@Success        200                {string} string
+//	@Failure        401                {object} payload.GlobalErrorHandlerResp
+//	@Failure        403                {object} payload.GlobalErrorHandlerResp
+//	@Failure        500                {object} payload.GlobalErrorHandlerResp
+//	@Router         /v1/sru [get]
+func (h *catalogHandler) SRU(c *fiber.Ctx) error {
+	var request payload.SRURequest
+
+	if err := c.QueryParser(&request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	request.Host, request.Port = sruServerAddress(c)
+
+	var res any
+	var err error
+
+	switch operation := request.ResolvedOperation(); operation {
+	case payload.SRUOperationSearchRetrieve:
+		res, err = h.catalogService.SearchRetrieve(c.Context(), request)
+	case payload.SRUOperationScan:
+		res, err = h.catalogService.Scan(c.Context(), request)
+	default:
+		// explain answers unknown operations with a diagnostic
+		res, err = h.catalogService.Explain(c.Context(), request)
+	}
+	if err != nil {
+		return util.ErrInternalResponse(c)
+	}
+
+	raw, err := sru.Marshal(res)
+	if err != nil {
+		return util.ErrInternalResponse(c)
+	}
+
+	c.Set(fiber.HeaderContentType, sru.ContentType+";charset=utf-8")
+	return c.Send(raw)
+}
+
+// sruServerAddress is the host and port the request was sent to, the port of the scheme when the
+// host does not name one.
+func sruServerAddress(c *fiber.Ctx) (string, int) {
+	host, port, err := net.SplitHostPort(c.Hostname())
+	if err == nil {
+		if n, err := strconv.Atoi(port); err == nil {
+			return host, n
+		}
+	}
+
+	if c.Protocol() == "https" {
+		return c.Hostname(), 443
+	}
+	return c.Hostname(), 80
+}
+
+func sendOPDSFeed(c *fiber.Ctx, feed opds.Feed, contentType string) error {
+	raw, err := opds.Marshal(feed)
+	if err != nil {
+		return util.ErrInternalResponse(c)
+	}
+
+	c.Set(fiber.HeaderContentType, contentType+";charset=utf-8")
+	return c.Send(raw)
+}
@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"errors"
+	"library-backend/errorcustom"
+	"library-backend/internal/payload"
+	"library-backend/internal/service"
+	"library-backend/internal/util"
+	"library-backend/internal/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type FineHandler interface {
+	GetMemberFines(c *fiber.Ctx) error
+	CreatePayment(c *fiber.Ctx) error
+	CreateWaiver(c *fiber.Ctx) error
+	GetFineBalances(c *fiber.Ctx) error
+}
+
+type fineHandler struct {
+	fineService service.FineService
+}
+
+func NewFineHandler(fineService service.FineService) FineHandler {
+	return &fineHandler{fineService: fineService}
+}
+
+// GetMemberFines Getting Member Fines
+//
+//	@Summary        Get a member's fines ledger
+//	@Description    Get the outstanding fine balance of a member together with the ledger of charges, waivers and payments, newest first. Amounts are in minor currency units.
+//	@Tags           Fines
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "fines:read"
+//	@Param          id     path     string  true   "Member ID"
+//	@Param          page   query    int     false  "Page number (default: 1)"
+//	@Param          limit  query    int     false  "Items per page (default: 10)"
+//	@Success        200    {object} payload.Response{data=payload.GetMemberFinesResponse}
+//	@Failure        400    {object} payload.GlobalErrorHandlerResp
+//	@Failure        401    {object} payload.GlobalErrorHandlerResp
+//	@Failure        403    {object} payload.GlobalErrorHandlerResp
+//	@Failure        404    {object} payload.GlobalErrorHandlerResp
+//	@Failure        500    {object} payload.GlobalErrorHandlerResp
+//	@Router         /v1/members/{id}/fines [get]
+func (h *fineHandler) GetMemberFines(c *fiber.Ctx) error {
+	var request payload.GetMemberFinesRequest
+
+	if err := c.QueryParser(&request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	request.MemberID = c.Params("id")
+
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	if request.Page == 0 {
+		request.Page = 1
+	}
+
+	if request.Limit == 0 {
+		request.Limit = 10 // set default limit is 10
+	}
+
+	res, err := h.fineService.GetMemberFines(c.Context(), request)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrMemberNotFound) {
+			return util.ErrNotFoundResponse(c)
+		}
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, res)
+}
+
+// CreatePayment Recording a Fine Payment
+//
+//	@Summary        Record a fine payment
+//	@Description    Record a payment against a member's outstanding fines. The amount, in minor currency units, cannot exceed the balance.
+//	@Tags           Fines
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "fines:write"
+//	@Param          id       path      string                            true  "Member ID"
+//	@Param          payment  body      payload.CreateFinePaymentRequest  true  "Payment data"
+//	@Success        200      {object}  payload.Response{data=payload.CreateFineEntryResponse}
+//	@Failure        400      {object}  payload.GlobalErrorHandlerResp
+//	@Failure        401      {object}  payload.GlobalErrorHandlerResp
+//	@Failure        403      {object}  payload.GlobalErrorHandlerResp
+//	@Failure        404      {object}  payload.GlobalErrorHandlerResp
+//	@Failure        500      {object}  payload.GlobalErrorHandlerResp
+//	@Router         /v1/members/{id}/fines/payments [post]
+func (h *fineHandler) CreatePayment(c *fiber.Ctx) error {
+	var request payload.CreateFinePaymentRequest
+
+	if err := c.BodyParser(&request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	request.MemberID = c.Params("id")
+
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	res, err := h.fineService.CreatePayment(c.Context(), request)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrMemberNotFound) {
+			return util.ErrNotFoundResponse(c)
+		}
+		if errors.Is(err, errorcustom.ErrFineExceedsBalance) {
+			return util.ErrBadRequestResponse(c, err.Error())
+		}
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, res)
+}
+
+// CreateWaiver Waiving a Fine
+//
+//	@Summary        Waive fines
+//	@Description    Forgive part or all of a member's outstanding fines, optionally pointing at the loan the waiver is for. The amount, in minor currency units, cannot exceed the balance.
+//	@Tags           Fines
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "fines:write"
+//	@Param          id      path      string                           true  "Member ID"
+//	@Param          waiver  body      payload.CreateFineWaiverRequest  true  "Waiver data"
+//	@Success        200     {object}  payload.Response{data=payload.CreateFineEntryResponse}
+//	@Failure        400     {object}  payload.GlobalErrorHandlerResp
+//	@Failure        401     {object}  payload.GlobalErrorHandlerResp
+//	@Failure        403     {object}  payload.GlobalErrorHandlerResp
+//	@Failure        404     {object}  payload.GlobalErrorHandlerResp
+//	@Failure        500     {object}  payload.GlobalErrorHandlerResp
+//	@Router         /v1/members/{id}/fines/waivers [post]
+func (h *fineHandler) CreateWaiver(c *fiber.Ctx) error {
+	var request payload.CreateFineWaiverRequest
+
+	if err := c.BodyParser(&request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	request.MemberID = c.Params("id")
+
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	res, err := h.fineService.CreateWaiver(c.Context(), request)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrMemberNotFound) {
+			return util.ErrNotFoundResponse(c)
+		}
+		if errors.Is(err, errorcustom.ErrFineExceedsBalance) || errors.Is(err, errorcustom.ErrLoanNotFound) {
+			return util.ErrBadRequestResponse(c, err.Error())
+		}
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, res)
+}
+
+// GetFineBalances Getting Fine Balances
+//
+//	@Summary        Get outstanding fine balances
+//	@Description    Get the members who still owe fines with their charged, waived and paid totals, largest balance first
+//	@Tags           Fines
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "fines:read"
+//	@Param          page   query    int  false  "Page number (default: 1)"
+//	@Param          limit  query    int  false  "Items per page (default: 10)"
+//	@Success        200    {object} payload.Response{data=payload.GetFineBalancesResponse}
+//	@Failure        400    {object} payload.GlobalErrorHandlerResp
+//	@Failure        401    {object} payload.GlobalErrorHandlerResp
+//	@Failure        403    {object} payload.GlobalErrorHandlerResp
+//	@Failure        500    {object} payload.GlobalErrorHandlerResp
+//	@Router         /v1/fines/balances [get]
+func (h *fineHandler) GetFineBalances(c *fiber.Ctx) error {
+	var request payload.GetFineBalancesRequest
+
+	if err := c.QueryParser(&request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	if request.Page == 0 {
+		request.Page = 1
+	}
+
+	if request.Limit == 0 {
+		request.Limit = 10 // set default limit is 10
+	}
+
+	res, err := h.fineService.GetFineBalances(c.Context(), request)
+	if err != nil {
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, res)
+}
@@ -6,7 +6,17 @@ import (
 )
 
 type Handler struct {
-	BookHandler BookHandler
+	BookHandler     BookHandler
+	MemberHandler   MemberHandler
+	LoanHandler     LoanHandler
+	BookCopyHandler BookCopyHandler
+	HoldHandler     HoldHandler
+	FineHandler     FineHandler
+	AuthHandler     AuthHandler
+	UserHandler     UserHandler
+	APIKeyHandler   APIKeyHandler
+	AuditHandler    AuditHandler
+	CatalogHandler  CatalogHandler
 }
 
 type Option struct {
@@ -16,6 +26,16 @@ type Option struct {
 
 func InitiateHandler(opt Option) *Handler {
 	return &Handler{
-		BookHandler: NewBookHandler(opt.Service.BookService),
+		BookHandler:     NewBookHandler(opt.Service.BookService),
+		MemberHandler:   NewMemberHandler(opt.Service.MemberService),
+		LoanHandler:     NewLoanHandler(opt.Service.LoanService),
+		BookCopyHandler: NewBookCopyHandler(opt.Service.BookCopyService),
+		HoldHandler:     NewHoldHandler(opt.Service.HoldService),
+		FineHandler:     NewFineHandler(opt.Service.FineService),
+		AuthHandler:     NewAuthHandler(opt.Service.AuthService),
+		UserHandler:     NewUserHandler(opt.Service.UserService),
+		APIKeyHandler:   NewAPIKeyHandler(opt.Service.APIKeyService),
+		AuditHandler:    NewAuditHandler(opt.Service.AuditService),
+		CatalogHandler:  NewCatalogHandler(opt.Service.CatalogService),
 	}
 }
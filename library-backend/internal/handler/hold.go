@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"errors"
+	"library-backend/errorcustom"
+	"library-backend/internal/payload"
+	"library-backend/internal/service"
+	"library-backend/internal/util"
+	"library-backend/internal/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type HoldHandler interface {
+	CreateHold(c *fiber.Ctx) error
+	GetHolds(c *fiber.Ctx) error
+	GetHoldByID(c *fiber.Ctx) error
+	CancelHold(c *fiber.Ctx) error
+}
+
+type holdHandler struct {
+	holdService service.HoldService
+}
+
+func NewHoldHandler(holdService service.HoldService) HoldHandler {
+	return &holdHandler{holdService: holdService}
+}
+
+// CreateHold Placing a Hold
+//
+//	@Summary        Place a hold on a book
+//	@Description    Queue a member for a book whose copies are all checked out. Returned copies are set aside for holds first come, first served.
+//	@Tags           Holds
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "holds:write"
+//	@Param          hold  body      payload.CreateHoldRequest  true  "Hold data"
+//	@Success        200   {object}  payload.Response{data=payload.CreateHoldResponse}
+//	@Failure        400   {object}  payload.GlobalErrorHandlerResp
+//	@Failure        401   {object}  payload.GlobalErrorHandlerResp
+//	@Failure        403   {object}  payload.GlobalErrorHandlerResp
+//	@Failure        500   {object}  payload.GlobalErrorHandlerResp
+//	@Router         /v1/holds [post]
+func (h *holdHandler) CreateHold(c *fiber.Ctx) error {
+	var request payload.CreateHoldRequest
+
+	if err := c.BodyParser(&request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	err := validator.Validate.Struct(request)
+	if err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	res, err := h.holdService.CreateHold(c.Context(), request)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrBookNotFound) ||
+			errors.Is(err, errorcustom.ErrMemberNotFound) ||
+			errors.Is(err, errorcustom.ErrMemberNotActive) ||
+			errors.Is(err, errorcustom.ErrHoldNotNeeded) ||
+			errors.Is(err, errorcustom.ErrHoldAlreadyExists) {
+			return util.ErrBadRequestResponse(c, err.Error())
+		}
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, res)
+}
+
+// GetHolds Getting Holds
+//
+//	@Summary        Get Holds with pagination
+//	@Description    Get a list of holds in queue order, filterable by status, member and book
+//	@Tags           Holds
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "holds:read"
+//	@Param          page       query    int     false  "Page number (default: 1)"
+//	@Param          limit      query    int     false  "Items per page (default: 10)"
+//	@Param          status     query    string  false  "Filter by status (waiting, ready, fulfilled, cancelled, expired)"
+//	@Param          member_id  query    string  false  "Filter by member ID"
+//	@Param          book_id    query    string  false  "Filter by book ID"
+//	@Success        200        {object} payload.Response{data=payload.GetHoldsResponse}
+//	@Failure        400        {object} payload.GlobalErrorHandlerResp
+//	@Failure        401        {object} payload.GlobalErrorHandlerResp
+//	@Failure        403        {object} payload.GlobalErrorHandlerResp
+//	@Failure        500        {object} payload.GlobalErrorHandlerResp
+//	@Router         /v1/holds [get]
+func (h *holdHandler) GetHolds(c *fiber.Ctx) error {
+	var request payload.GetHoldsRequest
+
+	if err := c.QueryParser(&request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	if request.Page == 0 {
+		request.Page = 1
+	}
+
+	if request.Limit == 0 {
+		request.Limit = 10 // set default limit is 10
+	}
+
+	res, err := h.holdService.GetHolds(c.Context(), request)
+	if err != nil {
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, res)
+}
+
+// GetHoldByID Getting Hold by ID
+//
+//	@Summary        Get Hold by ID
+//	@Description    Get a specific hold by its ID, including its position in the queue while it is waiting
+//	@Tags           Holds
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "holds:read"
+//	@Param          id   path     string  true  "Hold ID"
+//	@Success        200  {object} payload.Response{data=payload.GetHoldByIDResponse}
+//	@Failure        400  {object} payload.GlobalErrorHandlerResp
+//	@Failure        401  {object} payload.GlobalErrorHandlerResp
+//	@Failure        403  {object} payload.GlobalErrorHandlerResp
+//	@Failure        404  {object} payload.GlobalErrorHandlerResp
+//	@Failure        500  {object} payload.GlobalErrorHandlerResp
+//	@Router         /v1/holds/{id} [get]
+func (h *holdHandler) GetHoldByID(c *fiber.Ctx) error {
+	var request payload.GetHoldByIDRequest
+
+	id := c.Params("id")
+	request.ID = id
+
+	err := validator.Validate.Struct(request)
+	if err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	res, err := h.holdService.GetHoldByID(c.Context(), request.ID)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrHoldNotFound) {
+			return util.ErrNotFoundResponse(c)
+		}
+		return util.ErrInternalResponse(c)
+	}
+	return util.SuccessResponse(c, res)
+}
+
+// CancelHold Cancelling a Hold
+//
+//	@Summary        Cancel a hold
+//	@Description    Cancel a waiting or ready hold. A copy set aside for the hold is passed on to the next member in the queue.
+//	@Tags           Holds
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "holds:write"
+//	@Param          id   path      string  true  "Hold ID"
+//	@Success        200  {object}  payload.Response{}
+//	@Failure        400  {object}  payload.GlobalErrorHandlerResp
+//	@Failure        401  {object}  payload.GlobalErrorHandlerResp
+//	@Failure        403  {object}  payload.GlobalErrorHandlerResp
+//	@Failure        404  {object}  payload.GlobalErrorHandlerResp
+//	@Failure        500  {object}  payload.GlobalErrorHandlerResp
+//	@Router         /v1/holds/{id}/cancel [post]
+func (h *holdHandler) CancelHold(c *fiber.Ctx) error {
+	var request payload.CancelHoldRequest
+
+	id := c.Params("id")
+	request.ID = id
+
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	err := h.holdService.CancelHold(c.Context(), request)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrHoldNotFound) {
+			return util.ErrNotFoundResponse(c)
+		}
+		if errors.Is(err, errorcustom.ErrHoldNotActive) {
+			return util.ErrBadRequestResponse(c, err.Error())
+		}
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, nil)
+}
@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"errors"
+	"library-backend/errorcustom"
+	"library-backend/internal/payload"
+	"library-backend/internal/service"
+	"library-backend/internal/util"
+	"library-backend/internal/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type LoanHandler interface {
+	CreateLoan(c *fiber.Ctx) error
+	GetLoans(c *fiber.Ctx) error
+	GetLoanByID(c *fiber.Ctx) error
+	ReturnLoan(c *fiber.Ctx) error
+	GetBorrowingReport(c *fiber.Ctx) error
+}
+
+type loanHandler struct {
+	loanService service.LoanService
+}
+
+func NewLoanHandler(loanService service.LoanService) LoanHandler {
+	return &loanHandler{loanService: loanService}
+}
+
+// CreateLoan Checking out a Book
+//
+//	@Summary        Check out a book
+//	@Description    Lend a copy of a book to a member. A member with a ready hold gets the copy set aside for them, otherwise any available copy is picked unless copy_id is given. The due date defaults to the configured loan period when omitted.
+//	@Tags           Loans
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "loans:write"
+//	@Param          loan  body      payload.CreateLoanRequest  true  "Loan data"
+//	@Success        200   {object}  payload.Response{data=payload.CreateLoanResponse}
+//	@Failure        400   {object}  payload.GlobalErrorHandlerResp
+//	@Failure        401   {object}  payload.GlobalErrorHandlerResp
+//	@Failure        403   {object}  payload.GlobalErrorHandlerResp
+//	@Failure        500   {object}  payload.GlobalErrorHandlerResp
+//	@Router         /v1/loans [post]
+func (h *loanHandler) CreateLoan(c *fiber.Ctx) error {
+	var request payload.CreateLoanRequest
+
+	if err := c.BodyParser(&request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	err := validator.Validate.Struct(request)
+	if err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	res, err := h.loanService.CreateLoan(c.Context(), request)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrBookNotFound) ||
+			errors.Is(err, errorcustom.ErrMemberNotFound) ||
+			errors.Is(err, errorcustom.ErrMemberNotActive) ||
+			errors.Is(err, errorcustom.ErrBookNotAvailable) ||
+			errors.Is(err, errorcustom.ErrBookCopyNotFound) ||
+			errors.Is(err, errorcustom.ErrBookCopyNotAvailable) ||
+			errors.Is(err, errorcustom.ErrInvalidDueDate) {
+			return util.ErrBadRequestResponse(c, err.Error())
+		}
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, res)
+}
+
+// GetLoans Getting Loans
+//
+//	@Summary        Get Loans with pagination
+//	@Description    Get a list of loans with pagination, filterable by status, member and book
+//	@Tags           Loans
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "loans:read"
+//	@Param          page       query    int     false  "Page number (default: 1)"
+//	@Param          limit      query    int     false  "Items per page (default: 10)"
+//	@Param          status     query    string  false  "Filter by status (active, overdue, returned)"
+//	@Param          member_id  query    string  false  "Filter by member ID"
+//	@Param          book_id    query    string  false  "Filter by book ID"
+//	@Success        200        {object} payload.Response{data=payload.GetLoansResponse}
+//	@Failure        400        {object} payload.GlobalErrorHandlerResp
+//	@Failure        401        {object} payload.GlobalErrorHandlerResp
+//	@Failure        403        {object} payload.GlobalErrorHandlerResp
+//	@Failure        500        {object} payload.GlobalErrorHandlerResp
+//	@Router         /v1/loans [get]
+func (h *loanHandler) GetLoans(c *fiber.Ctx) error {
+	var request payload.GetLoansRequest
+
+	if err := c.QueryParser(&request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	if request.Page == 0 {
+		request.Page = 1
+	}
+
+	if request.Limit == 0 {
+		request.Limit = 10 // set default limit is 10
+	}
+
+	res, err := h.loanService.GetLoans(c.Context(), request)
+	if err != nil {
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, res)
+}
+
+// GetLoanByID Getting Loan by ID
+//
+//	@Summary        Get Loan by ID
+//	@Description    Get a specific loan by its ID
+//	@Tags           Loans
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "loans:read"
+//	@Param          id   path     string  true  "Loan ID"
+//	@Success        200  {object} payload.Response{data=payload.GetLoanByIDResponse}
+//	@Failure        400  {object} payload.GlobalErrorHandlerResp
+//	@Failure        401  {object} payload.GlobalErrorHandlerResp
+//	@Failure        403  {object} payload.GlobalErrorHandlerResp
+//	@Failure        404  {object} payload.GlobalErrorHandlerResp
+//	@Failure        500  {object} payload.GlobalErrorHandlerResp
+//	@Router         /v1/loans/{id} [get]
+func (h *loanHandler) GetLoanByID(c *fiber.Ctx) error {
+	var request payload.GetLoanByIDRequest
+
+	id := c.Params("id")
+	request.ID = id
+
+	err := validator.Validate.Struct(request)
+	if err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	res, err := h.loanService.GetLoanByID(c.Context(), request.ID)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrLoanNotFound) {
+			return util.ErrNotFoundResponse(c)
+		}
+		return util.ErrInternalResponse(c)
+	}
+	return util.SuccessResponse(c, res)
+}
+
+// ReturnLoan Returning a Book
+//
+//	@Summary        Return a borrowed book
+//	@Description    Mark a loan as returned, charging an overdue fine to the member when it comes back late. The copy is set aside for the first waiting hold on the book, or put back on the shelf when nobody is queueing
+//	@Tags           Loans
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "loans:write"
+//	@Param          id   path      string  true  "Loan ID"
+//	@Success        200  {object}  payload.Response{}
+//	@Failure        400  {object}  payload.GlobalErrorHandlerResp
+//	@Failure        401  {object}  payload.GlobalErrorHandlerResp
+//	@Failure        403  {object}  payload.GlobalErrorHandlerResp
+//	@Failure        404  {object}  payload.GlobalErrorHandlerResp
+//	@Failure        500  {object}  payload.GlobalErrorHandlerResp
+//	@Router         /v1/loans/{id}/return [post]
+func (h *loanHandler) ReturnLoan(c *fiber.Ctx) error {
+	var request payload.ReturnLoanRequest
+
+	id := c.Params("id")
+	request.ID = id
+
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	err := h.loanService.ReturnLoan(c.Context(), request)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrLoanNotFound) {
+			return util.ErrNotFoundResponse(c)
+		}
+		if errors.Is(err, errorcustom.ErrLoanAlreadyReturned) {
+			return util.ErrBadRequestResponse(c, err.Error())
+		}
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, nil)
+}
+
+// GetBorrowingReport Getting Borrowing Report
+//
+//	@Summary        Get borrowing report
+//	@Description    Get loan totals by status, the most borrowed books and loans per month for the last 12 months
+//	@Tags           Loans
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "loans:read"
+//	@Success        200  {object}  payload.Response{data=payload.BorrowingReportResponse}
+//	@Failure        401  {object}  payload.GlobalErrorHandlerResp
+//	@Failure        403  {object}  payload.GlobalErrorHandlerResp
+//	@Failure        500  {object}  payload.GlobalErrorHandlerResp
+//	@Router         /v1/loans/report [get]
+func (h *loanHandler) GetBorrowingReport(c *fiber.Ctx) error {
+	res, err := h.loanService.GetBorrowingReport(c.Context())
+	if err != nil {
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, res)
+}
@@ -0,0 +1,240 @@
+package handler
+
+import (
+	"errors"
+	"library-backend/errorcustom"
+	"library-backend/internal/payload"
+	"library-backend/internal/service"
+	"library-backend/internal/util"
+	"library-backend/internal/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type MemberHandler interface {
+	CreateMember(c *fiber.Ctx) error
+	GetMembers(c *fiber.Ctx) error
+	GetMemberByID(c *fiber.Ctx) error
+	UpdateMember(c *fiber.Ctx) error
+	DeleteMember(c *fiber.Ctx) error
+}
+
+type memberHandler struct {
+	memberService service.MemberService
+}
+
+func NewMemberHandler(memberService service.MemberService) MemberHandler {
+	return &memberHandler{memberService: memberService}
+}
+
+// CreateMember Creating Member
+//
+//	@Summary        Create a new member
+//	@Description    Register a new library member with the provided details
+//	@Tags           Members
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "members:write"
+//	@Param          member  body      payload.CreateMemberRequest  true  "Member data"
+//	@Success        200     {object}  payload.Response{data=payload.CreateMemberResponse}
+//	@Failure        400     {object}  payload.GlobalErrorHandlerResp
+//	@Failure        401     {object}  payload.GlobalErrorHandlerResp
+//	@Failure        403     {object}  payload.GlobalErrorHandlerResp
+//	@Failure        500     {object}  payload.GlobalErrorHandlerResp
+//	@Router         /v1/members [post]
+func (h *memberHandler) CreateMember(c *fiber.Ctx) error {
+	var request payload.CreateMemberRequest
+
+	if err := c.BodyParser(&request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	err := validator.Validate.Struct(request)
+	if err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	res, err := h.memberService.CreateMember(c.Context(), request)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrMemberAlreadyExists) {
+			return util.ErrBadRequestResponse(c, err.Error())
+		}
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, res)
+}
+
+// GetMembers Getting Members
+//
+//	@Summary        Get Members with pagination
+//	@Description    Get a list of members with pagination, search and status filter support
+//	@Tags           Members
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "members:read"
+//	@Param          page     query    int     false  "Page number (default: 1)"
+//	@Param          limit    query    int     false  "Items per page (default: 10)"
+//	@Param          search   query    string  false  "Search by name or email"
+//	@Param          status   query    string  false  "Filter by status (active, inactive, suspended)"
+//	@Success        200      {object} payload.Response{data=payload.GetMembersResponse}
+//	@Failure        400      {object} payload.GlobalErrorHandlerResp
+//	@Failure        401      {object} payload.GlobalErrorHandlerResp
+//	@Failure        403      {object} payload.GlobalErrorHandlerResp
+//	@Failure        500      {object} payload.GlobalErrorHandlerResp
+//	@Router         /v1/members [get]
+func (h *memberHandler) GetMembers(c *fiber.Ctx) error {
+	var request payload.GetMembersRequest
+
+	if err := c.QueryParser(&request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	if request.Page == 0 {
+		request.Page = 1
+	}
+
+	if request.Limit == 0 {
+		request.Limit = 10 // set default limit is 10
+	}
+
+	res, err := h.memberService.GetMembers(c.Context(), request)
+	if err != nil {
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, res)
+}
+
+// GetMemberByID Getting Member by ID
+//
+//	@Summary        Get Member by ID
+//	@Description    Get a specific member by its ID
+//	@Tags           Members
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "members:read"
+//	@Param          id   path     string  true  "Member ID"
+//	@Success        200  {object} payload.Response{data=payload.GetMemberByIDResponse}
+//	@Failure        400  {object} payload.GlobalErrorHandlerResp
+//	@Failure        401  {object} payload.GlobalErrorHandlerResp
+//	@Failure        403  {object} payload.GlobalErrorHandlerResp
+//	@Failure        404  {object} payload.GlobalErrorHandlerResp
+//	@Failure        500  {object} payload.GlobalErrorHandlerResp
+//	@Router         /v1/members/{id} [get]
+func (h *memberHandler) GetMemberByID(c *fiber.Ctx) error {
+	var request payload.GetMemberByIDRequest
+
+	id := c.Params("id")
+	request.ID = id
+
+	err := validator.Validate.Struct(request)
+	if err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	res, err := h.memberService.GetMemberByID(c.Context(), request.ID)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrMemberNotFound) {
+			return util.ErrNotFoundResponse(c)
+		}
+		return util.ErrInternalResponse(c)
+	}
+	return util.SuccessResponse(c, res)
+}
+
+// UpdateMember Updating Member
+//
+//	@Summary        Update a member
+//	@Description    Update a member's information by ID. Only provided fields will be updated.
+//	@Tags           Members
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "members:write"
+//	@Param          id      path      string                       true   "Member ID"
+//	@Param          member  body      payload.UpdateMemberRequest  true   "Member update data"
+//	@Success        200     {object}  payload.Response{}
+//	@Failure        400     {object}  payload.GlobalErrorHandlerResp
+//	@Failure        401     {object}  payload.GlobalErrorHandlerResp
+//	@Failure        403     {object}  payload.GlobalErrorHandlerResp
+//	@Failure        404     {object}  payload.GlobalErrorHandlerResp
+//	@Failure        500     {object}  payload.GlobalErrorHandlerResp
+//	@Router         /v1/members/{id} [put]
+func (h *memberHandler) UpdateMember(c *fiber.Ctx) error {
+	var request payload.UpdateMemberRequest
+
+	id := c.Params("id")
+	request.ID = id
+
+	if err := c.BodyParser(&request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	err := h.memberService.UpdateMember(c.Context(), request)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrMemberNotFound) {
+			return util.ErrNotFoundResponse(c)
+		}
+		if errors.Is(err, errorcustom.ErrMemberAlreadyExists) {
+			return util.ErrBadRequestResponse(c, err.Error())
+		}
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, nil)
+}
+
+// DeleteMember Deleting Member
+//
+//	@Summary        Delete a member
+//	@Description    Soft delete a member by ID. Sets the deleted_at timestamp.
+//	@Tags           Members
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@Security       ApiKeyAuth
+//	@x-permission   "members:delete"
+//	@Param          id   path      string  true  "Member ID"
+//	@Success        200  {object}  payload.Response{}
+//	@Failure        400  {object}  payload.GlobalErrorHandlerResp
+//	@Failure        401  {object}  payload.GlobalErrorHandlerResp
+//	@Failure        403  {object}  payload.GlobalErrorHandlerResp
+//	@Failure        404  {object}  payload.GlobalErrorHandlerResp
+//	@Failure        500  {object}  payload.GlobalErrorHandlerResp
+//	@Router         /v1/members/{id} [delete]
+func (h *memberHandler) DeleteMember(c *fiber.Ctx) error {
+	var request payload.DeleteMemberRequest
+
+	id := c.Params("id")
+	request.ID = id
+
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	err := h.memberService.DeleteMember(c.Context(), request)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrMemberNotFound) {
+			return util.ErrNotFoundResponse(c)
+		}
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, nil)
+}
@@ -0,0 +1,238 @@
+package handler
+
+import (
+	"errors"
+	"library-backend/errorcustom"
+	"library-backend/internal/payload"
+	"library-backend/internal/service"
+	"library-backend/internal/util"
+	"library-backend/internal/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type UserHandler interface {
+	CreateUser(c *fiber.Ctx) error
+	GetUsers(c *fiber.Ctx) error
+	GetUserByID(c *fiber.Ctx) error
+	UpdateUser(c *fiber.Ctx) error
+	DeleteUser(c *fiber.Ctx) error
+}
+
+type userHandler struct {
+	userService service.UserService
+}
+
+func NewUserHandler(userService service.UserService) UserHandler {
+	return &userHandler{userService: userService}
+}
+
+// CreateUser Creating User
+//
+//	@Summary        Create a new user
+//	@Description    Create an account that can sign in to the API, the role defaults to librarian
+//	@Tags           Users
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@x-permission   "users:manage"
+//	@Param          user  body      payload.CreateUserRequest  true  "User data"
+//	@Success        200   {object}  payload.Response{data=payload.CreateUserResponse}
+//	@Failure        400   {object}  payload.GlobalErrorHandlerResp
+//	@Failure        401   {object}  payload.GlobalErrorHandlerResp
+//	@Failure        403   {object}  payload.GlobalErrorHandlerResp
+//	@Failure        500   {object}  payload.GlobalErrorHandlerResp
+//	@Router         /v1/users [post]
+func (h *userHandler) CreateUser(c *fiber.Ctx) error {
+	var request payload.CreateUserRequest
+
+	if err := c.BodyParser(&request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	res, err := h.userService.CreateUser(c.Context(), request)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrUserAlreadyExists) {
+			return util.ErrBadRequestResponse(c, err.Error())
+		}
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, res)
+}
+
+// GetUsers Getting Users
+//
+//	@Summary        Get Users with pagination
+//	@Description    Get a list of users with pagination, search, role and status filter support
+//	@Tags           Users
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@x-permission   "users:manage"
+//	@Param          page     query    int     false  "Page number (default: 1)"
+//	@Param          limit    query    int     false  "Items per page (default: 10)"
+//	@Param          search   query    string  false  "Search by name or email"
+//	@Param          role     query    string  false  "Filter by role (member, librarian, admin)"
+//	@Param          status   query    string  false  "Filter by status (active, disabled)"
+//	@Success        200      {object} payload.Response{data=payload.GetUsersResponse}
+//	@Failure        400      {object} payload.GlobalErrorHandlerResp
+//	@Failure        401      {object} payload.GlobalErrorHandlerResp
+//	@Failure        403      {object} payload.GlobalErrorHandlerResp
+//	@Failure        500      {object} payload.GlobalErrorHandlerResp
+//	@Router         /v1/users [get]
+func (h *userHandler) GetUsers(c *fiber.Ctx) error {
+	var request payload.GetUsersRequest
+
+	if err := c.QueryParser(&request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	if request.Page == 0 {
+		request.Page = 1
+	}
+
+	if request.Limit == 0 {
+		request.Limit = 10 // set default limit is 10
+	}
+
+	res, err := h.userService.GetUsers(c.Context(), request)
+	if err != nil {
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, res)
+}
+
+// GetUserByID Getting User by ID
+//
+//	@Summary        Get User by ID
+//	@Description    Get a specific user by its ID
+//	@Tags           Users
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@x-permission   "users:manage"
+//	@Param          id   path     string  true  "User ID"
+//	@Success        200  {object} payload.Response{data=payload.GetUserByIDResponse}
+//	@Failure        400  {object} payload.GlobalErrorHandlerResp
+//	@Failure        401  {object} payload.GlobalErrorHandlerResp
+//	@Failure        403  {object} payload.GlobalErrorHandlerResp
+//	@Failure        404  {object} payload.GlobalErrorHandlerResp
+//	@Failure        500  {object} payload.GlobalErrorHandlerResp
+//	@Router         /v1/users/{id} [get]
+func (h *userHandler) GetUserByID(c *fiber.Ctx) error {
+	var request payload.GetUserByIDRequest
+
+	id := c.Params("id")
+	request.ID = id
+
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	res, err := h.userService.GetUserByID(c.Context(), request.ID)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrUserNotFound) {
+			return util.ErrNotFoundResponse(c)
+		}
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, res)
+}
+
+// UpdateUser Updating User
+//
+//	@Summary        Update a user
+//	@Description    Update a user by ID. Only provided fields will be updated. Changing the password or role, or disabling the user signs them out everywhere. Users cannot change their own role or disable themselves.
+//	@Tags           Users
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@x-permission   "users:manage"
+//	@Param          id    path      string                     true  "User ID"
+//	@Param          user  body      payload.UpdateUserRequest  true  "User update data"
+//	@Success        200   {object}  payload.Response{}
+//	@Failure        400   {object}  payload.GlobalErrorHandlerResp
+//	@Failure        401   {object}  payload.GlobalErrorHandlerResp
+//	@Failure        403   {object}  payload.GlobalErrorHandlerResp
+//	@Failure        404   {object}  payload.GlobalErrorHandlerResp
+//	@Failure        500   {object}  payload.GlobalErrorHandlerResp
+//	@Router         /v1/users/{id} [put]
+func (h *userHandler) UpdateUser(c *fiber.Ctx) error {
+	var request payload.UpdateUserRequest
+
+	id := c.Params("id")
+	request.ID = id
+
+	if err := c.BodyParser(&request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	err := h.userService.UpdateUser(c.Context(), request)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrUserNotFound) {
+			return util.ErrNotFoundResponse(c)
+		}
+		if errors.Is(err, errorcustom.ErrUserAlreadyExists) || errors.Is(err, errorcustom.ErrCannotChangeOwnRole) {
+			return util.ErrBadRequestResponse(c, err.Error())
+		}
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, nil)
+}
+
+// DeleteUser Deleting User
+//
+//	@Summary        Delete a user
+//	@Description    Soft delete a user by ID and revoke their refresh tokens. Users cannot delete themselves.
+//	@Tags           Users
+//	@Accept         json
+//	@Produce        json
+//	@Security       BearerAuth
+//	@x-permission   "users:manage"
+//	@Param          id   path      string  true  "User ID"
+//	@Success        200  {object}  payload.Response{}
+//	@Failure        400  {object}  payload.GlobalErrorHandlerResp
+//	@Failure        401  {object}  payload.GlobalErrorHandlerResp
+//	@Failure        403  {object}  payload.GlobalErrorHandlerResp
+//	@Failure        404  {object}  payload.GlobalErrorHandlerResp
+//	@Failure        500  {object}  payload.GlobalErrorHandlerResp
+//	@Router         /v1/users/{id} [delete]
+func (h *userHandler) DeleteUser(c *fiber.Ctx) error {
+	var request payload.DeleteUserRequest
+
+	id := c.Params("id")
+	request.ID = id
+
+	if err := validator.Validate.Struct(request); err != nil {
+		return util.ErrBindResponse(c, err)
+	}
+
+	err := h.userService.DeleteUser(c.Context(), request)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrUserNotFound) {
+			return util.ErrNotFoundResponse(c)
+		}
+		if errors.Is(err, errorcustom.ErrCannotDeleteSelf) {
+			return util.ErrBadRequestResponse(c, err.Error())
+		}
+		return util.ErrInternalResponse(c)
+	}
+
+	return util.SuccessResponse(c, nil)
+}
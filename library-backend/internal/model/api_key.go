@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	APIKeyStatusActive  = "active"
+	APIKeyStatusExpired = "expired"
+	APIKeyStatusRevoked = "revoked"
+)
+
+// APIKey is a stored API key, the key itself is only shown once when it is created. Scopes are
+// the permissions granted to the key separated by spaces, a key without an expiry never expires.
+type APIKey struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	Name       string     `json:"name" db:"name"`
+	Prefix     string     `json:"prefix" db:"prefix"`
+	KeyHash    string     `json:"-" db:"key_hash"`
+	Scopes     string     `json:"scopes" db:"scopes"`
+	CreatedBy  uuid.UUID  `json:"created_by" db:"created_by"`
+	ExpiresAt  *time.Time `json:"expires_at" db:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at" db:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at" db:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Status tells whether the key can still be used at the given time.
+func (k APIKey) Status(now time.Time) string {
+	switch {
+	case k.RevokedAt != nil:
+		return APIKeyStatusRevoked
+	case k.ExpiresAt != nil && !k.ExpiresAt.After(now):
+		return APIKeyStatusExpired
+	default:
+		return APIKeyStatusActive
+	}
+}
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// actor types, system is anything done without a caller such as a command line job.
+const (
+	AuditActorUser   = "user"
+	AuditActorAPIKey = "api_key"
+	AuditActorSystem = "system"
+)
+
+const (
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionDelete  = "delete"
+	AuditActionRestore = "restore"
+	AuditActionPurge   = "purge"
+)
+
+const (
+	AuditEntityBook = "book"
+)
+
+// AuditEvent records one mutation of an entity, Before is empty for a create and After is empty
+// for a delete.
+type AuditEvent struct {
+	ID         uuid.UUID       `json:"id" db:"id"`
+	OccurredAt time.Time       `json:"occurred_at" db:"occurred_at"`
+	ActorType  string          `json:"actor_type" db:"actor_type"`
+	ActorID    *uuid.UUID      `json:"actor_id" db:"actor_id"`
+	Action     string          `json:"action" db:"action"`
+	EntityType string          `json:"entity_type" db:"entity_type"`
+	EntityID   uuid.UUID       `json:"entity_id" db:"entity_id"`
+	Before     json.RawMessage `json:"before" db:"before"`
+	After      json.RawMessage `json:"after" db:"after"`
+	RequestID  *string         `json:"request_id" db:"request_id"`
+}
@@ -9,6 +9,7 @@ import (
 type Book struct {
 	ID                uuid.UUID `json:"id" db:"id"`
 	ISBN              string    `json:"isbn" db:"isbn"`
+	ISBN13            string    `json:"isbn13" db:"isbn13"`
 	Title             string    `json:"title" db:"title"`
 	Author            string    `json:"author" db:"author"`
 	Publisher         string    `json:"publisher" db:"publisher"`
@@ -17,5 +18,13 @@ type Book struct {
 	ImageURL          string    `json:"image_url" db:"image_url"`
 	CreatedAt         time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt         time.Time `json:"updated_at" db:"updated_at"`
-	DeletedAt         time.Time `json:"deleted_at" db:"deleted_at"`
+	TotalCopies       int       `json:"total_copies" db:"total_copies"`
+	AvailableCopies   int       `json:"available_copies" db:"available_copies"`
+
+	// only set for books in the trash
+	DeletedAt *time.Time `json:"deleted_at" db:"deleted_at"`
+
+	// only set when listing books by a full-text query
+	Rank      *float64 `json:"rank" db:"rank"`
+	Highlight *string  `json:"highlight" db:"highlight"`
 }
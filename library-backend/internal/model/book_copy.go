@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	BookCopyStatusAvailable   = "available"
+	BookCopyStatusOnLoan      = "on_loan"
+	BookCopyStatusOnHold      = "on_hold"
+	BookCopyStatusMaintenance = "maintenance"
+	BookCopyStatusLost        = "lost"
+)
+
+type BookCopy struct {
+	ID            uuid.UUID `json:"id" db:"id"`
+	BookID        uuid.UUID `json:"book_id" db:"book_id"`
+	Barcode       string    `json:"barcode" db:"barcode"`
+	ShelfLocation string    `json:"shelf_location" db:"shelf_location"`
+	Condition     string    `json:"condition" db:"condition"`
+	Status        string    `json:"status" db:"status"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+	DeletedAt     time.Time `json:"deleted_at" db:"deleted_at"`
+}
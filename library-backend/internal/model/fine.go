@@ -0,0 +1,34 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Fine entries form an append-only ledger, amounts are always positive and the entry type
+// decides whether they add to or settle the member's balance.
+const (
+	FineEntryTypeCharge  = "charge"
+	FineEntryTypeWaiver  = "waiver"
+	FineEntryTypePayment = "payment"
+)
+
+type FineEntry struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	MemberID  uuid.UUID  `json:"member_id" db:"member_id"`
+	LoanID    *uuid.UUID `json:"loan_id" db:"loan_id"`
+	EntryType string     `json:"entry_type" db:"entry_type"`
+	Amount    int64      `json:"amount" db:"amount"`
+	Note      string     `json:"note" db:"note"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+type FineBalance struct {
+	MemberID   uuid.UUID `db:"member_id"`
+	MemberName string    `db:"member_name"`
+	Charged    int64     `db:"charged"`
+	Waived     int64     `db:"waived"`
+	Paid       int64     `db:"paid"`
+	Balance    int64     `db:"balance"`
+}
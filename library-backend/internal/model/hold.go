@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// A hold waits in the queue of its book until a returned copy is set aside for it, the member
+// then has until expires_at to pick the copy up.
+const (
+	HoldStatusWaiting   = "waiting"
+	HoldStatusReady     = "ready"
+	HoldStatusFulfilled = "fulfilled"
+	HoldStatusCancelled = "cancelled"
+	HoldStatusExpired   = "expired"
+)
+
+type Hold struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	BookID     uuid.UUID  `json:"book_id" db:"book_id"`
+	MemberID   uuid.UUID  `json:"member_id" db:"member_id"`
+	CopyID     *uuid.UUID `json:"copy_id" db:"copy_id"`
+	Barcode    *string    `json:"barcode" db:"barcode"`
+	Status     string     `json:"status" db:"status"`
+	Position   int        `json:"position" db:"position"`
+	PlacedAt   time.Time  `json:"placed_at" db:"placed_at"`
+	ReadyAt    *time.Time `json:"ready_at" db:"ready_at"`
+	ExpiresAt  *time.Time `json:"expires_at" db:"expires_at"`
+	ClosedAt   *time.Time `json:"closed_at" db:"closed_at"`
+	BookTitle  string     `json:"book_title" db:"book_title"`
+	MemberName string     `json:"member_name" db:"member_name"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Loan statuses are derived from the returned_at and due_at columns rather than stored.
+const (
+	LoanStatusActive   = "active"
+	LoanStatusOverdue  = "overdue"
+	LoanStatusReturned = "returned"
+)
+
+type Loan struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	BookID     uuid.UUID  `json:"book_id" db:"book_id"`
+	CopyID     uuid.UUID  `json:"copy_id" db:"copy_id"`
+	Barcode    string     `json:"barcode" db:"barcode"`
+	MemberID   uuid.UUID  `json:"member_id" db:"member_id"`
+	BorrowedAt time.Time  `json:"borrowed_at" db:"borrowed_at"`
+	DueAt      time.Time  `json:"due_at" db:"due_at"`
+	ReturnedAt *time.Time `json:"returned_at" db:"returned_at"`
+	Status     string     `json:"status" db:"status"`
+	BookTitle  string     `json:"book_title" db:"book_title"`
+	MemberName string     `json:"member_name" db:"member_name"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+type LoanSummary struct {
+	TotalLoans    int `db:"total_loans"`
+	ActiveLoans   int `db:"active_loans"`
+	OverdueLoans  int `db:"overdue_loans"`
+	ReturnedLoans int `db:"returned_loans"`
+}
+
+type BookLoanCount struct {
+	BookID    uuid.UUID `db:"book_id"`
+	Title     string    `db:"title"`
+	Author    string    `db:"author"`
+	LoanCount int       `db:"loan_count"`
+}
+
+type MonthlyLoanCount struct {
+	Month    string `db:"month"`
+	Borrowed int    `db:"borrowed"`
+	Returned int    `db:"returned"`
+}
@@ -0,0 +1,55 @@
+package payload
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" validate:"required,min=3,max=100"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1,dive,required"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// CreateAPIKeyResponse carries the key itself, it is only ever returned here.
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+type GetAPIKeysRequest struct {
+	PaginationRequest
+	Offset int
+	Status string `query:"status" validate:"omitempty,oneof=active expired revoked"`
+}
+
+type GetAPIKeysResponse struct {
+	APIKeys    []APIKeyResponse `json:"api_keys"`
+	Pagination Pagination       `json:"pagination"`
+}
+
+type GetAPIKeyByIDRequest struct {
+	ID string `params:"id" validate:"required,uuid"`
+}
+
+type GetAPIKeyByIDResponse struct {
+	APIKeyResponse
+}
+
+type RevokeAPIKeyRequest struct {
+	ID string `params:"id" validate:"required,uuid"`
+}
+
+type APIKeyResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	Status     string     `json:"status"`
+	CreatedBy  uuid.UUID  `json:"created_by"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
@@ -0,0 +1,40 @@
+package payload
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type GetAuditEventsRequest struct {
+	PaginationRequest
+	Offset     int
+	ActorType  string `query:"actor_type" validate:"omitempty,oneof=user api_key system"`
+	ActorID    string `query:"actor_id" validate:"omitempty,uuid"`
+	Action     string `query:"action" validate:"omitempty,oneof=create update delete restore purge"`
+	EntityType string `query:"entity_type" validate:"omitempty,max=50"`
+	EntityID   string `query:"entity_id" validate:"omitempty,uuid"`
+	RequestID  string `query:"request_id" validate:"omitempty,max=100"`
+	// date range is whole days (YYYY-MM-DD), both ends inclusive
+	From string `query:"from" validate:"omitempty,datetime=2006-01-02"`
+	To   string `query:"to" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type GetAuditEventsResponse struct {
+	Events     []AuditEventResponse `json:"events"`
+	Pagination Pagination           `json:"pagination"`
+}
+
+type AuditEventResponse struct {
+	ID         uuid.UUID       `json:"id"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	ActorType  string          `json:"actor_type"`
+	ActorID    *uuid.UUID      `json:"actor_id"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   uuid.UUID       `json:"entity_id"`
+	Before     json.RawMessage `json:"before" swaggertype:"object"`
+	After      json.RawMessage `json:"after" swaggertype:"object"`
+	RequestID  *string         `json:"request_id"`
+}
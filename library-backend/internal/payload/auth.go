@@ -0,0 +1,32 @@
+package payload
+
+import "time"
+
+type LoginRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,max=72"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required,max=100"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required,max=100"`
+}
+
+// TokenResponse is a new access token together with the refresh token to get the next one,
+// a refresh token can only be used once.
+type TokenResponse struct {
+	AccessToken      string    `json:"access_token"`
+	TokenType        string    `json:"token_type"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// MeResponse is the signed in user together with the permissions granted by their role.
+type MeResponse struct {
+	UserResponse
+	Permissions []string `json:"permissions"`
+}
@@ -1,7 +1,14 @@
 package payload
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"library-backend/errorcustom"
 	"library-backend/internal/model"
+	"library-backend/pkg/filter"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,12 +22,15 @@ type CreateBookRequest struct {
 	YearOfPublication int    `json:"year_of_publication" validate:"required,min=1800,max=2050"`
 	Category          string `json:"category" validate:"required,oneof=programming novel fantasy romance mystery horror science-fiction other"`
 	ImageURL          string `json:"image_url,omitempty" validate:"omitempty,url"`
+	// Restore brings back a deleted book with the same ISBN, updated with this request, instead
+	// of refusing to create a second record for it
+	Restore bool `json:"restore,omitempty"`
 }
 
 func (r *CreateBookRequest) ToModel() model.Book {
 	return model.Book{
 		ID:                uuid.New(),
-		ISBN:              r.ISBN,
+		ISBN:              strings.TrimSpace(r.ISBN),
 		Title:             r.Title,
 		Author:            r.Author,
 		Publisher:         r.Publisher,
@@ -32,23 +42,260 @@ func (r *CreateBookRequest) ToModel() model.Book {
 
 type CreateBookResponse struct {
 	ID uuid.UUID `json:"id"`
+	// Restored is set when a deleted book was restored rather than a new one created
+	Restored bool `json:"restored,omitempty"`
+}
+
+// BookCategories are the categories a book can be in, in the order they are listed to browse by.
+var BookCategories = []string{
+	"programming",
+	"novel",
+	"fantasy",
+	"romance",
+	"mystery",
+	"horror",
+	"science-fiction",
+	"other",
+}
+
+// BookSortFields are the fields books can be sorted by through the sort parameter.
+var BookSortFields = map[string]bool{
+	"title":               true,
+	"author":              true,
+	"publisher":           true,
+	"year_of_publication": true,
+	"category":            true,
+	"created_at":          true,
+	"updated_at":          true,
+}
+
+// BookFilterFields are the fields books can be filtered by through the filter parameter.
+var BookFilterFields = filter.Schema{
+	"title":               {Type: filter.String},
+	"author":              {Type: filter.String},
+	"publisher":           {Type: filter.String},
+	"year_of_publication": {Type: filter.Number},
+	"category":            {Type: filter.Enum, Values: BookCategories},
+	"created_at":          {Type: filter.Date},
+	"updated_at":          {Type: filter.Date},
+}
+
+const (
+	BookFacetCategory  = "category"
+	BookFacetDecade    = "decade"
+	BookFacetPublisher = "publisher"
+)
+
+// BookFacetFields are the facets the book list can be counted by through the facets parameter.
+var BookFacetFields = map[string]bool{
+	BookFacetCategory:  true,
+	BookFacetDecade:    true,
+	BookFacetPublisher: true,
 }
 
 type GetBooksRequest struct {
 	PaginationRequest
 	Offset int
 	Title  string `query:"title" validate:"omitempty"`
+	// Q is a full-text query over title, author, publisher and ISBN in web search syntax
+	// (quoted phrases, OR, -excluded)
+	Q         string   `query:"q" validate:"omitempty,max=200"`
+	Category  []string `query:"category" validate:"omitempty,dive,oneof=programming novel fantasy romance mystery horror science-fiction other"`
+	Author    string   `query:"author" validate:"omitempty,max=255"`
+	Publisher string   `query:"publisher" validate:"omitempty,max=255"`
+	YearFrom  int      `query:"year_from" validate:"omitempty,min=1800,max=2050"`
+	YearTo    int      `query:"year_to" validate:"omitempty,min=1800,max=2050,gtefield=YearFrom"`
+	// date ranges are whole days (YYYY-MM-DD), both ends inclusive
+	CreatedFrom string `query:"created_from" validate:"omitempty,datetime=2006-01-02"`
+	CreatedTo   string `query:"created_to" validate:"omitempty,datetime=2006-01-02"`
+	UpdatedFrom string `query:"updated_from" validate:"omitempty,datetime=2006-01-02"`
+	UpdatedTo   string `query:"updated_to" validate:"omitempty,datetime=2006-01-02"`
+	// Sort is a comma separated list of BookSortFields, a leading "-" sorts that field descending
+	Sort       string `query:"sort" validate:"omitempty,max=200"`
+	SortFields []SortField
+	// Filter is an expression over BookFilterFields, such as
+	// "category in ('fantasy','novel') and year_of_publication >= 2000 or author ~ 'tolkien'",
+	// applied on top of the other filters
+	Filter     string      `query:"filter" validate:"omitempty,max=1000"`
+	FilterExpr filter.Expr `query:"-"`
+	// Facets is a comma separated list of BookFacetFields to count the matching books by
+	Facets      string   `query:"facets" validate:"omitempty,max=100"`
+	FacetFields []string `query:"-"`
+	// Cursor is the next_cursor of the previous page. CursorMode is set whenever the cursor
+	// parameter is present, an empty cursor asks for the first page.
+	Cursor     string      `query:"cursor" validate:"omitempty,max=200"`
+	CursorMode bool        `query:"-"`
+	After      *BookCursor `query:"-"`
+}
+
+// BookCursor is the position of a book in the updated_at DESC, id DESC ordering that cursor
+// pagination walks through.
+type BookCursor struct {
+	UpdatedAt time.Time `json:"u"`
+	ID        uuid.UUID `json:"i"`
+}
+
+// Encode returns the cursor as an opaque URL safe string.
+func (c BookCursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeBookCursor parses a cursor produced by BookCursor.Encode.
+func DecodeBookCursor(cursor string) (*BookCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errorcustom.ErrInvalidCursor
+	}
+
+	var c BookCursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID == uuid.Nil || c.UpdatedAt.IsZero() {
+		return nil, errorcustom.ErrInvalidCursor
+	}
+
+	return &c, nil
+}
+
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// ParseSort turns a sort parameter such as "title,-year_of_publication" into sort fields,
+// rejecting any field that is not allowed.
+func ParseSort(sort string, allowed map[string]bool) ([]SortField, error) {
+	var fields []SortField
+
+	for _, part := range strings.Split(sort, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		field := SortField{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		if !allowed[field.Field] {
+			return nil, fmt.Errorf("%w: %s", errorcustom.ErrInvalidSortField, field.Field)
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+// ParseFilter parses a filter expression and checks it against the fields that can be filtered
+// by. An empty expression filters nothing and parses to nil.
+func ParseFilter(expression string, fields filter.Schema) (filter.Expr, error) {
+	if strings.TrimSpace(expression) == "" {
+		return nil, nil
+	}
+
+	expr, err := filter.Parse(expression)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errorcustom.ErrInvalidFilter, err)
+	}
+
+	if err := fields.Validate(expr); err != nil {
+		return nil, fmt.Errorf("%w: %s", errorcustom.ErrInvalidFilter, err)
+	}
+
+	return expr, nil
+}
+
+// ParseFacets turns a facets parameter such as "category,decade" into facet names, rejecting any
+// facet that is not allowed. A facet asked for twice is counted once.
+func ParseFacets(facets string, allowed map[string]bool) ([]string, error) {
+	var names []string
+
+	for _, part := range strings.Split(facets, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" || slices.Contains(names, part) {
+			continue
+		}
+
+		if !allowed[part] {
+			return nil, fmt.Errorf("%w: %s", errorcustom.ErrInvalidFacet, part)
+		}
+
+		names = append(names, part)
+	}
+
+	return names, nil
+}
+
+const (
+	BookExportFormatCSV    = "csv"
+	BookExportFormatNDJSON = "ndjson"
+	BookExportFormatJSON   = "json"
+	// MARC 21 records, in ISO 2709 or as a MARCXML collection
+	BookExportFormatMARC    = "marc"
+	BookExportFormatMARCXML = "marcxml"
+)
+
+// ExportBooksRequest takes the filters and sort of the book list, its page, limit and cursor are
+// ignored since the export has every matching book.
+type ExportBooksRequest struct {
+	GetBooksRequest
+	Format string `query:"format" validate:"omitempty,oneof=csv ndjson json marc marcxml"`
 }
 
 type GetBooksResponse struct {
 	Books      []BookResponse `json:"books"`
 	Pagination Pagination     `json:"pagination"`
+	// NextCursor is only set in cursor mode, when there are more books after this page
+	NextCursor string `json:"next_cursor,omitempty"`
+	// Facets are only set when the request asks for them, by facet name
+	Facets map[string][]BookFacetBucket `json:"facets,omitempty"`
+}
+
+// BookFacetBucket is a value of a facet with the number of matching books that have it. Decades
+// are named by their first year.
+type BookFacetBucket struct {
+	Value string `json:"value" db:"value"`
+	Count int    `json:"count" db:"count"`
 }
 
 type GetBookByIDRequest struct {
 	ID string `params:"id" validate:"required,uuid"`
 }
 
+type GetBookByISBNRequest struct {
+	// ISBN is an ISBN-10 or ISBN-13 in any form, or a scanned EAN-13 book barcode
+	ISBN string `params:"isbn" validate:"required,max=50"`
+}
+
+type GetBookByBarcodeRequest struct {
+	Barcode string `params:"barcode" validate:"required,max=50"`
+}
+
+type GetBookMARCRequest struct {
+	ID     string `params:"id" validate:"required,uuid"`
+	Format string `query:"format" validate:"omitempty,oneof=marc marcxml"`
+}
+
+const (
+	BookCitationFormatBibTeX  = "bibtex"
+	BookCitationFormatRIS     = "ris"
+	BookCitationFormatCSLJSON = "csl-json"
+)
+
+type GetBookCitationRequest struct {
+	ID     string `params:"id" validate:"required,uuid"`
+	Format string `query:"format" validate:"omitempty,oneof=bibtex ris csl-json"`
+}
+
+// GetBooksCitationRequest cites several books at once, in the order of their IDs.
+type GetBooksCitationRequest struct {
+	IDs    []string `query:"ids" validate:"required,min=1,max=100,dive,uuid"`
+	Format string   `query:"format" validate:"omitempty,oneof=bibtex ris csl-json"`
+}
+
+// BookIndexTerm is a value of a searchable book field with the number of books that have it.
+type BookIndexTerm struct {
+	Value string `db:"value"`
+	Count int    `db:"count"`
+}
+
 type GetBookByIDResponse struct {
 	BookResponse
 }
@@ -67,6 +314,7 @@ type UpdateBookRequest struct {
 type BookResponse struct {
 	ID                uuid.UUID `json:"id"`
 	ISBN              string    `json:"isbn"`
+	ISBN13            string    `json:"isbn13"`
 	Title             string    `json:"title"`
 	Author            string    `json:"author"`
 	Publisher         string    `json:"publisher"`
@@ -75,8 +323,33 @@ type BookResponse struct {
 	ImageURL          string    `json:"image_url"`
 	CreatedAt         time.Time `json:"created_at"`
 	UpdatedAt         time.Time `json:"updated_at"`
+	TotalCopies       int       `json:"total_copies"`
+	AvailableCopies   int       `json:"available_copies"`
+	Rank              *float64  `json:"rank,omitempty"`
+	Highlight         string    `json:"highlight,omitempty"`
+	// only set for books in the trash
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
 type DeleteBookRequest struct {
 	ID string `params:"id" validate:"required,uuid"`
 }
+
+type GetDeletedBooksRequest struct {
+	PaginationRequest
+	Offset int
+	Title  string `query:"title" validate:"omitempty"`
+}
+
+type GetDeletedBooksResponse struct {
+	Books      []BookResponse `json:"books"`
+	Pagination Pagination     `json:"pagination"`
+}
+
+type RestoreBookRequest struct {
+	ID string `params:"id" validate:"required,uuid"`
+}
+
+type PurgeBookRequest struct {
+	ID string `params:"id" validate:"required,uuid"`
+}
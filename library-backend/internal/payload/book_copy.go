@@ -0,0 +1,74 @@
+package payload
+
+import (
+	"library-backend/internal/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateBookCopyRequest struct {
+	BookID        string `params:"id" validate:"required,uuid"`
+	Barcode       string `json:"barcode" validate:"required,max=50"`
+	ShelfLocation string `json:"shelf_location,omitempty" validate:"omitempty,max=100"`
+	Condition     string `json:"condition,omitempty" validate:"omitempty,oneof=new good fair poor damaged"`
+	Status        string `json:"status,omitempty" validate:"omitempty,oneof=available maintenance lost"`
+}
+
+func (r *CreateBookCopyRequest) ToModel() model.BookCopy {
+	return model.BookCopy{
+		ID:            uuid.New(),
+		BookID:        uuid.MustParse(r.BookID),
+		Barcode:       r.Barcode,
+		ShelfLocation: r.ShelfLocation,
+		Condition:     r.Condition,
+		Status:        r.Status,
+	}
+}
+
+type CreateBookCopyResponse struct {
+	ID uuid.UUID `json:"id"`
+}
+
+type GetBookCopiesRequest struct {
+	BookID string `params:"id" validate:"required,uuid"`
+	Status string `query:"status" validate:"omitempty,oneof=available on_loan on_hold maintenance lost"`
+}
+
+type GetBookCopiesResponse struct {
+	Copies []BookCopyResponse `json:"copies"`
+}
+
+type GetBookCopyByIDRequest struct {
+	BookID string `params:"id" validate:"required,uuid"`
+	CopyID string `params:"copyId" validate:"required,uuid"`
+}
+
+type GetBookCopyByIDResponse struct {
+	BookCopyResponse
+}
+
+type UpdateBookCopyRequest struct {
+	BookID        string  `params:"id" validate:"required,uuid"`
+	CopyID        string  `params:"copyId" validate:"required,uuid"`
+	Barcode       *string `json:"barcode,omitempty" validate:"omitempty,max=50"`
+	ShelfLocation *string `json:"shelf_location,omitempty" validate:"omitempty,max=100"`
+	Condition     *string `json:"condition,omitempty" validate:"omitempty,oneof=new good fair poor damaged"`
+	Status        *string `json:"status,omitempty" validate:"omitempty,oneof=available maintenance lost"`
+}
+
+type DeleteBookCopyRequest struct {
+	BookID string `params:"id" validate:"required,uuid"`
+	CopyID string `params:"copyId" validate:"required,uuid"`
+}
+
+type BookCopyResponse struct {
+	ID            uuid.UUID `json:"id"`
+	BookID        uuid.UUID `json:"book_id"`
+	Barcode       string    `json:"barcode"`
+	ShelfLocation string    `json:"shelf_location"`
+	Condition     string    `json:"condition"`
+	Status        string    `json:"status"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
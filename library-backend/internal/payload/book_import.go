@@ -0,0 +1,55 @@
+package payload
+
+import "github.com/google/uuid"
+
+const (
+	BookImportFormatCSV    = "csv"
+	BookImportFormatNDJSON = "ndjson"
+	// MARC 21 records, in ISO 2709 or MARCXML
+	BookImportFormatMARC    = "marc"
+	BookImportFormatMARCXML = "marcxml"
+)
+
+// the outcome of a single import row
+const (
+	ImportRowStatusCreated = "created"
+	ImportRowStatusValid   = "valid"
+	ImportRowStatusInvalid = "invalid"
+	ImportRowStatusFailed  = "failed"
+	ImportRowStatusSkipped = "skipped"
+)
+
+type ImportBooksRequest struct {
+	// Format is csv, ndjson, marc or marcxml, taken from the content type when not given
+	Format string `query:"format" validate:"omitempty,oneof=csv ndjson marc marcxml"`
+	// DryRun checks every row, including ISBN conflicts, without writing anything
+	DryRun bool `query:"dry_run"`
+	// Atomic writes either every row or, when any row is invalid or fails, none of them
+	Atomic bool `query:"atomic"`
+}
+
+type ImportBooksResponse struct {
+	DryRun  bool                  `json:"dry_run"`
+	Atomic  bool                  `json:"atomic"`
+	Total   int                   `json:"total"`
+	Created int                   `json:"created"`
+	Valid   int                   `json:"valid"`
+	Invalid int                   `json:"invalid"`
+	Failed  int                   `json:"failed"`
+	Skipped int                   `json:"skipped"`
+	Rows    []ImportBookRowResult `json:"rows"`
+}
+
+type ImportBookRowResult struct {
+	// Line is the line of the file the row starts on, for MARC the number of the record
+	Line   int    `json:"line"`
+	ISBN   string `json:"isbn,omitempty"`
+	Status string `json:"status"`
+	// ID is the created or restored book, in a dry run the book that would be restored
+	ID       *uuid.UUID        `json:"id,omitempty"`
+	Restored bool              `json:"restored,omitempty"`
+	Error    string            `json:"error,omitempty"`
+	Errors   []ErrorValidation `json:"errors,omitempty"`
+	// Unmapped lists the fields of a MARC record that were not imported, as a tag or tag$code
+	Unmapped []string `json:"unmapped,omitempty"`
+}
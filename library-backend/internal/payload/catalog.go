@@ -0,0 +1,48 @@
+package payload
+
+const (
+	SRUOperationExplain        = "explain"
+	SRUOperationSearchRetrieve = "searchRetrieve"
+	SRUOperationScan           = "scan"
+)
+
+// SRURequest holds the parameters of the SRU operations. SRU reports a bad parameter as a
+// diagnostic in the response, so they are read as given and checked by the service rather than
+// validated.
+type SRURequest struct {
+	// Operation is only needed by SRU 1.x clients, SRU 2.0 tells the operations apart by their
+	// parameters
+	Operation string `query:"operation"`
+	Version   string `query:"version"`
+
+	// searchRetrieve
+	Query             string `query:"query"`
+	StartRecord       string `query:"startRecord"`
+	MaximumRecords    string `query:"maximumRecords"`
+	RecordSchema      string `query:"recordSchema"`
+	RecordXMLEscaping string `query:"recordXMLEscaping"`
+
+	// scan
+	ScanClause       string `query:"scanClause"`
+	ResponsePosition string `query:"responsePosition"`
+	MaximumTerms     string `query:"maximumTerms"`
+
+	// Host and Port are where the request was sent to, explain tells clients about them
+	Host string `query:"-"`
+	Port int    `query:"-"`
+}
+
+// ResolvedOperation is the operation the request asks for: the operation parameter, or
+// searchRetrieve for a query, scan for a scan clause and explain for neither.
+func (r SRURequest) ResolvedOperation() string {
+	switch {
+	case r.Operation != "":
+		return r.Operation
+	case r.Query != "":
+		return SRUOperationSearchRetrieve
+	case r.ScanClause != "":
+		return SRUOperationScan
+	default:
+		return SRUOperationExplain
+	}
+}
@@ -0,0 +1,66 @@
+package payload
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type GetMemberFinesRequest struct {
+	PaginationRequest
+	Offset   int
+	MemberID string `params:"id" validate:"required,uuid"`
+}
+
+type GetMemberFinesResponse struct {
+	Balance    FineBalanceResponse `json:"balance"`
+	Entries    []FineEntryResponse `json:"entries"`
+	Pagination Pagination          `json:"pagination"`
+}
+
+type CreateFinePaymentRequest struct {
+	MemberID string `params:"id" validate:"required,uuid"`
+	Amount   int64  `json:"amount" validate:"required,gt=0"`
+	Note     string `json:"note" validate:"max=255"`
+}
+
+type CreateFineWaiverRequest struct {
+	MemberID string `params:"id" validate:"required,uuid"`
+	LoanID   string `json:"loan_id,omitempty" validate:"omitempty,uuid"`
+	Amount   int64  `json:"amount" validate:"required,gt=0"`
+	Note     string `json:"note" validate:"max=255"`
+}
+
+type CreateFineEntryResponse struct {
+	ID      uuid.UUID `json:"id"`
+	Balance int64     `json:"balance"`
+}
+
+type GetFineBalancesRequest struct {
+	PaginationRequest
+	Offset int
+}
+
+type GetFineBalancesResponse struct {
+	Balances   []FineBalanceResponse `json:"balances"`
+	Pagination Pagination            `json:"pagination"`
+}
+
+type FineEntryResponse struct {
+	ID        uuid.UUID  `json:"id"`
+	MemberID  uuid.UUID  `json:"member_id"`
+	LoanID    *uuid.UUID `json:"loan_id"`
+	EntryType string     `json:"entry_type"`
+	Amount    int64      `json:"amount"`
+	Note      string     `json:"note"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type FineBalanceResponse struct {
+	MemberID   uuid.UUID `json:"member_id"`
+	MemberName string    `json:"member_name,omitempty"`
+	Charged    int64     `json:"charged"`
+	Waived     int64     `json:"waived"`
+	Paid       int64     `json:"paid"`
+	Balance    int64     `json:"balance"`
+}
@@ -0,0 +1,58 @@
+package payload
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateHoldRequest struct {
+	BookID   string `json:"book_id" validate:"required,uuid"`
+	MemberID string `json:"member_id" validate:"required,uuid"`
+}
+
+type CreateHoldResponse struct {
+	ID       uuid.UUID `json:"id"`
+	Position int       `json:"position"`
+}
+
+type GetHoldsRequest struct {
+	PaginationRequest
+	Offset   int
+	Status   string `query:"status" validate:"omitempty,oneof=waiting ready fulfilled cancelled expired"`
+	MemberID string `query:"member_id" validate:"omitempty,uuid"`
+	BookID   string `query:"book_id" validate:"omitempty,uuid"`
+}
+
+type GetHoldsResponse struct {
+	Holds      []HoldResponse `json:"holds"`
+	Pagination Pagination     `json:"pagination"`
+}
+
+type GetHoldByIDRequest struct {
+	ID string `params:"id" validate:"required,uuid"`
+}
+
+type GetHoldByIDResponse struct {
+	HoldResponse
+}
+
+type CancelHoldRequest struct {
+	ID string `params:"id" validate:"required,uuid"`
+}
+
+type HoldResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	BookID     uuid.UUID  `json:"book_id"`
+	BookTitle  string     `json:"book_title"`
+	MemberID   uuid.UUID  `json:"member_id"`
+	MemberName string     `json:"member_name"`
+	CopyID     *uuid.UUID `json:"copy_id"`
+	Barcode    *string    `json:"barcode"`
+	Status     string     `json:"status"`
+	Position   int        `json:"position,omitempty"`
+	PlacedAt   time.Time  `json:"placed_at"`
+	ReadyAt    *time.Time `json:"ready_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	ClosedAt   *time.Time `json:"closed_at"`
+}
@@ -0,0 +1,82 @@
+package payload
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateLoanRequest struct {
+	BookID   string     `json:"book_id" validate:"required,uuid"`
+	CopyID   string     `json:"copy_id,omitempty" validate:"omitempty,uuid"`
+	MemberID string     `json:"member_id" validate:"required,uuid"`
+	DueAt    *time.Time `json:"due_at,omitempty"`
+}
+
+type CreateLoanResponse struct {
+	ID      uuid.UUID `json:"id"`
+	CopyID  uuid.UUID `json:"copy_id"`
+	Barcode string    `json:"barcode"`
+	DueAt   time.Time `json:"due_at"`
+}
+
+type GetLoansRequest struct {
+	PaginationRequest
+	Offset   int
+	Status   string `query:"status" validate:"omitempty,oneof=active overdue returned"`
+	MemberID string `query:"member_id" validate:"omitempty,uuid"`
+	BookID   string `query:"book_id" validate:"omitempty,uuid"`
+}
+
+type GetLoansResponse struct {
+	Loans      []LoanResponse `json:"loans"`
+	Pagination Pagination     `json:"pagination"`
+}
+
+type GetLoanByIDRequest struct {
+	ID string `params:"id" validate:"required,uuid"`
+}
+
+type GetLoanByIDResponse struct {
+	LoanResponse
+}
+
+type ReturnLoanRequest struct {
+	ID string `params:"id" validate:"required,uuid"`
+}
+
+type LoanResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	BookID     uuid.UUID  `json:"book_id"`
+	BookTitle  string     `json:"book_title"`
+	CopyID     uuid.UUID  `json:"copy_id"`
+	Barcode    string     `json:"barcode"`
+	MemberID   uuid.UUID  `json:"member_id"`
+	MemberName string     `json:"member_name"`
+	BorrowedAt time.Time  `json:"borrowed_at"`
+	DueAt      time.Time  `json:"due_at"`
+	ReturnedAt *time.Time `json:"returned_at"`
+	Status     string     `json:"status"`
+}
+
+type BorrowingReportResponse struct {
+	TotalLoans    int               `json:"total_loans"`
+	ActiveLoans   int               `json:"active_loans"`
+	OverdueLoans  int               `json:"overdue_loans"`
+	ReturnedLoans int               `json:"returned_loans"`
+	MostBorrowed  []BookLoanStat    `json:"most_borrowed"`
+	Monthly       []MonthlyLoanStat `json:"monthly"`
+}
+
+type BookLoanStat struct {
+	BookID    uuid.UUID `json:"book_id"`
+	Title     string    `json:"title"`
+	Author    string    `json:"author"`
+	LoanCount int       `json:"loan_count"`
+}
+
+type MonthlyLoanStat struct {
+	Month    string `json:"month"`
+	Borrowed int    `json:"borrowed"`
+	Returned int    `json:"returned"`
+}
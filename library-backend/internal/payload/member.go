@@ -0,0 +1,75 @@
+package payload
+
+import (
+	"library-backend/internal/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateMemberRequest struct {
+	Name    string `json:"name" validate:"required,min=3,max=150"`
+	Email   string `json:"email" validate:"required,email"`
+	Phone   string `json:"phone,omitempty" validate:"omitempty,max=30"`
+	Address string `json:"address,omitempty"`
+	Status  string `json:"status,omitempty" validate:"omitempty,oneof=active inactive suspended"`
+}
+
+func (r *CreateMemberRequest) ToModel() model.Member {
+	return model.Member{
+		ID:      uuid.New(),
+		Name:    r.Name,
+		Email:   r.Email,
+		Phone:   r.Phone,
+		Address: r.Address,
+		Status:  r.Status,
+	}
+}
+
+type CreateMemberResponse struct {
+	ID uuid.UUID `json:"id"`
+}
+
+type GetMembersRequest struct {
+	PaginationRequest
+	Offset int
+	Search string `query:"search" validate:"omitempty"`
+	Status string `query:"status" validate:"omitempty,oneof=active inactive suspended"`
+}
+
+type GetMembersResponse struct {
+	Members    []MemberResponse `json:"members"`
+	Pagination Pagination       `json:"pagination"`
+}
+
+type GetMemberByIDRequest struct {
+	ID string `params:"id" validate:"required,uuid"`
+}
+
+type GetMemberByIDResponse struct {
+	MemberResponse
+}
+
+type UpdateMemberRequest struct {
+	ID      string  `params:"id" validate:"required,uuid"`
+	Name    *string `json:"name,omitempty" validate:"omitempty,min=3,max=150"`
+	Email   *string `json:"email,omitempty" validate:"omitempty,email"`
+	Phone   *string `json:"phone,omitempty" validate:"omitempty,max=30"`
+	Address *string `json:"address,omitempty"`
+	Status  *string `json:"status,omitempty" validate:"omitempty,oneof=active inactive suspended"`
+}
+
+type MemberResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Phone     string    `json:"phone"`
+	Address   string    `json:"address"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type DeleteMemberRequest struct {
+	ID string `params:"id" validate:"required,uuid"`
+}
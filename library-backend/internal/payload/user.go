@@ -0,0 +1,75 @@
+package payload
+
+import (
+	"library-backend/internal/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateUserRequest struct {
+	Name     string `json:"name" validate:"required,min=3,max=150"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+	Role     string `json:"role,omitempty" validate:"omitempty,oneof=member librarian admin"`
+	Status   string `json:"status,omitempty" validate:"omitempty,oneof=active disabled"`
+}
+
+func (r *CreateUserRequest) ToModel() model.User {
+	return model.User{
+		ID:     uuid.New(),
+		Name:   r.Name,
+		Email:  r.Email,
+		Role:   r.Role,
+		Status: r.Status,
+	}
+}
+
+type CreateUserResponse struct {
+	ID uuid.UUID `json:"id"`
+}
+
+type GetUsersRequest struct {
+	PaginationRequest
+	Offset int
+	Search string `query:"search" validate:"omitempty"`
+	Role   string `query:"role" validate:"omitempty,oneof=member librarian admin"`
+	Status string `query:"status" validate:"omitempty,oneof=active disabled"`
+}
+
+type GetUsersResponse struct {
+	Users      []UserResponse `json:"users"`
+	Pagination Pagination     `json:"pagination"`
+}
+
+type GetUserByIDRequest struct {
+	ID string `params:"id" validate:"required,uuid"`
+}
+
+type GetUserByIDResponse struct {
+	UserResponse
+}
+
+type UpdateUserRequest struct {
+	ID       string  `params:"id" validate:"required,uuid"`
+	Name     *string `json:"name,omitempty" validate:"omitempty,min=3,max=150"`
+	Email    *string `json:"email,omitempty" validate:"omitempty,email"`
+	Password *string `json:"password,omitempty" validate:"omitempty,min=8,max=72"`
+	Role     *string `json:"role,omitempty" validate:"omitempty,oneof=member librarian admin"`
+	Status   *string `json:"status,omitempty" validate:"omitempty,oneof=active disabled"`
+}
+
+type DeleteUserRequest struct {
+	ID string `params:"id" validate:"required,uuid"`
+}
+
+type UserResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	Email       string     `json:"email"`
+	Role        string     `json:"role"`
+	Status      string     `json:"status"`
+	LastLoginAt *time.Time `json:"last_login_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
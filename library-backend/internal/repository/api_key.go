@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"library-backend/internal/model"
+	"library-backend/internal/payload"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+var apiKeyColumns = []string{
+	"id",
+	"name",
+	"prefix",
+	"key_hash",
+	"scopes",
+	"created_by",
+	"expires_at",
+	"last_used_at",
+	"revoked_at",
+	"created_at",
+	"updated_at",
+}
+
+// apiKeyTouchInterval is how stale last_used_at may get, it saves a write on every request of a
+// busy client.
+const apiKeyTouchInterval = time.Minute
+
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key model.APIKey) error
+	GetAPIKeys(ctx context.Context, req payload.GetAPIKeysRequest) ([]model.APIKey, error)
+	GetAPIKeysCount(ctx context.Context, req payload.GetAPIKeysRequest) (int, error)
+	GetAPIKeyByID(ctx context.Context, id string) (*model.APIKey, error)
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (*model.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id string, revokedAt time.Time) error
+	TouchAPIKey(ctx context.Context, id string, usedAt time.Time) error
+}
+
+type apiKeyRepository struct {
+	db *sqlx.DB
+}
+
+func NewAPIKeyRepository(db *sqlx.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+func (r *apiKeyRepository) CreateAPIKey(ctx context.Context, key model.APIKey) error {
+	q := sq.Insert("api_keys").
+		Columns("id",
+			"name",
+			"prefix",
+			"key_hash",
+			"scopes",
+			"created_by",
+			"expires_at",
+			"updated_at",
+		).
+		Values(key.ID, key.Name, key.Prefix, key.KeyHash, key.Scopes, key.CreatedBy, key.ExpiresAt, "NOW()").
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+
+	return err
+}
+
+// applyAPIKeyFilters narrows an api keys query down to the rows matching the request filters,
+// it is shared by GetAPIKeys and GetAPIKeysCount so both always describe the same result set.
+func applyAPIKeyFilters(q sq.SelectBuilder, req payload.GetAPIKeysRequest) sq.SelectBuilder {
+	switch req.Status {
+	case model.APIKeyStatusActive:
+		q = q.Where(sq.Eq{"revoked_at": nil}).
+			Where(sq.Or{sq.Eq{"expires_at": nil}, sq.Expr("expires_at > NOW()")})
+	case model.APIKeyStatusExpired:
+		q = q.Where(sq.Eq{"revoked_at": nil}).
+			Where("expires_at <= NOW()")
+	case model.APIKeyStatusRevoked:
+		q = q.Where(sq.NotEq{"revoked_at": nil})
+	}
+
+	return q
+}
+
+func (r *apiKeyRepository) GetAPIKeys(ctx context.Context, req payload.GetAPIKeysRequest) ([]model.APIKey, error) {
+	q := sq.Select(apiKeyColumns...).
+		From("api_keys")
+
+	q = applyAPIKeyFilters(q, req).
+		OrderBy("created_at DESC", "id DESC").
+		Limit(uint64(req.Limit)).
+		Offset(uint64(req.Offset)).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []model.APIKey
+	err = conn(ctx, r.db).SelectContext(ctx, &keys, query, args...)
+
+	return keys, err
+}
+
+func (r *apiKeyRepository) GetAPIKeysCount(ctx context.Context, req payload.GetAPIKeysRequest) (int, error) {
+	q := sq.Select("COUNT(id)").
+		From("api_keys")
+
+	q = applyAPIKeyFilters(q, req).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = conn(ctx, r.db).GetContext(ctx, &count, query, args...)
+
+	return count, err
+}
+
+func (r *apiKeyRepository) GetAPIKeyByID(ctx context.Context, id string) (*model.APIKey, error) {
+	return r.getAPIKey(ctx, sq.Eq{"id": id})
+}
+
+func (r *apiKeyRepository) GetAPIKeyByHash(ctx context.Context, keyHash string) (*model.APIKey, error) {
+	return r.getAPIKey(ctx, sq.Eq{"key_hash": keyHash})
+}
+
+func (r *apiKeyRepository) getAPIKey(ctx context.Context, where sq.Eq) (*model.APIKey, error) {
+	var key model.APIKey
+
+	q := sq.Select(apiKeyColumns...).
+		From("api_keys").
+		Where(where).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = conn(ctx, r.db).GetContext(ctx, &key, query, args...)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	return &key, err
+}
+
+// RevokeAPIKey revokes a key that is not revoked yet, it returns sql.ErrNoRows otherwise.
+func (r *apiKeyRepository) RevokeAPIKey(ctx context.Context, id string, revokedAt time.Time) error {
+	q := sq.Update("api_keys").
+		Set("revoked_at", revokedAt).
+		Set("updated_at", revokedAt).
+		Where(sq.Eq{"id": id, "revoked_at": nil}).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return err
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// TouchAPIKey records that the key was used, unless that was already recorded within the last
+// apiKeyTouchInterval.
+func (r *apiKeyRepository) TouchAPIKey(ctx context.Context, id string, usedAt time.Time) error {
+	q := sq.Update("api_keys").
+		Set("last_used_at", usedAt).
+		Where(sq.Eq{"id": id}).
+		Where(sq.Or{
+			sq.Eq{"last_used_at": nil},
+			sq.Lt{"last_used_at": usedAt.Add(-apiKeyTouchInterval)},
+		}).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+
+	return err
+}
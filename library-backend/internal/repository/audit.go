@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"library-backend/internal/model"
+	"library-backend/internal/payload"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+var auditEventColumns = []string{
+	"id",
+	"occurred_at",
+	"actor_type",
+	"actor_id",
+	"action",
+	"entity_type",
+	"entity_id",
+	"before",
+	"after",
+	"request_id",
+}
+
+// AuditRepository only appends and reads, the table rejects updates and deletes.
+type AuditRepository interface {
+	CreateAuditEvent(ctx context.Context, event model.AuditEvent) error
+	GetAuditEvents(ctx context.Context, req payload.GetAuditEventsRequest) ([]model.AuditEvent, error)
+	GetAuditEventsCount(ctx context.Context, req payload.GetAuditEventsRequest) (int, error)
+}
+
+type auditRepository struct {
+	db *sqlx.DB
+}
+
+func NewAuditRepository(db *sqlx.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+// CreateAuditEvent appends an event, on the transaction in ctx when there is one so the event is
+// only kept if the mutation it describes is.
+func (r *auditRepository) CreateAuditEvent(ctx context.Context, event model.AuditEvent) error {
+	q := sq.Insert("audit_events").
+		Columns("id",
+			"occurred_at",
+			"actor_type",
+			"actor_id",
+			"action",
+			"entity_type",
+			"entity_id",
+			"before",
+			"after",
+			"request_id",
+		).
+		Values(event.ID,
+			event.OccurredAt,
+			event.ActorType,
+			event.ActorID,
+			event.Action,
+			event.EntityType,
+			event.EntityID,
+			jsonValue(event.Before),
+			jsonValue(event.After),
+			event.RequestID,
+		).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+
+	return err
+}
+
+// jsonValue passes a JSON document to a JSONB column as text, or NULL when it is empty.
+func jsonValue(doc []byte) any {
+	if len(doc) == 0 {
+		return nil
+	}
+
+	return string(doc)
+}
+
+// applyAuditEventFilters narrows an audit events query down to the rows matching the request
+// filters, it is shared by GetAuditEvents and GetAuditEventsCount so both always describe the
+// same result set.
+func applyAuditEventFilters(q sq.SelectBuilder, req payload.GetAuditEventsRequest) sq.SelectBuilder {
+	if req.ActorType != "" {
+		q = q.Where(sq.Eq{"actor_type": req.ActorType})
+	}
+
+	if req.ActorID != "" {
+		q = q.Where(sq.Eq{"actor_id": req.ActorID})
+	}
+
+	if req.Action != "" {
+		q = q.Where(sq.Eq{"action": req.Action})
+	}
+
+	if req.EntityType != "" {
+		q = q.Where(sq.Eq{"entity_type": req.EntityType})
+	}
+
+	if req.EntityID != "" {
+		q = q.Where(sq.Eq{"entity_id": req.EntityID})
+	}
+
+	if req.RequestID != "" {
+		q = q.Where(sq.Eq{"request_id": req.RequestID})
+	}
+
+	// date bounds are whole days, the upper one is made exclusive on the following day
+	if req.From != "" {
+		q = q.Where("occurred_at >= ?::date", req.From)
+	}
+
+	if req.To != "" {
+		q = q.Where("occurred_at < ?::date + 1", req.To)
+	}
+
+	return q
+}
+
+func (r *auditRepository) GetAuditEvents(ctx context.Context, req payload.GetAuditEventsRequest) ([]model.AuditEvent, error) {
+	q := sq.Select(auditEventColumns...).
+		From("audit_events")
+
+	q = applyAuditEventFilters(q, req).
+		OrderBy("occurred_at DESC", "id DESC").
+		Limit(uint64(req.Limit)).
+		Offset(uint64(req.Offset)).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var events []model.AuditEvent
+	err = conn(ctx, r.db).SelectContext(ctx, &events, query, args...)
+
+	return events, err
+}
+
+func (r *auditRepository) GetAuditEventsCount(ctx context.Context, req payload.GetAuditEventsRequest) (int, error) {
+	q := sq.Select("COUNT(id)").
+		From("audit_events")
+
+	q = applyAuditEventFilters(q, req).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = conn(ctx, r.db).GetContext(ctx, &count, query, args...)
+
+	return count, err
+}
@@ -4,21 +4,138 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"library-backend/internal/model"
 	"library-backend/internal/payload"
+	"library-backend/pkg/cql"
+	"library-backend/pkg/filter"
+	"library-backend/pkg/isbn"
+	"strconv"
+	"strings"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jmoiron/sqlx"
 )
 
+// copy counts are computed per book so callers always see live availability.
+const (
+	bookTotalCopiesColumn = "(SELECT COUNT(c.id) FROM book_copies c " +
+		"WHERE c.book_id = books.id AND c.deleted_at IS NULL) AS total_copies"
+	bookAvailableCopiesColumn = "(SELECT COUNT(c.id) FROM book_copies c " +
+		"WHERE c.book_id = books.id AND c.deleted_at IS NULL AND c.status = 'available') AS available_copies"
+)
+
+// full-text search uses the english configuration, the same one search_vector is built with.
+const (
+	bookSearchQuery     = "websearch_to_tsquery('english', ?)"
+	bookRankColumn      = "ts_rank_cd(search_vector, " + bookSearchQuery + ") AS rank"
+	bookHighlightColumn = "ts_headline('english', CONCAT_WS(' · ', title, author, publisher), " + bookSearchQuery + ", " +
+		"'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS highlight"
+)
+
+// bookExportBatchSize is how many rows an export fetches from its cursor at a time.
+const bookExportBatchSize = 500
+
+// bookSortColumns maps the public sort fields onto columns, anything else is never sorted on.
+var bookSortColumns = map[string]string{
+	"title":               "title",
+	"author":              "author",
+	"publisher":           "publisher",
+	"year_of_publication": "year_of_publication",
+	"category":            "category",
+	"created_at":          "created_at",
+	"updated_at":          "updated_at",
+}
+
+// bookFilterColumns maps the fields of filter expressions onto columns, the expressions are checked
+// against payload.BookFilterFields before they get here.
+var bookFilterColumns = map[string]string{
+	"title":               "title",
+	"author":              "author",
+	"publisher":           "publisher",
+	"year_of_publication": "year_of_publication",
+	"category":            "category",
+	"created_at":          "created_at",
+	"updated_at":          "updated_at",
+}
+
+// bookFacet is how the books are counted for a facet: the value each book is counted under, the
+// order of the buckets and, for facets with many values, how many buckets are kept.
+type bookFacet struct {
+	Expr    string
+	OrderBy []string
+	Limit   uint64
+}
+
+// bookPublisherFacetLimit keeps the publisher facet to the publishers with the most books.
+const bookPublisherFacetLimit = 20
+
+var bookFacets = map[string]bookFacet{
+	payload.BookFacetCategory:  {Expr: "category", OrderBy: []string{"count DESC", "value ASC"}},
+	payload.BookFacetDecade:    {Expr: "year_of_publication / 10 * 10", OrderBy: []string{"year_of_publication / 10 * 10 ASC"}},
+	payload.BookFacetPublisher: {Expr: "publisher", OrderBy: []string{"count DESC", "value ASC"}, Limit: bookPublisherFacetLimit},
+}
+
+// likeEscaper escapes the wildcards of a LIKE pattern, so ~ matches its value literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// bookCQLSchema maps the indexes of SRU queries onto columns. Index names without a context set are
+// Dublin Core ones, so "title" is "dc.title".
+var bookCQLSchema = cql.Schema{
+	DefaultSet:       "dc",
+	TextSearchConfig: "english",
+	Indexes: map[string]cql.Index{
+		"cql.serverchoice": {Column: "search_vector", Type: cql.FullText},
+		"cql.anywhere":     {Column: "search_vector", Type: cql.FullText},
+		"dc.title":         {Column: "title", Type: cql.Text},
+		"dc.creator":       {Column: "author", Type: cql.Text},
+		"dc.publisher":     {Column: "publisher", Type: cql.Text},
+		"dc.date":          {Column: "year_of_publication", Type: cql.Number},
+		"dc.subject":       {Column: "category", Type: cql.Exact},
+		"dc.identifier":    {Column: "isbn13", Type: cql.Exact, Normalize: isbn.Normalize},
+		"bath.isbn":        {Column: "isbn13", Type: cql.Exact, Normalize: isbn.Normalize},
+		"rec.identifier":   {Column: "id::text", Type: cql.Exact},
+	},
+}
+
+var bookColumns = []string{
+	"id",
+	"isbn",
+	"isbn13",
+	"title",
+	"author",
+	"publisher",
+	"year_of_publication",
+	"category",
+	"image_url",
+	"created_at",
+	"updated_at",
+	bookTotalCopiesColumn,
+	bookAvailableCopiesColumn,
+}
+
 type BookRepository interface {
 	CreateBook(ctx context.Context, book model.Book) error
-	GetBooks(ctx context.Context, req payload.GetBooksRequest) ([]model.Book, error)
-	GetBooksCount(ctx context.Context) (int, error)
+	GetBooks(ctx context.Context, req payload.GetBooksRequest) ([]model.Book, int, error)
+	GetBooksCount(ctx context.Context, req payload.GetBooksRequest) (int, error)
+	GetBookFacets(ctx context.Context, req payload.GetBooksRequest) (map[string][]payload.BookFacetBucket, error)
+	ExportBooks(ctx context.Context, req payload.GetBooksRequest, fn func(book model.Book) error) error
 	GetBookByID(ctx context.Context, id string) (*model.Book, error)
+	GetBookByISBN(ctx context.Context, isbn13 string) (*model.Book, error)
+	GetBookByCopyBarcode(ctx context.Context, barcode string) (*model.Book, error)
+	GetBooksByIDs(ctx context.Context, ids []string) ([]model.Book, error)
+	SearchBooks(ctx context.Context, search *cql.Query, limit, offset int) ([]model.Book, error)
+	SearchBooksCount(ctx context.Context, search *cql.Query) (int, error)
+	ScanBooks(ctx context.Context, index, term string, before, after int) ([]payload.BookIndexTerm, error)
 	UpdateBook(ctx context.Context, id string, updates map[string]any) error
 	DeleteBook(ctx context.Context, id string) error
+	GetDeletedBooks(ctx context.Context, req payload.GetDeletedBooksRequest) ([]model.Book, error)
+	GetDeletedBooksCount(ctx context.Context, req payload.GetDeletedBooksRequest) (int, error)
+	GetDeletedBookByID(ctx context.Context, id string) (*model.Book, error)
+	GetDeletedBookByISBN(ctx context.Context, isbn13 string) (*model.Book, error)
+	RestoreBook(ctx context.Context, id string) error
+	PurgeBook(ctx context.Context, id string) error
 }
 
 type bookRepository struct {
@@ -33,6 +150,7 @@ func (r *bookRepository) CreateBook(ctx context.Context, book model.Book) error
 	q := sq.Insert("books").
 		Columns("id",
 			"isbn",
+			"isbn13",
 			"title",
 			"author",
 			"publisher",
@@ -41,7 +159,7 @@ func (r *bookRepository) CreateBook(ctx context.Context, book model.Book) error
 			"image_url",
 			"updated_at",
 		).
-		Values(book.ID, book.ISBN, book.Title, book.Author, book.Publisher, book.YearOfPublication, book.Category, book.ImageURL, "NOW()").
+		Values(book.ID, book.ISBN, book.ISBN13, book.Title, book.Author, book.Publisher, book.YearOfPublication, book.Category, book.ImageURL, "NOW()").
 		PlaceholderFormat(sq.Dollar)
 
 	query, args, err := q.ToSql()
@@ -49,53 +167,439 @@ func (r *bookRepository) CreateBook(ctx context.Context, book model.Book) error
 		return err
 	}
 
-	_, err = r.db.ExecContext(ctx, query, args...)
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
 
 	return err
 }
 
-func (r *bookRepository) GetBooks(ctx context.Context, req payload.GetBooksRequest) ([]model.Book, error) {
-	q := sq.Select("id",
-		"isbn",
-		"title",
-		"author",
-		"publisher",
-		"year_of_publication",
-		"category",
-		"image_url",
-		"created_at",
-		"updated_at",
-	)
+// applyBookFilters narrows a books query down to the live rows matching the request filters,
+// it is shared by GetBooks and GetBooksCount so both always describe the same result set.
+func applyBookFilters(q sq.SelectBuilder, req payload.GetBooksRequest) sq.SelectBuilder {
+	q = q.Where(sq.Eq{"deleted_at": nil})
 
 	if req.Title != "" {
 		q = q.Where(sq.ILike{"title": "%" + req.Title + "%"})
 	}
 
-	q = q.From("books").
-		Where(sq.Eq{"deleted_at": nil}).
-		OrderBy("updated_at DESC").
+	if req.Q != "" {
+		q = q.Where(sq.Expr("search_vector @@ "+bookSearchQuery, req.Q))
+	}
+
+	if len(req.Category) > 0 {
+		q = q.Where(sq.Eq{"category": req.Category})
+	}
+
+	if req.Author != "" {
+		q = q.Where(sq.ILike{"author": "%" + req.Author + "%"})
+	}
+
+	if req.Publisher != "" {
+		q = q.Where(sq.ILike{"publisher": "%" + req.Publisher + "%"})
+	}
+
+	if req.YearFrom != 0 {
+		q = q.Where(sq.GtOrEq{"year_of_publication": req.YearFrom})
+	}
+
+	if req.YearTo != 0 {
+		q = q.Where(sq.LtOrEq{"year_of_publication": req.YearTo})
+	}
+
+	// date bounds are whole days, the upper one is made exclusive on the following day
+	if req.CreatedFrom != "" {
+		q = q.Where("created_at >= ?::date", req.CreatedFrom)
+	}
+
+	if req.CreatedTo != "" {
+		q = q.Where("created_at < ?::date + 1", req.CreatedTo)
+	}
+
+	if req.UpdatedFrom != "" {
+		q = q.Where("updated_at >= ?::date", req.UpdatedFrom)
+	}
+
+	if req.UpdatedTo != "" {
+		q = q.Where("updated_at < ?::date + 1", req.UpdatedTo)
+	}
+
+	if req.FilterExpr != nil {
+		q = q.Where(bookFilterPredicate(req.FilterExpr))
+	}
+
+	return q
+}
+
+// bookFilterPredicate compiles a filter expression into squirrel conditions.
+func bookFilterPredicate(expr filter.Expr) sq.Sqlizer {
+	switch e := expr.(type) {
+	case *filter.Logical:
+		if e.Op == "or" {
+			return sq.Or{bookFilterPredicate(e.Left), bookFilterPredicate(e.Right)}
+		}
+		return sq.And{bookFilterPredicate(e.Left), bookFilterPredicate(e.Right)}
+	case *filter.Not:
+		return sq.Expr("NOT (?)", bookFilterPredicate(e.Expr))
+	case *filter.Comparison:
+		return bookFilterComparison(e)
+	}
+
+	// an expression that was not validated matches nothing rather than everything
+	return sq.Expr("FALSE")
+}
+
+func bookFilterComparison(c *filter.Comparison) sq.Sqlizer {
+	column, ok := bookFilterColumns[c.Field]
+	if !ok {
+		return sq.Expr("FALSE")
+	}
+
+	field := payload.BookFilterFields[c.Field]
+
+	values := make([]any, len(c.Values))
+	for i, value := range c.Values {
+		values[i] = value.Text
+		if field.Type == filter.Number {
+			values[i], _ = strconv.Atoi(value.Text)
+		}
+	}
+
+	// dates are whole days, as the created_from and created_to bounds are
+	if field.Type == filter.Date {
+		switch c.Op {
+		case filter.OpEq:
+			return sq.And{sq.Expr(column+" >= ?::date", values[0]), sq.Expr(column+" < ?::date + 1", values[0])}
+		case filter.OpNotEq:
+			return sq.Or{sq.Expr(column+" < ?::date", values[0]), sq.Expr(column+" >= ?::date + 1", values[0])}
+		case filter.OpLt:
+			return sq.Expr(column+" < ?::date", values[0])
+		case filter.OpLtOrEq:
+			return sq.Expr(column+" < ?::date + 1", values[0])
+		case filter.OpGt:
+			return sq.Expr(column+" >= ?::date + 1", values[0])
+		case filter.OpGtOrEq:
+			return sq.Expr(column+" >= ?::date", values[0])
+		}
+	}
+
+	switch c.Op {
+	case filter.OpEq:
+		return sq.Eq{column: values[0]}
+	case filter.OpNotEq:
+		return sq.NotEq{column: values[0]}
+	case filter.OpLt:
+		return sq.Lt{column: values[0]}
+	case filter.OpLtOrEq:
+		return sq.LtOrEq{column: values[0]}
+	case filter.OpGt:
+		return sq.Gt{column: values[0]}
+	case filter.OpGtOrEq:
+		return sq.GtOrEq{column: values[0]}
+	case filter.OpContains:
+		return sq.ILike{column: "%" + likeEscaper.Replace(c.Values[0].Text) + "%"}
+	case filter.OpNotContain:
+		return sq.NotILike{column: "%" + likeEscaper.Replace(c.Values[0].Text) + "%"}
+	case filter.OpIn:
+		return sq.Eq{column: values}
+	case filter.OpNotIn:
+		return sq.NotEq{column: values}
+	}
+
+	return sq.Expr("FALSE")
+}
+
+// applyBookSort orders books by the requested sort fields, by relevance for full-text
+// searches, or by most recently updated. The id tie-breaker keeps pages stable.
+func applyBookSort(q sq.SelectBuilder, req payload.GetBooksRequest) sq.SelectBuilder {
+	if len(req.SortFields) == 0 {
+		if req.Q != "" {
+			q = q.OrderBy("rank DESC")
+		}
+
+		return q.OrderBy("updated_at DESC", "id DESC")
+	}
+
+	for _, field := range req.SortFields {
+		column, ok := bookSortColumns[field.Field]
+		if !ok {
+			continue
+		}
+
+		if field.Desc {
+			q = q.OrderBy(column + " DESC")
+		} else {
+			q = q.OrderBy(column + " ASC")
+		}
+	}
+
+	return q.OrderBy("id ASC")
+}
+
+// bookPageRow is a book together with the size of the whole filtered result set.
+type bookPageRow struct {
+	model.Book
+	TotalCount int `db:"total_count"`
+}
+
+// GetBooks returns one page of books and the number of books matching the filters. The total is
+// counted by a window over the same query, so it is only known when the page has rows, and it is
+// not counted at all in cursor mode.
+func (r *bookRepository) GetBooks(ctx context.Context, req payload.GetBooksRequest) ([]model.Book, int, error) {
+	q := sq.Select(bookColumns...).
+		From("books")
+
+	// cursor pages skip the total, counting every match would undo the point of seeking
+	if !req.CursorMode {
+		q = q.Column("COUNT(*) OVER() AS total_count")
+	}
+
+	if req.After != nil {
+		q = q.Where("(updated_at, id) < (?, ?)", req.After.UpdatedAt, req.After.ID)
+	}
+
+	// full-text matches carry their relevance and the matched words highlighted
+	if req.Q != "" {
+		q = q.Column(sq.Expr(bookRankColumn, req.Q)).
+			Column(sq.Expr(bookHighlightColumn, req.Q))
+	}
+
+	q = applyBookFilters(q, req)
+
+	q = applyBookSort(q, req).
 		Limit(uint64(req.Limit)).
 		Offset(uint64(req.Offset)).
 		PlaceholderFormat(sq.Dollar)
 
 	query, args, err := q.ToSql()
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	var books []model.Book
-	err = r.db.SelectContext(ctx, &books, query, args...)
+	var rows []bookPageRow
+	err = r.db.SelectContext(ctx, &rows, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
 
-	return books, err
+	var total int
+	books := make([]model.Book, len(rows))
+	for i, row := range rows {
+		books[i] = row.Book
+		total = row.TotalCount
+	}
+
+	return books, total, nil
 }
 
-func (r *bookRepository) GetBooksCount(ctx context.Context) (int, error) {
+func (r *bookRepository) GetBooksCount(ctx context.Context, req payload.GetBooksRequest) (int, error) {
 	q := sq.Select("COUNT(id)").
+		From("books")
+
+	q = applyBookFilters(q, req).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = r.db.GetContext(ctx, &count, query, args...)
+
+	return count, err
+}
+
+// GetBookFacets counts the books matching the filters by each of the requested facets, over the
+// whole result set whatever the page or cursor.
+func (r *bookRepository) GetBookFacets(ctx context.Context, req payload.GetBooksRequest) (map[string][]payload.BookFacetBucket, error) {
+	facets := make(map[string][]payload.BookFacetBucket, len(req.FacetFields))
+
+	for _, name := range req.FacetFields {
+		facet, ok := bookFacets[name]
+		if !ok {
+			continue
+		}
+
+		q := sq.Select("("+facet.Expr+")::text AS value", "COUNT(id) AS count").
+			From("books")
+
+		q = applyBookFilters(q, req).
+			GroupBy(facet.Expr).
+			OrderBy(facet.OrderBy...).
+			PlaceholderFormat(sq.Dollar)
+
+		if facet.Limit > 0 {
+			q = q.Limit(facet.Limit)
+		}
+
+		query, args, err := q.ToSql()
+		if err != nil {
+			return nil, err
+		}
+
+		buckets := []payload.BookFacetBucket{}
+		err = r.db.SelectContext(ctx, &buckets, query, args...)
+		if err != nil {
+			return nil, err
+		}
+
+		facets[name] = buckets
+	}
+
+	return facets, nil
+}
+
+// ExportBooks hands every book matching the filters to fn, in the list order. The rows are read
+// through a server-side cursor bookExportBatchSize at a time, so memory stays flat however large the
+// catalog is; an error from fn stops the export.
+func (r *bookRepository) ExportBooks(ctx context.Context, req payload.GetBooksRequest, fn func(book model.Book) error) (err error) {
+	q := sq.Select(bookColumns...).
+		From("books")
+
+	// relevance is only selected to order full-text matches by
+	if req.Q != "" {
+		q = q.Column(sq.Expr(bookRankColumn, req.Q))
+	}
+
+	q = applyBookFilters(q, req)
+
+	q = applyBookSort(q, req).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return err
+	}
+
+	// a cursor only lives as long as its transaction
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	_, err = tx.ExecContext(ctx, "DECLARE books_export NO SCROLL CURSOR FOR "+query, args...)
+	if err != nil {
+		return err
+	}
+
+	fetch := fmt.Sprintf("FETCH %d FROM books_export", bookExportBatchSize)
+	for {
+		var books []model.Book
+		err = tx.SelectContext(ctx, &books, fetch)
+		if err != nil {
+			return err
+		}
+
+		if len(books) == 0 {
+			break
+		}
+
+		for _, book := range books {
+			if err = fn(book); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *bookRepository) GetBookByID(ctx context.Context, id string) (*model.Book, error) {
+	return r.getBook(ctx, sq.Eq{"id": id})
+}
+
+// GetBookByISBN returns the live book with the canonical ISBN-13.
+func (r *bookRepository) GetBookByISBN(ctx context.Context, isbn13 string) (*model.Book, error) {
+	return r.getBook(ctx, sq.Eq{"isbn13": isbn13})
+}
+
+// GetBookByCopyBarcode returns the live book a live copy with the barcode belongs to.
+func (r *bookRepository) GetBookByCopyBarcode(ctx context.Context, barcode string) (*model.Book, error) {
+	return r.getBook(ctx, sq.Expr("id = (SELECT c.book_id FROM book_copies c WHERE c.barcode = ? AND c.deleted_at IS NULL)", barcode))
+}
+
+// GetBooksByIDs returns the live books among the IDs, in no particular order. IDs of missing or
+// deleted books are skipped.
+func (r *bookRepository) GetBooksByIDs(ctx context.Context, ids []string) ([]model.Book, error) {
+	q := sq.Select(bookColumns...).
 		From("books").
+		Where(sq.Eq{"id": ids}).
 		Where(sq.Eq{"deleted_at": nil}).
 		PlaceholderFormat(sq.Dollar)
 
 	query, args, err := q.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var books []model.Book
+	err = conn(ctx, r.db).SelectContext(ctx, &books, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return books, nil
+}
+
+// SearchBooks returns a page of the live books matching a CQL query, in the order of its sortBy
+// clause or most recently updated first. A query the schema cannot run fails with a *cql.Error.
+func (r *bookRepository) SearchBooks(ctx context.Context, search *cql.Query, limit, offset int) ([]model.Book, error) {
+	where, err := bookCQLSchema.Where(search.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := bookCQLSchema.OrderBy(search.SortKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	q := sq.Select(bookColumns...).
+		From("books").
+		Where(sq.Eq{"deleted_at": nil}).
+		Where(where)
+
+	// the id tie-breaker keeps pages stable
+	if len(order) == 0 {
+		q = q.OrderBy("updated_at DESC", "id DESC")
+	} else {
+		q = q.OrderBy(order...).OrderBy("id ASC")
+	}
+
+	query, args, err := q.Limit(uint64(limit)).
+		Offset(uint64(offset)).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var books []model.Book
+	err = r.db.SelectContext(ctx, &books, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return books, nil
+}
+
+// SearchBooksCount returns the number of live books matching a CQL query.
+func (r *bookRepository) SearchBooksCount(ctx context.Context, search *cql.Query) (int, error) {
+	where, err := bookCQLSchema.Where(search.Root)
+	if err != nil {
+		return 0, err
+	}
+
+	query, args, err := sq.Select("COUNT(id)").
+		From("books").
+		Where(sq.Eq{"deleted_at": nil}).
+		Where(where).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
 	if err != nil {
 		return 0, err
 	}
@@ -106,22 +610,79 @@ func (r *bookRepository) GetBooksCount(ctx context.Context) (int, error) {
 	return count, err
 }
 
-func (r *bookRepository) GetBookByID(ctx context.Context, id string) (*model.Book, error) {
+// ScanBooks browses the values of a CQL index across the live books: up to before values ordered
+// before the term, then up to after values from the term on, each with the number of books that
+// have it. Full-text indexes have no values to browse and fail with a *cql.Error.
+func (r *bookRepository) ScanBooks(ctx context.Context, index, term string, before, after int) ([]payload.BookIndexTerm, error) {
+	idx, ok := bookCQLSchema.Index(index)
+	if !ok || idx.Type == cql.FullText {
+		return nil, &cql.Error{Err: cql.ErrUnsupportedIndex, Detail: index}
+	}
+
+	var from any = term
+	switch {
+	case idx.Type == cql.Number:
+		year, err := strconv.Atoi(term)
+		if err != nil {
+			return nil, &cql.Error{Err: cql.ErrInvalidTerm, Detail: term}
+		}
+		from = year
+	case idx.Normalize != nil:
+		// a partial ISBN is browsed from as written
+		if normalized, err := idx.Normalize(term); err == nil {
+			from = normalized
+		}
+	}
+
+	scan := func(where sq.Sqlizer, direction string, limit int) ([]payload.BookIndexTerm, error) {
+		if limit <= 0 {
+			return nil, nil
+		}
+
+		query, args, err := sq.Select(idx.Column+"::text AS value", "COUNT(id) AS count").
+			From("books").
+			Where(sq.Eq{"deleted_at": nil}).
+			Where(where).
+			GroupBy(idx.Column).
+			OrderBy(idx.Column + " " + direction).
+			Limit(uint64(limit)).
+			PlaceholderFormat(sq.Dollar).
+			ToSql()
+		if err != nil {
+			return nil, err
+		}
+
+		var terms []payload.BookIndexTerm
+		err = r.db.SelectContext(ctx, &terms, query, args...)
+
+		return terms, err
+	}
+
+	preceding, err := scan(sq.Lt{idx.Column: from}, "DESC", before)
+	if err != nil {
+		return nil, err
+	}
+
+	following, err := scan(sq.GtOrEq{idx.Column: from}, "ASC", after)
+	if err != nil {
+		return nil, err
+	}
+
+	terms := make([]payload.BookIndexTerm, 0, len(preceding)+len(following))
+	for i := len(preceding) - 1; i >= 0; i-- {
+		terms = append(terms, preceding[i])
+	}
+
+	return append(terms, following...), nil
+}
+
+func (r *bookRepository) getBook(ctx context.Context, where sq.Sqlizer) (*model.Book, error) {
 	var book model.Book
 
-	q := sq.Select("id",
-		"isbn",
-		"title",
-		"author",
-		"publisher",
-		"year_of_publication",
-		"category",
-		"image_url",
-		"created_at",
-		"updated_at",
-	).
+	q := sq.Select(bookColumns...).
 		From("books").
-		Where(sq.Eq{"id": id, "deleted_at": nil}).
+		Where(where).
+		Where(sq.Eq{"deleted_at": nil}).
 		PlaceholderFormat(sq.Dollar)
 
 	query, args, err := q.ToSql()
@@ -129,7 +690,7 @@ func (r *bookRepository) GetBookByID(ctx context.Context, id string) (*model.Boo
 		return nil, err
 	}
 
-	err = r.db.GetContext(ctx, &book, query, args...)
+	err = conn(ctx, r.db).GetContext(ctx, &book, query, args...)
 	if err != nil && errors.Is(err, sql.ErrNoRows) {
 		return nil, nil
 	}
@@ -157,7 +718,7 @@ func (r *bookRepository) UpdateBook(ctx context.Context, id string, updates map[
 		return err
 	}
 
-	result, err := r.db.ExecContext(ctx, query, args...)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
 	if err != nil {
 		return err
 	}
@@ -185,7 +746,157 @@ func (r *bookRepository) DeleteBook(ctx context.Context, id string) error {
 		return err
 	}
 
-	result, err := r.db.ExecContext(ctx, query, args...)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// applyDeletedBookFilters narrows a books query down to the trashed rows matching the request
+// filters, it is shared by GetDeletedBooks and GetDeletedBooksCount.
+func applyDeletedBookFilters(q sq.SelectBuilder, req payload.GetDeletedBooksRequest) sq.SelectBuilder {
+	q = q.Where(sq.NotEq{"deleted_at": nil})
+
+	if req.Title != "" {
+		q = q.Where(sq.ILike{"title": "%" + req.Title + "%"})
+	}
+
+	return q
+}
+
+// GetDeletedBooks returns one page of the trash, most recently deleted first.
+func (r *bookRepository) GetDeletedBooks(ctx context.Context, req payload.GetDeletedBooksRequest) ([]model.Book, error) {
+	q := sq.Select(bookColumns...).
+		Column("deleted_at").
+		From("books")
+
+	q = applyDeletedBookFilters(q, req).
+		OrderBy("deleted_at DESC", "id DESC").
+		Limit(uint64(req.Limit)).
+		Offset(uint64(req.Offset)).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var books []model.Book
+	err = conn(ctx, r.db).SelectContext(ctx, &books, query, args...)
+
+	return books, err
+}
+
+func (r *bookRepository) GetDeletedBooksCount(ctx context.Context, req payload.GetDeletedBooksRequest) (int, error) {
+	q := sq.Select("COUNT(id)").
+		From("books")
+
+	q = applyDeletedBookFilters(q, req).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = conn(ctx, r.db).GetContext(ctx, &count, query, args...)
+
+	return count, err
+}
+
+func (r *bookRepository) GetDeletedBookByID(ctx context.Context, id string) (*model.Book, error) {
+	return r.getDeletedBook(ctx, sq.Eq{"id": id})
+}
+
+// GetDeletedBookByISBN returns the most recently deleted book with the canonical ISBN-13, there can
+// be several once a book has been created and deleted again.
+func (r *bookRepository) GetDeletedBookByISBN(ctx context.Context, isbn13 string) (*model.Book, error) {
+	return r.getDeletedBook(ctx, sq.Eq{"isbn13": isbn13})
+}
+
+func (r *bookRepository) getDeletedBook(ctx context.Context, where sq.Eq) (*model.Book, error) {
+	var book model.Book
+
+	q := sq.Select(bookColumns...).
+		Column("deleted_at").
+		From("books").
+		Where(where).
+		Where(sq.NotEq{"deleted_at": nil}).
+		OrderBy("deleted_at DESC").
+		Limit(1).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = conn(ctx, r.db).GetContext(ctx, &book, query, args...)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	return &book, err
+}
+
+// RestoreBook takes a book out of the trash, it returns sql.ErrNoRows when the book is not in it.
+func (r *bookRepository) RestoreBook(ctx context.Context, id string) error {
+	q := sq.Update("books").
+		Set("deleted_at", nil).
+		Set("updated_at", time.Now()).
+		Where(sq.Eq{"id": id}).
+		Where(sq.NotEq{"deleted_at": nil}).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return err
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// PurgeBook permanently deletes a book in the trash along with its copies, it returns
+// sql.ErrNoRows when the book is not in the trash. Books with loans or holds are kept by their
+// foreign keys.
+func (r *bookRepository) PurgeBook(ctx context.Context, id string) error {
+	q := sq.Delete("books").
+		Where(sq.Eq{"id": id}).
+		Where(sq.NotEq{"deleted_at": nil}).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return err
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
 	if err != nil {
 		return err
 	}
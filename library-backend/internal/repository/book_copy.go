@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"library-backend/internal/model"
+	"library-backend/internal/payload"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+var bookCopyColumns = []string{
+	"id",
+	"book_id",
+	"barcode",
+	"shelf_location",
+	"condition",
+	"status",
+	"created_at",
+	"updated_at",
+}
+
+type BookCopyRepository interface {
+	CreateBookCopy(ctx context.Context, bookCopy model.BookCopy) error
+	GetBookCopies(ctx context.Context, req payload.GetBookCopiesRequest) ([]model.BookCopy, error)
+	GetBookCopyByID(ctx context.Context, id string) (*model.BookCopy, error)
+	UpdateBookCopy(ctx context.Context, id string, updates map[string]any) error
+	DeleteBookCopy(ctx context.Context, id string) error
+	// ClaimAvailableCopy atomically flags an available copy of the book as on loan and returns it,
+	// or returns nil when every copy is taken.
+	ClaimAvailableCopy(ctx context.Context, bookID string) (*model.BookCopy, error)
+	// ClaimCopy atomically flags the given copy as on loan when it is available.
+	ClaimCopy(ctx context.Context, id string) (*model.BookCopy, error)
+	// ClaimHeldCopy atomically flags a copy set aside for a hold as on loan.
+	ClaimHeldCopy(ctx context.Context, id string) (*model.BookCopy, error)
+	UpdateBookCopyStatus(ctx context.Context, id string, status string) error
+}
+
+type bookCopyRepository struct {
+	db *sqlx.DB
+}
+
+func NewBookCopyRepository(db *sqlx.DB) BookCopyRepository {
+	return &bookCopyRepository{db: db}
+}
+
+func (r *bookCopyRepository) CreateBookCopy(ctx context.Context, bookCopy model.BookCopy) error {
+	q := sq.Insert("book_copies").
+		Columns("id",
+			"book_id",
+			"barcode",
+			"shelf_location",
+			"condition",
+			"status",
+			"updated_at",
+		).
+		Values(bookCopy.ID, bookCopy.BookID, bookCopy.Barcode, bookCopy.ShelfLocation, bookCopy.Condition, bookCopy.Status, "NOW()").
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+
+	return err
+}
+
+func (r *bookCopyRepository) GetBookCopies(ctx context.Context, req payload.GetBookCopiesRequest) ([]model.BookCopy, error) {
+	q := sq.Select(bookCopyColumns...).
+		From("book_copies").
+		Where(sq.Eq{"book_id": req.BookID, "deleted_at": nil})
+
+	if req.Status != "" {
+		q = q.Where(sq.Eq{"status": req.Status})
+	}
+
+	q = q.OrderBy("created_at ASC").
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var copies []model.BookCopy
+	err = conn(ctx, r.db).SelectContext(ctx, &copies, query, args...)
+
+	return copies, err
+}
+
+func (r *bookCopyRepository) GetBookCopyByID(ctx context.Context, id string) (*model.BookCopy, error) {
+	var bookCopy model.BookCopy
+
+	q := sq.Select(bookCopyColumns...).
+		From("book_copies").
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = conn(ctx, r.db).GetContext(ctx, &bookCopy, query, args...)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	return &bookCopy, err
+}
+
+func (r *bookCopyRepository) UpdateBookCopy(ctx context.Context, id string, updates map[string]any) error {
+	if len(updates) == 0 {
+		return errors.New("no fields to update")
+	}
+
+	q := sq.Update("book_copies").
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
+		PlaceholderFormat(sq.Dollar)
+
+	for field, value := range updates {
+		q = q.Set(field, value)
+	}
+
+	q = q.Set("updated_at", time.Now())
+
+	return r.execUpdate(ctx, q)
+}
+
+func (r *bookCopyRepository) DeleteBookCopy(ctx context.Context, id string) error {
+	q := sq.Update("book_copies").
+		Set("deleted_at", time.Now()).
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
+		PlaceholderFormat(sq.Dollar)
+
+	return r.execUpdate(ctx, q)
+}
+
+func (r *bookCopyRepository) ClaimAvailableCopy(ctx context.Context, bookID string) (*model.BookCopy, error) {
+	// SKIP LOCKED lets concurrent checkouts of the same title grab different copies
+	// instead of queueing up behind each other
+	available := sq.Select("id").
+		From("book_copies").
+		Where(sq.Eq{"book_id": bookID, "status": model.BookCopyStatusAvailable, "deleted_at": nil}).
+		OrderBy("created_at ASC").
+		Limit(1).
+		Suffix("FOR UPDATE SKIP LOCKED")
+
+	q := sq.Update("book_copies").
+		Set("status", model.BookCopyStatusOnLoan).
+		Set("updated_at", time.Now()).
+		Where(sq.Expr("id = (?)", available))
+
+	return r.claim(ctx, q)
+}
+
+func (r *bookCopyRepository) ClaimCopy(ctx context.Context, id string) (*model.BookCopy, error) {
+	q := sq.Update("book_copies").
+		Set("status", model.BookCopyStatusOnLoan).
+		Set("updated_at", time.Now()).
+		Where(sq.Eq{"id": id, "status": model.BookCopyStatusAvailable, "deleted_at": nil})
+
+	return r.claim(ctx, q)
+}
+
+func (r *bookCopyRepository) ClaimHeldCopy(ctx context.Context, id string) (*model.BookCopy, error) {
+	q := sq.Update("book_copies").
+		Set("status", model.BookCopyStatusOnLoan).
+		Set("updated_at", time.Now()).
+		Where(sq.Eq{"id": id, "status": model.BookCopyStatusOnHold, "deleted_at": nil})
+
+	return r.claim(ctx, q)
+}
+
+func (r *bookCopyRepository) UpdateBookCopyStatus(ctx context.Context, id string, status string) error {
+	q := sq.Update("book_copies").
+		Set("status", status).
+		Set("updated_at", time.Now()).
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar)
+
+	return r.execUpdate(ctx, q)
+}
+
+func (r *bookCopyRepository) claim(ctx context.Context, q sq.UpdateBuilder) (*model.BookCopy, error) {
+	var bookCopy model.BookCopy
+
+	query, args, err := q.
+		Suffix("RETURNING " + joinColumns(bookCopyColumns)).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = conn(ctx, r.db).GetContext(ctx, &bookCopy, query, args...)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	return &bookCopy, err
+}
+
+func (r *bookCopyRepository) execUpdate(ctx context.Context, q sq.UpdateBuilder) error {
+	query, args, err := q.ToSql()
+	if err != nil {
+		return err
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
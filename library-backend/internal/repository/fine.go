@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"library-backend/internal/model"
+	"library-backend/internal/payload"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+// fineBalanceColumns sum up a member's ledger, charges add to the balance while waivers
+// and payments settle it. SUM over BIGINT yields NUMERIC, hence the casts.
+var fineBalanceColumns = []string{
+	"COALESCE(SUM(f.amount) FILTER (WHERE f.entry_type = 'charge'), 0)::BIGINT AS charged",
+	"COALESCE(SUM(f.amount) FILTER (WHERE f.entry_type = 'waiver'), 0)::BIGINT AS waived",
+	"COALESCE(SUM(f.amount) FILTER (WHERE f.entry_type = 'payment'), 0)::BIGINT AS paid",
+	fineBalanceExpr + " AS balance",
+}
+
+const fineBalanceExpr = "COALESCE(SUM(CASE WHEN f.entry_type = 'charge' THEN f.amount ELSE -f.amount END), 0)::BIGINT"
+
+type FineRepository interface {
+	CreateFineEntry(ctx context.Context, entry model.FineEntry) error
+	GetFineEntries(ctx context.Context, req payload.GetMemberFinesRequest) ([]model.FineEntry, error)
+	GetFineEntriesCount(ctx context.Context, req payload.GetMemberFinesRequest) (int, error)
+	GetMemberFineBalance(ctx context.Context, memberID string) (model.FineBalance, error)
+	// GetFineBalances lists the members who still owe fines, largest balance first.
+	GetFineBalances(ctx context.Context, req payload.GetFineBalancesRequest) ([]model.FineBalance, error)
+	GetFineBalancesCount(ctx context.Context, req payload.GetFineBalancesRequest) (int, error)
+	// LockMemberFines serialises ledger updates of a member until the surrounding transaction ends.
+	LockMemberFines(ctx context.Context, memberID string) error
+}
+
+type fineRepository struct {
+	db *sqlx.DB
+}
+
+func NewFineRepository(db *sqlx.DB) FineRepository {
+	return &fineRepository{db: db}
+}
+
+func (r *fineRepository) CreateFineEntry(ctx context.Context, entry model.FineEntry) error {
+	q := sq.Insert("fine_entries").
+		Columns("id",
+			"member_id",
+			"loan_id",
+			"entry_type",
+			"amount",
+			"note",
+			"created_at",
+		).
+		Values(entry.ID, entry.MemberID, entry.LoanID, entry.EntryType, entry.Amount, entry.Note, entry.CreatedAt).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+
+	return err
+}
+
+func (r *fineRepository) GetFineEntries(ctx context.Context, req payload.GetMemberFinesRequest) ([]model.FineEntry, error) {
+	q := sq.Select("id",
+		"member_id",
+		"loan_id",
+		"entry_type",
+		"amount",
+		"note",
+		"created_at",
+	).
+		From("fine_entries").
+		Where(sq.Eq{"member_id": req.MemberID}).
+		OrderBy("created_at DESC", "id DESC").
+		Limit(uint64(req.Limit)).
+		Offset(uint64(req.Offset)).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []model.FineEntry
+	err = conn(ctx, r.db).SelectContext(ctx, &entries, query, args...)
+
+	return entries, err
+}
+
+func (r *fineRepository) GetFineEntriesCount(ctx context.Context, req payload.GetMemberFinesRequest) (int, error) {
+	q := sq.Select("COUNT(id)").
+		From("fine_entries").
+		Where(sq.Eq{"member_id": req.MemberID}).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = conn(ctx, r.db).GetContext(ctx, &count, query, args...)
+
+	return count, err
+}
+
+func (r *fineRepository) GetMemberFineBalance(ctx context.Context, memberID string) (model.FineBalance, error) {
+	var balance model.FineBalance
+
+	q := sq.Select(fineBalanceColumns...).
+		From("fine_entries f").
+		Where(sq.Eq{"f.member_id": memberID}).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return balance, err
+	}
+
+	err = conn(ctx, r.db).GetContext(ctx, &balance, query, args...)
+
+	return balance, err
+}
+
+func selectOutstandingBalances() sq.SelectBuilder {
+	columns := append([]string{"m.id AS member_id", "m.name AS member_name"}, fineBalanceColumns...)
+
+	return sq.Select(columns...).
+		From("fine_entries f").
+		Join("members m ON m.id = f.member_id").
+		GroupBy("m.id", "m.name").
+		Having(fineBalanceExpr + " > 0")
+}
+
+func (r *fineRepository) GetFineBalances(ctx context.Context, req payload.GetFineBalancesRequest) ([]model.FineBalance, error) {
+	q := selectOutstandingBalances().
+		OrderBy("balance DESC", "m.name ASC").
+		Limit(uint64(req.Limit)).
+		Offset(uint64(req.Offset)).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var balances []model.FineBalance
+	err = conn(ctx, r.db).SelectContext(ctx, &balances, query, args...)
+
+	return balances, err
+}
+
+func (r *fineRepository) GetFineBalancesCount(ctx context.Context, req payload.GetFineBalancesRequest) (int, error) {
+	q := sq.Select("COUNT(*)").
+		FromSelect(selectOutstandingBalances(), "outstanding").
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = conn(ctx, r.db).GetContext(ctx, &count, query, args...)
+
+	return count, err
+}
+
+func (r *fineRepository) LockMemberFines(ctx context.Context, memberID string) error {
+	q := sq.Select("id").
+		From("members").
+		Where(sq.Eq{"id": memberID}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+
+	return err
+}
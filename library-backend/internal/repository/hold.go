@@ -0,0 +1,273 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"library-backend/internal/model"
+	"library-backend/internal/payload"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+// holdPositionColumn numbers waiting holds per book in the order they were placed, holds that
+// are no longer queueing get position 0.
+const holdPositionColumn = "CASE WHEN h.status = 'waiting' THEN (" +
+	"SELECT COUNT(q.id) FROM holds q " +
+	"WHERE q.book_id = h.book_id AND q.status = 'waiting' AND (q.placed_at, q.id) <= (h.placed_at, h.id)" +
+	") ELSE 0 END AS position"
+
+var holdColumns = []string{
+	"id",
+	"book_id",
+	"member_id",
+	"copy_id",
+	"status",
+	"placed_at",
+	"ready_at",
+	"expires_at",
+	"closed_at",
+	"created_at",
+	"updated_at",
+}
+
+type HoldRepository interface {
+	CreateHold(ctx context.Context, hold model.Hold) error
+	GetHolds(ctx context.Context, req payload.GetHoldsRequest) ([]model.Hold, error)
+	GetHoldsCount(ctx context.Context, req payload.GetHoldsRequest) (int, error)
+	GetHoldByID(ctx context.Context, id string) (*model.Hold, error)
+	// GetActiveHold returns the waiting or ready hold of the member on the book, if any.
+	GetActiveHold(ctx context.Context, bookID, memberID string) (*model.Hold, error)
+	// AssignNextHold sets the copy aside for the oldest waiting hold on the book and returns that
+	// hold, or returns nil when nobody is queueing.
+	AssignNextHold(ctx context.Context, bookID, copyID string, readyAt, expiresAt time.Time) (*model.Hold, error)
+	// CloseHold moves a waiting or ready hold to the given final status and returns it, or returns
+	// nil when the hold is no longer active.
+	CloseHold(ctx context.Context, id string, status string, closedAt time.Time) (*model.Hold, error)
+	// ExpireHold expires a ready hold whose pickup window has passed and returns it, or returns nil
+	// when the hold was picked up or closed in the meantime.
+	ExpireHold(ctx context.Context, id string, now time.Time) (*model.Hold, error)
+	GetExpiredHolds(ctx context.Context, now time.Time) ([]model.Hold, error)
+}
+
+type holdRepository struct {
+	db *sqlx.DB
+}
+
+func NewHoldRepository(db *sqlx.DB) HoldRepository {
+	return &holdRepository{db: db}
+}
+
+func (r *holdRepository) CreateHold(ctx context.Context, hold model.Hold) error {
+	q := sq.Insert("holds").
+		Columns("id",
+			"book_id",
+			"member_id",
+			"status",
+			"placed_at",
+			"updated_at",
+		).
+		Values(hold.ID, hold.BookID, hold.MemberID, hold.Status, hold.PlacedAt, "NOW()").
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+
+	return err
+}
+
+func selectHolds() sq.SelectBuilder {
+	return sq.Select("h.id",
+		"h.book_id",
+		"h.member_id",
+		"h.copy_id",
+		"c.barcode",
+		"h.status",
+		holdPositionColumn,
+		"h.placed_at",
+		"h.ready_at",
+		"h.expires_at",
+		"h.closed_at",
+		"b.title AS book_title",
+		"m.name AS member_name",
+		"h.created_at",
+		"h.updated_at",
+	).
+		From("holds h").
+		Join("books b ON b.id = h.book_id").
+		Join("members m ON m.id = h.member_id").
+		LeftJoin("book_copies c ON c.id = h.copy_id")
+}
+
+// applyHoldFilters narrows a holds query down to the rows matching the request filters,
+// it is shared by GetHolds and GetHoldsCount so both always describe the same result set.
+func applyHoldFilters(q sq.SelectBuilder, req payload.GetHoldsRequest) sq.SelectBuilder {
+	if req.Status != "" {
+		q = q.Where(sq.Eq{"h.status": req.Status})
+	}
+
+	if req.MemberID != "" {
+		q = q.Where(sq.Eq{"h.member_id": req.MemberID})
+	}
+
+	if req.BookID != "" {
+		q = q.Where(sq.Eq{"h.book_id": req.BookID})
+	}
+
+	return q
+}
+
+func (r *holdRepository) GetHolds(ctx context.Context, req payload.GetHoldsRequest) ([]model.Hold, error) {
+	q := applyHoldFilters(selectHolds(), req).
+		OrderBy("h.placed_at ASC", "h.id ASC").
+		Limit(uint64(req.Limit)).
+		Offset(uint64(req.Offset)).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var holds []model.Hold
+	err = conn(ctx, r.db).SelectContext(ctx, &holds, query, args...)
+
+	return holds, err
+}
+
+func (r *holdRepository) GetHoldsCount(ctx context.Context, req payload.GetHoldsRequest) (int, error) {
+	q := sq.Select("COUNT(h.id)").
+		From("holds h")
+
+	q = applyHoldFilters(q, req).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = conn(ctx, r.db).GetContext(ctx, &count, query, args...)
+
+	return count, err
+}
+
+func (r *holdRepository) GetHoldByID(ctx context.Context, id string) (*model.Hold, error) {
+	q := selectHolds().
+		Where(sq.Eq{"h.id": id})
+
+	return r.getHold(ctx, q)
+}
+
+func (r *holdRepository) GetActiveHold(ctx context.Context, bookID, memberID string) (*model.Hold, error) {
+	q := selectHolds().
+		Where(sq.Eq{
+			"h.book_id":   bookID,
+			"h.member_id": memberID,
+			"h.status":    []string{model.HoldStatusWaiting, model.HoldStatusReady},
+		})
+
+	return r.getHold(ctx, q)
+}
+
+func (r *holdRepository) AssignNextHold(ctx context.Context, bookID, copyID string, readyAt, expiresAt time.Time) (*model.Hold, error) {
+	// plain FOR UPDATE rather than SKIP LOCKED, the head of the queue must be served first
+	next := sq.Select("id").
+		From("holds").
+		Where(sq.Eq{"book_id": bookID, "status": model.HoldStatusWaiting}).
+		OrderBy("placed_at ASC", "id ASC").
+		Limit(1).
+		Suffix("FOR UPDATE")
+
+	q := sq.Update("holds").
+		Set("status", model.HoldStatusReady).
+		Set("copy_id", copyID).
+		Set("ready_at", readyAt).
+		Set("expires_at", expiresAt).
+		Set("updated_at", time.Now()).
+		Where(sq.Expr("id = (?)", next))
+
+	return r.updateHold(ctx, q)
+}
+
+func (r *holdRepository) CloseHold(ctx context.Context, id string, status string, closedAt time.Time) (*model.Hold, error) {
+	q := sq.Update("holds").
+		Set("status", status).
+		Set("closed_at", closedAt).
+		Set("updated_at", time.Now()).
+		Where(sq.Eq{"id": id, "status": []string{model.HoldStatusWaiting, model.HoldStatusReady}})
+
+	return r.updateHold(ctx, q)
+}
+
+func (r *holdRepository) ExpireHold(ctx context.Context, id string, now time.Time) (*model.Hold, error) {
+	q := sq.Update("holds").
+		Set("status", model.HoldStatusExpired).
+		Set("closed_at", now).
+		Set("updated_at", time.Now()).
+		Where(sq.Eq{"id": id, "status": model.HoldStatusReady}).
+		Where(sq.LtOrEq{"expires_at": now})
+
+	return r.updateHold(ctx, q)
+}
+
+func (r *holdRepository) GetExpiredHolds(ctx context.Context, now time.Time) ([]model.Hold, error) {
+	q := sq.Select(holdColumns...).
+		From("holds").
+		Where(sq.Eq{"status": model.HoldStatusReady}).
+		Where(sq.LtOrEq{"expires_at": now}).
+		OrderBy("expires_at ASC").
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var holds []model.Hold
+	err = conn(ctx, r.db).SelectContext(ctx, &holds, query, args...)
+
+	return holds, err
+}
+
+func (r *holdRepository) getHold(ctx context.Context, q sq.SelectBuilder) (*model.Hold, error) {
+	var hold model.Hold
+
+	query, args, err := q.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = conn(ctx, r.db).GetContext(ctx, &hold, query, args...)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	return &hold, err
+}
+
+func (r *holdRepository) updateHold(ctx context.Context, q sq.UpdateBuilder) (*model.Hold, error) {
+	var hold model.Hold
+
+	query, args, err := q.
+		Suffix("RETURNING " + joinColumns(holdColumns)).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = conn(ctx, r.db).GetContext(ctx, &hold, query, args...)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	return &hold, err
+}
@@ -0,0 +1,258 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"library-backend/internal/model"
+	"library-backend/internal/payload"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+// loanStatusColumn derives the loan status from the return and due timestamps.
+const loanStatusColumn = "CASE WHEN l.returned_at IS NOT NULL THEN 'returned' " +
+	"WHEN l.due_at < NOW() THEN 'overdue' " +
+	"ELSE 'active' END AS status"
+
+type LoanRepository interface {
+	CreateLoan(ctx context.Context, loan model.Loan) error
+	GetLoans(ctx context.Context, req payload.GetLoansRequest) ([]model.Loan, error)
+	GetLoansCount(ctx context.Context, req payload.GetLoansRequest) (int, error)
+	GetLoanByID(ctx context.Context, id string) (*model.Loan, error)
+	ReturnLoan(ctx context.Context, id string, returnedAt time.Time) error
+	GetLoanSummary(ctx context.Context) (model.LoanSummary, error)
+	GetMostBorrowedBooks(ctx context.Context, limit int) ([]model.BookLoanCount, error)
+	GetMonthlyLoanCounts(ctx context.Context, months int) ([]model.MonthlyLoanCount, error)
+}
+
+type loanRepository struct {
+	db *sqlx.DB
+}
+
+func NewLoanRepository(db *sqlx.DB) LoanRepository {
+	return &loanRepository{db: db}
+}
+
+func (r *loanRepository) CreateLoan(ctx context.Context, loan model.Loan) error {
+	q := sq.Insert("loans").
+		Columns("id",
+			"book_id",
+			"copy_id",
+			"member_id",
+			"borrowed_at",
+			"due_at",
+			"updated_at",
+		).
+		Values(loan.ID, loan.BookID, loan.CopyID, loan.MemberID, loan.BorrowedAt, loan.DueAt, "NOW()").
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+
+	return err
+}
+
+func selectLoans() sq.SelectBuilder {
+	return sq.Select("l.id",
+		"l.book_id",
+		"l.copy_id",
+		"c.barcode",
+		"l.member_id",
+		"l.borrowed_at",
+		"l.due_at",
+		"l.returned_at",
+		loanStatusColumn,
+		"b.title AS book_title",
+		"m.name AS member_name",
+		"l.created_at",
+		"l.updated_at",
+	).
+		From("loans l").
+		Join("books b ON b.id = l.book_id").
+		Join("book_copies c ON c.id = l.copy_id").
+		Join("members m ON m.id = l.member_id")
+}
+
+// applyLoanFilters narrows a loans query down to the rows matching the request filters,
+// it is shared by GetLoans and GetLoansCount so both always describe the same result set.
+func applyLoanFilters(q sq.SelectBuilder, req payload.GetLoansRequest) sq.SelectBuilder {
+	switch req.Status {
+	case model.LoanStatusActive:
+		q = q.Where("l.returned_at IS NULL AND l.due_at >= NOW()")
+	case model.LoanStatusOverdue:
+		q = q.Where("l.returned_at IS NULL AND l.due_at < NOW()")
+	case model.LoanStatusReturned:
+		q = q.Where("l.returned_at IS NOT NULL")
+	}
+
+	if req.MemberID != "" {
+		q = q.Where(sq.Eq{"l.member_id": req.MemberID})
+	}
+
+	if req.BookID != "" {
+		q = q.Where(sq.Eq{"l.book_id": req.BookID})
+	}
+
+	return q
+}
+
+func (r *loanRepository) GetLoans(ctx context.Context, req payload.GetLoansRequest) ([]model.Loan, error) {
+	q := applyLoanFilters(selectLoans(), req).
+		OrderBy("l.borrowed_at DESC").
+		Limit(uint64(req.Limit)).
+		Offset(uint64(req.Offset)).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var loans []model.Loan
+	err = conn(ctx, r.db).SelectContext(ctx, &loans, query, args...)
+
+	return loans, err
+}
+
+func (r *loanRepository) GetLoansCount(ctx context.Context, req payload.GetLoansRequest) (int, error) {
+	q := sq.Select("COUNT(l.id)").
+		From("loans l")
+
+	q = applyLoanFilters(q, req).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = conn(ctx, r.db).GetContext(ctx, &count, query, args...)
+
+	return count, err
+}
+
+func (r *loanRepository) GetLoanByID(ctx context.Context, id string) (*model.Loan, error) {
+	var loan model.Loan
+
+	q := selectLoans().
+		Where(sq.Eq{"l.id": id}).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = conn(ctx, r.db).GetContext(ctx, &loan, query, args...)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	return &loan, err
+}
+
+func (r *loanRepository) ReturnLoan(ctx context.Context, id string, returnedAt time.Time) error {
+	q := sq.Update("loans").
+		Set("returned_at", returnedAt).
+		Set("updated_at", time.Now()).
+		Where(sq.Eq{"id": id, "returned_at": nil}).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return err
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *loanRepository) GetLoanSummary(ctx context.Context) (model.LoanSummary, error) {
+	var summary model.LoanSummary
+
+	q := sq.Select("COUNT(id) AS total_loans",
+		"COUNT(id) FILTER (WHERE returned_at IS NULL AND due_at >= NOW()) AS active_loans",
+		"COUNT(id) FILTER (WHERE returned_at IS NULL AND due_at < NOW()) AS overdue_loans",
+		"COUNT(id) FILTER (WHERE returned_at IS NOT NULL) AS returned_loans",
+	).
+		From("loans").
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return summary, err
+	}
+
+	err = conn(ctx, r.db).GetContext(ctx, &summary, query, args...)
+
+	return summary, err
+}
+
+func (r *loanRepository) GetMostBorrowedBooks(ctx context.Context, limit int) ([]model.BookLoanCount, error) {
+	q := sq.Select("b.id AS book_id",
+		"b.title",
+		"b.author",
+		"COUNT(l.id) AS loan_count",
+	).
+		From("loans l").
+		Join("books b ON b.id = l.book_id").
+		GroupBy("b.id", "b.title", "b.author").
+		OrderBy("loan_count DESC", "b.title ASC").
+		Limit(uint64(limit)).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var counts []model.BookLoanCount
+	err = conn(ctx, r.db).SelectContext(ctx, &counts, query, args...)
+
+	return counts, err
+}
+
+// GetMonthlyLoanCounts returns, for each of the last n months, how many loans were started
+// in that month and how many of those have been returned since.
+func (r *loanRepository) GetMonthlyLoanCounts(ctx context.Context, months int) ([]model.MonthlyLoanCount, error) {
+	q := sq.Select("TO_CHAR(DATE_TRUNC('month', borrowed_at), 'YYYY-MM') AS month",
+		"COUNT(id) AS borrowed",
+		"COUNT(returned_at) AS returned",
+	).
+		From("loans").
+		Where("borrowed_at >= DATE_TRUNC('month', NOW()) - MAKE_INTERVAL(months => ?)", months-1).
+		GroupBy("month").
+		OrderBy("month ASC").
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var counts []model.MonthlyLoanCount
+	err = conn(ctx, r.db).SelectContext(ctx, &counts, query, args...)
+
+	return counts, err
+}
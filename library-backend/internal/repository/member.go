@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"library-backend/internal/model"
+	"library-backend/internal/payload"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+type MemberRepository interface {
+	CreateMember(ctx context.Context, member model.Member) error
+	GetMembers(ctx context.Context, req payload.GetMembersRequest) ([]model.Member, error)
+	GetMembersCount(ctx context.Context, req payload.GetMembersRequest) (int, error)
+	GetMemberByID(ctx context.Context, id string) (*model.Member, error)
+	UpdateMember(ctx context.Context, id string, updates map[string]any) error
+	DeleteMember(ctx context.Context, id string) error
+}
+
+type memberRepository struct {
+	db *sqlx.DB
+}
+
+func NewMemberRepository(db *sqlx.DB) MemberRepository {
+	return &memberRepository{db: db}
+}
+
+func (r *memberRepository) CreateMember(ctx context.Context, member model.Member) error {
+	q := sq.Insert("members").
+		Columns("id",
+			"name",
+			"email",
+			"phone",
+			"address",
+			"status",
+			"updated_at",
+		).
+		Values(member.ID, member.Name, member.Email, member.Phone, member.Address, member.Status, "NOW()").
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, query, args...)
+
+	return err
+}
+
+// applyMemberFilters narrows a members query down to the live rows matching the request filters,
+// it is shared by GetMembers and GetMembersCount so both always describe the same result set.
+func applyMemberFilters(q sq.SelectBuilder, req payload.GetMembersRequest) sq.SelectBuilder {
+	q = q.Where(sq.Eq{"deleted_at": nil})
+
+	if req.Search != "" {
+		q = q.Where(sq.Or{
+			sq.ILike{"name": "%" + req.Search + "%"},
+			sq.ILike{"email": "%" + req.Search + "%"},
+		})
+	}
+
+	if req.Status != "" {
+		q = q.Where(sq.Eq{"status": req.Status})
+	}
+
+	return q
+}
+
+func (r *memberRepository) GetMembers(ctx context.Context, req payload.GetMembersRequest) ([]model.Member, error) {
+	q := sq.Select("id",
+		"name",
+		"email",
+		"phone",
+		"address",
+		"status",
+		"created_at",
+		"updated_at",
+	).
+		From("members")
+
+	q = applyMemberFilters(q, req).
+		OrderBy("updated_at DESC").
+		Limit(uint64(req.Limit)).
+		Offset(uint64(req.Offset)).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var members []model.Member
+	err = r.db.SelectContext(ctx, &members, query, args...)
+
+	return members, err
+}
+
+func (r *memberRepository) GetMembersCount(ctx context.Context, req payload.GetMembersRequest) (int, error) {
+	q := sq.Select("COUNT(id)").
+		From("members")
+
+	q = applyMemberFilters(q, req).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = r.db.GetContext(ctx, &count, query, args...)
+
+	return count, err
+}
+
+func (r *memberRepository) GetMemberByID(ctx context.Context, id string) (*model.Member, error) {
+	var member model.Member
+
+	q := sq.Select("id",
+		"name",
+		"email",
+		"phone",
+		"address",
+		"status",
+		"created_at",
+		"updated_at",
+	).
+		From("members").
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.GetContext(ctx, &member, query, args...)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	return &member, err
+}
+
+func (r *memberRepository) UpdateMember(ctx context.Context, id string, updates map[string]any) error {
+	if len(updates) == 0 {
+		return errors.New("no fields to update")
+	}
+
+	q := sq.Update("members").
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
+		PlaceholderFormat(sq.Dollar)
+
+	for field, value := range updates {
+		q = q.Set(field, value)
+	}
+
+	q = q.Set("updated_at", time.Now())
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *memberRepository) DeleteMember(ctx context.Context, id string) error {
+	q := sq.Update("members").
+		Set("deleted_at", time.Now()).
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,168 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/book_copy.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	model "library-backend/internal/model"
+	payload "library-backend/internal/payload"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockBookCopyRepository is a mock of BookCopyRepository interface.
+type MockBookCopyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockBookCopyRepositoryMockRecorder
+}
+
+// MockBookCopyRepositoryMockRecorder is the mock recorder for MockBookCopyRepository.
+type MockBookCopyRepositoryMockRecorder struct {
+	mock *MockBookCopyRepository
+}
+
+// NewMockBookCopyRepository creates a new mock instance.
+func NewMockBookCopyRepository(ctrl *gomock.Controller) *MockBookCopyRepository {
+	mock := &MockBookCopyRepository{ctrl: ctrl}
+	mock.recorder = &MockBookCopyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBookCopyRepository) EXPECT() *MockBookCopyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimAvailableCopy mocks base method.
+func (m *MockBookCopyRepository) ClaimAvailableCopy(ctx context.Context, bookID string) (*model.BookCopy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimAvailableCopy", ctx, bookID)
+	ret0, _ := ret[0].(*model.BookCopy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimAvailableCopy indicates an expected call of ClaimAvailableCopy.
+func (mr *MockBookCopyRepositoryMockRecorder) ClaimAvailableCopy(ctx, bookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimAvailableCopy", reflect.TypeOf((*MockBookCopyRepository)(nil).ClaimAvailableCopy), ctx, bookID)
+}
+
+// ClaimCopy mocks base method.
+func (m *MockBookCopyRepository) ClaimCopy(ctx context.Context, id string) (*model.BookCopy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimCopy", ctx, id)
+	ret0, _ := ret[0].(*model.BookCopy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimCopy indicates an expected call of ClaimCopy.
+func (mr *MockBookCopyRepositoryMockRecorder) ClaimCopy(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimCopy", reflect.TypeOf((*MockBookCopyRepository)(nil).ClaimCopy), ctx, id)
+}
+
+// ClaimHeldCopy mocks base method.
+func (m *MockBookCopyRepository) ClaimHeldCopy(ctx context.Context, id string) (*model.BookCopy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimHeldCopy", ctx, id)
+	ret0, _ := ret[0].(*model.BookCopy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimHeldCopy indicates an expected call of ClaimHeldCopy.
+func (mr *MockBookCopyRepositoryMockRecorder) ClaimHeldCopy(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimHeldCopy", reflect.TypeOf((*MockBookCopyRepository)(nil).ClaimHeldCopy), ctx, id)
+}
+
+// CreateBookCopy mocks base method.
+func (m *MockBookCopyRepository) CreateBookCopy(ctx context.Context, bookCopy model.BookCopy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBookCopy", ctx, bookCopy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateBookCopy indicates an expected call of CreateBookCopy.
+func (mr *MockBookCopyRepositoryMockRecorder) CreateBookCopy(ctx, bookCopy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBookCopy", reflect.TypeOf((*MockBookCopyRepository)(nil).CreateBookCopy), ctx, bookCopy)
+}
+
+// DeleteBookCopy mocks base method.
+func (m *MockBookCopyRepository) DeleteBookCopy(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBookCopy", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBookCopy indicates an expected call of DeleteBookCopy.
+func (mr *MockBookCopyRepositoryMockRecorder) DeleteBookCopy(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBookCopy", reflect.TypeOf((*MockBookCopyRepository)(nil).DeleteBookCopy), ctx, id)
+}
+
+// GetBookCopies mocks base method.
+func (m *MockBookCopyRepository) GetBookCopies(ctx context.Context, req payload.GetBookCopiesRequest) ([]model.BookCopy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookCopies", ctx, req)
+	ret0, _ := ret[0].([]model.BookCopy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookCopies indicates an expected call of GetBookCopies.
+func (mr *MockBookCopyRepositoryMockRecorder) GetBookCopies(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookCopies", reflect.TypeOf((*MockBookCopyRepository)(nil).GetBookCopies), ctx, req)
+}
+
+// GetBookCopyByID mocks base method.
+func (m *MockBookCopyRepository) GetBookCopyByID(ctx context.Context, id string) (*model.BookCopy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookCopyByID", ctx, id)
+	ret0, _ := ret[0].(*model.BookCopy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookCopyByID indicates an expected call of GetBookCopyByID.
+func (mr *MockBookCopyRepositoryMockRecorder) GetBookCopyByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookCopyByID", reflect.TypeOf((*MockBookCopyRepository)(nil).GetBookCopyByID), ctx, id)
+}
+
+// UpdateBookCopy mocks base method.
+func (m *MockBookCopyRepository) UpdateBookCopy(ctx context.Context, id string, updates map[string]any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBookCopy", ctx, id, updates)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBookCopy indicates an expected call of UpdateBookCopy.
+func (mr *MockBookCopyRepositoryMockRecorder) UpdateBookCopy(ctx, id, updates interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBookCopy", reflect.TypeOf((*MockBookCopyRepository)(nil).UpdateBookCopy), ctx, id, updates)
+}
+
+// UpdateBookCopyStatus mocks base method.
+func (m *MockBookCopyRepository) UpdateBookCopyStatus(ctx context.Context, id, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBookCopyStatus", ctx, id, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBookCopyStatus indicates an expected call of UpdateBookCopyStatus.
+func (mr *MockBookCopyRepositoryMockRecorder) UpdateBookCopyStatus(ctx, id, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBookCopyStatus", reflect.TypeOf((*MockBookCopyRepository)(nil).UpdateBookCopyStatus), ctx, id, status)
+}
@@ -8,6 +8,7 @@ import (
 	context "context"
 	model "library-backend/internal/model"
 	payload "library-backend/internal/payload"
+	cql "library-backend/pkg/cql"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -64,6 +65,35 @@ func (mr *MockBookRepositoryMockRecorder) DeleteBook(ctx, id interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBook", reflect.TypeOf((*MockBookRepository)(nil).DeleteBook), ctx, id)
 }
 
+// ExportBooks mocks base method.
+func (m *MockBookRepository) ExportBooks(ctx context.Context, req payload.GetBooksRequest, fn func(model.Book) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportBooks", ctx, req, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportBooks indicates an expected call of ExportBooks.
+func (mr *MockBookRepositoryMockRecorder) ExportBooks(ctx, req, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportBooks", reflect.TypeOf((*MockBookRepository)(nil).ExportBooks), ctx, req, fn)
+}
+
+// GetBookByCopyBarcode mocks base method.
+func (m *MockBookRepository) GetBookByCopyBarcode(ctx context.Context, barcode string) (*model.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookByCopyBarcode", ctx, barcode)
+	ret0, _ := ret[0].(*model.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookByCopyBarcode indicates an expected call of GetBookByCopyBarcode.
+func (mr *MockBookRepositoryMockRecorder) GetBookByCopyBarcode(ctx, barcode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookByCopyBarcode", reflect.TypeOf((*MockBookRepository)(nil).GetBookByCopyBarcode), ctx, barcode)
+}
+
 // GetBookByID mocks base method.
 func (m *MockBookRepository) GetBookByID(ctx context.Context, id string) (*model.Book, error) {
 	m.ctrl.T.Helper()
@@ -79,13 +109,44 @@ func (mr *MockBookRepositoryMockRecorder) GetBookByID(ctx, id interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookByID", reflect.TypeOf((*MockBookRepository)(nil).GetBookByID), ctx, id)
 }
 
+// GetBookByISBN mocks base method.
+func (m *MockBookRepository) GetBookByISBN(ctx context.Context, isbn13 string) (*model.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookByISBN", ctx, isbn13)
+	ret0, _ := ret[0].(*model.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookByISBN indicates an expected call of GetBookByISBN.
+func (mr *MockBookRepositoryMockRecorder) GetBookByISBN(ctx, isbn13 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookByISBN", reflect.TypeOf((*MockBookRepository)(nil).GetBookByISBN), ctx, isbn13)
+}
+
+// GetBookFacets mocks base method.
+func (m *MockBookRepository) GetBookFacets(ctx context.Context, req payload.GetBooksRequest) (map[string][]payload.BookFacetBucket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookFacets", ctx, req)
+	ret0, _ := ret[0].(map[string][]payload.BookFacetBucket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookFacets indicates an expected call of GetBookFacets.
+func (mr *MockBookRepositoryMockRecorder) GetBookFacets(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookFacets", reflect.TypeOf((*MockBookRepository)(nil).GetBookFacets), ctx, req)
+}
+
 // GetBooks mocks base method.
-func (m *MockBookRepository) GetBooks(ctx context.Context, req payload.GetBooksRequest) ([]model.Book, error) {
+func (m *MockBookRepository) GetBooks(ctx context.Context, req payload.GetBooksRequest) ([]model.Book, int, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetBooks", ctx, req)
 	ret0, _ := ret[0].([]model.Book)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // GetBooks indicates an expected call of GetBooks.
@@ -94,19 +155,167 @@ func (mr *MockBookRepositoryMockRecorder) GetBooks(ctx, req interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBooks", reflect.TypeOf((*MockBookRepository)(nil).GetBooks), ctx, req)
 }
 
+// GetBooksByIDs mocks base method.
+func (m *MockBookRepository) GetBooksByIDs(ctx context.Context, ids []string) ([]model.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBooksByIDs", ctx, ids)
+	ret0, _ := ret[0].([]model.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBooksByIDs indicates an expected call of GetBooksByIDs.
+func (mr *MockBookRepositoryMockRecorder) GetBooksByIDs(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBooksByIDs", reflect.TypeOf((*MockBookRepository)(nil).GetBooksByIDs), ctx, ids)
+}
+
 // GetBooksCount mocks base method.
-func (m *MockBookRepository) GetBooksCount(ctx context.Context) (int, error) {
+func (m *MockBookRepository) GetBooksCount(ctx context.Context, req payload.GetBooksRequest) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetBooksCount", ctx)
+	ret := m.ctrl.Call(m, "GetBooksCount", ctx, req)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetBooksCount indicates an expected call of GetBooksCount.
-func (mr *MockBookRepositoryMockRecorder) GetBooksCount(ctx interface{}) *gomock.Call {
+func (mr *MockBookRepositoryMockRecorder) GetBooksCount(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBooksCount", reflect.TypeOf((*MockBookRepository)(nil).GetBooksCount), ctx, req)
+}
+
+// GetDeletedBookByID mocks base method.
+func (m *MockBookRepository) GetDeletedBookByID(ctx context.Context, id string) (*model.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedBookByID", ctx, id)
+	ret0, _ := ret[0].(*model.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedBookByID indicates an expected call of GetDeletedBookByID.
+func (mr *MockBookRepositoryMockRecorder) GetDeletedBookByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedBookByID", reflect.TypeOf((*MockBookRepository)(nil).GetDeletedBookByID), ctx, id)
+}
+
+// GetDeletedBookByISBN mocks base method.
+func (m *MockBookRepository) GetDeletedBookByISBN(ctx context.Context, isbn13 string) (*model.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedBookByISBN", ctx, isbn13)
+	ret0, _ := ret[0].(*model.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedBookByISBN indicates an expected call of GetDeletedBookByISBN.
+func (mr *MockBookRepositoryMockRecorder) GetDeletedBookByISBN(ctx, isbn13 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedBookByISBN", reflect.TypeOf((*MockBookRepository)(nil).GetDeletedBookByISBN), ctx, isbn13)
+}
+
+// GetDeletedBooks mocks base method.
+func (m *MockBookRepository) GetDeletedBooks(ctx context.Context, req payload.GetDeletedBooksRequest) ([]model.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedBooks", ctx, req)
+	ret0, _ := ret[0].([]model.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedBooks indicates an expected call of GetDeletedBooks.
+func (mr *MockBookRepositoryMockRecorder) GetDeletedBooks(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedBooks", reflect.TypeOf((*MockBookRepository)(nil).GetDeletedBooks), ctx, req)
+}
+
+// GetDeletedBooksCount mocks base method.
+func (m *MockBookRepository) GetDeletedBooksCount(ctx context.Context, req payload.GetDeletedBooksRequest) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedBooksCount", ctx, req)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedBooksCount indicates an expected call of GetDeletedBooksCount.
+func (mr *MockBookRepositoryMockRecorder) GetDeletedBooksCount(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedBooksCount", reflect.TypeOf((*MockBookRepository)(nil).GetDeletedBooksCount), ctx, req)
+}
+
+// PurgeBook mocks base method.
+func (m *MockBookRepository) PurgeBook(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeBook", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeBook indicates an expected call of PurgeBook.
+func (mr *MockBookRepositoryMockRecorder) PurgeBook(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeBook", reflect.TypeOf((*MockBookRepository)(nil).PurgeBook), ctx, id)
+}
+
+// RestoreBook mocks base method.
+func (m *MockBookRepository) RestoreBook(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreBook", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreBook indicates an expected call of RestoreBook.
+func (mr *MockBookRepositoryMockRecorder) RestoreBook(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreBook", reflect.TypeOf((*MockBookRepository)(nil).RestoreBook), ctx, id)
+}
+
+// ScanBooks mocks base method.
+func (m *MockBookRepository) ScanBooks(ctx context.Context, index, term string, before, after int) ([]payload.BookIndexTerm, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScanBooks", ctx, index, term, before, after)
+	ret0, _ := ret[0].([]payload.BookIndexTerm)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScanBooks indicates an expected call of ScanBooks.
+func (mr *MockBookRepositoryMockRecorder) ScanBooks(ctx, index, term, before, after interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScanBooks", reflect.TypeOf((*MockBookRepository)(nil).ScanBooks), ctx, index, term, before, after)
+}
+
+// SearchBooks mocks base method.
+func (m *MockBookRepository) SearchBooks(ctx context.Context, search *cql.Query, limit, offset int) ([]model.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchBooks", ctx, search, limit, offset)
+	ret0, _ := ret[0].([]model.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchBooks indicates an expected call of SearchBooks.
+func (mr *MockBookRepositoryMockRecorder) SearchBooks(ctx, search, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchBooks", reflect.TypeOf((*MockBookRepository)(nil).SearchBooks), ctx, search, limit, offset)
+}
+
+// SearchBooksCount mocks base method.
+func (m *MockBookRepository) SearchBooksCount(ctx context.Context, search *cql.Query) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchBooksCount", ctx, search)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchBooksCount indicates an expected call of SearchBooksCount.
+func (mr *MockBookRepositoryMockRecorder) SearchBooksCount(ctx, search interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBooksCount", reflect.TypeOf((*MockBookRepository)(nil).GetBooksCount), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchBooksCount", reflect.TypeOf((*MockBookRepository)(nil).SearchBooksCount), ctx, search)
 }
 
 // UpdateBook mocks base method.
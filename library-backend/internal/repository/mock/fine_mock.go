@@ -0,0 +1,140 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/fine.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	model "library-backend/internal/model"
+	payload "library-backend/internal/payload"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockFineRepository is a mock of FineRepository interface.
+type MockFineRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFineRepositoryMockRecorder
+}
+
+// MockFineRepositoryMockRecorder is the mock recorder for MockFineRepository.
+type MockFineRepositoryMockRecorder struct {
+	mock *MockFineRepository
+}
+
+// NewMockFineRepository creates a new mock instance.
+func NewMockFineRepository(ctrl *gomock.Controller) *MockFineRepository {
+	mock := &MockFineRepository{ctrl: ctrl}
+	mock.recorder = &MockFineRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFineRepository) EXPECT() *MockFineRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateFineEntry mocks base method.
+func (m *MockFineRepository) CreateFineEntry(ctx context.Context, entry model.FineEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFineEntry", ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateFineEntry indicates an expected call of CreateFineEntry.
+func (mr *MockFineRepositoryMockRecorder) CreateFineEntry(ctx, entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFineEntry", reflect.TypeOf((*MockFineRepository)(nil).CreateFineEntry), ctx, entry)
+}
+
+// GetFineBalances mocks base method.
+func (m *MockFineRepository) GetFineBalances(ctx context.Context, req payload.GetFineBalancesRequest) ([]model.FineBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFineBalances", ctx, req)
+	ret0, _ := ret[0].([]model.FineBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFineBalances indicates an expected call of GetFineBalances.
+func (mr *MockFineRepositoryMockRecorder) GetFineBalances(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFineBalances", reflect.TypeOf((*MockFineRepository)(nil).GetFineBalances), ctx, req)
+}
+
+// GetFineBalancesCount mocks base method.
+func (m *MockFineRepository) GetFineBalancesCount(ctx context.Context, req payload.GetFineBalancesRequest) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFineBalancesCount", ctx, req)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFineBalancesCount indicates an expected call of GetFineBalancesCount.
+func (mr *MockFineRepositoryMockRecorder) GetFineBalancesCount(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFineBalancesCount", reflect.TypeOf((*MockFineRepository)(nil).GetFineBalancesCount), ctx, req)
+}
+
+// GetFineEntries mocks base method.
+func (m *MockFineRepository) GetFineEntries(ctx context.Context, req payload.GetMemberFinesRequest) ([]model.FineEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFineEntries", ctx, req)
+	ret0, _ := ret[0].([]model.FineEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFineEntries indicates an expected call of GetFineEntries.
+func (mr *MockFineRepositoryMockRecorder) GetFineEntries(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFineEntries", reflect.TypeOf((*MockFineRepository)(nil).GetFineEntries), ctx, req)
+}
+
+// GetFineEntriesCount mocks base method.
+func (m *MockFineRepository) GetFineEntriesCount(ctx context.Context, req payload.GetMemberFinesRequest) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFineEntriesCount", ctx, req)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFineEntriesCount indicates an expected call of GetFineEntriesCount.
+func (mr *MockFineRepositoryMockRecorder) GetFineEntriesCount(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFineEntriesCount", reflect.TypeOf((*MockFineRepository)(nil).GetFineEntriesCount), ctx, req)
+}
+
+// GetMemberFineBalance mocks base method.
+func (m *MockFineRepository) GetMemberFineBalance(ctx context.Context, memberID string) (model.FineBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMemberFineBalance", ctx, memberID)
+	ret0, _ := ret[0].(model.FineBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMemberFineBalance indicates an expected call of GetMemberFineBalance.
+func (mr *MockFineRepositoryMockRecorder) GetMemberFineBalance(ctx, memberID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMemberFineBalance", reflect.TypeOf((*MockFineRepository)(nil).GetMemberFineBalance), ctx, memberID)
+}
+
+// LockMemberFines mocks base method.
+func (m *MockFineRepository) LockMemberFines(ctx context.Context, memberID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockMemberFines", ctx, memberID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockMemberFines indicates an expected call of LockMemberFines.
+func (mr *MockFineRepositoryMockRecorder) LockMemberFines(ctx, memberID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockMemberFines", reflect.TypeOf((*MockFineRepository)(nil).LockMemberFines), ctx, memberID)
+}
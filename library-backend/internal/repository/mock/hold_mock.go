@@ -0,0 +1,172 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/hold.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	model "library-backend/internal/model"
+	payload "library-backend/internal/payload"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockHoldRepository is a mock of HoldRepository interface.
+type MockHoldRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockHoldRepositoryMockRecorder
+}
+
+// MockHoldRepositoryMockRecorder is the mock recorder for MockHoldRepository.
+type MockHoldRepositoryMockRecorder struct {
+	mock *MockHoldRepository
+}
+
+// NewMockHoldRepository creates a new mock instance.
+func NewMockHoldRepository(ctrl *gomock.Controller) *MockHoldRepository {
+	mock := &MockHoldRepository{ctrl: ctrl}
+	mock.recorder = &MockHoldRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHoldRepository) EXPECT() *MockHoldRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AssignNextHold mocks base method.
+func (m *MockHoldRepository) AssignNextHold(ctx context.Context, bookID, copyID string, readyAt, expiresAt time.Time) (*model.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignNextHold", ctx, bookID, copyID, readyAt, expiresAt)
+	ret0, _ := ret[0].(*model.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssignNextHold indicates an expected call of AssignNextHold.
+func (mr *MockHoldRepositoryMockRecorder) AssignNextHold(ctx, bookID, copyID, readyAt, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignNextHold", reflect.TypeOf((*MockHoldRepository)(nil).AssignNextHold), ctx, bookID, copyID, readyAt, expiresAt)
+}
+
+// CloseHold mocks base method.
+func (m *MockHoldRepository) CloseHold(ctx context.Context, id, status string, closedAt time.Time) (*model.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseHold", ctx, id, status, closedAt)
+	ret0, _ := ret[0].(*model.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseHold indicates an expected call of CloseHold.
+func (mr *MockHoldRepositoryMockRecorder) CloseHold(ctx, id, status, closedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseHold", reflect.TypeOf((*MockHoldRepository)(nil).CloseHold), ctx, id, status, closedAt)
+}
+
+// CreateHold mocks base method.
+func (m *MockHoldRepository) CreateHold(ctx context.Context, hold model.Hold) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHold", ctx, hold)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateHold indicates an expected call of CreateHold.
+func (mr *MockHoldRepositoryMockRecorder) CreateHold(ctx, hold interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHold", reflect.TypeOf((*MockHoldRepository)(nil).CreateHold), ctx, hold)
+}
+
+// ExpireHold mocks base method.
+func (m *MockHoldRepository) ExpireHold(ctx context.Context, id string, now time.Time) (*model.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireHold", ctx, id, now)
+	ret0, _ := ret[0].(*model.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireHold indicates an expected call of ExpireHold.
+func (mr *MockHoldRepositoryMockRecorder) ExpireHold(ctx, id, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireHold", reflect.TypeOf((*MockHoldRepository)(nil).ExpireHold), ctx, id, now)
+}
+
+// GetActiveHold mocks base method.
+func (m *MockHoldRepository) GetActiveHold(ctx context.Context, bookID, memberID string) (*model.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveHold", ctx, bookID, memberID)
+	ret0, _ := ret[0].(*model.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveHold indicates an expected call of GetActiveHold.
+func (mr *MockHoldRepositoryMockRecorder) GetActiveHold(ctx, bookID, memberID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveHold", reflect.TypeOf((*MockHoldRepository)(nil).GetActiveHold), ctx, bookID, memberID)
+}
+
+// GetExpiredHolds mocks base method.
+func (m *MockHoldRepository) GetExpiredHolds(ctx context.Context, now time.Time) ([]model.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpiredHolds", ctx, now)
+	ret0, _ := ret[0].([]model.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpiredHolds indicates an expected call of GetExpiredHolds.
+func (mr *MockHoldRepositoryMockRecorder) GetExpiredHolds(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiredHolds", reflect.TypeOf((*MockHoldRepository)(nil).GetExpiredHolds), ctx, now)
+}
+
+// GetHoldByID mocks base method.
+func (m *MockHoldRepository) GetHoldByID(ctx context.Context, id string) (*model.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHoldByID", ctx, id)
+	ret0, _ := ret[0].(*model.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHoldByID indicates an expected call of GetHoldByID.
+func (mr *MockHoldRepositoryMockRecorder) GetHoldByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHoldByID", reflect.TypeOf((*MockHoldRepository)(nil).GetHoldByID), ctx, id)
+}
+
+// GetHolds mocks base method.
+func (m *MockHoldRepository) GetHolds(ctx context.Context, req payload.GetHoldsRequest) ([]model.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHolds", ctx, req)
+	ret0, _ := ret[0].([]model.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHolds indicates an expected call of GetHolds.
+func (mr *MockHoldRepositoryMockRecorder) GetHolds(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHolds", reflect.TypeOf((*MockHoldRepository)(nil).GetHolds), ctx, req)
+}
+
+// GetHoldsCount mocks base method.
+func (m *MockHoldRepository) GetHoldsCount(ctx context.Context, req payload.GetHoldsRequest) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHoldsCount", ctx, req)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHoldsCount indicates an expected call of GetHoldsCount.
+func (mr *MockHoldRepositoryMockRecorder) GetHoldsCount(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHoldsCount", reflect.TypeOf((*MockHoldRepository)(nil).GetHoldsCount), ctx, req)
+}
@@ -0,0 +1,156 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/repository/loan.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	model "library-backend/internal/model"
+	payload "library-backend/internal/payload"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockLoanRepository is a mock of LoanRepository interface.
+type MockLoanRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoanRepositoryMockRecorder
+}
+
+// MockLoanRepositoryMockRecorder is the mock recorder for MockLoanRepository.
+type MockLoanRepositoryMockRecorder struct {
+	mock *MockLoanRepository
+}
+
+// NewMockLoanRepository creates a new mock instance.
+func NewMockLoanRepository(ctrl *gomock.Controller) *MockLoanRepository {
+	mock := &MockLoanRepository{ctrl: ctrl}
+	mock.recorder = &MockLoanRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoanRepository) EXPECT() *MockLoanRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateLoan mocks base method.
+func (m *MockLoanRepository) CreateLoan(ctx context.Context, loan model.Loan) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLoan", ctx, loan)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateLoan indicates an expected call of CreateLoan.
+func (mr *MockLoanRepositoryMockRecorder) CreateLoan(ctx, loan interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLoan", reflect.TypeOf((*MockLoanRepository)(nil).CreateLoan), ctx, loan)
+}
+
+// GetLoanByID mocks base method.
+func (m *MockLoanRepository) GetLoanByID(ctx context.Context, id string) (*model.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoanByID", ctx, id)
+	ret0, _ := ret[0].(*model.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoanByID indicates an expected call of GetLoanByID.
+func (mr *MockLoanRepositoryMockRecorder) GetLoanByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoanByID", reflect.TypeOf((*MockLoanRepository)(nil).GetLoanByID), ctx, id)
+}
+
+// GetLoanSummary mocks base method.
+func (m *MockLoanRepository) GetLoanSummary(ctx context.Context) (model.LoanSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoanSummary", ctx)
+	ret0, _ := ret[0].(model.LoanSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoanSummary indicates an expected call of GetLoanSummary.
+func (mr *MockLoanRepositoryMockRecorder) GetLoanSummary(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoanSummary", reflect.TypeOf((*MockLoanRepository)(nil).GetLoanSummary), ctx)
+}
+
+// GetLoans mocks base method.
+func (m *MockLoanRepository) GetLoans(ctx context.Context, req payload.GetLoansRequest) ([]model.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoans", ctx, req)
+	ret0, _ := ret[0].([]model.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoans indicates an expected call of GetLoans.
+func (mr *MockLoanRepositoryMockRecorder) GetLoans(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoans", reflect.TypeOf((*MockLoanRepository)(nil).GetLoans), ctx, req)
+}
+
+// GetLoansCount mocks base method.
+func (m *MockLoanRepository) GetLoansCount(ctx context.Context, req payload.GetLoansRequest) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoansCount", ctx, req)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoansCount indicates an expected call of GetLoansCount.
+func (mr *MockLoanRepositoryMockRecorder) GetLoansCount(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoansCount", reflect.TypeOf((*MockLoanRepository)(nil).GetLoansCount), ctx, req)
+}
+
+// GetMonthlyLoanCounts mocks base method.
+func (m *MockLoanRepository) GetMonthlyLoanCounts(ctx context.Context, months int) ([]model.MonthlyLoanCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMonthlyLoanCounts", ctx, months)
+	ret0, _ := ret[0].([]model.MonthlyLoanCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMonthlyLoanCounts indicates an expected call of GetMonthlyLoanCounts.
+func (mr *MockLoanRepositoryMockRecorder) GetMonthlyLoanCounts(ctx, months interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMonthlyLoanCounts", reflect.TypeOf((*MockLoanRepository)(nil).GetMonthlyLoanCounts), ctx, months)
+}
+
+// GetMostBorrowedBooks mocks base method.
+func (m *MockLoanRepository) GetMostBorrowedBooks(ctx context.Context, limit int) ([]model.BookLoanCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMostBorrowedBooks", ctx, limit)
+	ret0, _ := ret[0].([]model.BookLoanCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMostBorrowedBooks indicates an expected call of GetMostBorrowedBooks.
+func (mr *MockLoanRepositoryMockRecorder) GetMostBorrowedBooks(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMostBorrowedBooks", reflect.TypeOf((*MockLoanRepository)(nil).GetMostBorrowedBooks), ctx, limit)
+}
+
+// ReturnLoan mocks base method.
+func (m *MockLoanRepository) ReturnLoan(ctx context.Context, id string, returnedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReturnLoan", ctx, id, returnedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReturnLoan indicates an expected call of ReturnLoan.
+func (mr *MockLoanRepositoryMockRecorder) ReturnLoan(ctx, id, returnedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReturnLoan", reflect.TypeOf((*MockLoanRepository)(nil).ReturnLoan), ctx, id, returnedAt)
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"library-backend/internal/model"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+var refreshTokenColumns = []string{
+	"id",
+	"user_id",
+	"token_hash",
+	"expires_at",
+	"revoked_at",
+	"created_at",
+}
+
+type RefreshTokenRepository interface {
+	CreateRefreshToken(ctx context.Context, token model.RefreshToken) error
+	RevokeRefreshToken(ctx context.Context, tokenHash string, revokedAt time.Time) (*model.RefreshToken, error)
+	RevokeUserRefreshTokens(ctx context.Context, userID string, revokedAt time.Time) error
+}
+
+type refreshTokenRepository struct {
+	db *sqlx.DB
+}
+
+func NewRefreshTokenRepository(db *sqlx.DB) RefreshTokenRepository {
+	return &refreshTokenRepository{db: db}
+}
+
+func (r *refreshTokenRepository) CreateRefreshToken(ctx context.Context, token model.RefreshToken) error {
+	q := sq.Insert("refresh_tokens").
+		Columns("id",
+			"user_id",
+			"token_hash",
+			"expires_at",
+		).
+		Values(token.ID, token.UserID, token.TokenHash, token.ExpiresAt).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+
+	return err
+}
+
+// RevokeRefreshToken consumes a refresh token that is neither revoked nor expired and returns it,
+// or nil when there is no such token. Revoking in the same statement that checks the token makes
+// sure a token can be used only once, even by concurrent requests.
+func (r *refreshTokenRepository) RevokeRefreshToken(ctx context.Context, tokenHash string, revokedAt time.Time) (*model.RefreshToken, error) {
+	var token model.RefreshToken
+
+	query, args, err := sq.Update("refresh_tokens").
+		Set("revoked_at", revokedAt).
+		Where(sq.Eq{"token_hash": tokenHash, "revoked_at": nil}).
+		Where(sq.Gt{"expires_at": revokedAt}).
+		Suffix("RETURNING " + joinColumns(refreshTokenColumns)).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = conn(ctx, r.db).GetContext(ctx, &token, query, args...)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	return &token, err
+}
+
+// RevokeUserRefreshTokens signs the user out everywhere by revoking all their live refresh tokens.
+func (r *refreshTokenRepository) RevokeUserRefreshTokens(ctx context.Context, userID string, revokedAt time.Time) error {
+	q := sq.Update("refresh_tokens").
+		Set("revoked_at", revokedAt).
+		Where(sq.Eq{"user_id": userID, "revoked_at": nil}).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+
+	return err
+}
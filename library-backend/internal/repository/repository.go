@@ -1,11 +1,25 @@
 package repository
 
 import (
+	"context"
+	"database/sql"
+	"strings"
+
 	"github.com/jmoiron/sqlx"
 )
 
 type Repository struct {
-	BookRepository BookRepository
+	BookRepository         BookRepository
+	MemberRepository       MemberRepository
+	LoanRepository         LoanRepository
+	BookCopyRepository     BookCopyRepository
+	HoldRepository         HoldRepository
+	FineRepository         FineRepository
+	UserRepository         UserRepository
+	RefreshTokenRepository RefreshTokenRepository
+	APIKeyRepository       APIKeyRepository
+	AuditRepository        AuditRepository
+	Transactor             Transactor
 }
 
 type Option struct {
@@ -14,6 +28,37 @@ type Option struct {
 
 func InitiateRepository(opt Option) *Repository {
 	return &Repository{
-		BookRepository: NewBookRepository(opt.DB),
+		BookRepository:         NewBookRepository(opt.DB),
+		MemberRepository:       NewMemberRepository(opt.DB),
+		LoanRepository:         NewLoanRepository(opt.DB),
+		BookCopyRepository:     NewBookCopyRepository(opt.DB),
+		HoldRepository:         NewHoldRepository(opt.DB),
+		FineRepository:         NewFineRepository(opt.DB),
+		UserRepository:         NewUserRepository(opt.DB),
+		RefreshTokenRepository: NewRefreshTokenRepository(opt.DB),
+		APIKeyRepository:       NewAPIKeyRepository(opt.DB),
+		AuditRepository:        NewAuditRepository(opt.DB),
+		Transactor:             NewTransactor(opt.DB),
 	}
 }
+
+func joinColumns(columns []string) string {
+	return strings.Join(columns, ", ")
+}
+
+// dbConn is the subset of query methods shared by *sqlx.DB and *sqlx.Tx.
+type dbConn interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	GetContext(ctx context.Context, dest any, query string, args ...any) error
+	SelectContext(ctx context.Context, dest any, query string, args ...any) error
+	QueryxContext(ctx context.Context, query string, args ...any) (*sqlx.Rows, error)
+}
+
+// conn returns the transaction carried by ctx, or db when there is none.
+func conn(ctx context.Context, db *sqlx.DB) dbConn {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return tx
+	}
+
+	return db
+}
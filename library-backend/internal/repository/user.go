@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"library-backend/internal/model"
+	"library-backend/internal/payload"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+var userColumns = []string{
+	"id",
+	"name",
+	"email",
+	"password_hash",
+	"role",
+	"status",
+	"last_login_at",
+	"created_at",
+	"updated_at",
+}
+
+type UserRepository interface {
+	CreateUser(ctx context.Context, user model.User) error
+	GetUsers(ctx context.Context, req payload.GetUsersRequest) ([]model.User, error)
+	GetUsersCount(ctx context.Context, req payload.GetUsersRequest) (int, error)
+	GetUserByID(ctx context.Context, id string) (*model.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*model.User, error)
+	UpdateUser(ctx context.Context, id string, updates map[string]any) error
+	UpdateLastLogin(ctx context.Context, id string, at time.Time) error
+	DeleteUser(ctx context.Context, id string) error
+}
+
+type userRepository struct {
+	db *sqlx.DB
+}
+
+func NewUserRepository(db *sqlx.DB) UserRepository {
+	return &userRepository{db: db}
+}
+
+func (r *userRepository) CreateUser(ctx context.Context, user model.User) error {
+	q := sq.Insert("users").
+		Columns("id",
+			"name",
+			"email",
+			"password_hash",
+			"role",
+			"status",
+			"updated_at",
+		).
+		Values(user.ID, user.Name, user.Email, user.PasswordHash, user.Role, user.Status, "NOW()").
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+
+	return err
+}
+
+// applyUserFilters narrows a users query down to the live rows matching the request filters,
+// it is shared by GetUsers and GetUsersCount so both always describe the same result set.
+func applyUserFilters(q sq.SelectBuilder, req payload.GetUsersRequest) sq.SelectBuilder {
+	q = q.Where(sq.Eq{"deleted_at": nil})
+
+	if req.Search != "" {
+		q = q.Where(sq.Or{
+			sq.ILike{"name": "%" + req.Search + "%"},
+			sq.ILike{"email": "%" + req.Search + "%"},
+		})
+	}
+
+	if req.Role != "" {
+		q = q.Where(sq.Eq{"role": req.Role})
+	}
+
+	if req.Status != "" {
+		q = q.Where(sq.Eq{"status": req.Status})
+	}
+
+	return q
+}
+
+func (r *userRepository) GetUsers(ctx context.Context, req payload.GetUsersRequest) ([]model.User, error) {
+	q := sq.Select(userColumns...).
+		From("users")
+
+	q = applyUserFilters(q, req).
+		OrderBy("name ASC", "id ASC").
+		Limit(uint64(req.Limit)).
+		Offset(uint64(req.Offset)).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var users []model.User
+	err = conn(ctx, r.db).SelectContext(ctx, &users, query, args...)
+
+	return users, err
+}
+
+func (r *userRepository) GetUsersCount(ctx context.Context, req payload.GetUsersRequest) (int, error) {
+	q := sq.Select("COUNT(id)").
+		From("users")
+
+	q = applyUserFilters(q, req).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = conn(ctx, r.db).GetContext(ctx, &count, query, args...)
+
+	return count, err
+}
+
+func (r *userRepository) GetUserByID(ctx context.Context, id string) (*model.User, error) {
+	return r.getUser(ctx, sq.Eq{"id": id, "deleted_at": nil})
+}
+
+func (r *userRepository) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
+	return r.getUser(ctx, sq.Eq{"email": email, "deleted_at": nil})
+}
+
+func (r *userRepository) getUser(ctx context.Context, where sq.Eq) (*model.User, error) {
+	var user model.User
+
+	q := sq.Select(userColumns...).
+		From("users").
+		Where(where).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := q.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = conn(ctx, r.db).GetContext(ctx, &user, query, args...)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	return &user, err
+}
+
+func (r *userRepository) UpdateUser(ctx context.Context, id string, updates map[string]any) error {
+	if len(updates) == 0 {
+		return errors.New("no fields to update")
+	}
+
+	q := sq.Update("users").
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
+		PlaceholderFormat(sq.Dollar)
+
+	for field, value := range updates {
+		q = q.Set(field, value)
+	}
+
+	q = q.Set("updated_at", time.Now())
+
+	return r.exec(ctx, q)
+}
+
+func (r *userRepository) UpdateLastLogin(ctx context.Context, id string, at time.Time) error {
+	q := sq.Update("users").
+		Set("last_login_at", at).
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
+		PlaceholderFormat(sq.Dollar)
+
+	return r.exec(ctx, q)
+}
+
+func (r *userRepository) DeleteUser(ctx context.Context, id string) error {
+	q := sq.Update("users").
+		Set("deleted_at", time.Now()).
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
+		PlaceholderFormat(sq.Dollar)
+
+	return r.exec(ctx, q)
+}
+
+// exec runs an update and reports sql.ErrNoRows when no live user matched.
+func (r *userRepository) exec(ctx context.Context, q sq.UpdateBuilder) error {
+	query, args, err := q.ToSql()
+	if err != nil {
+		return err
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
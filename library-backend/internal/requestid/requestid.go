@@ -0,0 +1,18 @@
+package requestid
+
+import "context"
+
+// LocalsKey is the fiber local the request ID middleware stores the ID under. Like the auth user
+// it is a plain string so the ID can be read back from the request context.
+const LocalsKey = "request_id"
+
+// WithRequestID returns a copy of ctx carrying the request ID, for callers outside of fiber.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, LocalsKey, id)
+}
+
+// FromContext returns the ID of the request, or an empty string outside of a request.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(LocalsKey).(string)
+	return id
+}
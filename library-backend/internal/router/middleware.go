@@ -0,0 +1,132 @@
+package router
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"library-backend/errorcustom"
+	"library-backend/internal/auth"
+	"library-backend/internal/service"
+	"library-backend/internal/util"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// RequireAuth rejects requests without a valid bearer access token or API key, the caller is
+// stored in the auth.LocalsKey local where handlers and services can pick it up. An API key can
+// also be sent as the password of basic auth, for clients such as e-reader apps that know nothing
+// else; the user name is ignored.
+func RequireAuth(tokens *auth.TokenManager, apiKeys service.APIKeyService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		scheme, token, _ := strings.Cut(c.Get(fiber.HeaderAuthorization), " ")
+		if token == "" {
+			return util.ErrUnauthorizedResponse(c, errorcustom.ErrUnauthorized.Error())
+		}
+
+		if strings.EqualFold(scheme, "Basic") {
+			credentials, err := base64.StdEncoding.DecodeString(token)
+			if err != nil {
+				return util.ErrUnauthorizedResponse(c, errorcustom.ErrUnauthorized.Error())
+			}
+
+			_, password, _ := strings.Cut(string(credentials), ":")
+			if password == "" {
+				return util.ErrUnauthorizedResponse(c, errorcustom.ErrUnauthorized.Error())
+			}
+
+			scheme, token = "ApiKey", password
+		}
+
+		if strings.EqualFold(scheme, "ApiKey") {
+			user, err := apiKeys.Authenticate(c.Context(), token)
+			if err != nil {
+				if errors.Is(err, errorcustom.ErrInvalidAPIKey) {
+					return util.ErrUnauthorizedResponse(c, err.Error())
+				}
+				return util.ErrInternalResponse(c)
+			}
+
+			c.Locals(auth.LocalsKey, user)
+
+			return c.Next()
+		}
+
+		if !strings.EqualFold(scheme, "Bearer") {
+			return util.ErrUnauthorizedResponse(c, errorcustom.ErrUnauthorized.Error())
+		}
+
+		claims, err := tokens.ParseAccessToken(token)
+		if err != nil {
+			return util.ErrUnauthorizedResponse(c, errorcustom.ErrUnauthorized.Error())
+		}
+
+		userID, err := uuid.Parse(claims.Subject)
+		if err != nil {
+			return util.ErrUnauthorizedResponse(c, errorcustom.ErrUnauthorized.Error())
+		}
+
+		c.Locals(auth.LocalsKey, auth.User{ID: userID, Email: claims.Email, Role: claims.Role})
+
+		return c.Next()
+	}
+}
+
+// RequirePermission rejects callers whose role is not granted the permission, it has to run
+// after RequireAuth.
+func RequirePermission(permission auth.Permission) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		user, ok := auth.UserFromContext(c.Context())
+		if !ok {
+			return util.ErrUnauthorizedResponse(c, errorcustom.ErrUnauthorized.Error())
+		}
+
+		if !user.HasPermission(permission) {
+			return util.ErrForbiddenResponse(c)
+		}
+
+		return c.Next()
+	}
+}
+
+// ChallengeBasicAuth asks for basic auth when a request is turned away as unauthorized, so clients
+// that only support basic auth prompt for it. It has to run before RequireAuth.
+func ChallengeBasicAuth(realm string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		err := c.Next()
+
+		if c.Response().StatusCode() == fiber.StatusUnauthorized {
+			c.Set(fiber.HeaderWWWAuthenticate, fmt.Sprintf(`Basic realm=%q, charset="UTF-8"`, realm))
+		}
+
+		return err
+	}
+}
+
+// maxRequestIDLength matches the request_id column of the audit events.
+const maxRequestIDLength = 100
+
+// DropInvalidRequestID removes an X-Request-ID header the audit trail could not store, so the
+// request ID middleware generates a fresh ID instead of adopting it.
+func DropInvalidRequestID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Get(fiber.HeaderXRequestID)
+		if id == "" {
+			return c.Next()
+		}
+
+		valid := len(id) <= maxRequestIDLength
+		for i := 0; valid && i < len(id); i++ {
+			ch := id[i]
+			valid = ch >= '0' && ch <= '9' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' ||
+				ch == '-' || ch == '_' || ch == '.' || ch == ':'
+		}
+
+		if !valid {
+			c.Request().Header.Del(fiber.HeaderXRequestID)
+		}
+
+		return c.Next()
+	}
+}
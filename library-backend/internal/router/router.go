@@ -1,17 +1,21 @@
 package router
 
 import (
+	"library-backend/internal/auth"
 	"library-backend/internal/handler" // swagger handler
 	"library-backend/internal/payload"
+	"library-backend/internal/requestid"
+	"library-backend/internal/service"
 
 	_ "library-backend/docs"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
+	fiberrequestid "github.com/gofiber/fiber/v2/middleware/requestid"
 	"github.com/gofiber/swagger"
 )
 
-func NewRouter(hndler *handler.Handler) *fiber.App {
+func NewRouter(hndler *handler.Handler, tokens *auth.TokenManager, apiKeys service.APIKeyService) *fiber.App {
 	app := fiber.New(fiber.Config{
 		// Global custom error handler
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
@@ -23,23 +27,125 @@ func NewRouter(hndler *handler.Handler) *fiber.App {
 	})
 
 	app.Use(cors.New(cors.Config{
-		AllowOrigins: "*",
-		AllowHeaders: "Origin, Content-Type, Accept",
-		AllowMethods: "GET, POST, PUT, DELETE",
+		AllowOrigins:  "*",
+		AllowHeaders:  "Origin, Content-Type, Accept, Authorization, X-Request-ID",
+		AllowMethods:  "GET, POST, PUT, DELETE",
+		ExposeHeaders: "X-Request-ID",
 	}))
-	app.Get("/swagger/*", swagger.HandlerDefault)
+
+	// every request gets an ID, taken from X-Request-ID when the client sends one, which is echoed
+	// back and recorded with the audit events the request causes
+	app.Use(DropInvalidRequestID())
+	app.Use(fiberrequestid.New(fiberrequestid.Config{
+		ContextKey: requestid.LocalsKey,
+	}))
+	// extensions are shown so the x-permission of each operation is visible in the UI
+	app.Get("/swagger/*", swagger.New(swagger.Config{ShowExtensions: true}))
 
 	app.Get("/", GetHello)
 
 	v1 := app.Group("/v1")
 
+	// everything past sign in needs a token or an API key, each route then checks the caller's
+	// role or key scopes grant the permission it requires, see auth.Permissions
+	requireAuth := RequireAuth(tokens, apiKeys)
+
+	// auth route
+	authGroup := v1.Group("/auth")
+	authGroup.Post("/login", hndler.AuthHandler.Login)
+	authGroup.Post("/refresh", hndler.AuthHandler.Refresh)
+	authGroup.Post("/logout", hndler.AuthHandler.Logout)
+	authGroup.Get("/me", requireAuth, hndler.AuthHandler.Me)
+
+	// user route
+	userGroup := v1.Group("/users", requireAuth, RequirePermission(auth.PermUsersManage))
+	userGroup.Get("/", hndler.UserHandler.GetUsers)
+	userGroup.Get("/:id", hndler.UserHandler.GetUserByID)
+	userGroup.Post("/", hndler.UserHandler.CreateUser)
+	userGroup.Put("/:id", hndler.UserHandler.UpdateUser)
+	userGroup.Delete("/:id", hndler.UserHandler.DeleteUser)
+
+	// api key route
+	apiKeyGroup := v1.Group("/api-keys", requireAuth, RequirePermission(auth.PermAPIKeysManage))
+	apiKeyGroup.Get("/", hndler.APIKeyHandler.GetAPIKeys)
+	apiKeyGroup.Get("/:id", hndler.APIKeyHandler.GetAPIKeyByID)
+	apiKeyGroup.Post("/", hndler.APIKeyHandler.CreateAPIKey)
+	apiKeyGroup.Post("/:id/revoke", hndler.APIKeyHandler.RevokeAPIKey)
+
+	// audit route
+	auditGroup := v1.Group("/audit", requireAuth)
+	auditGroup.Get("/", RequirePermission(auth.PermAuditRead), hndler.AuditHandler.GetAuditEvents)
+
 	// book route
-	bookGroup := v1.Group("/books")
-	bookGroup.Get("/", hndler.BookHandler.GetBooks)
-	bookGroup.Get("/:id", hndler.BookHandler.GetBookByID)
-	bookGroup.Post("/", hndler.BookHandler.CreateBook)
-	bookGroup.Put("/:id", hndler.BookHandler.UpdateBook)
-	bookGroup.Delete("/:id", hndler.BookHandler.DeleteBook)
+	bookGroup := v1.Group("/books", requireAuth)
+
+	// book trash route, registered before /:id so "trash" is not taken for a book ID
+	bookGroup.Get("/trash", RequirePermission(auth.PermBooksDelete), hndler.BookHandler.GetDeletedBooks)
+	bookGroup.Post("/trash/:id/restore", RequirePermission(auth.PermBooksDelete), hndler.BookHandler.RestoreBook)
+	bookGroup.Delete("/trash/:id", RequirePermission(auth.PermBooksDelete), hndler.BookHandler.PurgeBook)
+
+	// book lookup route, also registered before /:id
+	bookGroup.Get("/isbn/:isbn", RequirePermission(auth.PermBooksRead), hndler.BookHandler.GetBookByISBN)
+	bookGroup.Get("/barcode/:barcode", RequirePermission(auth.PermBooksRead), hndler.BookHandler.GetBookByBarcode)
+	bookGroup.Post("/import", RequirePermission(auth.PermBooksWrite), hndler.BookHandler.ImportBooks)
+	bookGroup.Get("/export", RequirePermission(auth.PermBooksRead), hndler.BookHandler.ExportBooks)
+	bookGroup.Get("/citation", RequirePermission(auth.PermBooksRead), hndler.BookHandler.GetBooksCitation)
+
+	bookGroup.Get("/", RequirePermission(auth.PermBooksRead), hndler.BookHandler.GetBooks)
+	bookGroup.Get("/:id", RequirePermission(auth.PermBooksRead), hndler.BookHandler.GetBookByID)
+	bookGroup.Post("/", RequirePermission(auth.PermBooksWrite), hndler.BookHandler.CreateBook)
+	bookGroup.Put("/:id", RequirePermission(auth.PermBooksWrite), hndler.BookHandler.UpdateBook)
+	bookGroup.Delete("/:id", RequirePermission(auth.PermBooksDelete), hndler.BookHandler.DeleteBook)
+	bookGroup.Get("/:id/marc", RequirePermission(auth.PermBooksRead), hndler.BookHandler.GetBookMARC)
+	bookGroup.Get("/:id/citation", RequirePermission(auth.PermBooksRead), hndler.BookHandler.GetBookCitation)
+
+	// book copy route
+	bookGroup.Get("/:id/copies", RequirePermission(auth.PermBooksRead), hndler.BookCopyHandler.GetBookCopies)
+	bookGroup.Get("/:id/copies/:copyId", RequirePermission(auth.PermBooksRead), hndler.BookCopyHandler.GetBookCopyByID)
+	bookGroup.Post("/:id/copies", RequirePermission(auth.PermBooksWrite), hndler.BookCopyHandler.CreateBookCopy)
+	bookGroup.Put("/:id/copies/:copyId", RequirePermission(auth.PermBooksWrite), hndler.BookCopyHandler.UpdateBookCopy)
+	bookGroup.Delete("/:id/copies/:copyId", RequirePermission(auth.PermBooksDelete), hndler.BookCopyHandler.DeleteBookCopy)
+
+	// opds catalog route, e-reader apps ask for basic auth on a challenge and send an API key
+	opdsGroup := v1.Group("/opds", ChallengeBasicAuth("Library catalog"), requireAuth, RequirePermission(auth.PermBooksRead))
+	opdsGroup.Get("/", hndler.CatalogHandler.GetOPDSRoot)
+	opdsGroup.Get("/books", hndler.CatalogHandler.GetOPDSBooks)
+	opdsGroup.Get("/search.xml", hndler.CatalogHandler.GetOpenSearchDescription)
+
+	// sru route, library systems search the catalog with the same auth as the opds catalog
+	v1.Get("/sru", ChallengeBasicAuth("Library catalog"), requireAuth, RequirePermission(auth.PermBooksRead), hndler.CatalogHandler.SRU)
+
+	// member route
+	memberGroup := v1.Group("/members", requireAuth)
+	memberGroup.Get("/", RequirePermission(auth.PermMembersRead), hndler.MemberHandler.GetMembers)
+	memberGroup.Get("/:id", RequirePermission(auth.PermMembersRead), hndler.MemberHandler.GetMemberByID)
+	memberGroup.Post("/", RequirePermission(auth.PermMembersWrite), hndler.MemberHandler.CreateMember)
+	memberGroup.Put("/:id", RequirePermission(auth.PermMembersWrite), hndler.MemberHandler.UpdateMember)
+	memberGroup.Delete("/:id", RequirePermission(auth.PermMembersDelete), hndler.MemberHandler.DeleteMember)
+
+	// member fine route
+	memberGroup.Get("/:id/fines", RequirePermission(auth.PermFinesRead), hndler.FineHandler.GetMemberFines)
+	memberGroup.Post("/:id/fines/payments", RequirePermission(auth.PermFinesWrite), hndler.FineHandler.CreatePayment)
+	memberGroup.Post("/:id/fines/waivers", RequirePermission(auth.PermFinesWrite), hndler.FineHandler.CreateWaiver)
+
+	// loan route
+	loanGroup := v1.Group("/loans", requireAuth)
+	loanGroup.Get("/", RequirePermission(auth.PermLoansRead), hndler.LoanHandler.GetLoans)
+	loanGroup.Get("/report", RequirePermission(auth.PermLoansRead), hndler.LoanHandler.GetBorrowingReport)
+	loanGroup.Get("/:id", RequirePermission(auth.PermLoansRead), hndler.LoanHandler.GetLoanByID)
+	loanGroup.Post("/", RequirePermission(auth.PermLoansWrite), hndler.LoanHandler.CreateLoan)
+	loanGroup.Post("/:id/return", RequirePermission(auth.PermLoansWrite), hndler.LoanHandler.ReturnLoan)
+
+	// hold route
+	holdGroup := v1.Group("/holds", requireAuth)
+	holdGroup.Get("/", RequirePermission(auth.PermHoldsRead), hndler.HoldHandler.GetHolds)
+	holdGroup.Get("/:id", RequirePermission(auth.PermHoldsRead), hndler.HoldHandler.GetHoldByID)
+	holdGroup.Post("/", RequirePermission(auth.PermHoldsWrite), hndler.HoldHandler.CreateHold)
+	holdGroup.Post("/:id/cancel", RequirePermission(auth.PermHoldsWrite), hndler.HoldHandler.CancelHold)
+
+	// fine route
+	fineGroup := v1.Group("/fines", requireAuth)
+	fineGroup.Get("/balances", RequirePermission(auth.PermFinesRead), hndler.FineHandler.GetFineBalances)
 
 	return app
 }
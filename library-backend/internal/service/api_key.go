@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"library-backend/errorcustom"
+	"library-backend/internal/auth"
+	"library-backend/internal/model"
+	"library-backend/internal/payload"
+	"library-backend/internal/repository"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type APIKeyService interface {
+	CreateAPIKey(ctx context.Context, request payload.CreateAPIKeyRequest) (payload.CreateAPIKeyResponse, error)
+	GetAPIKeys(ctx context.Context, request payload.GetAPIKeysRequest) (payload.GetAPIKeysResponse, error)
+	GetAPIKeyByID(ctx context.Context, id string) (payload.GetAPIKeyByIDResponse, error)
+	RevokeAPIKey(ctx context.Context, request payload.RevokeAPIKeyRequest) error
+	Authenticate(ctx context.Context, key string) (auth.User, error)
+}
+
+type apiKeyService struct {
+	apiKeyRepo repository.APIKeyRepository
+}
+
+func NewAPIKeyService(apiKeyRepo repository.APIKeyRepository) APIKeyService {
+	return &apiKeyService{apiKeyRepo: apiKeyRepo}
+}
+
+// CreateAPIKey mints a key for the signed in user, the key is returned once and only its hash is
+// kept.
+func (s *apiKeyService) CreateAPIKey(ctx context.Context, request payload.CreateAPIKeyRequest) (res payload.CreateAPIKeyResponse, err error) {
+	caller, ok := auth.UserFromContext(ctx)
+	if !ok || caller.APIKey {
+		return res, errorcustom.ErrUnauthorized
+	}
+
+	for _, scope := range request.Scopes {
+		if !auth.IsGrantableScope(auth.Permission(scope)) {
+			return res, errorcustom.ErrInvalidAPIKeyScope
+		}
+	}
+
+	now := time.Now()
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(now) {
+		return res, errorcustom.ErrInvalidAPIKeyExpiry
+	}
+
+	key, prefix, hash, err := auth.NewAPIKey()
+	if err != nil {
+		slog.ErrorContext(ctx, "[APIKeyService][CreateAPIKey] failed to generate API key", "error", err)
+		return res, err
+	}
+
+	apiKey := model.APIKey{
+		ID:        uuid.New(),
+		Name:      request.Name,
+		Prefix:    prefix,
+		KeyHash:   hash,
+		Scopes:    strings.Join(request.Scopes, " "),
+		CreatedBy: caller.ID,
+		ExpiresAt: request.ExpiresAt,
+		CreatedAt: now,
+	}
+
+	err = s.apiKeyRepo.CreateAPIKey(ctx, apiKey)
+	if err != nil {
+		slog.ErrorContext(ctx, "[APIKeyService][CreateAPIKey] failed to create API key", "error", err)
+		return res, err
+	}
+
+	res.APIKeyResponse = toAPIKeyResponse(apiKey, now)
+	res.Key = key
+
+	return res, nil
+}
+
+func (s *apiKeyService) GetAPIKeys(ctx context.Context, request payload.GetAPIKeysRequest) (res payload.GetAPIKeysResponse, err error) {
+	request.Offset = (request.Page - 1) * request.Limit
+
+	keys, err := s.apiKeyRepo.GetAPIKeys(ctx, request)
+	if err != nil {
+		slog.ErrorContext(ctx, "[APIKeyService][GetAPIKeys] failed to get API keys", "error", err)
+		return res, err
+	}
+
+	totalCount, err := s.apiKeyRepo.GetAPIKeysCount(ctx, request)
+	if err != nil {
+		slog.ErrorContext(ctx, "[APIKeyService][GetAPIKeys] failed to get API keys count", "error", err)
+		return res, err
+	}
+
+	totalPages := int(math.Ceil(float64(totalCount) / float64(request.Limit)))
+
+	now := time.Now()
+	keyResponses := make([]payload.APIKeyResponse, len(keys))
+	for i, key := range keys {
+		keyResponses[i] = toAPIKeyResponse(key, now)
+	}
+
+	res.APIKeys = keyResponses
+	res.Pagination = payload.Pagination{
+		Page:      request.Page,
+		Limit:     request.Limit,
+		TotalPage: totalPages,
+		TotalItem: totalCount,
+	}
+
+	return res, nil
+}
+
+func (s *apiKeyService) GetAPIKeyByID(ctx context.Context, id string) (res payload.GetAPIKeyByIDResponse, err error) {
+	key, err := s.apiKeyRepo.GetAPIKeyByID(ctx, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "[APIKeyService][GetAPIKeyByID] failed to get API key by ID", "error", err, "id", id)
+		return res, err
+	}
+
+	if key == nil {
+		return res, errorcustom.ErrAPIKeyNotFound
+	}
+
+	res.APIKeyResponse = toAPIKeyResponse(*key, time.Now())
+
+	return res, nil
+}
+
+// RevokeAPIKey stops a key from being used, revoking a key twice is not an error.
+func (s *apiKeyService) RevokeAPIKey(ctx context.Context, request payload.RevokeAPIKeyRequest) error {
+	key, err := s.apiKeyRepo.GetAPIKeyByID(ctx, request.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[APIKeyService][RevokeAPIKey] failed to check API key existence", "error", err, "id", request.ID)
+		return err
+	}
+
+	if key == nil {
+		return errorcustom.ErrAPIKeyNotFound
+	}
+
+	err = s.apiKeyRepo.RevokeAPIKey(ctx, request.ID, time.Now())
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		slog.ErrorContext(ctx, "[APIKeyService][RevokeAPIKey] failed to revoke API key", "error", err, "id", request.ID)
+		return err
+	}
+
+	return nil
+}
+
+// Authenticate resolves an API key to the caller it stands for, the key scopes are its
+// permissions.
+func (s *apiKeyService) Authenticate(ctx context.Context, key string) (user auth.User, err error) {
+	apiKey, err := s.apiKeyRepo.GetAPIKeyByHash(ctx, auth.HashAPIKey(key))
+	if err != nil {
+		slog.ErrorContext(ctx, "[APIKeyService][Authenticate] failed to get API key by hash", "error", err)
+		return user, err
+	}
+
+	now := time.Now()
+	if apiKey == nil || apiKey.Status(now) != model.APIKeyStatusActive {
+		return user, errorcustom.ErrInvalidAPIKey
+	}
+
+	// last used is only informational, a failure to record it must not fail the request
+	if err := s.apiKeyRepo.TouchAPIKey(ctx, apiKey.ID.String(), now); err != nil {
+		slog.ErrorContext(ctx, "[APIKeyService][Authenticate] failed to record API key use", "error", err, "id", apiKey.ID)
+	}
+
+	user = auth.User{ID: apiKey.ID, APIKey: true}
+	for _, scope := range strings.Fields(apiKey.Scopes) {
+		user.Scopes = append(user.Scopes, auth.Permission(scope))
+	}
+
+	return user, nil
+}
+
+func toAPIKeyResponse(key model.APIKey, now time.Time) payload.APIKeyResponse {
+	return payload.APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     strings.Fields(key.Scopes),
+		Status:     key.Status(now),
+		CreatedBy:  key.CreatedBy,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
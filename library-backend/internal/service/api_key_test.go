@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"library-backend/errorcustom"
+	"library-backend/internal/auth"
+	"library-backend/internal/model"
+	"library-backend/internal/payload"
+	"library-backend/internal/repository/mock"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+)
+
+func Test_apiKeyService_CreateAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAPIKeyRepo := mock.NewMockAPIKeyRepository(ctrl)
+	service := NewAPIKeyService(mockAPIKeyRepo)
+
+	caller := auth.User{ID: uuid.New(), Email: "admin@library.test", Role: model.UserRoleAdmin}
+	ctx := auth.WithUser(context.Background(), caller)
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name     string
+		mockFunc func()
+		request  payload.CreateAPIKeyRequest
+		wantErr  error
+	}{
+		{
+			name: "success stores the key hash",
+			mockFunc: func() {
+				mockAPIKeyRepo.EXPECT().CreateAPIKey(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, key model.APIKey) error {
+					if key.CreatedBy != caller.ID || key.Scopes != "books:read loans:write" || len(key.KeyHash) != 64 {
+						t.Errorf("apiKeyService.CreateAPIKey() unexpected key %+v", key)
+					}
+					return nil
+				})
+			},
+			request: payload.CreateAPIKeyRequest{Name: "Front desk kiosk", Scopes: []string{"books:read", "loans:write"}},
+		},
+		{
+			name:     "unknown scope",
+			mockFunc: func() {},
+			request:  payload.CreateAPIKeyRequest{Name: "Importer", Scopes: []string{"books:everything"}},
+			wantErr:  errorcustom.ErrInvalidAPIKeyScope,
+		},
+		{
+			name:     "keys cannot manage users",
+			mockFunc: func() {},
+			request:  payload.CreateAPIKeyRequest{Name: "Importer", Scopes: []string{"users:manage"}},
+			wantErr:  errorcustom.ErrInvalidAPIKeyScope,
+		},
+		{
+			name:     "expiry in the past",
+			mockFunc: func() {},
+			request:  payload.CreateAPIKeyRequest{Name: "Importer", Scopes: []string{"books:write"}, ExpiresAt: &past},
+			wantErr:  errorcustom.ErrInvalidAPIKeyExpiry,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+			gotRes, err := service.CreateAPIKey(ctx, tt.request)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("apiKeyService.CreateAPIKey() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if !strings.HasPrefix(gotRes.Key, gotRes.Prefix) || gotRes.Status != model.APIKeyStatusActive {
+				t.Errorf("apiKeyService.CreateAPIKey() unexpected response %+v", gotRes)
+			}
+		})
+	}
+}
+
+func Test_apiKeyService_Authenticate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAPIKeyRepo := mock.NewMockAPIKeyRepository(ctrl)
+	service := NewAPIKeyService(mockAPIKeyRepo)
+
+	ctx := context.Background()
+	key, prefix, hash, err := auth.NewAPIKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	past := time.Now().Add(-time.Hour)
+	active := &model.APIKey{ID: uuid.New(), Prefix: prefix, KeyHash: hash, Scopes: "books:read books:write"}
+	expired := &model.APIKey{ID: uuid.New(), Prefix: prefix, KeyHash: hash, Scopes: "books:read", ExpiresAt: &past}
+	revoked := &model.APIKey{ID: uuid.New(), Prefix: prefix, KeyHash: hash, Scopes: "books:read", RevokedAt: &past}
+
+	tests := []struct {
+		name     string
+		mockFunc func()
+		wantErr  error
+	}{
+		{
+			name: "active key records its use",
+			mockFunc: func() {
+				mockAPIKeyRepo.EXPECT().GetAPIKeyByHash(ctx, hash).Return(active, nil)
+				mockAPIKeyRepo.EXPECT().TouchAPIKey(ctx, active.ID.String(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "failing to record the use does not fail the request",
+			mockFunc: func() {
+				mockAPIKeyRepo.EXPECT().GetAPIKeyByHash(ctx, hash).Return(active, nil)
+				mockAPIKeyRepo.EXPECT().TouchAPIKey(ctx, active.ID.String(), gomock.Any()).Return(errors.New("connection reset"))
+			},
+		},
+		{
+			name: "unknown key",
+			mockFunc: func() {
+				mockAPIKeyRepo.EXPECT().GetAPIKeyByHash(ctx, hash).Return(nil, nil)
+			},
+			wantErr: errorcustom.ErrInvalidAPIKey,
+		},
+		{
+			name: "expired key",
+			mockFunc: func() {
+				mockAPIKeyRepo.EXPECT().GetAPIKeyByHash(ctx, hash).Return(expired, nil)
+			},
+			wantErr: errorcustom.ErrInvalidAPIKey,
+		},
+		{
+			name: "revoked key",
+			mockFunc: func() {
+				mockAPIKeyRepo.EXPECT().GetAPIKeyByHash(ctx, hash).Return(revoked, nil)
+			},
+			wantErr: errorcustom.ErrInvalidAPIKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+			gotUser, err := service.Authenticate(ctx, key)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("apiKeyService.Authenticate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if gotUser.ID != active.ID || !gotUser.APIKey {
+				t.Errorf("apiKeyService.Authenticate() = %+v, want key %v", gotUser, active.ID)
+			}
+			if !gotUser.HasPermission(auth.PermBooksWrite) || gotUser.HasPermission(auth.PermLoansWrite) {
+				t.Errorf("apiKeyService.Authenticate() scopes = %v, want books:read books:write", gotUser.Scopes)
+			}
+		})
+	}
+}
+
+func Test_apiKeyService_RevokeAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAPIKeyRepo := mock.NewMockAPIKeyRepository(ctrl)
+	service := NewAPIKeyService(mockAPIKeyRepo)
+
+	ctx := context.Background()
+	key := &model.APIKey{ID: uuid.New(), Scopes: "books:read"}
+
+	tests := []struct {
+		name     string
+		mockFunc func()
+		wantErr  error
+	}{
+		{
+			name: "success",
+			mockFunc: func() {
+				mockAPIKeyRepo.EXPECT().GetAPIKeyByID(ctx, key.ID.String()).Return(key, nil)
+				mockAPIKeyRepo.EXPECT().RevokeAPIKey(ctx, key.ID.String(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "already revoked",
+			mockFunc: func() {
+				mockAPIKeyRepo.EXPECT().GetAPIKeyByID(ctx, key.ID.String()).Return(key, nil)
+				mockAPIKeyRepo.EXPECT().RevokeAPIKey(ctx, key.ID.String(), gomock.Any()).Return(sql.ErrNoRows)
+			},
+		},
+		{
+			name: "key not found",
+			mockFunc: func() {
+				mockAPIKeyRepo.EXPECT().GetAPIKeyByID(ctx, key.ID.String()).Return(nil, nil)
+			},
+			wantErr: errorcustom.ErrAPIKeyNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+			err := service.RevokeAPIKey(ctx, payload.RevokeAPIKeyRequest{ID: key.ID.String()})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("apiKeyService.RevokeAPIKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"library-backend/internal/auth"
+	"library-backend/internal/model"
+	"library-backend/internal/payload"
+	"library-backend/internal/repository"
+	"library-backend/internal/requestid"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditService interface {
+	GetAuditEvents(ctx context.Context, request payload.GetAuditEventsRequest) (payload.GetAuditEventsResponse, error)
+}
+
+type auditService struct {
+	auditRepo repository.AuditRepository
+}
+
+func NewAuditService(auditRepo repository.AuditRepository) AuditService {
+	return &auditService{auditRepo: auditRepo}
+}
+
+func (s *auditService) GetAuditEvents(ctx context.Context, request payload.GetAuditEventsRequest) (res payload.GetAuditEventsResponse, err error) {
+	request.Offset = (request.Page - 1) * request.Limit
+
+	events, err := s.auditRepo.GetAuditEvents(ctx, request)
+	if err != nil {
+		slog.ErrorContext(ctx, "[AuditService][GetAuditEvents] failed to get audit events", "error", err)
+		return res, err
+	}
+
+	totalCount, err := s.auditRepo.GetAuditEventsCount(ctx, request)
+	if err != nil {
+		slog.ErrorContext(ctx, "[AuditService][GetAuditEvents] failed to get audit events count", "error", err)
+		return res, err
+	}
+
+	totalPages := int(math.Ceil(float64(totalCount) / float64(request.Limit)))
+
+	eventResponses := make([]payload.AuditEventResponse, len(events))
+	for i, event := range events {
+		eventResponses[i] = payload.AuditEventResponse{
+			ID:         event.ID,
+			OccurredAt: event.OccurredAt,
+			ActorType:  event.ActorType,
+			ActorID:    event.ActorID,
+			Action:     event.Action,
+			EntityType: event.EntityType,
+			EntityID:   event.EntityID,
+			Before:     event.Before,
+			After:      event.After,
+			RequestID:  event.RequestID,
+		}
+	}
+
+	res.Events = eventResponses
+	res.Pagination = payload.Pagination{
+		Page:      request.Page,
+		Limit:     request.Limit,
+		TotalPage: totalPages,
+		TotalItem: totalCount,
+	}
+
+	return res, nil
+}
+
+// recordAudit appends an audit event for a mutation of an entity, before and after are the entity
+// state around it and are left out when nil. Call it inside the transaction of the mutation, the
+// actor and request ID are taken from ctx.
+func recordAudit(ctx context.Context, auditRepo repository.AuditRepository, action, entityType string, entityID uuid.UUID, before, after any) error {
+	event := model.AuditEvent{
+		ID:         uuid.New(),
+		OccurredAt: time.Now(),
+		ActorType:  model.AuditActorSystem,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+	}
+
+	if caller, ok := auth.UserFromContext(ctx); ok {
+		event.ActorType = model.AuditActorUser
+		if caller.APIKey {
+			event.ActorType = model.AuditActorAPIKey
+		}
+		event.ActorID = &caller.ID
+	}
+
+	if id := requestid.FromContext(ctx); id != "" {
+		event.RequestID = &id
+	}
+
+	var err error
+	if before != nil {
+		if event.Before, err = json.Marshal(before); err != nil {
+			return err
+		}
+	}
+
+	if after != nil {
+		if event.After, err = json.Marshal(after); err != nil {
+			return err
+		}
+	}
+
+	return auditRepo.CreateAuditEvent(ctx, event)
+}
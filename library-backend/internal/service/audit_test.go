@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"library-backend/internal/auth"
+	"library-backend/internal/model"
+	"library-backend/internal/repository/mock"
+	"library-backend/internal/requestid"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+)
+
+func Test_recordAudit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuditRepo := mock.NewMockAuditRepository(ctrl)
+
+	user := auth.User{ID: uuid.New(), Email: "librarian@library.test", Role: model.UserRoleLibrarian}
+	apiKey := auth.User{ID: uuid.New(), APIKey: true, Scopes: []auth.Permission{auth.PermBooksWrite}}
+	entityID := uuid.New()
+
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		wantActorType string
+		wantActorID   *uuid.UUID
+		wantRequestID string
+	}{
+		{
+			name:          "signed in user",
+			ctx:           requestid.WithRequestID(auth.WithUser(context.Background(), user), "req-1"),
+			wantActorType: model.AuditActorUser,
+			wantActorID:   &user.ID,
+			wantRequestID: "req-1",
+		},
+		{
+			name:          "api key",
+			ctx:           auth.WithUser(context.Background(), apiKey),
+			wantActorType: model.AuditActorAPIKey,
+			wantActorID:   &apiKey.ID,
+		},
+		{
+			name:          "no caller",
+			ctx:           context.Background(),
+			wantActorType: model.AuditActorSystem,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAuditRepo.EXPECT().CreateAuditEvent(tt.ctx, gomock.Any()).DoAndReturn(func(_ context.Context, event model.AuditEvent) error {
+				if event.ActorType != tt.wantActorType {
+					t.Errorf("recordAudit() actor type = %v, want %v", event.ActorType, tt.wantActorType)
+				}
+				if (event.ActorID == nil) != (tt.wantActorID == nil) || (event.ActorID != nil && *event.ActorID != *tt.wantActorID) {
+					t.Errorf("recordAudit() actor ID = %v, want %v", event.ActorID, tt.wantActorID)
+				}
+				if (event.RequestID == nil && tt.wantRequestID != "") || (event.RequestID != nil && *event.RequestID != tt.wantRequestID) {
+					t.Errorf("recordAudit() request ID = %v, want %q", event.RequestID, tt.wantRequestID)
+				}
+				if string(event.After) != `{"title":"Dune"}` || event.Before != nil {
+					t.Errorf("recordAudit() before = %s, after = %s", event.Before, event.After)
+				}
+				return nil
+			})
+
+			err := recordAudit(tt.ctx, mockAuditRepo, model.AuditActionCreate, model.AuditEntityBook, entityID, nil, map[string]string{"title": "Dune"})
+			if err != nil {
+				t.Errorf("recordAudit() error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"library-backend/errorcustom"
+	"library-backend/internal/auth"
+	"library-backend/internal/model"
+	"library-backend/internal/payload"
+	"library-backend/internal/repository"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuthService interface {
+	Login(ctx context.Context, request payload.LoginRequest) (payload.TokenResponse, error)
+	Refresh(ctx context.Context, request payload.RefreshTokenRequest) (payload.TokenResponse, error)
+	Logout(ctx context.Context, request payload.LogoutRequest) error
+	Me(ctx context.Context) (payload.MeResponse, error)
+}
+
+type authService struct {
+	tokens      *auth.TokenManager
+	transactor  repository.Transactor
+	userRepo    repository.UserRepository
+	refreshRepo repository.RefreshTokenRepository
+}
+
+func NewAuthService(
+	tokens *auth.TokenManager,
+	transactor repository.Transactor,
+	userRepo repository.UserRepository,
+	refreshRepo repository.RefreshTokenRepository,
+) AuthService {
+	return &authService{
+		tokens:      tokens,
+		transactor:  transactor,
+		userRepo:    userRepo,
+		refreshRepo: refreshRepo,
+	}
+}
+
+func (s *authService) Login(ctx context.Context, request payload.LoginRequest) (res payload.TokenResponse, err error) {
+	user, err := s.userRepo.GetUserByEmail(ctx, request.Email)
+	if err != nil {
+		slog.ErrorContext(ctx, "[AuthService][Login] failed to get user by email", "error", err)
+		return res, err
+	}
+
+	// unknown email, wrong password and disabled account all look the same to the caller
+	if user == nil || user.Status != model.UserStatusActive || !auth.CheckPassword(user.PasswordHash, request.Password) {
+		return res, errorcustom.ErrInvalidCredentials
+	}
+
+	now := time.Now()
+
+	res, err = s.issueTokens(ctx, *user, now)
+	if err != nil {
+		return res, err
+	}
+
+	if err = s.userRepo.UpdateLastLogin(ctx, user.ID.String(), now); err != nil {
+		slog.ErrorContext(ctx, "[AuthService][Login] failed to update last login", "error", err, "user_id", user.ID)
+		return res, err
+	}
+
+	return res, nil
+}
+
+// Refresh trades a refresh token for a new access and refresh token pair, the old refresh token
+// is revoked so it cannot be used again.
+func (s *authService) Refresh(ctx context.Context, request payload.RefreshTokenRequest) (res payload.TokenResponse, err error) {
+	now := time.Now()
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		token, err := s.refreshRepo.RevokeRefreshToken(ctx, auth.HashRefreshToken(request.RefreshToken), now)
+		if err != nil {
+			slog.ErrorContext(ctx, "[AuthService][Refresh] failed to revoke refresh token", "error", err)
+			return err
+		}
+
+		if token == nil {
+			return errorcustom.ErrInvalidRefreshToken
+		}
+
+		user, err := s.userRepo.GetUserByID(ctx, token.UserID.String())
+		if err != nil {
+			slog.ErrorContext(ctx, "[AuthService][Refresh] failed to get user", "error", err, "user_id", token.UserID)
+			return err
+		}
+
+		if user == nil || user.Status != model.UserStatusActive {
+			return errorcustom.ErrInvalidRefreshToken
+		}
+
+		res, err = s.issueTokens(ctx, *user, now)
+
+		return err
+	})
+
+	return res, err
+}
+
+// Logout revokes the refresh token, the access token stays valid until it expires. Unknown or
+// already revoked tokens are ignored so logging out twice is not an error.
+func (s *authService) Logout(ctx context.Context, request payload.LogoutRequest) error {
+	_, err := s.refreshRepo.RevokeRefreshToken(ctx, auth.HashRefreshToken(request.RefreshToken), time.Now())
+	if err != nil {
+		slog.ErrorContext(ctx, "[AuthService][Logout] failed to revoke refresh token", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *authService) Me(ctx context.Context) (res payload.MeResponse, err error) {
+	// an API key is not an account, there is nobody to describe
+	caller, ok := auth.UserFromContext(ctx)
+	if !ok || caller.APIKey {
+		return res, errorcustom.ErrUnauthorized
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, caller.ID.String())
+	if err != nil {
+		slog.ErrorContext(ctx, "[AuthService][Me] failed to get user", "error", err, "user_id", caller.ID)
+		return res, err
+	}
+
+	if user == nil {
+		return res, errorcustom.ErrUserNotFound
+	}
+
+	res.UserResponse = toUserResponse(*user)
+
+	// permissions follow the stored role, which a refresh will also put in the access token
+	res.Permissions = []string{}
+	for _, permission := range auth.Permissions(user.Role) {
+		res.Permissions = append(res.Permissions, string(permission))
+	}
+
+	return res, nil
+}
+
+func (s *authService) issueTokens(ctx context.Context, user model.User, now time.Time) (res payload.TokenResponse, err error) {
+	refreshToken, refreshHash, refreshExpiresAt, err := s.tokens.NewRefreshToken(now)
+	if err != nil {
+		slog.ErrorContext(ctx, "[AuthService] failed to generate refresh token", "error", err, "user_id", user.ID)
+		return res, err
+	}
+
+	err = s.refreshRepo.CreateRefreshToken(ctx, model.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		TokenHash: refreshHash,
+		ExpiresAt: refreshExpiresAt,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "[AuthService] failed to store refresh token", "error", err, "user_id", user.ID)
+		return res, err
+	}
+
+	accessToken, expiresAt, err := s.tokens.SignAccessToken(auth.User{ID: user.ID, Email: user.Email, Role: user.Role}, now)
+	if err != nil {
+		slog.ErrorContext(ctx, "[AuthService] failed to sign access token", "error", err, "user_id", user.ID)
+		return res, err
+	}
+
+	return payload.TokenResponse{
+		AccessToken:      accessToken,
+		TokenType:        "Bearer",
+		ExpiresAt:        expiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refreshExpiresAt,
+	}, nil
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"library-backend/errorcustom"
+	"library-backend/internal/auth"
+	"library-backend/internal/config"
+	"library-backend/internal/model"
+	"library-backend/internal/payload"
+	"library-backend/internal/repository/mock"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+)
+
+func newTestTokenManager() *auth.TokenManager {
+	return auth.NewTokenManager(&config.Config{
+		AppName:             "library-test",
+		JWTSecret:           "test-secret",
+		JWTAccessTTLMinutes: 15,
+		JWTRefreshTTLHours:  24,
+	})
+}
+
+func Test_authService_Login(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tokens := newTestTokenManager()
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockRefreshRepo := mock.NewMockRefreshTokenRepository(ctrl)
+	service := NewAuthService(tokens, mock.NewMockTransactor(ctrl), mockUserRepo, mockRefreshRepo)
+
+	ctx := context.Background()
+
+	passwordHash, err := auth.HashPassword("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user := &model.User{ID: uuid.New(), Email: "admin@library.test", PasswordHash: passwordHash, Role: model.UserRoleLibrarian, Status: model.UserStatusActive}
+	disabledUser := &model.User{ID: uuid.New(), Email: "old@library.test", PasswordHash: passwordHash, Status: model.UserStatusDisabled}
+
+	tests := []struct {
+		name     string
+		mockFunc func()
+		request  payload.LoginRequest
+		wantErr  error
+	}{
+		{
+			name: "success",
+			mockFunc: func() {
+				mockUserRepo.EXPECT().GetUserByEmail(ctx, user.Email).Return(user, nil)
+				mockRefreshRepo.EXPECT().CreateRefreshToken(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, token model.RefreshToken) error {
+					if token.UserID != user.ID || len(token.TokenHash) != 64 {
+						t.Errorf("authService.Login() unexpected refresh token %+v", token)
+					}
+					return nil
+				})
+				mockUserRepo.EXPECT().UpdateLastLogin(ctx, user.ID.String(), gomock.Any()).Return(nil)
+			},
+			request: payload.LoginRequest{Email: user.Email, Password: "correct horse"},
+		},
+		{
+			name: "wrong password",
+			mockFunc: func() {
+				mockUserRepo.EXPECT().GetUserByEmail(ctx, user.Email).Return(user, nil)
+			},
+			request: payload.LoginRequest{Email: user.Email, Password: "battery staple"},
+			wantErr: errorcustom.ErrInvalidCredentials,
+		},
+		{
+			name: "unknown email",
+			mockFunc: func() {
+				mockUserRepo.EXPECT().GetUserByEmail(ctx, "nobody@library.test").Return(nil, nil)
+			},
+			request: payload.LoginRequest{Email: "nobody@library.test", Password: "correct horse"},
+			wantErr: errorcustom.ErrInvalidCredentials,
+		},
+		{
+			name: "disabled user",
+			mockFunc: func() {
+				mockUserRepo.EXPECT().GetUserByEmail(ctx, disabledUser.Email).Return(disabledUser, nil)
+			},
+			request: payload.LoginRequest{Email: disabledUser.Email, Password: "correct horse"},
+			wantErr: errorcustom.ErrInvalidCredentials,
+		},
+		{
+			name: "repository error",
+			mockFunc: func() {
+				mockUserRepo.EXPECT().GetUserByEmail(ctx, user.Email).Return(nil, errors.New("db error"))
+			},
+			request: payload.LoginRequest{Email: user.Email, Password: "correct horse"},
+			wantErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+			gotRes, err := service.Login(ctx, tt.request)
+			if (err != nil) != (tt.wantErr != nil) || (err != nil && err.Error() != tt.wantErr.Error()) {
+				t.Errorf("authService.Login() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			claims, err := tokens.ParseAccessToken(gotRes.AccessToken)
+			if err != nil {
+				t.Fatalf("authService.Login() returned an unusable access token: %v", err)
+			}
+			if claims.Subject != user.ID.String() || claims.Email != user.Email || claims.Role != user.Role {
+				t.Errorf("authService.Login() claims = %+v, want subject %v", claims, user.ID)
+			}
+			if gotRes.TokenType != "Bearer" || gotRes.RefreshToken == "" {
+				t.Errorf("authService.Login() unexpected response %+v", gotRes)
+			}
+		})
+	}
+}
+
+func Test_authService_Refresh(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactor := mock.NewMockTransactor(ctrl)
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockRefreshRepo := mock.NewMockRefreshTokenRepository(ctrl)
+	service := NewAuthService(newTestTokenManager(), mockTransactor, mockUserRepo, mockRefreshRepo)
+
+	ctx := context.Background()
+	user := &model.User{ID: uuid.New(), Email: "admin@library.test", Status: model.UserStatusActive}
+	stored := &model.RefreshToken{ID: uuid.New(), UserID: user.ID, ExpiresAt: time.Now().Add(time.Hour)}
+	refreshHash := auth.HashRefreshToken("old-refresh-token")
+
+	tests := []struct {
+		name     string
+		mockFunc func()
+		wantErr  error
+	}{
+		{
+			name: "success rotates the refresh token",
+			mockFunc: func() {
+				runInTransaction(mockTransactor)
+				mockRefreshRepo.EXPECT().RevokeRefreshToken(ctx, refreshHash, gomock.Any()).Return(stored, nil)
+				mockUserRepo.EXPECT().GetUserByID(ctx, user.ID.String()).Return(user, nil)
+				mockRefreshRepo.EXPECT().CreateRefreshToken(ctx, gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "revoked, expired or unknown token",
+			mockFunc: func() {
+				runInTransaction(mockTransactor)
+				mockRefreshRepo.EXPECT().RevokeRefreshToken(ctx, refreshHash, gomock.Any()).Return(nil, nil)
+			},
+			wantErr: errorcustom.ErrInvalidRefreshToken,
+		},
+		{
+			name: "user disabled since",
+			mockFunc: func() {
+				runInTransaction(mockTransactor)
+				mockRefreshRepo.EXPECT().RevokeRefreshToken(ctx, refreshHash, gomock.Any()).Return(stored, nil)
+				mockUserRepo.EXPECT().GetUserByID(ctx, user.ID.String()).Return(&model.User{ID: user.ID, Status: model.UserStatusDisabled}, nil)
+			},
+			wantErr: errorcustom.ErrInvalidRefreshToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+			gotRes, err := service.Refresh(ctx, payload.RefreshTokenRequest{RefreshToken: "old-refresh-token"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("authService.Refresh() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr == nil && (gotRes.AccessToken == "" || gotRes.RefreshToken == "old-refresh-token") {
+				t.Errorf("authService.Refresh() unexpected response %+v", gotRes)
+			}
+		})
+	}
+}
+
+func Test_authService_Me(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	service := NewAuthService(newTestTokenManager(), mock.NewMockTransactor(ctrl), mockUserRepo, mock.NewMockRefreshTokenRepository(ctrl))
+
+	user := &model.User{ID: uuid.New(), Name: "Member", Email: "member@library.test", Role: model.UserRoleMember, Status: model.UserStatusActive}
+
+	t.Run("signed in", func(t *testing.T) {
+		ctx := auth.WithUser(context.Background(), auth.User{ID: user.ID, Email: user.Email, Role: user.Role})
+		mockUserRepo.EXPECT().GetUserByID(ctx, user.ID.String()).Return(user, nil)
+
+		gotRes, err := service.Me(ctx)
+		if err != nil {
+			t.Fatalf("authService.Me() error = %v", err)
+		}
+		if gotRes.ID != user.ID || gotRes.Email != user.Email {
+			t.Errorf("authService.Me() = %+v, want user %v", gotRes, user.ID)
+		}
+		if len(gotRes.Permissions) != 1 || gotRes.Permissions[0] != string(auth.PermBooksRead) {
+			t.Errorf("authService.Me() permissions = %v, want [%v]", gotRes.Permissions, auth.PermBooksRead)
+		}
+	})
+
+	t.Run("anonymous", func(t *testing.T) {
+		_, err := service.Me(context.Background())
+		if !errors.Is(err, errorcustom.ErrUnauthorized) {
+			t.Errorf("authService.Me() error = %v, wantErr %v", err, errorcustom.ErrUnauthorized)
+		}
+	})
+}
@@ -3,33 +3,129 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
 	"library-backend/errorcustom"
+	"library-backend/internal/model"
 	"library-backend/internal/payload"
 	"library-backend/internal/repository"
+	"library-backend/pkg/isbn"
 	"log/slog"
 	"math"
-	"reflect"
 	"strings"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 type BookService interface {
 	CreateBook(ctx context.Context, request payload.CreateBookRequest) (payload.CreateBookResponse, error)
 	GetBooks(ctx context.Context, request payload.GetBooksRequest) (payload.GetBooksResponse, error)
 	GetBookByID(ctx context.Context, id string) (payload.GetBookByIDResponse, error)
+	GetBookByISBN(ctx context.Context, request payload.GetBookByISBNRequest) (payload.GetBookByIDResponse, error)
+	GetBookByBarcode(ctx context.Context, request payload.GetBookByBarcodeRequest) (payload.GetBookByIDResponse, error)
+	ImportBooks(ctx context.Context, request payload.ImportBooksRequest, file io.Reader) (payload.ImportBooksResponse, error)
+	ExportBooks(ctx context.Context, request payload.ExportBooksRequest) (BookExport, error)
+	GetBookMARC(ctx context.Context, request payload.GetBookMARCRequest) ([]byte, error)
+	GetBookCitation(ctx context.Context, request payload.GetBookCitationRequest) ([]byte, error)
+	GetBooksCitation(ctx context.Context, request payload.GetBooksCitationRequest) ([]byte, error)
 	UpdateBook(ctx context.Context, request payload.UpdateBookRequest) error
 	DeleteBook(ctx context.Context, request payload.DeleteBookRequest) error
+	GetDeletedBooks(ctx context.Context, request payload.GetDeletedBooksRequest) (payload.GetDeletedBooksResponse, error)
+	RestoreBook(ctx context.Context, request payload.RestoreBookRequest) error
+	PurgeBook(ctx context.Context, request payload.PurgeBookRequest) error
 }
 
 type bookService struct {
-	bookRepo repository.BookRepository
+	transactor repository.Transactor
+	bookRepo   repository.BookRepository
+	auditRepo  repository.AuditRepository
+}
+
+func NewBookService(
+	transactor repository.Transactor,
+	bookRepo repository.BookRepository,
+	auditRepo repository.AuditRepository,
+) BookService {
+	return &bookService{
+		transactor: transactor,
+		bookRepo:   bookRepo,
+		auditRepo:  auditRepo,
+	}
 }
 
-func NewBookService(bookRepo repository.BookRepository) BookService {
-	return &bookService{bookRepo: bookRepo}
+// bookAuditState is what the audit trail records of a book, its stored fields without the copy
+// counts and search details that are derived per query.
+type bookAuditState struct {
+	ID                uuid.UUID  `json:"id"`
+	ISBN              string     `json:"isbn"`
+	ISBN13            string     `json:"isbn13"`
+	Title             string     `json:"title"`
+	Author            string     `json:"author"`
+	Publisher         string     `json:"publisher"`
+	YearOfPublication int        `json:"year_of_publication"`
+	Category          string     `json:"category"`
+	ImageURL          string     `json:"image_url"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	DeletedAt         *time.Time `json:"deleted_at,omitempty"`
+}
+
+func toBookAuditState(book model.Book) bookAuditState {
+	return bookAuditState{
+		ID:                book.ID,
+		ISBN:              book.ISBN,
+		ISBN13:            book.ISBN13,
+		Title:             book.Title,
+		Author:            book.Author,
+		Publisher:         book.Publisher,
+		YearOfPublication: book.YearOfPublication,
+		Category:          book.Category,
+		ImageURL:          book.ImageURL,
+		CreatedAt:         book.CreatedAt,
+		UpdatedAt:         book.UpdatedAt,
+		DeletedAt:         book.DeletedAt,
+	}
+}
+
+// getBookAfterWrite reads back a book written earlier in the same transaction, for the audit trail.
+func (s *bookService) getBookAfterWrite(ctx context.Context, id string) (bookAuditState, error) {
+	book, err := s.bookRepo.GetBookByID(ctx, id)
+	if err != nil {
+		return bookAuditState{}, err
+	}
+
+	if book == nil {
+		return bookAuditState{}, errorcustom.ErrBookNotFound
+	}
+
+	return toBookAuditState(*book), nil
 }
 
 func (s *bookService) CreateBook(ctx context.Context, request payload.CreateBookRequest) (res payload.CreateBookResponse, err error) {
+	// books are told apart by their canonical ISBN-13, however the ISBN was typed
+	isbn13, err := isbn.Normalize(request.ISBN)
+	if err != nil {
+		return res, errorcustom.ErrInvalidISBN
+	}
+
+	// a deleted book keeps its ISBN, so the same book is not quietly created twice
+	deleted, err := s.bookRepo.GetDeletedBookByISBN(ctx, isbn13)
+	if err != nil {
+		slog.ErrorContext(ctx, "[BookService][CreateBook] failed to get deleted book by isbn", "error", err, "isbn", request.ISBN)
+		return res, err
+	}
+
+	if deleted != nil {
+		if !request.Restore {
+			return res, fmt.Errorf("%w (id %s)", errorcustom.ErrBookInTrash, deleted.ID)
+		}
+
+		return s.restoreBookForCreate(ctx, *deleted, request)
+	}
+
 	book := request.ToModel()
+	book.ISBN13 = isbn13
 
 	// if image URL is empty, set it to the default image URL
 	if book.ImageURL == "" {
@@ -37,12 +133,31 @@ func (s *bookService) CreateBook(ctx context.Context, request payload.CreateBook
 		book.ImageURL = "https://ik.imagekit.io/tten6kleuk/book-covers/empty-book-cover.jpg"
 	}
 
-	err = s.bookRepo.CreateBook(ctx, book)
-	if err != nil {
-		if strings.Contains(err.Error(), "ERROR: duplicate key value violates unique constraint \"books_isbn_key\"") {
-			return res, errorcustom.ErrBookAlreadyExists
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := s.bookRepo.CreateBook(ctx, book)
+		if err != nil {
+			if strings.Contains(err.Error(), "ERROR: duplicate key value violates unique constraint \"books_isbn13_key\"") {
+				return errorcustom.ErrBookAlreadyExists
+			}
+			slog.ErrorContext(ctx, "[BookService][CreateBook] failed to create book", "error", err)
+			return err
+		}
+
+		after, err := s.getBookAfterWrite(ctx, book.ID.String())
+		if err != nil {
+			slog.ErrorContext(ctx, "[BookService][CreateBook] failed to read back book", "error", err, "id", book.ID)
+			return err
 		}
-		slog.ErrorContext(ctx, "[BookService][CreateBook] failed to create book", "error", err)
+
+		err = recordAudit(ctx, s.auditRepo, model.AuditActionCreate, model.AuditEntityBook, book.ID, nil, after)
+		if err != nil {
+			slog.ErrorContext(ctx, "[BookService][CreateBook] failed to record audit event", "error", err, "id", book.ID)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
 		return res, err
 	}
 
@@ -51,39 +166,106 @@ func (s *bookService) CreateBook(ctx context.Context, request payload.CreateBook
 	return res, nil
 }
 
+// restoreBookForCreate restores a deleted book and overwrites its details with the create request.
+// The deleted book keeps its cover when the request has none.
+func (s *bookService) restoreBookForCreate(ctx context.Context, deleted model.Book, request payload.CreateBookRequest) (res payload.CreateBookResponse, err error) {
+	id := deleted.ID.String()
+
+	updates := map[string]any{
+		"isbn":                strings.TrimSpace(request.ISBN),
+		"title":               request.Title,
+		"author":              request.Author,
+		"publisher":           request.Publisher,
+		"year_of_publication": request.YearOfPublication,
+		"category":            request.Category,
+	}
+	if request.ImageURL != "" {
+		updates["image_url"] = request.ImageURL
+	}
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := s.bookRepo.RestoreBook(ctx, id)
+		if err != nil {
+			if strings.Contains(err.Error(), "ERROR: duplicate key value violates unique constraint \"books_isbn13_key\"") {
+				return errorcustom.ErrBookAlreadyExists
+			}
+			slog.ErrorContext(ctx, "[BookService][CreateBook] failed to restore book", "error", err, "id", id)
+			return err
+		}
+
+		err = s.bookRepo.UpdateBook(ctx, id, updates)
+		if err != nil {
+			slog.ErrorContext(ctx, "[BookService][CreateBook] failed to update restored book", "error", err, "id", id)
+			return err
+		}
+
+		after, err := s.getBookAfterWrite(ctx, id)
+		if err != nil {
+			slog.ErrorContext(ctx, "[BookService][CreateBook] failed to read back book", "error", err, "id", id)
+			return err
+		}
+
+		err = recordAudit(ctx, s.auditRepo, model.AuditActionRestore, model.AuditEntityBook, deleted.ID, toBookAuditState(deleted), after)
+		if err != nil {
+			slog.ErrorContext(ctx, "[BookService][CreateBook] failed to record audit event", "error", err, "id", id)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return res, err
+	}
+
+	res.ID = deleted.ID
+	res.Restored = true
+
+	return res, nil
+}
+
 func (s *bookService) GetBooks(ctx context.Context, request payload.GetBooksRequest) (res payload.GetBooksResponse, err error) {
+	request.FilterExpr, err = payload.ParseFilter(request.Filter, payload.BookFilterFields)
+	if err != nil {
+		return res, err
+	}
+
+	request.FacetFields, err = payload.ParseFacets(request.Facets, payload.BookFacetFields)
+	if err != nil {
+		return res, err
+	}
+
+	if request.CursorMode {
+		return s.getBooksByCursor(ctx, request)
+	}
+
 	request.Offset = (request.Page - 1) * request.Limit
 
-	// get books with pagination
-	books, err := s.bookRepo.GetBooks(ctx, request)
+	request.SortFields, err = payload.ParseSort(request.Sort, payload.BookSortFields)
 	if err != nil {
-		slog.ErrorContext(ctx, "[BookService][GetBooks] failed to get books", "error", err)
 		return res, err
 	}
 
-	// get total count of books
-	totalCount, err := s.bookRepo.GetBooksCount(ctx)
+	// get books with pagination, along with the total count of matching books
+	books, totalCount, err := s.bookRepo.GetBooks(ctx, request)
 	if err != nil {
-		slog.ErrorContext(ctx, "[BookService][GetBooks] failed to get books count", "error", err)
+		slog.ErrorContext(ctx, "[BookService][GetBooks] failed to get books", "error", err)
 		return res, err
 	}
 
+	// a page past the end has no rows to carry the total, so count the matches separately
+	if len(books) == 0 && request.Offset > 0 {
+		totalCount, err = s.bookRepo.GetBooksCount(ctx, request)
+		if err != nil {
+			slog.ErrorContext(ctx, "[BookService][GetBooks] failed to get books count", "error", err)
+			return res, err
+		}
+	}
+
 	totalPages := int(math.Ceil(float64(totalCount) / float64(request.Limit)))
 
 	bookResponses := make([]payload.BookResponse, len(books))
 	for i, book := range books {
-		bookResponses[i] = payload.BookResponse{
-			ID:                book.ID,
-			ISBN:              book.ISBN,
-			Title:             book.Title,
-			Author:            book.Author,
-			Publisher:         book.Publisher,
-			YearOfPublication: book.YearOfPublication,
-			Category:          book.Category,
-			ImageURL:          book.ImageURL,
-			CreatedAt:         book.CreatedAt,
-			UpdatedAt:         book.UpdatedAt,
-		}
+		bookResponses[i] = toBookResponse(book)
 	}
 
 	res.Books = bookResponses
@@ -94,9 +276,75 @@ func (s *bookService) GetBooks(ctx context.Context, request payload.GetBooksRequ
 		TotalItem: totalCount,
 	}
 
+	res.Facets, err = s.getBookFacets(ctx, request)
+	if err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// getBooksByCursor pages through books by seeking past the last (updated_at, id) seen, which stays
+// stable while books are being updated, unlike an offset.
+func (s *bookService) getBooksByCursor(ctx context.Context, request payload.GetBooksRequest) (res payload.GetBooksResponse, err error) {
+	if request.Sort != "" || request.Q != "" {
+		return res, errorcustom.ErrCursorUnsupported
+	}
+
+	if request.Cursor != "" {
+		request.After, err = payload.DecodeBookCursor(request.Cursor)
+		if err != nil {
+			return res, err
+		}
+	}
+
+	// one extra book tells whether there is a next page
+	limit := request.Limit
+	request.Limit++
+
+	books, _, err := s.bookRepo.GetBooks(ctx, request)
+	if err != nil {
+		slog.ErrorContext(ctx, "[BookService][GetBooks] failed to get books by cursor", "error", err)
+		return res, err
+	}
+
+	if len(books) > limit {
+		books = books[:limit]
+		last := books[limit-1]
+		res.NextCursor = payload.BookCursor{UpdatedAt: last.UpdatedAt, ID: last.ID}.Encode()
+	}
+
+	res.Books = make([]payload.BookResponse, len(books))
+	for i, book := range books {
+		res.Books[i] = toBookResponse(book)
+	}
+
+	res.Pagination = payload.Pagination{Limit: limit}
+
+	res.Facets, err = s.getBookFacets(ctx, request)
+	if err != nil {
+		return res, err
+	}
+
 	return res, nil
 }
 
+// getBookFacets counts the books matching the request by the facets it asks for, nil when it asks
+// for none.
+func (s *bookService) getBookFacets(ctx context.Context, request payload.GetBooksRequest) (map[string][]payload.BookFacetBucket, error) {
+	if len(request.FacetFields) == 0 {
+		return nil, nil
+	}
+
+	facets, err := s.bookRepo.GetBookFacets(ctx, request)
+	if err != nil {
+		slog.ErrorContext(ctx, "[BookService][GetBooks] failed to get book facets", "error", err, "facets", request.FacetFields)
+		return nil, err
+	}
+
+	return facets, nil
+}
+
 func (s *bookService) GetBookByID(ctx context.Context, id string) (res payload.GetBookByIDResponse, err error) {
 	book, err := s.bookRepo.GetBookByID(ctx, id)
 	if err != nil {
@@ -108,54 +356,55 @@ func (s *bookService) GetBookByID(ctx context.Context, id string) (res payload.G
 		return res, errorcustom.ErrBookNotFound
 	}
 
-	res.BookResponse = payload.BookResponse{
-		ID:                book.ID,
-		ISBN:              book.ISBN,
-		Title:             book.Title,
-		Author:            book.Author,
-		Publisher:         book.Publisher,
-		YearOfPublication: book.YearOfPublication,
-		Category:          book.Category,
-		ImageURL:          book.ImageURL,
-		CreatedAt:         book.CreatedAt,
-		UpdatedAt:         book.UpdatedAt,
-	}
+	res.BookResponse = toBookResponse(*book)
 
 	return res, nil
 }
 
-func (s *bookService) buildUpdateMap(request payload.UpdateBookRequest) map[string]any {
-	updates := make(map[string]any)
+// GetBookByISBN finds a book by any form of its ISBN, or by the EAN-13 barcode printed on it.
+func (s *bookService) GetBookByISBN(ctx context.Context, request payload.GetBookByISBNRequest) (res payload.GetBookByIDResponse, err error) {
+	isbn13, err := isbn.Normalize(request.ISBN)
+	if err != nil {
+		isbn13, err = isbn.FromBarcode(request.ISBN)
+		if err != nil {
+			return res, errorcustom.ErrInvalidISBN
+		}
+	}
 
-	v := reflect.ValueOf(request)
-	t := reflect.TypeOf(request)
+	book, err := s.bookRepo.GetBookByISBN(ctx, isbn13)
+	if err != nil {
+		slog.ErrorContext(ctx, "[BookService][GetBookByISBN] failed to get book by isbn", "error", err, "isbn", isbn13)
+		return res, err
+	}
 
-	for i := 0; i < v.NumField(); i++ {
-		field := v.Field(i)
-		fieldType := t.Field(i)
+	if book == nil {
+		return res, errorcustom.ErrBookNotFound
+	}
 
-		jsonTag := fieldType.Tag.Get("json")
-		if jsonTag == "" || jsonTag == "-" {
-			continue
-		}
+	res.BookResponse = toBookResponse(*book)
 
-		// Extract field name from json tag (remove ",omitempty" etc.)
-		fieldName := jsonTag
-		if commaIdx := strings.Index(jsonTag, ","); commaIdx != -1 {
-			fieldName = jsonTag[:commaIdx]
-		}
+	return res, nil
+}
 
-		// Skip ID field as it shouldn't be updated
-		if fieldName == "id" {
-			continue
-		}
+// GetBookByBarcode finds the book a copy belongs to by the copy's barcode.
+func (s *bookService) GetBookByBarcode(ctx context.Context, request payload.GetBookByBarcodeRequest) (res payload.GetBookByIDResponse, err error) {
+	book, err := s.bookRepo.GetBookByCopyBarcode(ctx, strings.TrimSpace(request.Barcode))
+	if err != nil {
+		slog.ErrorContext(ctx, "[BookService][GetBookByBarcode] failed to get book by copy barcode", "error", err, "barcode", request.Barcode)
+		return res, err
+	}
 
-		if field.Kind() == reflect.Ptr && !field.IsNil() {
-			updates[fieldName] = field.Elem().Interface()
-		}
+	if book == nil {
+		return res, errorcustom.ErrBookNotFound
 	}
 
-	return updates
+	res.BookResponse = toBookResponse(*book)
+
+	return res, nil
+}
+
+func (s *bookService) buildUpdateMap(request payload.UpdateBookRequest) map[string]any {
+	return buildUpdateMap(request)
 }
 
 func (s *bookService) UpdateBook(ctx context.Context, request payload.UpdateBookRequest) (err error) {
@@ -176,14 +425,41 @@ func (s *bookService) UpdateBook(ctx context.Context, request payload.UpdateBook
 		return errors.New("no fields to update")
 	}
 
-	// Update the book
-	err = s.bookRepo.UpdateBook(ctx, request.ID, updates)
-	if err != nil {
-		slog.ErrorContext(ctx, "[BookService][UpdateBook] failed to update book", "error", err, "id", request.ID)
-		return err
+	// a new ISBN moves the canonical form along with it
+	if request.ISBN != nil {
+		isbn13, err := isbn.Normalize(*request.ISBN)
+		if err != nil {
+			return errorcustom.ErrInvalidISBN
+		}
+		updates["isbn"] = strings.TrimSpace(*request.ISBN)
+		updates["isbn13"] = isbn13
 	}
 
-	return nil
+	// Update the book, together with the audit event describing the change
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := s.bookRepo.UpdateBook(ctx, request.ID, updates)
+		if err != nil {
+			if strings.Contains(err.Error(), "ERROR: duplicate key value violates unique constraint \"books_isbn13_key\"") {
+				return errorcustom.ErrBookAlreadyExists
+			}
+			slog.ErrorContext(ctx, "[BookService][UpdateBook] failed to update book", "error", err, "id", request.ID)
+			return err
+		}
+
+		after, err := s.getBookAfterWrite(ctx, request.ID)
+		if err != nil {
+			slog.ErrorContext(ctx, "[BookService][UpdateBook] failed to read back book", "error", err, "id", request.ID)
+			return err
+		}
+
+		err = recordAudit(ctx, s.auditRepo, model.AuditActionUpdate, model.AuditEntityBook, book.ID, toBookAuditState(*book), after)
+		if err != nil {
+			slog.ErrorContext(ctx, "[BookService][UpdateBook] failed to record audit event", "error", err, "id", request.ID)
+			return err
+		}
+
+		return nil
+	})
 }
 
 func (s *bookService) DeleteBook(ctx context.Context, request payload.DeleteBookRequest) (err error) {
@@ -198,12 +474,150 @@ func (s *bookService) DeleteBook(ctx context.Context, request payload.DeleteBook
 		return errorcustom.ErrBookNotFound
 	}
 
-	// Soft delete the book
-	err = s.bookRepo.DeleteBook(ctx, request.ID)
+	// Soft delete the book, together with the audit event describing the change
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := s.bookRepo.DeleteBook(ctx, request.ID)
+		if err != nil {
+			slog.ErrorContext(ctx, "[BookService][DeleteBook] failed to delete book", "error", err, "id", request.ID)
+			return err
+		}
+
+		err = recordAudit(ctx, s.auditRepo, model.AuditActionDelete, model.AuditEntityBook, book.ID, toBookAuditState(*book), nil)
+		if err != nil {
+			slog.ErrorContext(ctx, "[BookService][DeleteBook] failed to record audit event", "error", err, "id", request.ID)
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (s *bookService) GetDeletedBooks(ctx context.Context, request payload.GetDeletedBooksRequest) (res payload.GetDeletedBooksResponse, err error) {
+	request.Offset = (request.Page - 1) * request.Limit
+
+	books, err := s.bookRepo.GetDeletedBooks(ctx, request)
+	if err != nil {
+		slog.ErrorContext(ctx, "[BookService][GetDeletedBooks] failed to get deleted books", "error", err)
+		return res, err
+	}
+
+	totalCount, err := s.bookRepo.GetDeletedBooksCount(ctx, request)
+	if err != nil {
+		slog.ErrorContext(ctx, "[BookService][GetDeletedBooks] failed to get deleted books count", "error", err)
+		return res, err
+	}
+
+	totalPages := int(math.Ceil(float64(totalCount) / float64(request.Limit)))
+
+	bookResponses := make([]payload.BookResponse, len(books))
+	for i, book := range books {
+		bookResponses[i] = toBookResponse(book)
+	}
+
+	res.Books = bookResponses
+	res.Pagination = payload.Pagination{
+		Page:      request.Page,
+		Limit:     request.Limit,
+		TotalPage: totalPages,
+		TotalItem: totalCount,
+	}
+
+	return res, nil
+}
+
+// RestoreBook takes a book out of the trash, together with the audit event describing it.
+func (s *bookService) RestoreBook(ctx context.Context, request payload.RestoreBookRequest) (err error) {
+	book, err := s.bookRepo.GetDeletedBookByID(ctx, request.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[BookService][RestoreBook] failed to get deleted book", "error", err, "id", request.ID)
+		return err
+	}
+
+	if book == nil {
+		return errorcustom.ErrBookNotFound
+	}
+
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := s.bookRepo.RestoreBook(ctx, request.ID)
+		if err != nil {
+			if strings.Contains(err.Error(), "ERROR: duplicate key value violates unique constraint \"books_isbn13_key\"") {
+				return errorcustom.ErrBookAlreadyExists
+			}
+			slog.ErrorContext(ctx, "[BookService][RestoreBook] failed to restore book", "error", err, "id", request.ID)
+			return err
+		}
+
+		after, err := s.getBookAfterWrite(ctx, request.ID)
+		if err != nil {
+			slog.ErrorContext(ctx, "[BookService][RestoreBook] failed to read back book", "error", err, "id", request.ID)
+			return err
+		}
+
+		err = recordAudit(ctx, s.auditRepo, model.AuditActionRestore, model.AuditEntityBook, book.ID, toBookAuditState(*book), after)
+		if err != nil {
+			slog.ErrorContext(ctx, "[BookService][RestoreBook] failed to record audit event", "error", err, "id", request.ID)
+			return err
+		}
+
+		return nil
+	})
+}
+
+// PurgeBook permanently deletes a book in the trash along with its copies. Books that were ever
+// loaned or held stay, their history refers to them.
+func (s *bookService) PurgeBook(ctx context.Context, request payload.PurgeBookRequest) (err error) {
+	book, err := s.bookRepo.GetDeletedBookByID(ctx, request.ID)
 	if err != nil {
-		slog.ErrorContext(ctx, "[BookService][DeleteBook] failed to delete book", "error", err, "id", request.ID)
+		slog.ErrorContext(ctx, "[BookService][PurgeBook] failed to get deleted book", "error", err, "id", request.ID)
 		return err
 	}
 
-	return nil
+	if book == nil {
+		return errorcustom.ErrBookNotFound
+	}
+
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := s.bookRepo.PurgeBook(ctx, request.ID)
+		if err != nil {
+			if strings.Contains(err.Error(), "violates foreign key constraint") {
+				return errorcustom.ErrBookPurgeBlocked
+			}
+			slog.ErrorContext(ctx, "[BookService][PurgeBook] failed to purge book", "error", err, "id", request.ID)
+			return err
+		}
+
+		err = recordAudit(ctx, s.auditRepo, model.AuditActionPurge, model.AuditEntityBook, book.ID, toBookAuditState(*book), nil)
+		if err != nil {
+			slog.ErrorContext(ctx, "[BookService][PurgeBook] failed to record audit event", "error", err, "id", request.ID)
+			return err
+		}
+
+		return nil
+	})
+}
+
+func toBookResponse(book model.Book) payload.BookResponse {
+	res := payload.BookResponse{
+		ID:                book.ID,
+		ISBN:              book.ISBN,
+		ISBN13:            book.ISBN13,
+		Title:             book.Title,
+		Author:            book.Author,
+		Publisher:         book.Publisher,
+		YearOfPublication: book.YearOfPublication,
+		Category:          book.Category,
+		ImageURL:          book.ImageURL,
+		CreatedAt:         book.CreatedAt,
+		UpdatedAt:         book.UpdatedAt,
+		TotalCopies:       book.TotalCopies,
+		AvailableCopies:   book.AvailableCopies,
+		Rank:              book.Rank,
+		DeletedAt:         book.DeletedAt,
+	}
+
+	if book.Highlight != nil {
+		res.Highlight = *book.Highlight
+	}
+
+	return res
 }
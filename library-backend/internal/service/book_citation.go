@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"library-backend/errorcustom"
+	"library-backend/internal/model"
+	"library-backend/internal/payload"
+	"library-backend/pkg/citation"
+	"log/slog"
+)
+
+// GetBookCitation returns a citation of a book, BibTeX unless RIS or CSL-JSON is asked for.
+func (s *bookService) GetBookCitation(ctx context.Context, request payload.GetBookCitationRequest) ([]byte, error) {
+	book, err := s.bookRepo.GetBookByID(ctx, request.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[BookService][GetBookCitation] failed to get book by ID", "error", err, "id", request.ID)
+		return nil, err
+	}
+
+	if book == nil {
+		return nil, errorcustom.ErrBookNotFound
+	}
+
+	res, err := formatCitation([]citation.Work{bookToCitation(*book)}, request.Format)
+	if err != nil {
+		slog.ErrorContext(ctx, "[BookService][GetBookCitation] failed to format citation", "error", err, "id", request.ID)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetBooksCitation cites several books in the order of their IDs, an ID given twice is cited once.
+// It fails with ErrBookNotFound, naming the ID, when one of the books does not exist.
+func (s *bookService) GetBooksCitation(ctx context.Context, request payload.GetBooksCitationRequest) ([]byte, error) {
+	var ids []string
+	seen := make(map[string]bool, len(request.IDs))
+	for _, id := range request.IDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	books, err := s.bookRepo.GetBooksByIDs(ctx, ids)
+	if err != nil {
+		slog.ErrorContext(ctx, "[BookService][GetBooksCitation] failed to get books by IDs", "error", err)
+		return nil, err
+	}
+
+	byID := make(map[string]model.Book, len(books))
+	for _, book := range books {
+		byID[book.ID.String()] = book
+	}
+
+	works := make([]citation.Work, len(ids))
+	for i, id := range ids {
+		book, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", errorcustom.ErrBookNotFound, id)
+		}
+		works[i] = bookToCitation(book)
+	}
+
+	res, err := formatCitation(works, request.Format)
+	if err != nil {
+		slog.ErrorContext(ctx, "[BookService][GetBooksCitation] failed to format citations", "error", err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func bookToCitation(book model.Book) citation.Work {
+	return citation.Work{
+		ID:        book.ID.String(),
+		Title:     book.Title,
+		Authors:   citation.ParseNames(book.Author),
+		Publisher: book.Publisher,
+		Year:      book.YearOfPublication,
+		ISBN:      book.ISBN13,
+	}
+}
+
+func formatCitation(works []citation.Work, format string) ([]byte, error) {
+	switch format {
+	case payload.BookCitationFormatRIS:
+		return []byte(citation.RIS(works)), nil
+	case payload.BookCitationFormatCSLJSON:
+		return citation.CSLJSON(works)
+	default:
+		return []byte(citation.BibTeX(works)), nil
+	}
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"library-backend/errorcustom"
+	"library-backend/internal/model"
+	"library-backend/internal/payload"
+	"library-backend/internal/repository/mock"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+)
+
+func Test_bookService_GetBookCitation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockBookRepository(ctrl)
+	service := NewBookService(mock.NewMockTransactor(ctrl), mockRepo, mock.NewMockAuditRepository(ctrl))
+
+	ctx := context.Background()
+	book := &model.Book{
+		ID:                uuid.New(),
+		ISBN13:            "9780131103627",
+		Title:             "The C Programming Language",
+		Author:            "Brian W. Kernighan & Dennis M. Ritchie",
+		Publisher:         "Prentice Hall",
+		YearOfPublication: 1988,
+	}
+
+	t.Run("bibtex", func(t *testing.T) {
+		mockRepo.EXPECT().GetBookByID(ctx, book.ID.String()).Return(book, nil)
+
+		res, err := service.GetBookCitation(ctx, payload.GetBookCitationRequest{ID: book.ID.String(), Format: payload.BookCitationFormatBibTeX})
+		if err != nil {
+			t.Fatalf("bookService.GetBookCitation() error = %v", err)
+		}
+
+		for _, want := range []string{"@book{kernighan1988c,", "author = {Kernighan, Brian W. and Ritchie, Dennis M.}", "isbn = {9780131103627}"} {
+			if !strings.Contains(string(res), want) {
+				t.Errorf("bookService.GetBookCitation() = %s, want it to contain %s", res, want)
+			}
+		}
+	})
+
+	t.Run("ris", func(t *testing.T) {
+		mockRepo.EXPECT().GetBookByID(ctx, book.ID.String()).Return(book, nil)
+
+		res, err := service.GetBookCitation(ctx, payload.GetBookCitationRequest{ID: book.ID.String(), Format: payload.BookCitationFormatRIS})
+		if err != nil {
+			t.Fatalf("bookService.GetBookCitation() error = %v", err)
+		}
+
+		if !strings.HasPrefix(string(res), "TY  - BOOK\r\nAU  - Kernighan, Brian W.\r\nAU  - Ritchie, Dennis M.\r\n") ||
+			!strings.Contains(string(res), "ID  - "+book.ID.String()+"\r\n") {
+			t.Errorf("bookService.GetBookCitation() = %q", res)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockRepo.EXPECT().GetBookByID(ctx, book.ID.String()).Return(nil, nil)
+
+		_, err := service.GetBookCitation(ctx, payload.GetBookCitationRequest{ID: book.ID.String()})
+		if !errors.Is(err, errorcustom.ErrBookNotFound) {
+			t.Errorf("bookService.GetBookCitation() error = %v, wantErr %v", err, errorcustom.ErrBookNotFound)
+		}
+	})
+}
+
+func Test_bookService_GetBooksCitation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockBookRepository(ctrl)
+	service := NewBookService(mock.NewMockTransactor(ctrl), mockRepo, mock.NewMockAuditRepository(ctrl))
+
+	ctx := context.Background()
+	dune := model.Book{ID: uuid.New(), Title: "Dune", Author: "Frank Herbert", YearOfPublication: 1965}
+	messiah := model.Book{ID: uuid.New(), Title: "Dune Messiah", Author: "Frank Herbert", YearOfPublication: 1965}
+
+	t.Run("request order without duplicates", func(t *testing.T) {
+		ids := []string{messiah.ID.String(), dune.ID.String(), messiah.ID.String()}
+
+		mockRepo.EXPECT().GetBooksByIDs(ctx, []string{messiah.ID.String(), dune.ID.String()}).Return([]model.Book{dune, messiah}, nil)
+
+		res, err := service.GetBooksCitation(ctx, payload.GetBooksCitationRequest{IDs: ids, Format: payload.BookCitationFormatBibTeX})
+		if err != nil {
+			t.Fatalf("bookService.GetBooksCitation() error = %v", err)
+		}
+
+		// the second book with the same key gets a letter
+		var keys []string
+		for _, line := range strings.Split(string(res), "\n") {
+			if strings.HasPrefix(line, "@book{") {
+				keys = append(keys, line)
+			}
+		}
+		if want := []string{"@book{herbert1965dune,", "@book{herbert1965dunea,"}; !reflect.DeepEqual(keys, want) {
+			t.Errorf("bookService.GetBooksCitation() keys = %v, want %v", keys, want)
+		}
+
+		if strings.Index(string(res), "Dune Messiah") > strings.Index(string(res), "title = {Dune}") {
+			t.Errorf("bookService.GetBooksCitation() = %s, want the books in request order", res)
+		}
+	})
+
+	t.Run("missing book", func(t *testing.T) {
+		missing := uuid.New().String()
+
+		mockRepo.EXPECT().GetBooksByIDs(ctx, []string{dune.ID.String(), missing}).Return([]model.Book{dune}, nil)
+
+		_, err := service.GetBooksCitation(ctx, payload.GetBooksCitationRequest{IDs: []string{dune.ID.String(), missing}, Format: payload.BookCitationFormatCSLJSON})
+		if !errors.Is(err, errorcustom.ErrBookNotFound) || !strings.Contains(err.Error(), missing) {
+			t.Errorf("bookService.GetBooksCitation() error = %v, want %v naming %s", err, errorcustom.ErrBookNotFound, missing)
+		}
+	})
+}
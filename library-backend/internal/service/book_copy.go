@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"library-backend/errorcustom"
+	"library-backend/internal/config"
+	"library-backend/internal/model"
+	"library-backend/internal/payload"
+	"library-backend/internal/repository"
+	"log/slog"
+	"strings"
+)
+
+type BookCopyService interface {
+	CreateBookCopy(ctx context.Context, request payload.CreateBookCopyRequest) (payload.CreateBookCopyResponse, error)
+	GetBookCopies(ctx context.Context, request payload.GetBookCopiesRequest) (payload.GetBookCopiesResponse, error)
+	GetBookCopyByID(ctx context.Context, request payload.GetBookCopyByIDRequest) (payload.GetBookCopyByIDResponse, error)
+	UpdateBookCopy(ctx context.Context, request payload.UpdateBookCopyRequest) error
+	DeleteBookCopy(ctx context.Context, request payload.DeleteBookCopyRequest) error
+}
+
+type bookCopyService struct {
+	cfg        *config.Config
+	transactor repository.Transactor
+	copyRepo   repository.BookCopyRepository
+	bookRepo   repository.BookRepository
+	holdRepo   repository.HoldRepository
+}
+
+func NewBookCopyService(
+	cfg *config.Config,
+	transactor repository.Transactor,
+	copyRepo repository.BookCopyRepository,
+	bookRepo repository.BookRepository,
+	holdRepo repository.HoldRepository,
+) BookCopyService {
+	return &bookCopyService{
+		cfg:        cfg,
+		transactor: transactor,
+		copyRepo:   copyRepo,
+		bookRepo:   bookRepo,
+		holdRepo:   holdRepo,
+	}
+}
+
+func (s *bookCopyService) CreateBookCopy(ctx context.Context, request payload.CreateBookCopyRequest) (res payload.CreateBookCopyResponse, err error) {
+	if err = s.ensureBookExists(ctx, request.BookID); err != nil {
+		return res, err
+	}
+
+	bookCopy := request.ToModel()
+
+	if bookCopy.Condition == "" {
+		bookCopy.Condition = "good"
+	}
+
+	if bookCopy.Status == "" {
+		bookCopy.Status = model.BookCopyStatusAvailable
+	}
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.copyRepo.CreateBookCopy(ctx, bookCopy); err != nil {
+			return err
+		}
+
+		// a new copy serves the members queueing for the book before it goes on the shelf
+		if bookCopy.Status != model.BookCopyStatusAvailable {
+			return nil
+		}
+
+		_, err := releaseCopy(ctx, s.holdRepo, s.copyRepo, bookCopy.BookID, bookCopy.ID, s.cfg.HoldPickupDays)
+
+		return err
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "ERROR: duplicate key value violates unique constraint \"book_copies_barcode_key\"") {
+			return res, errorcustom.ErrBookCopyAlreadyExists
+		}
+		slog.ErrorContext(ctx, "[BookCopyService][CreateBookCopy] failed to create book copy", "error", err, "book_id", request.BookID)
+		return res, err
+	}
+
+	res.ID = bookCopy.ID
+
+	return res, nil
+}
+
+func (s *bookCopyService) GetBookCopies(ctx context.Context, request payload.GetBookCopiesRequest) (res payload.GetBookCopiesResponse, err error) {
+	if err = s.ensureBookExists(ctx, request.BookID); err != nil {
+		return res, err
+	}
+
+	copies, err := s.copyRepo.GetBookCopies(ctx, request)
+	if err != nil {
+		slog.ErrorContext(ctx, "[BookCopyService][GetBookCopies] failed to get book copies", "error", err, "book_id", request.BookID)
+		return res, err
+	}
+
+	res.Copies = make([]payload.BookCopyResponse, len(copies))
+	for i, bookCopy := range copies {
+		res.Copies[i] = toBookCopyResponse(bookCopy)
+	}
+
+	return res, nil
+}
+
+func (s *bookCopyService) GetBookCopyByID(ctx context.Context, request payload.GetBookCopyByIDRequest) (res payload.GetBookCopyByIDResponse, err error) {
+	bookCopy, err := s.getBookCopy(ctx, request.BookID, request.CopyID)
+	if err != nil {
+		return res, err
+	}
+
+	res.BookCopyResponse = toBookCopyResponse(*bookCopy)
+
+	return res, nil
+}
+
+func (s *bookCopyService) UpdateBookCopy(ctx context.Context, request payload.UpdateBookCopyRequest) (err error) {
+	bookCopy, err := s.getBookCopy(ctx, request.BookID, request.CopyID)
+	if err != nil {
+		return err
+	}
+
+	// the status of a copy out on loan or set aside for a hold is owned by the circulation flow
+	if request.Status != nil {
+		if err := checkCopyInCirculation(bookCopy); err != nil {
+			return err
+		}
+	}
+
+	updates := buildUpdateMap(request)
+	if len(updates) == 0 {
+		return errors.New("no fields to update")
+	}
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.copyRepo.UpdateBookCopy(ctx, request.CopyID, updates); err != nil {
+			return err
+		}
+
+		// a copy back from maintenance or found again serves the hold queue like a returned one
+		if request.Status == nil || *request.Status != model.BookCopyStatusAvailable {
+			return nil
+		}
+
+		_, err := releaseCopy(ctx, s.holdRepo, s.copyRepo, bookCopy.BookID, bookCopy.ID, s.cfg.HoldPickupDays)
+
+		return err
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "ERROR: duplicate key value violates unique constraint \"book_copies_barcode_key\"") {
+			return errorcustom.ErrBookCopyAlreadyExists
+		}
+		slog.ErrorContext(ctx, "[BookCopyService][UpdateBookCopy] failed to update book copy", "error", err, "id", request.CopyID)
+		return err
+	}
+
+	return nil
+}
+
+func (s *bookCopyService) DeleteBookCopy(ctx context.Context, request payload.DeleteBookCopyRequest) (err error) {
+	bookCopy, err := s.getBookCopy(ctx, request.BookID, request.CopyID)
+	if err != nil {
+		return err
+	}
+
+	if err := checkCopyInCirculation(bookCopy); err != nil {
+		return err
+	}
+
+	err = s.copyRepo.DeleteBookCopy(ctx, request.CopyID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[BookCopyService][DeleteBookCopy] failed to delete book copy", "error", err, "id", request.CopyID)
+		return err
+	}
+
+	return nil
+}
+
+func (s *bookCopyService) ensureBookExists(ctx context.Context, bookID string) error {
+	book, err := s.bookRepo.GetBookByID(ctx, bookID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[BookCopyService] failed to check book existence", "error", err, "book_id", bookID)
+		return err
+	}
+
+	if book == nil {
+		return errorcustom.ErrBookNotFound
+	}
+
+	return nil
+}
+
+// getBookCopy loads a copy and makes sure it belongs to the book named in the path.
+func (s *bookCopyService) getBookCopy(ctx context.Context, bookID, copyID string) (*model.BookCopy, error) {
+	bookCopy, err := s.copyRepo.GetBookCopyByID(ctx, copyID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[BookCopyService] failed to get book copy", "error", err, "id", copyID)
+		return nil, err
+	}
+
+	if bookCopy == nil || !strings.EqualFold(bookCopy.BookID.String(), bookID) {
+		return nil, errorcustom.ErrBookCopyNotFound
+	}
+
+	return bookCopy, nil
+}
+
+// checkCopyInCirculation reports whether the copy is out on loan or waiting on the pickup shelf.
+func checkCopyInCirculation(bookCopy *model.BookCopy) error {
+	switch bookCopy.Status {
+	case model.BookCopyStatusOnLoan:
+		return errorcustom.ErrBookCopyOnLoan
+	case model.BookCopyStatusOnHold:
+		return errorcustom.ErrBookCopyOnHold
+	}
+
+	return nil
+}
+
+func toBookCopyResponse(bookCopy model.BookCopy) payload.BookCopyResponse {
+	return payload.BookCopyResponse{
+		ID:            bookCopy.ID,
+		BookID:        bookCopy.BookID,
+		Barcode:       bookCopy.Barcode,
+		ShelfLocation: bookCopy.ShelfLocation,
+		Condition:     bookCopy.Condition,
+		Status:        bookCopy.Status,
+		CreatedAt:     bookCopy.CreatedAt,
+		UpdatedAt:     bookCopy.UpdatedAt,
+	}
+}
@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"library-backend/errorcustom"
+	"library-backend/internal/config"
+	"library-backend/internal/model"
+	"library-backend/internal/payload"
+	"library-backend/internal/repository/mock"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+)
+
+func Test_bookCopyService_CreateBookCopy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactor := mock.NewMockTransactor(ctrl)
+	mockCopyRepo := mock.NewMockBookCopyRepository(ctrl)
+	mockBookRepo := mock.NewMockBookRepository(ctrl)
+	mockHoldRepo := mock.NewMockHoldRepository(ctrl)
+	service := NewBookCopyService(&config.Config{HoldPickupDays: 3}, mockTransactor, mockCopyRepo, mockBookRepo, mockHoldRepo)
+
+	ctx := context.Background()
+	bookID := uuid.New().String()
+	sampleBook := &model.Book{ID: uuid.MustParse(bookID), Title: "Clean Code"}
+
+	request := payload.CreateBookCopyRequest{
+		BookID:        bookID,
+		Barcode:       "LIB-0001",
+		ShelfLocation: "A-12",
+	}
+
+	tests := []struct {
+		name     string
+		mockFunc func()
+		wantErr  bool
+		errorMsg string
+	}{
+		{
+			name: "success with defaults",
+			mockFunc: func() {
+				mockBookRepo.EXPECT().GetBookByID(ctx, bookID).Return(sampleBook, nil)
+				runInTransaction(mockTransactor)
+				mockCopyRepo.EXPECT().CreateBookCopy(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, bookCopy model.BookCopy) error {
+					if bookCopy.Status != model.BookCopyStatusAvailable || bookCopy.Condition != "good" {
+						t.Errorf("bookCopyService.CreateBookCopy() unexpected defaults status=%q condition=%q", bookCopy.Status, bookCopy.Condition)
+					}
+					return nil
+				})
+				mockHoldRepo.EXPECT().AssignNextHold(ctx, bookID, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+				mockCopyRepo.EXPECT().UpdateBookCopyStatus(ctx, gomock.Any(), model.BookCopyStatusAvailable).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "new copy goes to the first waiting hold",
+			mockFunc: func() {
+				mockBookRepo.EXPECT().GetBookByID(ctx, bookID).Return(sampleBook, nil)
+				runInTransaction(mockTransactor)
+				mockCopyRepo.EXPECT().CreateBookCopy(ctx, gomock.Any()).Return(nil)
+				mockHoldRepo.EXPECT().AssignNextHold(ctx, bookID, gomock.Any(), gomock.Any(), gomock.Any()).Return(&model.Hold{ID: uuid.New()}, nil)
+				mockCopyRepo.EXPECT().UpdateBookCopyStatus(ctx, gomock.Any(), model.BookCopyStatusOnHold).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "hold queue error",
+			mockFunc: func() {
+				mockBookRepo.EXPECT().GetBookByID(ctx, bookID).Return(sampleBook, nil)
+				runInTransaction(mockTransactor)
+				mockCopyRepo.EXPECT().CreateBookCopy(ctx, gomock.Any()).Return(nil)
+				mockHoldRepo.EXPECT().AssignNextHold(ctx, bookID, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("db error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "book not found",
+			mockFunc: func() {
+				mockBookRepo.EXPECT().GetBookByID(ctx, bookID).Return(nil, nil)
+			},
+			wantErr:  true,
+			errorMsg: errorcustom.ErrBookNotFound.Error(),
+		},
+		{
+			name: "duplicate barcode",
+			mockFunc: func() {
+				mockBookRepo.EXPECT().GetBookByID(ctx, bookID).Return(sampleBook, nil)
+				runInTransaction(mockTransactor)
+				mockCopyRepo.EXPECT().CreateBookCopy(ctx, gomock.Any()).Return(errors.New("ERROR: duplicate key value violates unique constraint \"book_copies_barcode_key\" (SQLSTATE 23505)"))
+			},
+			wantErr:  true,
+			errorMsg: errorcustom.ErrBookCopyAlreadyExists.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+			gotRes, err := service.CreateBookCopy(ctx, request)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("bookCopyService.CreateBookCopy() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.errorMsg != "" && err != nil && err.Error() != tt.errorMsg {
+				t.Errorf("bookCopyService.CreateBookCopy() error = %v, want %v", err.Error(), tt.errorMsg)
+			}
+			if !tt.wantErr && gotRes.ID == uuid.Nil {
+				t.Errorf("bookCopyService.CreateBookCopy() expected valid ID, got nil")
+			}
+		})
+	}
+}
+
+func Test_bookCopyService_UpdateBookCopy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactor := mock.NewMockTransactor(ctrl)
+	mockCopyRepo := mock.NewMockBookCopyRepository(ctrl)
+	mockHoldRepo := mock.NewMockHoldRepository(ctrl)
+	service := NewBookCopyService(&config.Config{HoldPickupDays: 3}, mockTransactor, mockCopyRepo, mock.NewMockBookRepository(ctrl), mockHoldRepo)
+
+	ctx := context.Background()
+	bookID := uuid.New()
+	copyID := uuid.New().String()
+
+	shelvedCopy := &model.BookCopy{ID: uuid.MustParse(copyID), BookID: bookID, Status: model.BookCopyStatusAvailable}
+	loanedCopy := &model.BookCopy{ID: uuid.MustParse(copyID), BookID: bookID, Status: model.BookCopyStatusOnLoan}
+	heldCopy := &model.BookCopy{ID: uuid.MustParse(copyID), BookID: bookID, Status: model.BookCopyStatusOnHold}
+
+	repairedCopy := &model.BookCopy{ID: uuid.MustParse(copyID), BookID: bookID, Status: model.BookCopyStatusMaintenance}
+
+	lost := model.BookCopyStatusLost
+	available := model.BookCopyStatusAvailable
+	shelf := "B-03"
+
+	tests := []struct {
+		name     string
+		mockFunc func()
+		request  payload.UpdateBookCopyRequest
+		wantErr  bool
+		errorMsg string
+	}{
+		{
+			name: "success",
+			mockFunc: func() {
+				mockCopyRepo.EXPECT().GetBookCopyByID(ctx, copyID).Return(shelvedCopy, nil)
+				runInTransaction(mockTransactor)
+				mockCopyRepo.EXPECT().UpdateBookCopy(ctx, copyID, map[string]any{"status": "lost", "shelf_location": "B-03"}).Return(nil)
+			},
+			request: payload.UpdateBookCopyRequest{BookID: bookID.String(), CopyID: copyID, Status: &lost, ShelfLocation: &shelf},
+			wantErr: false,
+		},
+		{
+			name: "copy back from maintenance goes to the first waiting hold",
+			mockFunc: func() {
+				mockCopyRepo.EXPECT().GetBookCopyByID(ctx, copyID).Return(repairedCopy, nil)
+				runInTransaction(mockTransactor)
+				mockCopyRepo.EXPECT().UpdateBookCopy(ctx, copyID, map[string]any{"status": "available"}).Return(nil)
+				mockHoldRepo.EXPECT().AssignNextHold(ctx, bookID.String(), copyID, gomock.Any(), gomock.Any()).Return(&model.Hold{ID: uuid.New()}, nil)
+				mockCopyRepo.EXPECT().UpdateBookCopyStatus(ctx, copyID, model.BookCopyStatusOnHold).Return(nil)
+			},
+			request: payload.UpdateBookCopyRequest{BookID: bookID.String(), CopyID: copyID, Status: &available},
+			wantErr: false,
+		},
+		{
+			name: "copy back from maintenance with nobody queueing",
+			mockFunc: func() {
+				mockCopyRepo.EXPECT().GetBookCopyByID(ctx, copyID).Return(repairedCopy, nil)
+				runInTransaction(mockTransactor)
+				mockCopyRepo.EXPECT().UpdateBookCopy(ctx, copyID, map[string]any{"status": "available"}).Return(nil)
+				mockHoldRepo.EXPECT().AssignNextHold(ctx, bookID.String(), copyID, gomock.Any(), gomock.Any()).Return(nil, nil)
+				mockCopyRepo.EXPECT().UpdateBookCopyStatus(ctx, copyID, model.BookCopyStatusAvailable).Return(nil)
+			},
+			request: payload.UpdateBookCopyRequest{BookID: bookID.String(), CopyID: copyID, Status: &available},
+			wantErr: false,
+		},
+		{
+			name: "copy of another book",
+			mockFunc: func() {
+				mockCopyRepo.EXPECT().GetBookCopyByID(ctx, copyID).Return(shelvedCopy, nil)
+			},
+			request:  payload.UpdateBookCopyRequest{BookID: uuid.New().String(), CopyID: copyID, Status: &lost},
+			wantErr:  true,
+			errorMsg: errorcustom.ErrBookCopyNotFound.Error(),
+		},
+		{
+			name: "status of a copy on loan",
+			mockFunc: func() {
+				mockCopyRepo.EXPECT().GetBookCopyByID(ctx, copyID).Return(loanedCopy, nil)
+			},
+			request:  payload.UpdateBookCopyRequest{BookID: bookID.String(), CopyID: copyID, Status: &lost},
+			wantErr:  true,
+			errorMsg: errorcustom.ErrBookCopyOnLoan.Error(),
+		},
+		{
+			name: "status of a copy set aside for a hold",
+			mockFunc: func() {
+				mockCopyRepo.EXPECT().GetBookCopyByID(ctx, copyID).Return(heldCopy, nil)
+			},
+			request:  payload.UpdateBookCopyRequest{BookID: bookID.String(), CopyID: copyID, Status: &lost},
+			wantErr:  true,
+			errorMsg: errorcustom.ErrBookCopyOnHold.Error(),
+		},
+		{
+			name: "shelf location of a copy on loan",
+			mockFunc: func() {
+				mockCopyRepo.EXPECT().GetBookCopyByID(ctx, copyID).Return(loanedCopy, nil)
+				runInTransaction(mockTransactor)
+				mockCopyRepo.EXPECT().UpdateBookCopy(ctx, copyID, map[string]any{"shelf_location": "B-03"}).Return(nil)
+			},
+			request: payload.UpdateBookCopyRequest{BookID: bookID.String(), CopyID: copyID, ShelfLocation: &shelf},
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+			err := service.UpdateBookCopy(ctx, tt.request)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("bookCopyService.UpdateBookCopy() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.errorMsg != "" && err != nil && err.Error() != tt.errorMsg {
+				t.Errorf("bookCopyService.UpdateBookCopy() error = %v, want %v", err.Error(), tt.errorMsg)
+			}
+		})
+	}
+}
+
+func Test_bookCopyService_DeleteBookCopy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCopyRepo := mock.NewMockBookCopyRepository(ctrl)
+	service := NewBookCopyService(&config.Config{HoldPickupDays: 3}, mock.NewMockTransactor(ctrl), mockCopyRepo, mock.NewMockBookRepository(ctrl), mock.NewMockHoldRepository(ctrl))
+
+	ctx := context.Background()
+	bookID := uuid.New()
+	copyID := uuid.New().String()
+
+	tests := []struct {
+		name     string
+		mockFunc func()
+		wantErr  bool
+		errorMsg string
+	}{
+		{
+			name: "success",
+			mockFunc: func() {
+				mockCopyRepo.EXPECT().GetBookCopyByID(ctx, copyID).Return(&model.BookCopy{BookID: bookID, Status: model.BookCopyStatusAvailable}, nil)
+				mockCopyRepo.EXPECT().DeleteBookCopy(ctx, copyID).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "copy on loan",
+			mockFunc: func() {
+				mockCopyRepo.EXPECT().GetBookCopyByID(ctx, copyID).Return(&model.BookCopy{BookID: bookID, Status: model.BookCopyStatusOnLoan}, nil)
+			},
+			wantErr:  true,
+			errorMsg: errorcustom.ErrBookCopyOnLoan.Error(),
+		},
+		{
+			name: "copy not found",
+			mockFunc: func() {
+				mockCopyRepo.EXPECT().GetBookCopyByID(ctx, copyID).Return(nil, nil)
+			},
+			wantErr:  true,
+			errorMsg: errorcustom.ErrBookCopyNotFound.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+			err := service.DeleteBookCopy(ctx, payload.DeleteBookCopyRequest{BookID: bookID.String(), CopyID: copyID})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("bookCopyService.DeleteBookCopy() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.errorMsg != "" && err != nil && err.Error() != tt.errorMsg {
+				t.Errorf("bookCopyService.DeleteBookCopy() error = %v, want %v", err.Error(), tt.errorMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"library-backend/internal/model"
+	"library-backend/internal/payload"
+	"library-backend/pkg/marc"
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+// bookExportFlushEvery is how many books an export writes between flushes, so the client receives
+// the catalog as it is read rather than at the end.
+const bookExportFlushEvery = 500
+
+// bookExportColumns are the columns of a CSV export, named like the JSON fields.
+var bookExportColumns = []string{
+	"id",
+	"isbn",
+	"isbn13",
+	"title",
+	"author",
+	"publisher",
+	"year_of_publication",
+	"category",
+	"image_url",
+	"total_copies",
+	"available_copies",
+	"created_at",
+	"updated_at",
+}
+
+// BookExport writes an export to w. It is returned once the request was checked, so the caller
+// can still answer with an error before the first byte is written.
+type BookExport func(w io.Writer) error
+
+// ExportBooks prepares an export of every book matching the filters of the list endpoint, in CSV,
+// NDJSON, a JSON array or MARC 21 records, in the list order. The books are streamed from the
+// database as they are written.
+func (s *bookService) ExportBooks(ctx context.Context, request payload.ExportBooksRequest) (BookExport, error) {
+	filters := request.GetBooksRequest
+
+	sortFields, err := payload.ParseSort(filters.Sort, payload.BookSortFields)
+	if err != nil {
+		return nil, err
+	}
+	filters.SortFields = sortFields
+
+	filters.FilterExpr, err = payload.ParseFilter(filters.Filter, payload.BookFilterFields)
+	if err != nil {
+		return nil, err
+	}
+
+	format := request.Format
+	if format == "" {
+		format = payload.BookExportFormatCSV
+	}
+
+	return func(w io.Writer) error {
+		encoder, err := newBookExportEncoder(format, w)
+		if err != nil {
+			return err
+		}
+
+		flusher, _ := w.(interface{ Flush() error })
+
+		written := 0
+		err = s.bookRepo.ExportBooks(ctx, filters, func(book model.Book) error {
+			if err := encoder.Encode(toBookResponse(book)); err != nil {
+				return err
+			}
+
+			written++
+			if written%bookExportFlushEvery != 0 || flusher == nil {
+				return nil
+			}
+
+			if err := encoder.Flush(); err != nil {
+				return err
+			}
+			return flusher.Flush()
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "[BookService][ExportBooks] failed to export books", "error", err, "written", written)
+			return err
+		}
+
+		return encoder.Close()
+	}, nil
+}
+
+// bookExportEncoder writes the books of an export in one format.
+type bookExportEncoder interface {
+	Encode(book payload.BookResponse) error
+	// Flush hands the books encoded so far to the underlying writer.
+	Flush() error
+	// Close finishes the export, after the last book.
+	Close() error
+}
+
+func newBookExportEncoder(format string, w io.Writer) (bookExportEncoder, error) {
+	switch format {
+	case payload.BookExportFormatNDJSON:
+		return &ndjsonBookEncoder{encoder: json.NewEncoder(w)}, nil
+	case payload.BookExportFormatJSON:
+		return &jsonBookEncoder{w: w}, nil
+	case payload.BookExportFormatMARC:
+		return &marcBookEncoder{w: marc.NewWriter(w)}, nil
+	case payload.BookExportFormatMARCXML:
+		return &marcxmlBookEncoder{w: marc.NewXMLWriter(w)}, nil
+	default:
+		encoder := &csvBookEncoder{w: csv.NewWriter(w)}
+		if err := encoder.w.Write(bookExportColumns); err != nil {
+			return nil, err
+		}
+		return encoder, nil
+	}
+}
+
+type csvBookEncoder struct {
+	w *csv.Writer
+}
+
+func (e *csvBookEncoder) Encode(book payload.BookResponse) error {
+	return e.w.Write([]string{
+		book.ID.String(),
+		book.ISBN,
+		book.ISBN13,
+		book.Title,
+		book.Author,
+		book.Publisher,
+		strconv.Itoa(book.YearOfPublication),
+		book.Category,
+		book.ImageURL,
+		strconv.Itoa(book.TotalCopies),
+		strconv.Itoa(book.AvailableCopies),
+		book.CreatedAt.Format(time.RFC3339),
+		book.UpdatedAt.Format(time.RFC3339),
+	})
+}
+
+func (e *csvBookEncoder) Flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvBookEncoder) Close() error {
+	return e.Flush()
+}
+
+// ndjsonBookEncoder writes a JSON book per line.
+type ndjsonBookEncoder struct {
+	encoder *json.Encoder
+}
+
+func (e *ndjsonBookEncoder) Encode(book payload.BookResponse) error {
+	return e.encoder.Encode(book)
+}
+
+func (e *ndjsonBookEncoder) Flush() error {
+	return nil
+}
+
+func (e *ndjsonBookEncoder) Close() error {
+	return nil
+}
+
+// jsonBookEncoder writes a JSON array of books, one element at a time.
+type jsonBookEncoder struct {
+	w       io.Writer
+	written int
+}
+
+func (e *jsonBookEncoder) Encode(book payload.BookResponse) error {
+	raw, err := json.Marshal(book)
+	if err != nil {
+		return err
+	}
+
+	separator := ",\n"
+	if e.written == 0 {
+		separator = "[\n"
+	}
+	e.written++
+
+	if _, err := io.WriteString(e.w, separator); err != nil {
+		return err
+	}
+
+	_, err = e.w.Write(raw)
+	return err
+}
+
+func (e *jsonBookEncoder) Flush() error {
+	return nil
+}
+
+func (e *jsonBookEncoder) Close() error {
+	closing := "\n]\n"
+	if e.written == 0 {
+		closing = "[]\n"
+	}
+
+	_, err := io.WriteString(e.w, closing)
+	return err
+}
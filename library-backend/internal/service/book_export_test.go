@@ -0,0 +1,169 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"library-backend/errorcustom"
+	"library-backend/internal/model"
+	"library-backend/internal/payload"
+	"library-backend/internal/repository/mock"
+	"library-backend/pkg/marc"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+)
+
+func Test_bookService_ExportBooks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockBookRepository(ctrl)
+	service := NewBookService(mock.NewMockTransactor(ctrl), mockRepo, mock.NewMockAuditRepository(ctrl))
+
+	ctx := context.Background()
+	now := time.Date(2025, 8, 24, 8, 0, 0, 0, time.UTC)
+	books := []model.Book{
+		{ID: uuid.New(), ISBN: "978-0134685991", ISBN13: "9780134685991", Title: "Effective Java", Author: "Joshua Bloch", YearOfPublication: 2017, CreatedAt: now, UpdatedAt: now},
+		{ID: uuid.New(), ISBN: "9780132350884", ISBN13: "9780132350884", Title: "Clean Code, 1st", Author: "Robert C. Martin", YearOfPublication: 2008, CreatedAt: now, UpdatedAt: now},
+	}
+
+	// the repository hands the books to the export one at a time, as its cursor yields them
+	expectExport := func(books []model.Book) {
+		mockRepo.EXPECT().ExportBooks(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, req payload.GetBooksRequest, fn func(model.Book) error) error {
+				if req.Author != "Bloch" || len(req.SortFields) != 1 || req.SortFields[0].Field != "title" {
+					t.Errorf("bookService.ExportBooks() unexpected filters %+v", req)
+				}
+				for _, book := range books {
+					if err := fn(book); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+	}
+
+	request := payload.ExportBooksRequest{GetBooksRequest: payload.GetBooksRequest{Author: "Bloch", Sort: "title"}}
+
+	t.Run("csv", func(t *testing.T) {
+		expectExport(books)
+
+		export, err := service.ExportBooks(ctx, request)
+		if err != nil {
+			t.Fatalf("bookService.ExportBooks() error = %v", err)
+		}
+
+		var out bytes.Buffer
+		if err := export(&out); err != nil {
+			t.Fatalf("export() error = %v", err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		if len(lines) != 3 || lines[0] != strings.Join(bookExportColumns, ",") {
+			t.Fatalf("export() csv = %q", out.String())
+		}
+		if !strings.Contains(lines[2], `"Clean Code, 1st"`) || !strings.HasSuffix(lines[2], "2025-08-24T08:00:00Z") {
+			t.Errorf("export() csv row = %q", lines[2])
+		}
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		expectExport(books)
+
+		request := request
+		request.Format = payload.BookExportFormatNDJSON
+
+		export, err := service.ExportBooks(ctx, request)
+		if err != nil {
+			t.Fatalf("bookService.ExportBooks() error = %v", err)
+		}
+
+		var out bytes.Buffer
+		if err := export(&out); err != nil {
+			t.Fatalf("export() error = %v", err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		var book payload.BookResponse
+		if len(lines) != 2 || json.Unmarshal([]byte(lines[1]), &book) != nil || book.ID != books[1].ID {
+			t.Errorf("export() ndjson = %q", out.String())
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		for _, books := range [][]model.Book{books, nil} {
+			expectExport(books)
+
+			request := request
+			request.Format = payload.BookExportFormatJSON
+
+			export, err := service.ExportBooks(ctx, request)
+			if err != nil {
+				t.Fatalf("bookService.ExportBooks() error = %v", err)
+			}
+
+			var out bytes.Buffer
+			if err := export(&out); err != nil {
+				t.Fatalf("export() error = %v", err)
+			}
+
+			var got []payload.BookResponse
+			if err := json.Unmarshal(out.Bytes(), &got); err != nil || len(got) != len(books) {
+				t.Errorf("export() json = %q, error %v", out.String(), err)
+			}
+		}
+	})
+
+	t.Run("marcxml", func(t *testing.T) {
+		expectExport(books)
+
+		request := request
+		request.Format = payload.BookExportFormatMARCXML
+
+		export, err := service.ExportBooks(ctx, request)
+		if err != nil {
+			t.Fatalf("bookService.ExportBooks() error = %v", err)
+		}
+
+		var out bytes.Buffer
+		if err := export(&out); err != nil {
+			t.Fatalf("export() error = %v", err)
+		}
+
+		reader := marc.NewXMLReader(&out)
+		for _, book := range books {
+			record, err := reader.Read()
+			if id, _ := record.ControlField("001"); err != nil || id != book.ID.String() {
+				t.Errorf("export() marcxml record %+v, error %v", record, err)
+			}
+		}
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		mockRepo.EXPECT().ExportBooks(ctx, gomock.Any(), gomock.Any()).Return(errors.New("db error"))
+
+		export, err := service.ExportBooks(ctx, request)
+		if err != nil {
+			t.Fatalf("bookService.ExportBooks() error = %v", err)
+		}
+
+		if err := export(&bytes.Buffer{}); err == nil {
+			t.Error("export() expected the repository error")
+		}
+	})
+
+	t.Run("invalid sort field", func(t *testing.T) {
+		request := request
+		request.Sort = "isbn"
+
+		_, err := service.ExportBooks(ctx, request)
+		if !errors.Is(err, errorcustom.ErrInvalidSortField) {
+			t.Errorf("bookService.ExportBooks() error = %v, wantErr %v", err, errorcustom.ErrInvalidSortField)
+		}
+	})
+}
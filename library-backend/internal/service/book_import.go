@@ -0,0 +1,454 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"library-backend/errorcustom"
+	"library-backend/internal/payload"
+	"library-backend/internal/validator"
+	"library-backend/pkg/isbn"
+	"library-backend/pkg/marc"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// maxBookImportRows caps a single import, larger catalogues are imported in several files.
+const maxBookImportRows = 5000
+
+// maxBookImportLineSize is the longest NDJSON line an import reads.
+const maxBookImportLineSize = 1 << 20
+
+// bookImportColumns are the CSV columns an import understands, named like the JSON fields.
+var bookImportColumns = map[string]bool{
+	"isbn":                true,
+	"title":               true,
+	"author":              true,
+	"publisher":           true,
+	"year_of_publication": true,
+	"category":            true,
+	"image_url":           true,
+	"restore":             true,
+}
+
+// bookImportRow is a decoded row of an import file. A row that could not be decoded carries the
+// reason instead, a row read from a MARC record the fields that were left out.
+type bookImportRow struct {
+	line     int
+	request  payload.CreateBookRequest
+	err      string
+	errs     []payload.ErrorValidation
+	unmapped []string
+}
+
+// ImportBooks creates a book for every row of a CSV or NDJSON file, or every record of a MARC
+// file, each row going through the same validation and ISBN handling as CreateBook, and reports
+// the outcome per row.
+func (s *bookService) ImportBooks(ctx context.Context, request payload.ImportBooksRequest, file io.Reader) (res payload.ImportBooksResponse, err error) {
+	rows, err := decodeBookImport(request.Format, file)
+	if err != nil {
+		return res, err
+	}
+
+	res.DryRun = request.DryRun
+	res.Atomic = request.Atomic
+	res.Rows = checkBookImportRows(rows)
+
+	switch {
+	case request.DryRun:
+		err = s.dryRunBookImport(ctx, rows, res.Rows)
+	case request.Atomic:
+		err = s.importBooksAtomically(ctx, rows, res.Rows)
+	default:
+		s.importBooksByRow(ctx, rows, res.Rows)
+	}
+	if err != nil {
+		return res, err
+	}
+
+	res.Total = len(res.Rows)
+	for _, row := range res.Rows {
+		switch row.Status {
+		case payload.ImportRowStatusCreated:
+			res.Created++
+		case payload.ImportRowStatusValid:
+			res.Valid++
+		case payload.ImportRowStatusInvalid:
+			res.Invalid++
+		case payload.ImportRowStatusFailed:
+			res.Failed++
+		case payload.ImportRowStatusSkipped:
+			res.Skipped++
+		}
+	}
+
+	return res, nil
+}
+
+// checkBookImportRows validates every row without touching the database, an ISBN repeated within the
+// file is invalid from its second valid row on.
+func checkBookImportRows(rows []bookImportRow) []payload.ImportBookRowResult {
+	results := make([]payload.ImportBookRowResult, len(rows))
+	seen := make(map[string]int, len(rows))
+
+	for i, row := range rows {
+		result := payload.ImportBookRowResult{
+			Line:     row.line,
+			ISBN:     row.request.ISBN,
+			Status:   payload.ImportRowStatusValid,
+			Error:    row.err,
+			Errors:   row.errs,
+			Unmapped: row.unmapped,
+		}
+
+		if row.err == "" {
+			if err := validator.Validate.Struct(row.request); err != nil {
+				result.Errors = mergeImportErrors(result.Errors, validator.TranslateErrorValidator(err))
+			}
+		}
+
+		if result.Error != "" || len(result.Errors) > 0 {
+			result.Status = payload.ImportRowStatusInvalid
+			results[i] = result
+			continue
+		}
+
+		isbn13, _ := isbn.Normalize(row.request.ISBN)
+		if line, ok := seen[isbn13]; ok {
+			result.Status = payload.ImportRowStatusInvalid
+			result.Error = fmt.Sprintf("same ISBN as line %d", line)
+		} else {
+			seen[isbn13] = row.line
+		}
+
+		results[i] = result
+	}
+
+	return results
+}
+
+// mergeImportErrors adds the validation errors of fields that did not already fail to decode.
+func mergeImportErrors(decoded, validated []payload.ErrorValidation) []payload.ErrorValidation {
+	failed := make(map[string]bool, len(decoded))
+	for _, e := range decoded {
+		failed[e.Field] = true
+	}
+
+	for _, e := range validated {
+		if !failed[e.Field] {
+			decoded = append(decoded, e)
+		}
+	}
+
+	return decoded
+}
+
+// dryRunBookImport looks up the ISBN of every valid row, reporting the rows that would conflict with
+// an existing book and the deleted books that would be restored.
+func (s *bookService) dryRunBookImport(ctx context.Context, rows []bookImportRow, results []payload.ImportBookRowResult) error {
+	for i, row := range rows {
+		if results[i].Status != payload.ImportRowStatusValid {
+			continue
+		}
+
+		isbn13, _ := isbn.Normalize(row.request.ISBN)
+
+		live, err := s.bookRepo.GetBookByISBN(ctx, isbn13)
+		if err != nil {
+			slog.ErrorContext(ctx, "[BookService][ImportBooks] failed to get book by isbn", "error", err, "isbn", isbn13)
+			return err
+		}
+
+		if live != nil {
+			results[i].Status = payload.ImportRowStatusFailed
+			results[i].Error = errorcustom.ErrBookAlreadyExists.Error()
+			continue
+		}
+
+		deleted, err := s.bookRepo.GetDeletedBookByISBN(ctx, isbn13)
+		if err != nil {
+			slog.ErrorContext(ctx, "[BookService][ImportBooks] failed to get deleted book by isbn", "error", err, "isbn", isbn13)
+			return err
+		}
+
+		if deleted == nil {
+			continue
+		}
+
+		if !row.request.Restore {
+			results[i].Status = payload.ImportRowStatusFailed
+			results[i].Error = fmt.Errorf("%w (id %s)", errorcustom.ErrBookInTrash, deleted.ID).Error()
+			continue
+		}
+
+		results[i].ID = &deleted.ID
+		results[i].Restored = true
+	}
+
+	return nil
+}
+
+// importBooksByRow creates every valid row on its own, a failing row does not stop the others.
+func (s *bookService) importBooksByRow(ctx context.Context, rows []bookImportRow, results []payload.ImportBookRowResult) {
+	for i, row := range rows {
+		if results[i].Status != payload.ImportRowStatusValid {
+			continue
+		}
+
+		created, err := s.CreateBook(ctx, row.request)
+		if err != nil {
+			results[i].Status = payload.ImportRowStatusFailed
+			results[i].Error = bookImportError(err)
+			continue
+		}
+
+		results[i].Status = payload.ImportRowStatusCreated
+		results[i].ID = &created.ID
+		results[i].Restored = created.Restored
+	}
+}
+
+// importBooksAtomically creates every row in one transaction. Nothing is written when any row is
+// invalid, and the first row that fails to be created rolls back the rows before it; the other rows
+// are reported as skipped.
+func (s *bookService) importBooksAtomically(ctx context.Context, rows []bookImportRow, results []payload.ImportBookRowResult) error {
+	for _, result := range results {
+		if result.Status != payload.ImportRowStatusValid {
+			skipBookImportRows(results, -1)
+			return nil
+		}
+	}
+
+	failed := -1
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		for i, row := range rows {
+			created, err := s.CreateBook(ctx, row.request)
+			if err != nil {
+				failed = i
+				results[i].Status = payload.ImportRowStatusFailed
+				results[i].Error = bookImportError(err)
+				return err
+			}
+
+			results[i].Status = payload.ImportRowStatusCreated
+			results[i].ID = &created.ID
+			results[i].Restored = created.Restored
+		}
+
+		return nil
+	})
+	if err != nil {
+		// the rows were all written but the commit failed
+		if failed < 0 {
+			slog.ErrorContext(ctx, "[BookService][ImportBooks] failed to commit import", "error", err)
+			return err
+		}
+
+		skipBookImportRows(results, failed)
+	}
+
+	return nil
+}
+
+// skipBookImportRows marks every row that is not already invalid, apart from the failed one, as
+// skipped.
+func skipBookImportRows(results []payload.ImportBookRowResult, failed int) {
+	for i := range results {
+		if i == failed || results[i].Status == payload.ImportRowStatusInvalid {
+			continue
+		}
+
+		results[i].Status = payload.ImportRowStatusSkipped
+		results[i].ID = nil
+		results[i].Restored = false
+	}
+}
+
+// bookImportError is the message reported for a row that failed to be created, errors other than
+// the known book errors are not exposed.
+func bookImportError(err error) string {
+	for _, known := range []error{errorcustom.ErrBookAlreadyExists, errorcustom.ErrBookInTrash, errorcustom.ErrInvalidISBN} {
+		if errors.Is(err, known) {
+			return err.Error()
+		}
+	}
+
+	return "failed to create book"
+}
+
+// decodeBookImport reads the rows of an import file. Rows that cannot be decoded are returned with
+// the reason so they are reported, a file that cannot be read as a whole is an error.
+func decodeBookImport(format string, file io.Reader) ([]bookImportRow, error) {
+	var (
+		rows []bookImportRow
+		err  error
+	)
+
+	switch format {
+	case payload.BookImportFormatCSV:
+		rows, err = decodeBookImportCSV(file)
+	case payload.BookImportFormatNDJSON:
+		rows, err = decodeBookImportNDJSON(file)
+	case payload.BookImportFormatMARC:
+		rows, err = decodeBookImportMARC(marc.NewReader(file))
+	case payload.BookImportFormatMARCXML:
+		rows, err = decodeBookImportMARC(marc.NewXMLReader(file))
+	default:
+		return nil, errorcustom.ErrInvalidImportFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: no rows", errorcustom.ErrInvalidImportFile)
+	}
+
+	return rows, nil
+}
+
+// decodeBookImportCSV reads a CSV file whose first row names the columns.
+func decodeBookImportCSV(file io.Reader) ([]bookImportRow, error) {
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: no rows", errorcustom.ErrInvalidImportFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errorcustom.ErrInvalidImportFile, err)
+	}
+
+	columns := make([]string, len(header))
+	for i, name := range header {
+		// spreadsheets tend to save CSV with a byte order mark
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !bookImportColumns[name] {
+			return nil, fmt.Errorf("%w: unknown column %q", errorcustom.ErrInvalidImportFile, name)
+		}
+		for _, seen := range columns[:i] {
+			if seen == name {
+				return nil, fmt.Errorf("%w: column %q appears twice", errorcustom.ErrInvalidImportFile, name)
+			}
+		}
+		columns[i] = name
+	}
+
+	var rows []bookImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errorcustom.ErrInvalidImportFile, err)
+		}
+
+		if len(rows) == maxBookImportRows {
+			return nil, fmt.Errorf("%w: more than %d rows", errorcustom.ErrInvalidImportFile, maxBookImportRows)
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := bookImportRow{line: line}
+
+		if len(record) != len(columns) {
+			row.err = fmt.Sprintf("row has %d fields, the header has %d", len(record), len(columns))
+			rows = append(rows, row)
+			continue
+		}
+
+		for i, value := range record {
+			if e := setBookImportField(&row.request, columns[i], strings.TrimSpace(value)); e != nil {
+				row.errs = append(row.errs, *e)
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// setBookImportField sets a CSV value on the request, returning the error of a value that is not of
+// the column's type.
+func setBookImportField(request *payload.CreateBookRequest, column, value string) *payload.ErrorValidation {
+	switch column {
+	case "isbn":
+		request.ISBN = value
+	case "title":
+		request.Title = value
+	case "author":
+		request.Author = value
+	case "publisher":
+		request.Publisher = value
+	case "category":
+		request.Category = value
+	case "image_url":
+		request.ImageURL = value
+	case "year_of_publication":
+		if value == "" {
+			return nil
+		}
+		year, err := strconv.Atoi(value)
+		if err != nil {
+			return &payload.ErrorValidation{Field: column, Message: column + " must be a whole number"}
+		}
+		request.YearOfPublication = year
+	case "restore":
+		if value == "" {
+			return nil
+		}
+		restore, err := strconv.ParseBool(value)
+		if err != nil {
+			return &payload.ErrorValidation{Field: column, Message: column + " must be true or false"}
+		}
+		request.Restore = restore
+	}
+
+	return nil
+}
+
+// decodeBookImportNDJSON reads a file with a JSON book per line, shaped like a create request. Blank
+// lines are skipped.
+func decodeBookImportNDJSON(file io.Reader) ([]bookImportRow, error) {
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxBookImportLineSize)
+
+	var rows []bookImportRow
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		if len(rows) == maxBookImportRows {
+			return nil, fmt.Errorf("%w: more than %d rows", errorcustom.ErrInvalidImportFile, maxBookImportRows)
+		}
+
+		row := bookImportRow{line: line}
+
+		decoder := json.NewDecoder(bytes.NewReader(text))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&row.request); err != nil {
+			row.err = "invalid JSON: " + err.Error()
+		} else if decoder.More() {
+			row.err = "invalid JSON: more than one value on the line"
+		}
+
+		rows = append(rows, row)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errorcustom.ErrInvalidImportFile, err)
+	}
+
+	return rows, nil
+}
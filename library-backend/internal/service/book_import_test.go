@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"library-backend/errorcustom"
+	"library-backend/internal/model"
+	"library-backend/internal/payload"
+	"library-backend/internal/repository/mock"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+)
+
+const bookImportCSV = `isbn,title,author,publisher,year_of_publication,category
+978-0134685991,Effective Java,Joshua Bloch,Addison-Wesley,2017,programming
+0132350882,Clean Code,Robert C. Martin,Prentice Hall,2008,programming
+9780132350884,Clean Code (again),Robert C. Martin,Prentice Hall,2008,programming
+9780201633610,Design Patterns,Erich Gamma,Addison-Wesley,nineteen,programming
+`
+
+func Test_decodeBookImport(t *testing.T) {
+	t.Run("csv", func(t *testing.T) {
+		rows, err := decodeBookImport(payload.BookImportFormatCSV, strings.NewReader("\ufeffISBN, Title ,restore\n978-0134685991,\"Effective Java, 3rd\",true\n"))
+		if err != nil {
+			t.Fatalf("decodeBookImport() error = %v", err)
+		}
+		if len(rows) != 1 || rows[0].line != 2 || rows[0].request.Title != "Effective Java, 3rd" || !rows[0].request.Restore {
+			t.Errorf("decodeBookImport() = %+v", rows)
+		}
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		rows, err := decodeBookImport(payload.BookImportFormatNDJSON, strings.NewReader(
+			"{\"isbn\":\"9780134685991\",\"title\":\"Effective Java\"}\n\n{\"isbn\":\"9780132350884\",\"pages\":464}\n"))
+		if err != nil {
+			t.Fatalf("decodeBookImport() error = %v", err)
+		}
+		if len(rows) != 2 || rows[0].err != "" || rows[1].line != 3 || rows[1].err == "" {
+			t.Errorf("decodeBookImport() = %+v", rows)
+		}
+	})
+
+	for name, tt := range map[string]struct {
+		format  string
+		file    string
+		wantErr error
+	}{
+		"unknown format":  {format: "xml", file: "<books/>", wantErr: errorcustom.ErrInvalidImportFormat},
+		"unknown column":  {format: payload.BookImportFormatCSV, file: "isbn,pages\n9780134685991,416\n", wantErr: errorcustom.ErrInvalidImportFile},
+		"repeated column": {format: payload.BookImportFormatCSV, file: "isbn,isbn\n", wantErr: errorcustom.ErrInvalidImportFile},
+		"header only":     {format: payload.BookImportFormatCSV, file: "isbn,title\n", wantErr: errorcustom.ErrInvalidImportFile},
+		"empty":           {format: payload.BookImportFormatNDJSON, file: "\n\n", wantErr: errorcustom.ErrInvalidImportFile},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := decodeBookImport(tt.format, strings.NewReader(tt.file))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("decodeBookImport() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_bookService_ImportBooks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactor := mock.NewMockTransactor(ctrl)
+	mockRepo := mock.NewMockBookRepository(ctrl)
+	mockAuditRepo := mock.NewMockAuditRepository(ctrl)
+	service := NewBookService(mockTransactor, mockRepo, mockAuditRepo)
+
+	ctx := context.Background()
+
+	// creating a book that is not in the trash
+	expectCreate := func(isbn13 string, err error) {
+		mockRepo.EXPECT().GetDeletedBookByISBN(ctx, isbn13).Return(nil, nil)
+		runInTransaction(mockTransactor)
+		mockRepo.EXPECT().CreateBook(ctx, gomock.Any()).Return(err)
+		if err == nil {
+			mockRepo.EXPECT().GetBookByID(ctx, gomock.Any()).Return(&model.Book{ID: uuid.New()}, nil)
+			mockAuditRepo.EXPECT().CreateAuditEvent(ctx, gomock.Any()).Return(nil)
+		}
+	}
+
+	rowStatuses := func(res payload.ImportBooksResponse) []string {
+		var statuses []string
+		for _, row := range res.Rows {
+			statuses = append(statuses, row.Status)
+		}
+		return statuses
+	}
+
+	tests := []struct {
+		name         string
+		mockFunc     func()
+		request      payload.ImportBooksRequest
+		file         string
+		wantStatuses []string
+	}{
+		{
+			name: "valid rows are created, the others reported",
+			mockFunc: func() {
+				expectCreate("9780134685991", nil)
+				expectCreate("9780132350884", nil)
+			},
+			request: payload.ImportBooksRequest{Format: payload.BookImportFormatCSV},
+			file:    bookImportCSV,
+			wantStatuses: []string{
+				payload.ImportRowStatusCreated, payload.ImportRowStatusCreated,
+				payload.ImportRowStatusInvalid, payload.ImportRowStatusInvalid,
+			},
+		},
+		{
+			name: "dry run reports conflicts without writing",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetBookByISBN(ctx, "9780134685991").Return(&model.Book{ID: uuid.New()}, nil)
+				mockRepo.EXPECT().GetBookByISBN(ctx, "9780132350884").Return(nil, nil)
+				mockRepo.EXPECT().GetDeletedBookByISBN(ctx, "9780132350884").Return(nil, nil)
+			},
+			request: payload.ImportBooksRequest{Format: payload.BookImportFormatCSV, DryRun: true},
+			file:    bookImportCSV,
+			wantStatuses: []string{
+				payload.ImportRowStatusFailed, payload.ImportRowStatusValid,
+				payload.ImportRowStatusInvalid, payload.ImportRowStatusInvalid,
+			},
+		},
+		{
+			name:     "atomic import with an invalid row writes nothing",
+			mockFunc: func() {},
+			request:  payload.ImportBooksRequest{Format: payload.BookImportFormatCSV, Atomic: true},
+			file:     bookImportCSV,
+			wantStatuses: []string{
+				payload.ImportRowStatusSkipped, payload.ImportRowStatusSkipped,
+				payload.ImportRowStatusInvalid, payload.ImportRowStatusInvalid,
+			},
+		},
+		{
+			name: "atomic import rolls back when a row fails",
+			mockFunc: func() {
+				runInTransaction(mockTransactor)
+				expectCreate("9780134685991", nil)
+				expectCreate("9780132350884", errors.New("ERROR: duplicate key value violates unique constraint \"books_isbn13_key\" (SQLSTATE 23505)"))
+			},
+			request: payload.ImportBooksRequest{Format: payload.BookImportFormatNDJSON, Atomic: true},
+			file: `{"isbn":"9780134685991","title":"Effective Java","author":"Joshua Bloch","publisher":"Addison-Wesley","year_of_publication":2017,"category":"programming"}
+{"isbn":"9780132350884","title":"Clean Code","author":"Robert C. Martin","publisher":"Prentice Hall","year_of_publication":2008,"category":"programming"}
+`,
+			wantStatuses: []string{payload.ImportRowStatusSkipped, payload.ImportRowStatusFailed},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+			gotRes, err := service.ImportBooks(ctx, tt.request, strings.NewReader(tt.file))
+			if err != nil {
+				t.Fatalf("bookService.ImportBooks() error = %v", err)
+			}
+
+			got := rowStatuses(gotRes)
+			if strings.Join(got, ",") != strings.Join(tt.wantStatuses, ",") {
+				t.Errorf("bookService.ImportBooks() statuses = %v, want %v", got, tt.wantStatuses)
+			}
+			if gotRes.Total != len(tt.wantStatuses) {
+				t.Errorf("bookService.ImportBooks() total = %d, want %d", gotRes.Total, len(tt.wantStatuses))
+			}
+			for _, row := range gotRes.Rows {
+				if row.Status == payload.ImportRowStatusSkipped && row.ID != nil {
+					t.Errorf("bookService.ImportBooks() skipped line %d kept id %v", row.Line, row.ID)
+				}
+			}
+		})
+	}
+
+	t.Run("row errors", func(t *testing.T) {
+		mockRepo.EXPECT().GetBookByISBN(ctx, gomock.Any()).Return(nil, nil).Times(2)
+		mockRepo.EXPECT().GetDeletedBookByISBN(ctx, gomock.Any()).Return(nil, nil).Times(2)
+
+		gotRes, err := service.ImportBooks(ctx, payload.ImportBooksRequest{Format: payload.BookImportFormatCSV, DryRun: true}, strings.NewReader(bookImportCSV))
+		if err != nil {
+			t.Fatalf("bookService.ImportBooks() error = %v", err)
+		}
+
+		if duplicate := gotRes.Rows[2]; duplicate.Line != 4 || duplicate.Error != "same ISBN as line 3" {
+			t.Errorf("bookService.ImportBooks() duplicate row = %+v", duplicate)
+		}
+		if year := gotRes.Rows[3]; len(year.Errors) != 1 || year.Errors[0].Field != "year_of_publication" {
+			t.Errorf("bookService.ImportBooks() unparsable year row = %+v", year)
+		}
+	})
+}
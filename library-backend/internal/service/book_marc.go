@@ -0,0 +1,374 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"library-backend/errorcustom"
+	"library-backend/internal/payload"
+	"library-backend/pkg/marc"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// bookMARCLeader describes a book record: new, language material, monograph, RDA cataloguing.
+const bookMARCLeader = "00000nam a2200000 i 4500"
+
+// bookMARCCategories are the categories a subject heading (650 or 655) of an imported record is
+// matched against, a record without a match gets "other".
+var bookMARCCategories = map[string]string{
+	"programming":                   "programming",
+	"computer programming":          "programming",
+	"novel":                         "novel",
+	"novels":                        "novel",
+	"fantasy":                       "fantasy",
+	"fantasy fiction":               "fantasy",
+	"romance":                       "romance",
+	"romance fiction":               "romance",
+	"mystery":                       "mystery",
+	"detective and mystery stories": "mystery",
+	"horror":                        "horror",
+	"horror fiction":                "horror",
+	"science fiction":               "science-fiction",
+	"science-fiction":               "science-fiction",
+}
+
+// bookMARCIgnoredControlFields are the control fields an import reads or that only describe the
+// record itself, they are not reported as unmapped.
+var bookMARCIgnoredControlFields = map[string]bool{
+	"001": true,
+	"003": true,
+	"005": true,
+	"008": true,
+}
+
+var marcYear = regexp.MustCompile(`\d{4}`)
+
+// GetBookMARC returns a book as a MARC 21 record, MARCXML unless ISO 2709 is asked for.
+func (s *bookService) GetBookMARC(ctx context.Context, request payload.GetBookMARCRequest) ([]byte, error) {
+	book, err := s.bookRepo.GetBookByID(ctx, request.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[BookService][GetBookMARC] failed to get book by ID", "error", err, "id", request.ID)
+		return nil, err
+	}
+
+	if book == nil {
+		return nil, errorcustom.ErrBookNotFound
+	}
+
+	record := bookToMARC(toBookResponse(*book))
+
+	if request.Format == payload.BookExportFormatMARC {
+		raw, err := marc.Marshal(record)
+		if err != nil {
+			slog.ErrorContext(ctx, "[BookService][GetBookMARC] failed to encode record", "error", err, "id", request.ID)
+			return nil, err
+		}
+		return raw, nil
+	}
+
+	var buf bytes.Buffer
+	w := marc.NewXMLWriter(&buf)
+	if err := w.Write(record); err != nil {
+		slog.ErrorContext(ctx, "[BookService][GetBookMARC] failed to encode record", "error", err, "id", request.ID)
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// bookToMARC maps a book onto a MARC 21 bibliographic record:
+//
+//	001      book ID
+//	005      last update
+//	008      date entered and year of publication
+//	020 $a   ISBN-13
+//	100 $a   author
+//	245 $a   title
+//	264 $b   publisher, $c year of publication
+//	650 $a   category
+//	856 $u   cover image
+func bookToMARC(book payload.BookResponse) marc.Record {
+	record := marc.Record{
+		Leader: bookMARCLeader,
+		ControlFields: []marc.ControlField{
+			{Tag: "001", Value: book.ID.String()},
+			{Tag: "005", Value: book.UpdatedAt.UTC().Format("20060102150405.0")},
+			{Tag: "008", Value: bookMARC008(book)},
+		},
+	}
+
+	// a name without a comma is in direct order, "Surname, Forename" is inverted
+	authorInd1 := byte('0')
+	if strings.Contains(book.Author, ",") {
+		authorInd1 = '1'
+	}
+
+	record.DataFields = append(record.DataFields,
+		marc.DataField{Tag: "020", Ind1: ' ', Ind2: ' ', Subfields: []marc.Subfield{{Code: 'a', Value: book.ISBN13}}},
+		marc.DataField{Tag: "100", Ind1: authorInd1, Ind2: ' ', Subfields: []marc.Subfield{{Code: 'a', Value: book.Author}}},
+		marc.DataField{Tag: "245", Ind1: '1', Ind2: '0', Subfields: []marc.Subfield{{Code: 'a', Value: book.Title}}},
+		marc.DataField{Tag: "264", Ind1: ' ', Ind2: '1', Subfields: []marc.Subfield{
+			{Code: 'b', Value: book.Publisher},
+			{Code: 'c', Value: strconv.Itoa(book.YearOfPublication)},
+		}},
+		marc.DataField{Tag: "650", Ind1: ' ', Ind2: '4', Subfields: []marc.Subfield{{Code: 'a', Value: book.Category}}},
+	)
+
+	if book.ImageURL != "" {
+		record.DataFields = append(record.DataFields, marc.DataField{Tag: "856", Ind1: '4', Ind2: '2', Subfields: []marc.Subfield{
+			{Code: '3', Value: "Cover image"},
+			{Code: 'u', Value: book.ImageURL},
+		}})
+	}
+
+	return record
+}
+
+// bookMARC008 builds the 40 character fixed-length data elements, with only the dates known and the
+// rest left blank or undetermined.
+func bookMARC008(book payload.BookResponse) string {
+	return book.CreatedAt.UTC().Format("060102") + // 00-05 date entered
+		"s" + fmt.Sprintf("%04d", book.YearOfPublication) + "    " + // 06-14 single known date
+		"xx " + // 15-17 place of publication unknown
+		strings.Repeat(" ", 17) + // 18-34 book material details
+		"und" + // 35-37 language undetermined
+		" d" // 38-39 not modified, other cataloguing source
+}
+
+// marcBookMapper reads the fields of a record that map onto a book, keeping track of the subfields
+// it used so the others can be reported.
+type marcBookMapper struct {
+	record marc.Record
+	used   map[[2]int]bool
+}
+
+// bookFromMARC maps a MARC 21 bibliographic record onto a create request, the reverse of
+// bookToMARC. It also falls back on the fields other catalogues commonly use: 110 and 700 for the
+// author, 245 $b for the subtitle, 260 for the publication, 655 for the category and 008 for the
+// year. The returned unmapped fields are the tags and subfields that were not imported.
+func bookFromMARC(record marc.Record) (payload.CreateBookRequest, []string) {
+	m := marcBookMapper{record: record, used: make(map[[2]int]bool)}
+
+	var request payload.CreateBookRequest
+
+	if _, value := m.take('a', "020"); value != "" {
+		// an ISBN may be followed by a qualifier, "9780134685991 (paperback)"
+		request.ISBN = strings.Fields(value)[0]
+	}
+
+	title := trimMARCPunctuation(m.value('a', "245"))
+	if subtitle := trimMARCPunctuation(m.value('b', "245")); subtitle != "" {
+		title += ": " + subtitle
+	}
+	request.Title = title
+
+	if field, value := m.take('a', "100", "110", "700"); value != "" {
+		request.Author = marcName(field, trimMARCPunctuation(value))
+	}
+
+	request.Publisher = trimMARCPunctuation(m.value('b', "264", "260"))
+
+	if year := marcYear.FindString(m.value('c', "264", "260")); year != "" {
+		request.YearOfPublication, _ = strconv.Atoi(year)
+	} else if f008, ok := record.ControlField("008"); ok && len(f008) >= 11 {
+		request.YearOfPublication, _ = strconv.Atoi(f008[7:11])
+	}
+
+	request.Category = "other"
+	m.each('a', []string{"650", "655"}, func(_ marc.DataField, value string) bool {
+		category, ok := bookMARCCategories[strings.ToLower(trimMARCPunctuation(value))]
+		if ok {
+			request.Category = category
+		}
+		return ok
+	})
+
+	request.ImageURL = strings.TrimSpace(m.value('u', "856"))
+
+	return request, m.unmapped()
+}
+
+// take returns the first non-empty subfield with the code, looking at the tags in order, and marks
+// it as imported.
+func (m *marcBookMapper) take(code byte, tags ...string) (field marc.DataField, value string) {
+	m.each(code, tags, func(f marc.DataField, v string) bool {
+		field, value = f, v
+		return true
+	})
+
+	return field, value
+}
+
+func (m *marcBookMapper) value(code byte, tags ...string) string {
+	_, value := m.take(code, tags...)
+	return value
+}
+
+// each calls fn with the non-empty subfields with the code, looking at the tags in order, until fn
+// returns true, marking that subfield as imported.
+func (m *marcBookMapper) each(code byte, tags []string, fn func(field marc.DataField, value string) bool) {
+	for _, tag := range tags {
+		for i, f := range m.record.DataFields {
+			if f.Tag != tag {
+				continue
+			}
+
+			for j, s := range f.Subfields {
+				if s.Code != code || strings.TrimSpace(s.Value) == "" {
+					continue
+				}
+
+				if fn(f, s.Value) {
+					m.used[[2]int{i, j}] = true
+					return
+				}
+			}
+		}
+	}
+}
+
+// unmapped lists the control fields, as a tag, and the subfields, as tag$code, that were not
+// imported, each once in record order.
+func (m *marcBookMapper) unmapped() []string {
+	var unmapped []string
+	seen := make(map[string]bool)
+
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			unmapped = append(unmapped, name)
+		}
+	}
+
+	for _, f := range m.record.ControlFields {
+		if !bookMARCIgnoredControlFields[f.Tag] {
+			add(f.Tag)
+		}
+	}
+
+	for i, f := range m.record.DataFields {
+		for j, s := range f.Subfields {
+			if !m.used[[2]int{i, j}] {
+				add(f.Tag + "$" + string(s.Code))
+			}
+		}
+	}
+
+	return unmapped
+}
+
+// trimMARCPunctuation strips the ISBD punctuation that ends a subfield, such as "Effective Java /"
+// or "Addison-Wesley,". A full stop is kept after an initial, as in "Martin, Robert C.".
+func trimMARCPunctuation(value string) string {
+	value = strings.TrimSpace(value)
+
+	for {
+		trimmed := strings.TrimRight(value, " /:;,=")
+		if strings.HasSuffix(trimmed, ".") && !endsWithInitial(trimmed) {
+			trimmed = strings.TrimSuffix(trimmed, ".")
+		}
+
+		if trimmed == value {
+			return value
+		}
+		value = trimmed
+	}
+}
+
+// endsWithInitial reports whether value ends with a single letter and a full stop.
+func endsWithInitial(value string) bool {
+	runes := []rune(strings.TrimSuffix(value, "."))
+	if len(runes) == 0 || !unicode.IsUpper(runes[len(runes)-1]) {
+		return false
+	}
+
+	return len(runes) == 1 || !unicode.IsLetter(runes[len(runes)-2])
+}
+
+// marcName turns an inverted personal name, "Bloch, Joshua", into the direct order books are
+// stored in.
+func marcName(field marc.DataField, name string) string {
+	if field.Tag == "110" || field.Ind1 != '1' {
+		return name
+	}
+
+	surname, forename, ok := strings.Cut(name, ", ")
+	if !ok || strings.Contains(forename, ",") {
+		return name
+	}
+
+	return forename + " " + surname
+}
+
+// marcRecordReader is implemented by the ISO 2709 and MARCXML readers.
+type marcRecordReader interface {
+	Read() (marc.Record, error)
+}
+
+// decodeBookImportMARC reads the records of a MARC file as import rows, numbered by record. A
+// record that cannot be read makes the whole file invalid, since the records after it cannot be
+// found reliably.
+func decodeBookImportMARC(reader marcRecordReader) ([]bookImportRow, error) {
+	var rows []bookImportRow
+	for number := 1; ; number++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: record %d: %v", errorcustom.ErrInvalidImportFile, number, err)
+		}
+
+		if len(rows) == maxBookImportRows {
+			return nil, fmt.Errorf("%w: more than %d rows", errorcustom.ErrInvalidImportFile, maxBookImportRows)
+		}
+
+		request, unmapped := bookFromMARC(record)
+		rows = append(rows, bookImportRow{line: number, request: request, unmapped: unmapped})
+	}
+
+	return rows, nil
+}
+
+// marcBookEncoder writes an export as ISO 2709 records.
+type marcBookEncoder struct {
+	w *marc.Writer
+}
+
+func (e *marcBookEncoder) Encode(book payload.BookResponse) error {
+	return e.w.Write(bookToMARC(book))
+}
+
+func (e *marcBookEncoder) Flush() error {
+	return nil
+}
+
+func (e *marcBookEncoder) Close() error {
+	return nil
+}
+
+// marcxmlBookEncoder writes an export as a MARCXML collection.
+type marcxmlBookEncoder struct {
+	w *marc.XMLWriter
+}
+
+func (e *marcxmlBookEncoder) Encode(book payload.BookResponse) error {
+	return e.w.Write(bookToMARC(book))
+}
+
+func (e *marcxmlBookEncoder) Flush() error {
+	return nil
+}
+
+func (e *marcxmlBookEncoder) Close() error {
+	return e.w.Close()
+}
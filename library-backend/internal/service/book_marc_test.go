@@ -0,0 +1,235 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"library-backend/errorcustom"
+	"library-backend/internal/model"
+	"library-backend/internal/payload"
+	"library-backend/internal/repository/mock"
+	"library-backend/pkg/marc"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+)
+
+// a record as a library catalogue would have it, with ISBD punctuation and fields the books do not
+// have
+const bookImportMARCXML = `<?xml version="1.0" encoding="UTF-8"?>
+<marc:collection xmlns:marc="http://www.loc.gov/MARC21/slim">
+  <marc:record>
+    <marc:leader>01142cam  2200301 a 4500</marc:leader>
+    <marc:controlfield tag="001">ocm12345</marc:controlfield>
+    <marc:controlfield tag="007">ta</marc:controlfield>
+    <marc:controlfield tag="008">080303s2008    njua          001 0 eng d</marc:controlfield>
+    <marc:datafield tag="020" ind1=" " ind2=" ">
+      <marc:subfield code="a">0132350882 (pbk. : alk. paper)</marc:subfield>
+    </marc:datafield>
+    <marc:datafield tag="100" ind1="1" ind2=" ">
+      <marc:subfield code="a">Martin, Robert C.</marc:subfield>
+    </marc:datafield>
+    <marc:datafield tag="245" ind1="1" ind2="0">
+      <marc:subfield code="a">Clean code :</marc:subfield>
+      <marc:subfield code="b">a handbook of agile software craftsmanship /</marc:subfield>
+      <marc:subfield code="c">Robert C. Martin.</marc:subfield>
+    </marc:datafield>
+    <marc:datafield tag="260" ind1=" " ind2=" ">
+      <marc:subfield code="a">Upper Saddle River, NJ :</marc:subfield>
+      <marc:subfield code="b">Prentice Hall,</marc:subfield>
+      <marc:subfield code="c">c2009.</marc:subfield>
+    </marc:datafield>
+    <marc:datafield tag="650" ind1=" " ind2="0">
+      <marc:subfield code="a">Agile software development.</marc:subfield>
+    </marc:datafield>
+    <marc:datafield tag="650" ind1=" " ind2="0">
+      <marc:subfield code="a">Computer programming.</marc:subfield>
+    </marc:datafield>
+  </marc:record>
+  <marc:record>
+    <marc:leader>00000nam a2200000 i 4500</marc:leader>
+    <marc:datafield tag="245" ind1="0" ind2="0">
+      <marc:subfield code="a">Untitled notes</marc:subfield>
+    </marc:datafield>
+  </marc:record>
+</marc:collection>`
+
+func Test_bookToMARC_bookFromMARC(t *testing.T) {
+	now := time.Date(2025, 8, 24, 8, 0, 0, 0, time.UTC)
+	book := payload.BookResponse{
+		ID:                uuid.New(),
+		ISBN:              "0-13-468599-7",
+		ISBN13:            "9780134685991",
+		Title:             "Effective Java",
+		Author:            "Joshua Bloch",
+		Publisher:         "Addison-Wesley",
+		YearOfPublication: 2017,
+		Category:          "programming",
+		ImageURL:          "https://example.com/effective-java.jpg",
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+
+	record := bookToMARC(book)
+
+	if f008, _ := record.ControlField("008"); len(f008) != 40 || f008[7:11] != "2017" {
+		t.Errorf("bookToMARC() 008 = %q", f008)
+	}
+
+	// through ISO 2709 and back, the record maps onto the same book
+	raw, err := marc.Marshal(record)
+	if err != nil {
+		t.Fatalf("marc.Marshal() error = %v", err)
+	}
+	decoded, err := marc.Unmarshal(raw)
+	if err != nil {
+		t.Fatalf("marc.Unmarshal() error = %v", err)
+	}
+
+	request, unmapped := bookFromMARC(decoded)
+
+	want := payload.CreateBookRequest{
+		ISBN:              book.ISBN13,
+		Title:             book.Title,
+		Author:            book.Author,
+		Publisher:         book.Publisher,
+		YearOfPublication: book.YearOfPublication,
+		Category:          book.Category,
+		ImageURL:          book.ImageURL,
+	}
+	if request != want {
+		t.Errorf("bookFromMARC() = %+v, want %+v", request, want)
+	}
+	if !reflect.DeepEqual(unmapped, []string{"856$3"}) {
+		t.Errorf("bookFromMARC() unmapped = %v", unmapped)
+	}
+}
+
+func Test_bookFromMARC_catalogueRecord(t *testing.T) {
+	rows, err := decodeBookImport(payload.BookImportFormatMARCXML, strings.NewReader(bookImportMARCXML))
+	if err != nil {
+		t.Fatalf("decodeBookImport() error = %v", err)
+	}
+	if len(rows) != 2 || rows[1].line != 2 {
+		t.Fatalf("decodeBookImport() = %+v", rows)
+	}
+
+	want := payload.CreateBookRequest{
+		ISBN:              "0132350882",
+		Title:             "Clean code: a handbook of agile software craftsmanship",
+		Author:            "Robert C. Martin",
+		Publisher:         "Prentice Hall",
+		YearOfPublication: 2009,
+		Category:          "programming",
+	}
+	if rows[0].request != want {
+		t.Errorf("bookFromMARC() = %+v, want %+v", rows[0].request, want)
+	}
+
+	wantUnmapped := []string{"007", "245$c", "260$a", "650$a"}
+	if !reflect.DeepEqual(rows[0].unmapped, wantUnmapped) {
+		t.Errorf("bookFromMARC() unmapped = %v, want %v", rows[0].unmapped, wantUnmapped)
+	}
+
+	// the second record lacks most fields, it is reported by the validation like any other row
+	results := checkBookImportRows(rows)
+	if results[0].Status != payload.ImportRowStatusValid || results[1].Status != payload.ImportRowStatusInvalid {
+		t.Errorf("checkBookImportRows() = %+v", results)
+	}
+}
+
+func Test_decodeBookImport_marc(t *testing.T) {
+	var file bytes.Buffer
+	w := marc.NewWriter(&file)
+	for _, title := range []string{"Effective Java", "Clean Code"} {
+		if err := w.Write(bookToMARC(payload.BookResponse{Title: title, YearOfPublication: 2017})); err != nil {
+			t.Fatalf("marc.Writer.Write() error = %v", err)
+		}
+	}
+
+	rows, err := decodeBookImport(payload.BookImportFormatMARC, bytes.NewReader(file.Bytes()))
+	if err != nil {
+		t.Fatalf("decodeBookImport() error = %v", err)
+	}
+	if len(rows) != 2 || rows[1].request.Title != "Clean Code" {
+		t.Errorf("decodeBookImport() = %+v", rows)
+	}
+
+	// a record cut short cannot be skipped, the file is refused
+	_, err = decodeBookImport(payload.BookImportFormatMARC, bytes.NewReader(file.Bytes()[:file.Len()-5]))
+	if !errors.Is(err, errorcustom.ErrInvalidImportFile) {
+		t.Errorf("decodeBookImport() error = %v, wantErr %v", err, errorcustom.ErrInvalidImportFile)
+	}
+}
+
+func Test_trimMARCPunctuation(t *testing.T) {
+	tests := map[string]string{
+		"Effective Java /":    "Effective Java",
+		"Prentice Hall,":      "Prentice Hall",
+		"Clean code :":        "Clean code",
+		"Martin, Robert C.":   "Martin, Robert C.",
+		"Computer science.":   "Computer science",
+		"  Tolkien, J. R. R.": "Tolkien, J. R. R.",
+	}
+
+	for in, want := range tests {
+		if got := trimMARCPunctuation(in); got != want {
+			t.Errorf("trimMARCPunctuation(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func Test_bookService_GetBookMARC(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockBookRepository(ctrl)
+	service := NewBookService(mock.NewMockTransactor(ctrl), mockRepo, mock.NewMockAuditRepository(ctrl))
+
+	ctx := context.Background()
+	book := &model.Book{ID: uuid.New(), ISBN13: "9780134685991", Title: "Effective Java", Author: "Joshua Bloch", YearOfPublication: 2017}
+
+	t.Run("marcxml", func(t *testing.T) {
+		mockRepo.EXPECT().GetBookByID(ctx, book.ID.String()).Return(book, nil)
+
+		got, err := service.GetBookMARC(ctx, payload.GetBookMARCRequest{ID: book.ID.String(), Format: payload.BookExportFormatMARCXML})
+		if err != nil {
+			t.Fatalf("bookService.GetBookMARC() error = %v", err)
+		}
+
+		record, err := marc.NewXMLReader(bytes.NewReader(got)).Read()
+		if err != nil {
+			t.Fatalf("marc.XMLReader.Read() error = %v", err)
+		}
+		if id, _ := record.ControlField("001"); id != book.ID.String() {
+			t.Errorf("bookService.GetBookMARC() 001 = %q", id)
+		}
+	})
+
+	t.Run("marc", func(t *testing.T) {
+		mockRepo.EXPECT().GetBookByID(ctx, book.ID.String()).Return(book, nil)
+
+		got, err := service.GetBookMARC(ctx, payload.GetBookMARCRequest{ID: book.ID.String(), Format: payload.BookExportFormatMARC})
+		if err != nil {
+			t.Fatalf("bookService.GetBookMARC() error = %v", err)
+		}
+
+		record, err := marc.Unmarshal(got)
+		if f, _ := record.Field("245"); err != nil || f.Subfield('a') != "Effective Java" {
+			t.Errorf("bookService.GetBookMARC() = %q, error %v", got, err)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockRepo.EXPECT().GetBookByID(ctx, book.ID.String()).Return(nil, nil)
+
+		_, err := service.GetBookMARC(ctx, payload.GetBookMARCRequest{ID: book.ID.String()})
+		if !errors.Is(err, errorcustom.ErrBookNotFound) {
+			t.Errorf("bookService.GetBookMARC() error = %v, wantErr %v", err, errorcustom.ErrBookNotFound)
+		}
+	})
+}
@@ -7,7 +7,9 @@ import (
 	"library-backend/internal/model"
 	"library-backend/internal/payload"
 	"library-backend/internal/repository/mock"
+	"library-backend/pkg/filter"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -19,8 +21,10 @@ func Test_bookService_CreateBook(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	mockTransactor := mock.NewMockTransactor(ctrl)
 	mockRepo := mock.NewMockBookRepository(ctrl)
-	service := NewBookService(mockRepo)
+	mockAuditRepo := mock.NewMockAuditRepository(ctrl)
+	service := NewBookService(mockTransactor, mockRepo, mockAuditRepo)
 
 	ctx := context.Background()
 	request := payload.CreateBookRequest{
@@ -32,6 +36,11 @@ func Test_bookService_CreateBook(t *testing.T) {
 		Category:          "Programming",
 		ImageURL:          "https://example.com/image.jpg",
 	}
+	restoreRequest := request
+	restoreRequest.Restore = true
+
+	deletedAt := time.Now().Add(-time.Hour)
+	deleted := &model.Book{ID: uuid.New(), ISBN: request.ISBN, Title: "Effective Java, 2nd Edition", DeletedAt: &deletedAt}
 
 	tests := []struct {
 		name     string
@@ -42,7 +51,22 @@ func Test_bookService_CreateBook(t *testing.T) {
 		{
 			name: "success",
 			mockFunc: func() {
-				mockRepo.EXPECT().CreateBook(ctx, gomock.Any()).Return(nil)
+				mockRepo.EXPECT().GetDeletedBookByISBN(ctx, "9780134685991").Return(nil, nil)
+				runInTransaction(mockTransactor)
+				mockRepo.EXPECT().CreateBook(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, book model.Book) error {
+					if book.ISBN != request.ISBN || book.ISBN13 != "9780134685991" {
+						t.Errorf("bookService.CreateBook() isbn = %q, isbn13 = %q", book.ISBN, book.ISBN13)
+					}
+					return nil
+				})
+				mockRepo.EXPECT().GetBookByID(ctx, gomock.Any()).Return(&model.Book{ID: uuid.New(), Title: request.Title}, nil)
+				mockAuditRepo.EXPECT().CreateAuditEvent(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, event model.AuditEvent) error {
+					if event.Action != model.AuditActionCreate || event.EntityType != model.AuditEntityBook ||
+						event.Before != nil || !strings.Contains(string(event.After), request.Title) {
+						t.Errorf("bookService.CreateBook() unexpected audit event %+v", event)
+					}
+					return nil
+				})
 			},
 			request: request,
 			wantErr: false,
@@ -50,11 +74,56 @@ func Test_bookService_CreateBook(t *testing.T) {
 		{
 			name: "repository error",
 			mockFunc: func() {
+				mockRepo.EXPECT().GetDeletedBookByISBN(ctx, "9780134685991").Return(nil, nil)
+				runInTransaction(mockTransactor)
 				mockRepo.EXPECT().CreateBook(ctx, gomock.Any()).Return(errors.New("db error"))
 			},
 			request: request,
 			wantErr: true,
 		},
+		{
+			name: "audit error fails the create",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetDeletedBookByISBN(ctx, "9780134685991").Return(nil, nil)
+				runInTransaction(mockTransactor)
+				mockRepo.EXPECT().CreateBook(ctx, gomock.Any()).Return(nil)
+				mockRepo.EXPECT().GetBookByID(ctx, gomock.Any()).Return(&model.Book{ID: uuid.New()}, nil)
+				mockAuditRepo.EXPECT().CreateAuditEvent(ctx, gomock.Any()).Return(errors.New("db error"))
+			},
+			request: request,
+			wantErr: true,
+		},
+		{
+			name: "deleted book with the same isbn",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetDeletedBookByISBN(ctx, "9780134685991").Return(deleted, nil)
+			},
+			request: request,
+			wantErr: true,
+		},
+		{
+			name: "restore a deleted book with the same isbn",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetDeletedBookByISBN(ctx, "9780134685991").Return(deleted, nil)
+				runInTransaction(mockTransactor)
+				mockRepo.EXPECT().RestoreBook(ctx, deleted.ID.String()).Return(nil)
+				mockRepo.EXPECT().UpdateBook(ctx, deleted.ID.String(), gomock.Any()).DoAndReturn(func(_ context.Context, _ string, updates map[string]any) error {
+					if updates["title"] != request.Title || updates["image_url"] != request.ImageURL {
+						t.Errorf("bookService.CreateBook() unexpected updates %v", updates)
+					}
+					return nil
+				})
+				mockRepo.EXPECT().GetBookByID(ctx, deleted.ID.String()).Return(&model.Book{ID: deleted.ID, Title: request.Title}, nil)
+				mockAuditRepo.EXPECT().CreateAuditEvent(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, event model.AuditEvent) error {
+					if event.Action != model.AuditActionRestore || event.EntityID != deleted.ID {
+						t.Errorf("bookService.CreateBook() unexpected audit event %+v", event)
+					}
+					return nil
+				})
+			},
+			request: restoreRequest,
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -68,6 +137,9 @@ func Test_bookService_CreateBook(t *testing.T) {
 			if !tt.wantErr && gotRes.ID == uuid.Nil {
 				t.Errorf("bookService.CreateBook() expected valid ID, got nil")
 			}
+			if !tt.wantErr && gotRes.Restored != tt.request.Restore {
+				t.Errorf("bookService.CreateBook() restored = %v, want %v", gotRes.Restored, tt.request.Restore)
+			}
 		})
 	}
 }
@@ -76,8 +148,10 @@ func Test_bookService_GetBooks(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	mockTransactor := mock.NewMockTransactor(ctrl)
 	mockRepo := mock.NewMockBookRepository(ctrl)
-	service := NewBookService(mockRepo)
+	mockAuditRepo := mock.NewMockAuditRepository(ctrl)
+	service := NewBookService(mockTransactor, mockRepo, mockAuditRepo)
 
 	ctx := context.Background()
 	now := time.Now()
@@ -97,11 +171,21 @@ func Test_bookService_GetBooks(t *testing.T) {
 		},
 	}
 
+	rank := 0.6
+	highlight := "<mark>Effective</mark> <mark>Java</mark> · Joshua Bloch · Addison-Wesley"
+	rankedBooks := []model.Book{sampleBooks[0]}
+	rankedBooks[0].Rank = &rank
+	rankedBooks[0].Highlight = &highlight
+
 	tests := []struct {
-		name     string
-		mockFunc func()
-		request  payload.GetBooksRequest
-		wantErr  bool
+		name          string
+		mockFunc      func()
+		request       payload.GetBooksRequest
+		wantErr       bool
+		wantEmpty     bool
+		wantTotal     int
+		wantHighlight string
+		wantFacets    map[string][]payload.BookFacetBucket
 	}{
 		{
 			name: "success",
@@ -110,43 +194,193 @@ func Test_bookService_GetBooks(t *testing.T) {
 					PaginationRequest: payload.PaginationRequest{Page: 1, Limit: 10},
 					Offset: 0,
 				}
-				mockRepo.EXPECT().GetBooks(ctx, expectedReq).Return(sampleBooks, nil)
-				mockRepo.EXPECT().GetBooksCount(ctx).Return(1, nil)
+				mockRepo.EXPECT().GetBooks(ctx, expectedReq).Return(sampleBooks, 1, nil)
 			},
 			request: payload.GetBooksRequest{
 				PaginationRequest: payload.PaginationRequest{Page: 1, Limit: 10},
 			},
-			wantErr: false,
+			wantErr:   false,
+			wantTotal: 1,
 		},
 		{
-			name: "get books error",
+			name: "success with full-text query",
 			mockFunc: func() {
 				expectedReq := payload.GetBooksRequest{
 					PaginationRequest: payload.PaginationRequest{Page: 1, Limit: 10},
 					Offset: 0,
+					Q:      "effective java",
 				}
-				mockRepo.EXPECT().GetBooks(ctx, expectedReq).Return(nil, errors.New("db error"))
+				mockRepo.EXPECT().GetBooks(ctx, expectedReq).Return(rankedBooks, 1, nil)
 			},
 			request: payload.GetBooksRequest{
 				PaginationRequest: payload.PaginationRequest{Page: 1, Limit: 10},
+				Q:                 "effective java",
+			},
+			wantErr:       false,
+			wantTotal:     1,
+			wantHighlight: "<mark>Effective</mark> <mark>Java</mark> · Joshua Bloch · Addison-Wesley",
+		},
+		{
+			name: "success with sort",
+			mockFunc: func() {
+				expectedReq := payload.GetBooksRequest{
+					PaginationRequest: payload.PaginationRequest{Page: 1, Limit: 10},
+					Offset: 0,
+					Sort:   "-year_of_publication,title",
+					SortFields: []payload.SortField{
+						{Field: "year_of_publication", Desc: true},
+						{Field: "title"},
+					},
+				}
+				mockRepo.EXPECT().GetBooks(ctx, expectedReq).Return(sampleBooks, 1, nil)
+			},
+			request: payload.GetBooksRequest{
+				PaginationRequest: payload.PaginationRequest{Page: 1, Limit: 10},
+				Sort:              "-year_of_publication,title",
+			},
+			wantErr:   false,
+			wantTotal: 1,
+		},
+		{
+			name:     "invalid sort field",
+			mockFunc: func() {},
+			request: payload.GetBooksRequest{
+				PaginationRequest: payload.PaginationRequest{Page: 1, Limit: 10},
+				Sort:              "title,-isbn",
 			},
 			wantErr: true,
 		},
 		{
-			name: "get books count error",
+			name: "success with filter",
+			mockFunc: func() {
+				expr, _ := filter.Parse("category in ('fantasy','novel') and year_of_publication >= 2000 or author ~ 'tolkien'")
+				expectedReq := payload.GetBooksRequest{
+					PaginationRequest: payload.PaginationRequest{Page: 1, Limit: 10},
+					Offset:     0,
+					Filter:     "category in ('fantasy','novel') and year_of_publication >= 2000 or author ~ 'tolkien'",
+					FilterExpr: expr,
+				}
+				mockRepo.EXPECT().GetBooks(ctx, expectedReq).Return(sampleBooks, 1, nil)
+			},
+			request: payload.GetBooksRequest{
+				PaginationRequest: payload.PaginationRequest{Page: 1, Limit: 10},
+				Filter:            "category in ('fantasy','novel') and year_of_publication >= 2000 or author ~ 'tolkien'",
+			},
+			wantErr:   false,
+			wantTotal: 1,
+		},
+		{
+			name: "success with facets",
+			mockFunc: func() {
+				expectedReq := payload.GetBooksRequest{
+					PaginationRequest: payload.PaginationRequest{Page: 1, Limit: 10},
+					Offset:      0,
+					Q:           "java",
+					Facets:      "category, decade,category",
+					FacetFields: []string{"category", "decade"},
+				}
+				mockRepo.EXPECT().GetBooks(ctx, expectedReq).Return(sampleBooks, 1, nil)
+				mockRepo.EXPECT().GetBookFacets(ctx, expectedReq).Return(map[string][]payload.BookFacetBucket{
+					"category": {{Value: "programming", Count: 1}},
+					"decade":   {{Value: "2010", Count: 1}},
+				}, nil)
+			},
+			request: payload.GetBooksRequest{
+				PaginationRequest: payload.PaginationRequest{Page: 1, Limit: 10},
+				Q:                 "java",
+				Facets:            "category, decade,category",
+			},
+			wantErr:   false,
+			wantTotal: 1,
+			wantFacets: map[string][]payload.BookFacetBucket{
+				"category": {{Value: "programming", Count: 1}},
+				"decade":   {{Value: "2010", Count: 1}},
+			},
+		},
+		{
+			name:     "invalid facet",
+			mockFunc: func() {},
+			request: payload.GetBooksRequest{
+				PaginationRequest: payload.PaginationRequest{Page: 1, Limit: 10},
+				Facets:            "category,author",
+			},
+			wantErr: true,
+		},
+		{
+			name: "get book facets error",
+			mockFunc: func() {
+				expectedReq := payload.GetBooksRequest{
+					PaginationRequest: payload.PaginationRequest{Page: 1, Limit: 10},
+					Offset:      0,
+					Facets:      "publisher",
+					FacetFields: []string{"publisher"},
+				}
+				mockRepo.EXPECT().GetBooks(ctx, expectedReq).Return(sampleBooks, 1, nil)
+				mockRepo.EXPECT().GetBookFacets(ctx, expectedReq).Return(nil, errors.New("db error"))
+			},
+			request: payload.GetBooksRequest{
+				PaginationRequest: payload.PaginationRequest{Page: 1, Limit: 10},
+				Facets:            "publisher",
+			},
+			wantErr: true,
+		},
+		{
+			name:     "invalid filter",
+			mockFunc: func() {},
+			request: payload.GetBooksRequest{
+				PaginationRequest: payload.PaginationRequest{Page: 1, Limit: 10},
+				Filter:            "isbn = '9780134685991'",
+			},
+			wantErr: true,
+		},
+		{
+			name: "get books error",
 			mockFunc: func() {
 				expectedReq := payload.GetBooksRequest{
 					PaginationRequest: payload.PaginationRequest{Page: 1, Limit: 10},
 					Offset: 0,
 				}
-				mockRepo.EXPECT().GetBooks(ctx, expectedReq).Return(sampleBooks, nil)
-				mockRepo.EXPECT().GetBooksCount(ctx).Return(0, errors.New("db error"))
+				mockRepo.EXPECT().GetBooks(ctx, expectedReq).Return(nil, 0, errors.New("db error"))
 			},
 			request: payload.GetBooksRequest{
 				PaginationRequest: payload.PaginationRequest{Page: 1, Limit: 10},
 			},
 			wantErr: true,
 		},
+		{
+			name: "page past the end counts the matches",
+			mockFunc: func() {
+				expectedReq := payload.GetBooksRequest{
+					PaginationRequest: payload.PaginationRequest{Page: 3, Limit: 10},
+					Offset: 20,
+					Title:  "java",
+				}
+				mockRepo.EXPECT().GetBooks(ctx, expectedReq).Return([]model.Book{}, 0, nil)
+				mockRepo.EXPECT().GetBooksCount(ctx, expectedReq).Return(12, nil)
+			},
+			request: payload.GetBooksRequest{
+				PaginationRequest: payload.PaginationRequest{Page: 3, Limit: 10},
+				Title:             "java",
+			},
+			wantErr:   false,
+			wantEmpty: true,
+			wantTotal: 12,
+		},
+		{
+			name: "get books count error",
+			mockFunc: func() {
+				expectedReq := payload.GetBooksRequest{
+					PaginationRequest: payload.PaginationRequest{Page: 3, Limit: 10},
+					Offset: 20,
+				}
+				mockRepo.EXPECT().GetBooks(ctx, expectedReq).Return([]model.Book{}, 0, nil)
+				mockRepo.EXPECT().GetBooksCount(ctx, expectedReq).Return(0, errors.New("db error"))
+			},
+			request: payload.GetBooksRequest{
+				PaginationRequest: payload.PaginationRequest{Page: 3, Limit: 10},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -157,9 +391,131 @@ func Test_bookService_GetBooks(t *testing.T) {
 				t.Errorf("bookService.GetBooks() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !tt.wantErr && len(gotRes.Books) != len(sampleBooks) {
+			if tt.wantErr {
+				return
+			}
+			if gotRes.Pagination.TotalItem != tt.wantTotal {
+				t.Errorf("bookService.GetBooks() total item = %d, want %d", gotRes.Pagination.TotalItem, tt.wantTotal)
+			}
+			if !reflect.DeepEqual(gotRes.Facets, tt.wantFacets) {
+				t.Errorf("bookService.GetBooks() facets = %v, want %v", gotRes.Facets, tt.wantFacets)
+			}
+			if tt.wantEmpty {
+				if len(gotRes.Books) != 0 {
+					t.Errorf("bookService.GetBooks() expected no books, got %d", len(gotRes.Books))
+				}
+				return
+			}
+			if len(gotRes.Books) != len(sampleBooks) {
 				t.Errorf("bookService.GetBooks() expected %d books, got %d", len(sampleBooks), len(gotRes.Books))
 			}
+			if gotRes.Books[0].Highlight != tt.wantHighlight {
+				t.Errorf("bookService.GetBooks() highlight = %q, want %q", gotRes.Books[0].Highlight, tt.wantHighlight)
+			}
+		})
+	}
+}
+
+func Test_bookService_GetBooks_Cursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactor := mock.NewMockTransactor(ctrl)
+	mockRepo := mock.NewMockBookRepository(ctrl)
+	mockAuditRepo := mock.NewMockAuditRepository(ctrl)
+	service := NewBookService(mockTransactor, mockRepo, mockAuditRepo)
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	books := []model.Book{
+		{ID: uuid.New(), Title: "Effective Java", UpdatedAt: now},
+		{ID: uuid.New(), Title: "Clean Code", UpdatedAt: now.Add(-time.Minute)},
+		{ID: uuid.New(), Title: "Refactoring", UpdatedAt: now.Add(-time.Hour)},
+	}
+	after := payload.BookCursor{UpdatedAt: books[1].UpdatedAt, ID: books[1].ID}
+
+	tests := []struct {
+		name           string
+		mockFunc       func()
+		request        payload.GetBooksRequest
+		wantErr        error
+		wantBooks      int
+		wantNextCursor string
+	}{
+		{
+			name: "first page with more books",
+			mockFunc: func() {
+				expectedReq := payload.GetBooksRequest{
+					PaginationRequest: payload.PaginationRequest{Page: 1, Limit: 3},
+					CursorMode:        true,
+				}
+				mockRepo.EXPECT().GetBooks(ctx, expectedReq).Return(books, 0, nil)
+			},
+			request: payload.GetBooksRequest{
+				PaginationRequest: payload.PaginationRequest{Page: 1, Limit: 2},
+				CursorMode:        true,
+			},
+			wantBooks:      2,
+			wantNextCursor: after.Encode(),
+		},
+		{
+			name: "last page",
+			mockFunc: func() {
+				expectedReq := payload.GetBooksRequest{
+					PaginationRequest: payload.PaginationRequest{Page: 1, Limit: 3},
+					Cursor:            after.Encode(),
+					CursorMode:        true,
+					After:             &after,
+				}
+				mockRepo.EXPECT().GetBooks(ctx, expectedReq).Return(books[2:], 0, nil)
+			},
+			request: payload.GetBooksRequest{
+				PaginationRequest: payload.PaginationRequest{Page: 1, Limit: 2},
+				Cursor:            after.Encode(),
+				CursorMode:        true,
+			},
+			wantBooks: 1,
+		},
+		{
+			name:     "invalid cursor",
+			mockFunc: func() {},
+			request: payload.GetBooksRequest{
+				PaginationRequest: payload.PaginationRequest{Page: 1, Limit: 2},
+				Cursor:            "not-a-cursor",
+				CursorMode:        true,
+			},
+			wantErr: errorcustom.ErrInvalidCursor,
+		},
+		{
+			name:     "cursor with full-text query",
+			mockFunc: func() {},
+			request: payload.GetBooksRequest{
+				PaginationRequest: payload.PaginationRequest{Page: 1, Limit: 2},
+				Q:                 "java",
+				CursorMode:        true,
+			},
+			wantErr: errorcustom.ErrCursorUnsupported,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+			gotRes, err := service.GetBooks(ctx, tt.request)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("bookService.GetBooks() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if len(gotRes.Books) != tt.wantBooks {
+				t.Errorf("bookService.GetBooks() expected %d books, got %d", tt.wantBooks, len(gotRes.Books))
+			}
+			if gotRes.NextCursor != tt.wantNextCursor {
+				t.Errorf("bookService.GetBooks() next cursor = %q, want %q", gotRes.NextCursor, tt.wantNextCursor)
+			}
 		})
 	}
 }
@@ -168,8 +524,10 @@ func Test_bookService_GetBookByID(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	mockTransactor := mock.NewMockTransactor(ctrl)
 	mockRepo := mock.NewMockBookRepository(ctrl)
-	service := NewBookService(mockRepo)
+	mockAuditRepo := mock.NewMockAuditRepository(ctrl)
+	service := NewBookService(mockTransactor, mockRepo, mockAuditRepo)
 
 	ctx := context.Background()
 	bookID := uuid.New().String()
@@ -240,6 +598,103 @@ func Test_bookService_GetBookByID(t *testing.T) {
 	}
 }
 
+func Test_bookService_GetBookByISBN(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockBookRepository(ctrl)
+	service := NewBookService(mock.NewMockTransactor(ctrl), mockRepo, mock.NewMockAuditRepository(ctrl))
+
+	ctx := context.Background()
+	sampleBook := &model.Book{ID: uuid.New(), ISBN: "978-0134685991", ISBN13: "9780134685991", Title: "Effective Java"}
+
+	tests := []struct {
+		name     string
+		mockFunc func()
+		isbn     string
+		wantErr  error
+	}{
+		{
+			name: "isbn-13 with hyphens",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetBookByISBN(ctx, "9780134685991").Return(sampleBook, nil)
+			},
+			isbn: "978-0-13-468599-1",
+		},
+		{
+			name: "isbn-10",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetBookByISBN(ctx, "9780134685991").Return(sampleBook, nil)
+			},
+			isbn: "0134685997",
+		},
+		{
+			name: "barcode scan with price add-on",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetBookByISBN(ctx, "9780134685991").Return(sampleBook, nil)
+			},
+			isbn: "978013468599154999",
+		},
+		{
+			name:     "not an isbn",
+			mockFunc: func() {},
+			isbn:     "4006381333931",
+			wantErr:  errorcustom.ErrInvalidISBN,
+		},
+		{
+			name: "book not found",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetBookByISBN(ctx, "9780132350884").Return(nil, nil)
+			},
+			isbn:    "9780132350884",
+			wantErr: errorcustom.ErrBookNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+			gotRes, err := service.GetBookByISBN(ctx, payload.GetBookByISBNRequest{ISBN: tt.isbn})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("bookService.GetBookByISBN() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr == nil && gotRes.ID != sampleBook.ID {
+				t.Errorf("bookService.GetBookByISBN() expected ID %v, got %v", sampleBook.ID, gotRes.ID)
+			}
+		})
+	}
+}
+
+func Test_bookService_GetBookByBarcode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockBookRepository(ctrl)
+	service := NewBookService(mock.NewMockTransactor(ctrl), mockRepo, mock.NewMockAuditRepository(ctrl))
+
+	ctx := context.Background()
+	sampleBook := &model.Book{ID: uuid.New(), Title: "Effective Java"}
+
+	t.Run("success", func(t *testing.T) {
+		mockRepo.EXPECT().GetBookByCopyBarcode(ctx, "LIB-000123").Return(sampleBook, nil)
+
+		gotRes, err := service.GetBookByBarcode(ctx, payload.GetBookByBarcodeRequest{Barcode: " LIB-000123 "})
+		if err != nil || gotRes.ID != sampleBook.ID {
+			t.Errorf("bookService.GetBookByBarcode() = %v, %v, want book %v", gotRes.ID, err, sampleBook.ID)
+		}
+	})
+
+	t.Run("unknown barcode", func(t *testing.T) {
+		mockRepo.EXPECT().GetBookByCopyBarcode(ctx, "LIB-999999").Return(nil, nil)
+
+		_, err := service.GetBookByBarcode(ctx, payload.GetBookByBarcodeRequest{Barcode: "LIB-999999"})
+		if !errors.Is(err, errorcustom.ErrBookNotFound) {
+			t.Errorf("bookService.GetBookByBarcode() error = %v, wantErr %v", err, errorcustom.ErrBookNotFound)
+		}
+	})
+}
+
 func Test_bookService_buildUpdateMap(t *testing.T) {
 	service := &bookService{}
 
@@ -300,8 +755,10 @@ func Test_bookService_UpdateBook(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	mockTransactor := mock.NewMockTransactor(ctrl)
 	mockRepo := mock.NewMockBookRepository(ctrl)
-	service := NewBookService(mockRepo)
+	mockAuditRepo := mock.NewMockAuditRepository(ctrl)
+	service := NewBookService(mockTransactor, mockRepo, mockAuditRepo)
 
 	ctx := context.Background()
 	bookID := uuid.New().String()
@@ -321,6 +778,7 @@ func Test_bookService_UpdateBook(t *testing.T) {
 	}
 
 	title := "Updated Title"
+	isbn10 := "0-13-468599-7"
 
 	tests := []struct {
 		name     string
@@ -333,7 +791,18 @@ func Test_bookService_UpdateBook(t *testing.T) {
 			name: "success",
 			mockFunc: func() {
 				mockRepo.EXPECT().GetBookByID(ctx, bookID).Return(sampleBook, nil)
+				runInTransaction(mockTransactor)
 				mockRepo.EXPECT().UpdateBook(ctx, bookID, map[string]any{"title": "Updated Title"}).Return(nil)
+				updatedBook := *sampleBook
+				updatedBook.Title = title
+				mockRepo.EXPECT().GetBookByID(ctx, bookID).Return(&updatedBook, nil)
+				mockAuditRepo.EXPECT().CreateAuditEvent(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, event model.AuditEvent) error {
+					if event.Action != model.AuditActionUpdate || event.EntityID != sampleBook.ID ||
+						!strings.Contains(string(event.Before), sampleBook.Title) || !strings.Contains(string(event.After), title) {
+						t.Errorf("bookService.UpdateBook() unexpected audit event %+v", event)
+					}
+					return nil
+				})
 			},
 			request: payload.UpdateBookRequest{
 				ID:    bookID,
@@ -341,6 +810,36 @@ func Test_bookService_UpdateBook(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "new isbn is stored with its canonical form",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetBookByID(ctx, bookID).Return(sampleBook, nil)
+				runInTransaction(mockTransactor)
+				mockRepo.EXPECT().UpdateBook(ctx, bookID, map[string]any{"isbn": isbn10, "isbn13": "9780134685991"}).Return(nil)
+				mockRepo.EXPECT().GetBookByID(ctx, bookID).Return(sampleBook, nil)
+				mockAuditRepo.EXPECT().CreateAuditEvent(ctx, gomock.Any()).Return(nil)
+			},
+			request: payload.UpdateBookRequest{
+				ID:   bookID,
+				ISBN: &isbn10,
+			},
+			wantErr: false,
+		},
+		{
+			name: "new isbn taken by another book",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetBookByID(ctx, bookID).Return(sampleBook, nil)
+				runInTransaction(mockTransactor)
+				mockRepo.EXPECT().UpdateBook(ctx, bookID, gomock.Any()).
+					Return(errors.New("ERROR: duplicate key value violates unique constraint \"books_isbn13_key\" (SQLSTATE 23505)"))
+			},
+			request: payload.UpdateBookRequest{
+				ID:   bookID,
+				ISBN: &isbn10,
+			},
+			wantErr:  true,
+			errorMsg: errorcustom.ErrBookAlreadyExists.Error(),
+		},
 		{
 			name: "book not found",
 			mockFunc: func() {
@@ -379,6 +878,7 @@ func Test_bookService_UpdateBook(t *testing.T) {
 			name: "repository update error",
 			mockFunc: func() {
 				mockRepo.EXPECT().GetBookByID(ctx, bookID).Return(sampleBook, nil)
+				runInTransaction(mockTransactor)
 				mockRepo.EXPECT().UpdateBook(ctx, bookID, map[string]any{"title": "Updated Title"}).Return(errors.New("update error"))
 			},
 			request: payload.UpdateBookRequest{
@@ -408,8 +908,10 @@ func Test_bookService_DeleteBook(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	mockTransactor := mock.NewMockTransactor(ctrl)
 	mockRepo := mock.NewMockBookRepository(ctrl)
-	service := NewBookService(mockRepo)
+	mockAuditRepo := mock.NewMockAuditRepository(ctrl)
+	service := NewBookService(mockTransactor, mockRepo, mockAuditRepo)
 
 	ctx := context.Background()
 	bookID := uuid.New().String()
@@ -439,7 +941,14 @@ func Test_bookService_DeleteBook(t *testing.T) {
 			name: "success",
 			mockFunc: func() {
 				mockRepo.EXPECT().GetBookByID(ctx, bookID).Return(sampleBook, nil)
+				runInTransaction(mockTransactor)
 				mockRepo.EXPECT().DeleteBook(ctx, bookID).Return(nil)
+				mockAuditRepo.EXPECT().CreateAuditEvent(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, event model.AuditEvent) error {
+					if event.Action != model.AuditActionDelete || event.Before == nil || event.After != nil {
+						t.Errorf("bookService.DeleteBook() unexpected audit event %+v", event)
+					}
+					return nil
+				})
 			},
 			request: payload.DeleteBookRequest{
 				ID: bookID,
@@ -471,6 +980,7 @@ func Test_bookService_DeleteBook(t *testing.T) {
 			name: "repository delete error",
 			mockFunc: func() {
 				mockRepo.EXPECT().GetBookByID(ctx, bookID).Return(sampleBook, nil)
+				runInTransaction(mockTransactor)
 				mockRepo.EXPECT().DeleteBook(ctx, bookID).Return(errors.New("delete error"))
 			},
 			request: payload.DeleteBookRequest{
@@ -494,3 +1004,131 @@ func Test_bookService_DeleteBook(t *testing.T) {
 		})
 	}
 }
+
+func Test_bookService_RestoreBook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactor := mock.NewMockTransactor(ctrl)
+	mockRepo := mock.NewMockBookRepository(ctrl)
+	mockAuditRepo := mock.NewMockAuditRepository(ctrl)
+	service := NewBookService(mockTransactor, mockRepo, mockAuditRepo)
+
+	ctx := context.Background()
+	bookID := uuid.New().String()
+	deletedAt := time.Now()
+	deletedBook := &model.Book{ID: uuid.MustParse(bookID), Title: "Effective Java", DeletedAt: &deletedAt}
+
+	tests := []struct {
+		name     string
+		mockFunc func()
+		wantErr  error
+	}{
+		{
+			name: "success",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetDeletedBookByID(ctx, bookID).Return(deletedBook, nil)
+				runInTransaction(mockTransactor)
+				mockRepo.EXPECT().RestoreBook(ctx, bookID).Return(nil)
+				mockRepo.EXPECT().GetBookByID(ctx, bookID).Return(&model.Book{ID: deletedBook.ID, Title: deletedBook.Title}, nil)
+				mockAuditRepo.EXPECT().CreateAuditEvent(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, event model.AuditEvent) error {
+					if event.Action != model.AuditActionRestore || !strings.Contains(string(event.Before), "deleted_at") ||
+						strings.Contains(string(event.After), "deleted_at") {
+						t.Errorf("bookService.RestoreBook() unexpected audit event %+v", event)
+					}
+					return nil
+				})
+			},
+		},
+		{
+			name: "not in the trash",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetDeletedBookByID(ctx, bookID).Return(nil, nil)
+			},
+			wantErr: errorcustom.ErrBookNotFound,
+		},
+		{
+			name: "isbn taken by a live book",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetDeletedBookByID(ctx, bookID).Return(deletedBook, nil)
+				runInTransaction(mockTransactor)
+				mockRepo.EXPECT().RestoreBook(ctx, bookID).
+					Return(errors.New("ERROR: duplicate key value violates unique constraint \"books_isbn13_key\" (SQLSTATE 23505)"))
+			},
+			wantErr: errorcustom.ErrBookAlreadyExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+			err := service.RestoreBook(ctx, payload.RestoreBookRequest{ID: bookID})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("bookService.RestoreBook() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_bookService_PurgeBook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactor := mock.NewMockTransactor(ctrl)
+	mockRepo := mock.NewMockBookRepository(ctrl)
+	mockAuditRepo := mock.NewMockAuditRepository(ctrl)
+	service := NewBookService(mockTransactor, mockRepo, mockAuditRepo)
+
+	ctx := context.Background()
+	bookID := uuid.New().String()
+	deletedAt := time.Now()
+	deletedBook := &model.Book{ID: uuid.MustParse(bookID), Title: "Effective Java", DeletedAt: &deletedAt}
+
+	tests := []struct {
+		name     string
+		mockFunc func()
+		wantErr  error
+	}{
+		{
+			name: "success",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetDeletedBookByID(ctx, bookID).Return(deletedBook, nil)
+				runInTransaction(mockTransactor)
+				mockRepo.EXPECT().PurgeBook(ctx, bookID).Return(nil)
+				mockAuditRepo.EXPECT().CreateAuditEvent(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, event model.AuditEvent) error {
+					if event.Action != model.AuditActionPurge || event.Before == nil || event.After != nil {
+						t.Errorf("bookService.PurgeBook() unexpected audit event %+v", event)
+					}
+					return nil
+				})
+			},
+		},
+		{
+			name: "not in the trash",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetDeletedBookByID(ctx, bookID).Return(nil, nil)
+			},
+			wantErr: errorcustom.ErrBookNotFound,
+		},
+		{
+			name: "book with loan history",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetDeletedBookByID(ctx, bookID).Return(deletedBook, nil)
+				runInTransaction(mockTransactor)
+				mockRepo.EXPECT().PurgeBook(ctx, bookID).
+					Return(errors.New("ERROR: update or delete on table \"books\" violates foreign key constraint \"loans_book_id_fkey\" on table \"loans\" (SQLSTATE 23503)"))
+			},
+			wantErr: errorcustom.ErrBookPurgeBlocked,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+			err := service.PurgeBook(ctx, payload.PurgeBookRequest{ID: bookID})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("bookService.PurgeBook() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
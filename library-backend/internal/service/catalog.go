@@ -0,0 +1,284 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"library-backend/internal/payload"
+	"library-backend/internal/repository"
+	"library-backend/pkg/opds"
+	"library-backend/pkg/sru"
+	"mime"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// the paths of the OPDS catalog, feed links point at them
+const (
+	opdsRootPath       = "/v1/opds"
+	opdsBooksPath      = "/v1/opds/books"
+	opdsOpenSearchPath = "/v1/opds/search.xml"
+)
+
+// opdsID prefixes the IDs of the catalog feeds.
+const opdsID = "urn:library:opds"
+
+type CatalogService interface {
+	GetOPDSRoot(ctx context.Context) opds.Feed
+	GetOPDSBooks(ctx context.Context, request payload.GetBooksRequest) (opds.Feed, error)
+	GetOpenSearchDescription(ctx context.Context) opds.OpenSearchDescription
+	SearchRetrieve(ctx context.Context, request payload.SRURequest) (sru.SearchRetrieveResponse, error)
+	Scan(ctx context.Context, request payload.SRURequest) (sru.ScanResponse, error)
+	Explain(ctx context.Context, request payload.SRURequest) (sru.ExplainResponse, error)
+}
+
+// catalogService serves the book catalog to other systems: to e-reader apps as OPDS feeds on top of
+// the book list, and to library systems over SRU.
+type catalogService struct {
+	bookService BookService
+	bookRepo    repository.BookRepository
+}
+
+func NewCatalogService(bookService BookService, bookRepo repository.BookRepository) CatalogService {
+	return &catalogService{
+		bookService: bookService,
+		bookRepo:    bookRepo,
+	}
+}
+
+// GetOPDSRoot returns the navigation feed a client starts from: every book, the newest books and a
+// feed per category.
+func (s *catalogService) GetOPDSRoot(ctx context.Context) opds.Feed {
+	now := time.Now().UTC()
+
+	feed := opds.Feed{
+		ID:      opdsID,
+		Title:   "Library catalog",
+		Updated: now,
+		Links: append(opdsFeedLinks(),
+			opds.Link{Rel: opds.RelSelf, Href: opdsRootPath, Type: opds.TypeNavigation},
+		),
+	}
+
+	addEntry := func(id, title, content, rel string, query url.Values) {
+		feed.Entries = append(feed.Entries, opds.Entry{
+			ID:      opdsID + ":" + id,
+			Title:   title,
+			Updated: now,
+			Content: opds.TextContent(content),
+			Links:   []opds.Link{{Rel: rel, Href: opdsBooksHref(query), Type: opds.TypeAcquisition}},
+		})
+	}
+
+	addEntry("books", "All books", "Every book in the catalog, by title", opds.RelSubsection, url.Values{"sort": {"title"}})
+	addEntry("new", "New arrivals", "The books added most recently", opds.RelSortNew, url.Values{"sort": {"-created_at"}})
+
+	for _, category := range payload.BookCategories {
+		title := opdsCategoryLabel(category)
+		addEntry("category:"+category, title, title+" books, by title", opds.RelSubsection,
+			url.Values{"category": {category}, "sort": {"title"}})
+	}
+
+	return feed
+}
+
+// GetOPDSBooks returns a page of the book list as an acquisition feed, with the same filters and
+// sort as the list and links to the other pages.
+func (s *catalogService) GetOPDSBooks(ctx context.Context, request payload.GetBooksRequest) (opds.Feed, error) {
+	request.CursorMode = false
+
+	res, err := s.bookService.GetBooks(ctx, request)
+	if err != nil {
+		return opds.Feed{}, err
+	}
+
+	query := opdsBooksQuery(request)
+	page := res.Pagination
+
+	feed := opds.Feed{
+		ID:           opdsID + ":books?" + query.Encode(),
+		Title:        opdsBooksTitle(request),
+		TotalResults: &page.TotalItem,
+		ItemsPerPage: &page.Limit,
+		StartIndex:   intPtr((page.Page-1)*page.Limit + 1),
+		Links:        opdsFeedLinks(),
+	}
+
+	pageLink := func(rel string, number int) opds.Link {
+		values := url.Values{}
+		for key, value := range query {
+			values[key] = value
+		}
+		values.Set("page", strconv.Itoa(number))
+
+		return opds.Link{Rel: rel, Href: opdsBooksHref(values), Type: opds.TypeAcquisition}
+	}
+
+	feed.Links = append(feed.Links, pageLink(opds.RelSelf, page.Page), pageLink(opds.RelFirst, 1))
+	if page.Page > 1 {
+		feed.Links = append(feed.Links, pageLink(opds.RelPrevious, page.Page-1))
+	}
+	if page.Page < page.TotalPage {
+		feed.Links = append(feed.Links, pageLink(opds.RelNext, page.Page+1))
+	}
+	feed.Links = append(feed.Links, pageLink(opds.RelLast, max(page.TotalPage, 1)))
+
+	// the feed changed when the latest of its books did
+	for _, book := range res.Books {
+		if book.UpdatedAt.After(feed.Updated) {
+			feed.Updated = book.UpdatedAt
+		}
+		feed.Entries = append(feed.Entries, opdsBookEntry(book))
+	}
+	if feed.Updated.IsZero() {
+		feed.Updated = time.Now().UTC()
+	}
+
+	return feed, nil
+}
+
+// GetOpenSearchDescription describes the full-text search of the book list to clients.
+func (s *catalogService) GetOpenSearchDescription(ctx context.Context) opds.OpenSearchDescription {
+	return opds.OpenSearchDescription{
+		ShortName:   "Library",
+		Description: "Search the library catalog by title, author, publisher or ISBN",
+		URL: opds.OpenSearchURL{
+			Type:     opds.TypeAcquisition,
+			Template: opdsBooksPath + "?q={searchTerms}&page={startPage?}",
+		},
+	}
+}
+
+// opdsPhysicalCopyType is the type a borrow link ends in, a printed book lent at the desk. There is
+// no registered media type for one, clients show it as a format they cannot download.
+const opdsPhysicalCopyType = "application/vnd.library.physical-copy"
+
+// opdsFeedLinks are the links every feed has, to the root and to search.
+func opdsFeedLinks() []opds.Link {
+	return []opds.Link{
+		{Rel: opds.RelStart, Href: opdsRootPath, Type: opds.TypeNavigation},
+		{Rel: opds.RelUp, Href: opdsRootPath, Type: opds.TypeNavigation},
+		{Rel: opds.RelSearch, Href: opdsOpenSearchPath, Type: opds.TypeOpenSearch},
+	}
+}
+
+// opdsBookEntry is a book in an acquisition feed. The books are physical copies to borrow at the
+// desk, so the borrow link leads to the copies and their shelf locations, describes what it ends
+// in as a physical copy, and the entry carries how many are available.
+func opdsBookEntry(book payload.BookResponse) opds.Entry {
+	entry := opds.Entry{
+		ID:         "urn:uuid:" + book.ID.String(),
+		Title:      book.Title,
+		Updated:    book.UpdatedAt,
+		Authors:    []opds.Person{{Name: book.Author}},
+		Identifier: "urn:isbn:" + book.ISBN13,
+		Publisher:  book.Publisher,
+		Issued:     strconv.Itoa(book.YearOfPublication),
+		Categories: []opds.Category{{Term: book.Category, Label: opdsCategoryLabel(book.Category)}},
+		Content:    opds.TextContent(fmt.Sprintf("%d of %d copies available", book.AvailableCopies, book.TotalCopies)),
+		Links: []opds.Link{
+			{Rel: opds.RelAlternate, Href: "/v1/books/" + book.ID.String(), Type: "application/json"},
+			{Rel: opds.RelAlternate, Href: "/v1/books/" + book.ID.String() + "/marc", Type: "application/marcxml+xml"},
+			{
+				Rel:                  opds.RelAcquisitionBorrow,
+				Href:                 "/v1/books/" + book.ID.String() + "/copies",
+				Type:                 "application/json",
+				Title:                "Borrow at the library desk",
+				IndirectAcquisitions: []opds.IndirectAcquisition{{Type: opdsPhysicalCopyType}},
+			},
+		},
+	}
+
+	if book.ImageURL != "" {
+		// the type of the cover is only known from its extension, clients sniff it otherwise
+		var imageType string
+		if u, err := url.Parse(book.ImageURL); err == nil {
+			imageType, _, _ = mime.ParseMediaType(mime.TypeByExtension(path.Ext(u.Path)))
+		}
+
+		entry.Links = append(entry.Links,
+			opds.Link{Rel: opds.RelImage, Href: book.ImageURL, Type: imageType},
+			opds.Link{Rel: opds.RelThumbnail, Href: book.ImageURL, Type: imageType},
+		)
+	}
+
+	return entry
+}
+
+// opdsBooksQuery is the query of a book list request, without its page, so feed links keep the
+// filters.
+func opdsBooksQuery(request payload.GetBooksRequest) url.Values {
+	values := url.Values{}
+
+	set := func(key, value string) {
+		if value != "" {
+			values.Set(key, value)
+		}
+	}
+
+	set("title", request.Title)
+	set("q", request.Q)
+	set("author", request.Author)
+	set("publisher", request.Publisher)
+	set("created_from", request.CreatedFrom)
+	set("created_to", request.CreatedTo)
+	set("updated_from", request.UpdatedFrom)
+	set("updated_to", request.UpdatedTo)
+	set("sort", request.Sort)
+	set("filter", request.Filter)
+	if request.YearFrom != 0 {
+		values.Set("year_from", strconv.Itoa(request.YearFrom))
+	}
+	if request.YearTo != 0 {
+		values.Set("year_to", strconv.Itoa(request.YearTo))
+	}
+	if request.Limit != 0 {
+		values.Set("limit", strconv.Itoa(request.Limit))
+	}
+	for _, category := range request.Category {
+		values.Add("category", category)
+	}
+
+	return values
+}
+
+func opdsBooksHref(query url.Values) string {
+	if len(query) == 0 {
+		return opdsBooksPath
+	}
+
+	return opdsBooksPath + "?" + query.Encode()
+}
+
+// opdsBooksTitle names an acquisition feed after what it lists.
+func opdsBooksTitle(request payload.GetBooksRequest) string {
+	switch {
+	case request.Q != "":
+		return fmt.Sprintf("Search results for %q", request.Q)
+	case len(request.Category) == 1:
+		return opdsCategoryLabel(request.Category[0])
+	case request.Sort == "-created_at":
+		return "New arrivals"
+	default:
+		return "Books"
+	}
+}
+
+// opdsCategoryLabel is the name of a category as shown to patrons, "science-fiction" is "Science
+// Fiction".
+func opdsCategoryLabel(category string) string {
+	words := strings.Split(category, "-")
+	for i, word := range words {
+		if word != "" {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+
+	return strings.Join(words, " ")
+}
+
+func intPtr(v int) *int {
+	return &v
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"library-backend/errorcustom"
+	"library-backend/internal/model"
+	"library-backend/internal/payload"
+	"library-backend/internal/repository/mock"
+	"library-backend/pkg/opds"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+)
+
+func Test_catalogService_GetOPDSRoot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewCatalogService(NewBookService(mock.NewMockTransactor(ctrl), mock.NewMockBookRepository(ctrl), mock.NewMockAuditRepository(ctrl)), mock.NewMockBookRepository(ctrl))
+
+	feed := service.GetOPDSRoot(context.Background())
+
+	// all books, new arrivals and a feed per category
+	if len(feed.Entries) != 2+len(payload.BookCategories) {
+		t.Fatalf("catalogService.GetOPDSRoot() entries = %d", len(feed.Entries))
+	}
+
+	scifi := feed.Entries[len(feed.Entries)-2]
+	if scifi.Title != "Science Fiction" || scifi.Links[0].Href != "/v1/opds/books?category=science-fiction&sort=title" {
+		t.Errorf("catalogService.GetOPDSRoot() category entry = %+v", scifi)
+	}
+
+	if feed.Entries[1].Links[0].Rel != opds.RelSortNew {
+		t.Errorf("catalogService.GetOPDSRoot() new arrivals entry = %+v", feed.Entries[1])
+	}
+}
+
+func Test_catalogService_GetOPDSBooks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockBookRepository(ctrl)
+	service := NewCatalogService(NewBookService(mock.NewMockTransactor(ctrl), mockRepo, mock.NewMockAuditRepository(ctrl)), mockRepo)
+
+	ctx := context.Background()
+	updated := time.Date(2025, 8, 24, 8, 0, 0, 0, time.UTC)
+	books := []model.Book{
+		{ID: uuid.New(), ISBN13: "9780441172719", Title: "Dune", Author: "Frank Herbert", YearOfPublication: 1965, Category: "science-fiction", ImageURL: "https://example.com/dune.jpg?size=l", UpdatedAt: updated, TotalCopies: 3, AvailableCopies: 1},
+		{ID: uuid.New(), ISBN13: "9780553293357", Title: "Foundation", Author: "Isaac Asimov", YearOfPublication: 1951, Category: "science-fiction", UpdatedAt: updated.Add(-time.Hour)},
+	}
+
+	linkHref := func(feed opds.Feed, rel string) string {
+		for _, link := range feed.Links {
+			if link.Rel == rel {
+				return link.Href
+			}
+		}
+		return ""
+	}
+
+	t.Run("middle page", func(t *testing.T) {
+		request := payload.GetBooksRequest{
+			PaginationRequest: payload.PaginationRequest{Page: 2, Limit: 2},
+			Category:          []string{"science-fiction"},
+			Sort:              "title",
+		}
+
+		mockRepo.EXPECT().GetBooks(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, req payload.GetBooksRequest) ([]model.Book, int, error) {
+			if req.Offset != 2 || req.Category[0] != "science-fiction" || req.SortFields[0].Field != "title" {
+				t.Errorf("catalogService.GetOPDSBooks() unexpected request %+v", req)
+			}
+			return books, 5, nil
+		})
+
+		feed, err := service.GetOPDSBooks(ctx, request)
+		if err != nil {
+			t.Fatalf("catalogService.GetOPDSBooks() error = %v", err)
+		}
+
+		if feed.Title != "Science Fiction" || !feed.Updated.Equal(updated) || *feed.TotalResults != 5 || *feed.StartIndex != 3 {
+			t.Errorf("catalogService.GetOPDSBooks() feed = %+v", feed)
+		}
+
+		for rel, page := range map[string]string{opds.RelSelf: "2", opds.RelFirst: "1", opds.RelPrevious: "1", opds.RelNext: "3", opds.RelLast: "3"} {
+			href, err := url.Parse(linkHref(feed, rel))
+			if err != nil || href.Path != "/v1/opds/books" || href.Query().Get("page") != page || href.Query().Get("category") != "science-fiction" {
+				t.Errorf("catalogService.GetOPDSBooks() %s link = %v", rel, href)
+			}
+		}
+
+		dune := feed.Entries[0]
+		if dune.Identifier != "urn:isbn:9780441172719" || dune.Issued != "1965" || dune.Content.Text != "1 of 3 copies available" {
+			t.Errorf("catalogService.GetOPDSBooks() entry = %+v", dune)
+		}
+
+		var cover opds.Link
+		for _, link := range dune.Links {
+			if link.Rel == opds.RelImage {
+				cover = link
+			}
+		}
+		if cover.Href != books[0].ImageURL || cover.Type != "image/jpeg" {
+			t.Errorf("catalogService.GetOPDSBooks() cover link = %+v", cover)
+		}
+
+		var borrow opds.Link
+		for _, link := range dune.Links {
+			if link.Rel == opds.RelAcquisitionBorrow {
+				borrow = link
+			}
+		}
+		if borrow.Href != "/v1/books/"+books[0].ID.String()+"/copies" || len(borrow.IndirectAcquisitions) != 1 || borrow.IndirectAcquisitions[0].Type != opdsPhysicalCopyType {
+			t.Errorf("catalogService.GetOPDSBooks() borrow link = %+v", borrow)
+		}
+
+		// every entry of an acquisition feed needs an acquisition link
+		for _, entry := range feed.Entries {
+			var acquisition bool
+			for _, link := range entry.Links {
+				acquisition = acquisition || strings.HasPrefix(link.Rel, "http://opds-spec.org/acquisition")
+			}
+			if !acquisition {
+				t.Errorf("catalogService.GetOPDSBooks() entry without an acquisition link = %+v", entry)
+			}
+		}
+
+		for _, link := range feed.Entries[1].Links {
+			if strings.HasPrefix(link.Rel, opds.RelImage) {
+				t.Errorf("catalogService.GetOPDSBooks() cover link for a book without one = %+v", link)
+			}
+		}
+	})
+
+	t.Run("single page", func(t *testing.T) {
+		mockRepo.EXPECT().GetBooks(ctx, gomock.Any()).Return(books, 2, nil)
+
+		feed, err := service.GetOPDSBooks(ctx, payload.GetBooksRequest{PaginationRequest: payload.PaginationRequest{Page: 1, Limit: 25}, Q: "dune"})
+		if err != nil {
+			t.Fatalf("catalogService.GetOPDSBooks() error = %v", err)
+		}
+
+		if linkHref(feed, opds.RelNext) != "" || linkHref(feed, opds.RelPrevious) != "" || feed.Title != `Search results for "dune"` {
+			t.Errorf("catalogService.GetOPDSBooks() feed = %+v", feed)
+		}
+	})
+
+	t.Run("invalid sort field", func(t *testing.T) {
+		_, err := service.GetOPDSBooks(ctx, payload.GetBooksRequest{PaginationRequest: payload.PaginationRequest{Page: 1, Limit: 25}, Sort: "isbn"})
+		if !errors.Is(err, errorcustom.ErrInvalidSortField) {
+			t.Errorf("catalogService.GetOPDSBooks() error = %v, wantErr %v", err, errorcustom.ErrInvalidSortField)
+		}
+	})
+}
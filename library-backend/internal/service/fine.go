@@ -0,0 +1,302 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"library-backend/errorcustom"
+	"library-backend/internal/config"
+	"library-backend/internal/model"
+	"library-backend/internal/payload"
+	"library-backend/internal/repository"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FineService interface {
+	GetMemberFines(ctx context.Context, request payload.GetMemberFinesRequest) (payload.GetMemberFinesResponse, error)
+	CreatePayment(ctx context.Context, request payload.CreateFinePaymentRequest) (payload.CreateFineEntryResponse, error)
+	CreateWaiver(ctx context.Context, request payload.CreateFineWaiverRequest) (payload.CreateFineEntryResponse, error)
+	GetFineBalances(ctx context.Context, request payload.GetFineBalancesRequest) (payload.GetFineBalancesResponse, error)
+}
+
+type fineService struct {
+	transactor repository.Transactor
+	fineRepo   repository.FineRepository
+	memberRepo repository.MemberRepository
+	loanRepo   repository.LoanRepository
+}
+
+func NewFineService(
+	transactor repository.Transactor,
+	fineRepo repository.FineRepository,
+	memberRepo repository.MemberRepository,
+	loanRepo repository.LoanRepository,
+) FineService {
+	return &fineService{
+		transactor: transactor,
+		fineRepo:   fineRepo,
+		memberRepo: memberRepo,
+		loanRepo:   loanRepo,
+	}
+}
+
+func (s *fineService) GetMemberFines(ctx context.Context, request payload.GetMemberFinesRequest) (res payload.GetMemberFinesResponse, err error) {
+	if err = s.ensureMemberExists(ctx, request.MemberID); err != nil {
+		return res, err
+	}
+
+	request.Offset = (request.Page - 1) * request.Limit
+
+	balance, err := s.fineRepo.GetMemberFineBalance(ctx, request.MemberID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[FineService][GetMemberFines] failed to get fine balance", "error", err, "member_id", request.MemberID)
+		return res, err
+	}
+
+	// get ledger entries with pagination
+	entries, err := s.fineRepo.GetFineEntries(ctx, request)
+	if err != nil {
+		slog.ErrorContext(ctx, "[FineService][GetMemberFines] failed to get fine entries", "error", err, "member_id", request.MemberID)
+		return res, err
+	}
+
+	totalCount, err := s.fineRepo.GetFineEntriesCount(ctx, request)
+	if err != nil {
+		slog.ErrorContext(ctx, "[FineService][GetMemberFines] failed to get fine entries count", "error", err, "member_id", request.MemberID)
+		return res, err
+	}
+
+	totalPages := int(math.Ceil(float64(totalCount) / float64(request.Limit)))
+
+	entryResponses := make([]payload.FineEntryResponse, len(entries))
+	for i, entry := range entries {
+		entryResponses[i] = toFineEntryResponse(entry)
+	}
+
+	balance.MemberID = uuid.MustParse(request.MemberID)
+
+	res.Balance = toFineBalanceResponse(balance)
+	res.Entries = entryResponses
+	res.Pagination = payload.Pagination{
+		Page:      request.Page,
+		Limit:     request.Limit,
+		TotalPage: totalPages,
+		TotalItem: totalCount,
+	}
+
+	return res, nil
+}
+
+func (s *fineService) CreatePayment(ctx context.Context, request payload.CreateFinePaymentRequest) (res payload.CreateFineEntryResponse, err error) {
+	if err = s.ensureMemberExists(ctx, request.MemberID); err != nil {
+		return res, err
+	}
+
+	entry := model.FineEntry{
+		ID:        uuid.New(),
+		MemberID:  uuid.MustParse(request.MemberID),
+		EntryType: model.FineEntryTypePayment,
+		Amount:    request.Amount,
+		Note:      request.Note,
+		CreatedAt: time.Now(),
+	}
+
+	balance, err := s.settle(ctx, entry)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrFineExceedsBalance) {
+			return res, err
+		}
+		slog.ErrorContext(ctx, "[FineService][CreatePayment] failed to record payment", "error", err, "member_id", request.MemberID)
+		return res, err
+	}
+
+	res.ID = entry.ID
+	res.Balance = balance
+
+	return res, nil
+}
+
+func (s *fineService) CreateWaiver(ctx context.Context, request payload.CreateFineWaiverRequest) (res payload.CreateFineEntryResponse, err error) {
+	if err = s.ensureMemberExists(ctx, request.MemberID); err != nil {
+		return res, err
+	}
+
+	entry := model.FineEntry{
+		ID:        uuid.New(),
+		MemberID:  uuid.MustParse(request.MemberID),
+		EntryType: model.FineEntryTypeWaiver,
+		Amount:    request.Amount,
+		Note:      request.Note,
+		CreatedAt: time.Now(),
+	}
+
+	// a waiver may point at the loan whose charge it forgives
+	if request.LoanID != "" {
+		loan, err := s.loanRepo.GetLoanByID(ctx, request.LoanID)
+		if err != nil {
+			slog.ErrorContext(ctx, "[FineService][CreateWaiver] failed to get loan", "error", err, "loan_id", request.LoanID)
+			return res, err
+		}
+
+		if loan == nil || loan.MemberID != entry.MemberID {
+			return res, errorcustom.ErrLoanNotFound
+		}
+
+		entry.LoanID = &loan.ID
+	}
+
+	balance, err := s.settle(ctx, entry)
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrFineExceedsBalance) {
+			return res, err
+		}
+		slog.ErrorContext(ctx, "[FineService][CreateWaiver] failed to record waiver", "error", err, "member_id", request.MemberID)
+		return res, err
+	}
+
+	res.ID = entry.ID
+	res.Balance = balance
+
+	return res, nil
+}
+
+func (s *fineService) GetFineBalances(ctx context.Context, request payload.GetFineBalancesRequest) (res payload.GetFineBalancesResponse, err error) {
+	request.Offset = (request.Page - 1) * request.Limit
+
+	// get outstanding balances with pagination
+	balances, err := s.fineRepo.GetFineBalances(ctx, request)
+	if err != nil {
+		slog.ErrorContext(ctx, "[FineService][GetFineBalances] failed to get fine balances", "error", err)
+		return res, err
+	}
+
+	totalCount, err := s.fineRepo.GetFineBalancesCount(ctx, request)
+	if err != nil {
+		slog.ErrorContext(ctx, "[FineService][GetFineBalances] failed to get fine balances count", "error", err)
+		return res, err
+	}
+
+	totalPages := int(math.Ceil(float64(totalCount) / float64(request.Limit)))
+
+	balanceResponses := make([]payload.FineBalanceResponse, len(balances))
+	for i, balance := range balances {
+		balanceResponses[i] = toFineBalanceResponse(balance)
+	}
+
+	res.Balances = balanceResponses
+	res.Pagination = payload.Pagination{
+		Page:      request.Page,
+		Limit:     request.Limit,
+		TotalPage: totalPages,
+		TotalItem: totalCount,
+	}
+
+	return res, nil
+}
+
+// settle records a waiver or payment against the member's outstanding balance and returns the
+// balance left. The member's ledger is locked so concurrent payments cannot overshoot it.
+func (s *fineService) settle(ctx context.Context, entry model.FineEntry) (remaining int64, err error) {
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := s.fineRepo.LockMemberFines(ctx, entry.MemberID.String())
+		if err != nil {
+			return err
+		}
+
+		balance, err := s.fineRepo.GetMemberFineBalance(ctx, entry.MemberID.String())
+		if err != nil {
+			return err
+		}
+
+		if entry.Amount > balance.Balance {
+			return errorcustom.ErrFineExceedsBalance
+		}
+
+		remaining = balance.Balance - entry.Amount
+
+		return s.fineRepo.CreateFineEntry(ctx, entry)
+	})
+
+	return remaining, err
+}
+
+func (s *fineService) ensureMemberExists(ctx context.Context, memberID string) error {
+	member, err := s.memberRepo.GetMemberByID(ctx, memberID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[FineService] failed to check member existence", "error", err, "member_id", memberID)
+		return err
+	}
+
+	if member == nil {
+		return errorcustom.ErrMemberNotFound
+	}
+
+	return nil
+}
+
+// calculateFine charges the configured daily rate for every started day a loan was kept past its
+// due date, the grace days are free and the total is capped per loan when a cap is configured.
+func calculateFine(cfg *config.Config, dueAt, returnedAt time.Time) (amount int64, daysOverdue int) {
+	if !returnedAt.After(dueAt) {
+		return 0, 0
+	}
+
+	daysOverdue = int(math.Ceil(returnedAt.Sub(dueAt).Hours() / 24))
+
+	chargeableDays := daysOverdue - cfg.FineGraceDays
+	if chargeableDays <= 0 {
+		return 0, daysOverdue
+	}
+
+	amount = int64(chargeableDays) * int64(cfg.FinePerDay)
+	if cfg.FineMaxPerLoan > 0 && amount > int64(cfg.FineMaxPerLoan) {
+		amount = int64(cfg.FineMaxPerLoan)
+	}
+
+	return amount, daysOverdue
+}
+
+// overdueCharge builds the ledger charge for a loan returned late, or returns nil when no fine is due.
+func overdueCharge(cfg *config.Config, loan model.Loan, returnedAt time.Time) *model.FineEntry {
+	amount, daysOverdue := calculateFine(cfg, loan.DueAt, returnedAt)
+	if amount == 0 {
+		return nil
+	}
+
+	return &model.FineEntry{
+		ID:        uuid.New(),
+		MemberID:  loan.MemberID,
+		LoanID:    &loan.ID,
+		EntryType: model.FineEntryTypeCharge,
+		Amount:    amount,
+		Note:      fmt.Sprintf("returned %d day(s) late", daysOverdue),
+		CreatedAt: returnedAt,
+	}
+}
+
+func toFineEntryResponse(entry model.FineEntry) payload.FineEntryResponse {
+	return payload.FineEntryResponse{
+		ID:        entry.ID,
+		MemberID:  entry.MemberID,
+		LoanID:    entry.LoanID,
+		EntryType: entry.EntryType,
+		Amount:    entry.Amount,
+		Note:      entry.Note,
+		CreatedAt: entry.CreatedAt,
+	}
+}
+
+func toFineBalanceResponse(balance model.FineBalance) payload.FineBalanceResponse {
+	return payload.FineBalanceResponse{
+		MemberID:   balance.MemberID,
+		MemberName: balance.MemberName,
+		Charged:    balance.Charged,
+		Waived:     balance.Waived,
+		Paid:       balance.Paid,
+		Balance:    balance.Balance,
+	}
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"library-backend/errorcustom"
+	"library-backend/internal/config"
+	"library-backend/internal/model"
+	"library-backend/internal/payload"
+	"library-backend/internal/repository/mock"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+)
+
+func Test_calculateFine(t *testing.T) {
+	dueAt := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		cfg        config.Config
+		returnedAt time.Time
+		wantAmount int64
+		wantDays   int
+	}{
+		{
+			name:       "returned on time",
+			cfg:        config.Config{FinePerDay: 25},
+			returnedAt: dueAt.Add(-time.Hour),
+			wantAmount: 0,
+			wantDays:   0,
+		},
+		{
+			name:       "started day counts as a full day",
+			cfg:        config.Config{FinePerDay: 25},
+			returnedAt: dueAt.Add(26 * time.Hour),
+			wantAmount: 50,
+			wantDays:   2,
+		},
+		{
+			name:       "grace days are free",
+			cfg:        config.Config{FinePerDay: 25, FineGraceDays: 2},
+			returnedAt: dueAt.Add(5 * 24 * time.Hour),
+			wantAmount: 75,
+			wantDays:   5,
+		},
+		{
+			name:       "within grace period",
+			cfg:        config.Config{FinePerDay: 25, FineGraceDays: 2},
+			returnedAt: dueAt.Add(24 * time.Hour),
+			wantAmount: 0,
+			wantDays:   1,
+		},
+		{
+			name:       "capped per loan",
+			cfg:        config.Config{FinePerDay: 25, FineMaxPerLoan: 100},
+			returnedAt: dueAt.Add(30 * 24 * time.Hour),
+			wantAmount: 100,
+			wantDays:   30,
+		},
+		{
+			name:       "no cap configured",
+			cfg:        config.Config{FinePerDay: 25},
+			returnedAt: dueAt.Add(30 * 24 * time.Hour),
+			wantAmount: 750,
+			wantDays:   30,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount, days := calculateFine(&tt.cfg, dueAt, tt.returnedAt)
+			if amount != tt.wantAmount || days != tt.wantDays {
+				t.Errorf("calculateFine() = (%v, %v), want (%v, %v)", amount, days, tt.wantAmount, tt.wantDays)
+			}
+		})
+	}
+}
+
+func Test_fineService_CreatePayment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactor := mock.NewMockTransactor(ctrl)
+	mockFineRepo := mock.NewMockFineRepository(ctrl)
+	mockMemberRepo := mock.NewMockMemberRepository(ctrl)
+	service := NewFineService(mockTransactor, mockFineRepo, mockMemberRepo, mock.NewMockLoanRepository(ctrl))
+
+	ctx := context.Background()
+	memberID := uuid.New()
+	member := &model.Member{ID: memberID, Name: "Jane Doe", Status: model.MemberStatusActive}
+
+	tests := []struct {
+		name        string
+		mockFunc    func()
+		amount      int64
+		wantErr     bool
+		errorMsg    string
+		wantBalance int64
+	}{
+		{
+			name: "success",
+			mockFunc: func() {
+				mockMemberRepo.EXPECT().GetMemberByID(ctx, memberID.String()).Return(member, nil)
+				runInTransaction(mockTransactor)
+				mockFineRepo.EXPECT().LockMemberFines(ctx, memberID.String()).Return(nil)
+				mockFineRepo.EXPECT().GetMemberFineBalance(ctx, memberID.String()).Return(model.FineBalance{Charged: 150, Balance: 150}, nil)
+				mockFineRepo.EXPECT().CreateFineEntry(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, entry model.FineEntry) error {
+					if entry.EntryType != model.FineEntryTypePayment || entry.Amount != 100 {
+						t.Errorf("fineService.CreatePayment() unexpected entry %+v", entry)
+					}
+					return nil
+				})
+			},
+			amount:      100,
+			wantErr:     false,
+			wantBalance: 50,
+		},
+		{
+			name: "more than owed",
+			mockFunc: func() {
+				mockMemberRepo.EXPECT().GetMemberByID(ctx, memberID.String()).Return(member, nil)
+				runInTransaction(mockTransactor)
+				mockFineRepo.EXPECT().LockMemberFines(ctx, memberID.String()).Return(nil)
+				mockFineRepo.EXPECT().GetMemberFineBalance(ctx, memberID.String()).Return(model.FineBalance{Charged: 150, Paid: 100, Balance: 50}, nil)
+			},
+			amount:   100,
+			wantErr:  true,
+			errorMsg: errorcustom.ErrFineExceedsBalance.Error(),
+		},
+		{
+			name: "member not found",
+			mockFunc: func() {
+				mockMemberRepo.EXPECT().GetMemberByID(ctx, memberID.String()).Return(nil, nil)
+			},
+			amount:   100,
+			wantErr:  true,
+			errorMsg: errorcustom.ErrMemberNotFound.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+			gotRes, err := service.CreatePayment(ctx, payload.CreateFinePaymentRequest{MemberID: memberID.String(), Amount: tt.amount})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("fineService.CreatePayment() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.errorMsg != "" && err != nil && err.Error() != tt.errorMsg {
+				t.Errorf("fineService.CreatePayment() error = %v, want %v", err.Error(), tt.errorMsg)
+			}
+			if !tt.wantErr && gotRes.Balance != tt.wantBalance {
+				t.Errorf("fineService.CreatePayment() balance = %v, want %v", gotRes.Balance, tt.wantBalance)
+			}
+		})
+	}
+}
+
+func Test_fineService_CreateWaiver(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactor := mock.NewMockTransactor(ctrl)
+	mockFineRepo := mock.NewMockFineRepository(ctrl)
+	mockMemberRepo := mock.NewMockMemberRepository(ctrl)
+	mockLoanRepo := mock.NewMockLoanRepository(ctrl)
+	service := NewFineService(mockTransactor, mockFineRepo, mockMemberRepo, mockLoanRepo)
+
+	ctx := context.Background()
+	memberID := uuid.New()
+	loanID := uuid.New()
+	member := &model.Member{ID: memberID, Name: "Jane Doe", Status: model.MemberStatusActive}
+
+	tests := []struct {
+		name     string
+		mockFunc func()
+		wantErr  bool
+		errorMsg string
+	}{
+		{
+			name: "success for a loan",
+			mockFunc: func() {
+				mockMemberRepo.EXPECT().GetMemberByID(ctx, memberID.String()).Return(member, nil)
+				mockLoanRepo.EXPECT().GetLoanByID(ctx, loanID.String()).Return(&model.Loan{ID: loanID, MemberID: memberID}, nil)
+				runInTransaction(mockTransactor)
+				mockFineRepo.EXPECT().LockMemberFines(ctx, memberID.String()).Return(nil)
+				mockFineRepo.EXPECT().GetMemberFineBalance(ctx, memberID.String()).Return(model.FineBalance{Charged: 75, Balance: 75}, nil)
+				mockFineRepo.EXPECT().CreateFineEntry(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, entry model.FineEntry) error {
+					if entry.EntryType != model.FineEntryTypeWaiver || entry.LoanID == nil || *entry.LoanID != loanID {
+						t.Errorf("fineService.CreateWaiver() unexpected entry %+v", entry)
+					}
+					return nil
+				})
+			},
+			wantErr: false,
+		},
+		{
+			name: "loan of another member",
+			mockFunc: func() {
+				mockMemberRepo.EXPECT().GetMemberByID(ctx, memberID.String()).Return(member, nil)
+				mockLoanRepo.EXPECT().GetLoanByID(ctx, loanID.String()).Return(&model.Loan{ID: loanID, MemberID: uuid.New()}, nil)
+			},
+			wantErr:  true,
+			errorMsg: errorcustom.ErrLoanNotFound.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+			_, err := service.CreateWaiver(ctx, payload.CreateFineWaiverRequest{MemberID: memberID.String(), LoanID: loanID.String(), Amount: 75})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("fineService.CreateWaiver() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.errorMsg != "" && err != nil && err.Error() != tt.errorMsg {
+				t.Errorf("fineService.CreateWaiver() error = %v, want %v", err.Error(), tt.errorMsg)
+			}
+		})
+	}
+}
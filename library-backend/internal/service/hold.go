@@ -0,0 +1,295 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"library-backend/errorcustom"
+	"library-backend/internal/config"
+	"library-backend/internal/model"
+	"library-backend/internal/payload"
+	"library-backend/internal/repository"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type HoldService interface {
+	CreateHold(ctx context.Context, request payload.CreateHoldRequest) (payload.CreateHoldResponse, error)
+	GetHolds(ctx context.Context, request payload.GetHoldsRequest) (payload.GetHoldsResponse, error)
+	GetHoldByID(ctx context.Context, id string) (payload.GetHoldByIDResponse, error)
+	CancelHold(ctx context.Context, request payload.CancelHoldRequest) error
+	// ExpireHolds closes every ready hold whose pickup window has passed and passes its copy on
+	// to the next member in the queue, it returns the number of holds expired. A hold that fails
+	// does not stop the others, the failures are returned together.
+	ExpireHolds(ctx context.Context) (int, error)
+}
+
+type holdService struct {
+	cfg        *config.Config
+	transactor repository.Transactor
+	holdRepo   repository.HoldRepository
+	bookRepo   repository.BookRepository
+	copyRepo   repository.BookCopyRepository
+	memberRepo repository.MemberRepository
+}
+
+func NewHoldService(
+	cfg *config.Config,
+	transactor repository.Transactor,
+	holdRepo repository.HoldRepository,
+	bookRepo repository.BookRepository,
+	copyRepo repository.BookCopyRepository,
+	memberRepo repository.MemberRepository,
+) HoldService {
+	return &holdService{
+		cfg:        cfg,
+		transactor: transactor,
+		holdRepo:   holdRepo,
+		bookRepo:   bookRepo,
+		copyRepo:   copyRepo,
+		memberRepo: memberRepo,
+	}
+}
+
+func (s *holdService) CreateHold(ctx context.Context, request payload.CreateHoldRequest) (res payload.CreateHoldResponse, err error) {
+	book, err := s.bookRepo.GetBookByID(ctx, request.BookID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[HoldService][CreateHold] failed to get book", "error", err, "book_id", request.BookID)
+		return res, err
+	}
+
+	if book == nil {
+		return res, errorcustom.ErrBookNotFound
+	}
+
+	// holds are only for books that are all checked out
+	if book.AvailableCopies > 0 {
+		return res, errorcustom.ErrHoldNotNeeded
+	}
+
+	member, err := s.memberRepo.GetMemberByID(ctx, request.MemberID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[HoldService][CreateHold] failed to get member", "error", err, "member_id", request.MemberID)
+		return res, err
+	}
+
+	if member == nil {
+		return res, errorcustom.ErrMemberNotFound
+	}
+
+	if member.Status != model.MemberStatusActive {
+		return res, errorcustom.ErrMemberNotActive
+	}
+
+	hold := model.Hold{
+		ID:       uuid.New(),
+		BookID:   book.ID,
+		MemberID: member.ID,
+		Status:   model.HoldStatusWaiting,
+		PlacedAt: time.Now(),
+	}
+
+	err = s.holdRepo.CreateHold(ctx, hold)
+	if err != nil {
+		if strings.Contains(err.Error(), "ERROR: duplicate key value violates unique constraint \"holds_active_member_book_key\"") {
+			return res, errorcustom.ErrHoldAlreadyExists
+		}
+		slog.ErrorContext(ctx, "[HoldService][CreateHold] failed to create hold", "error", err)
+		return res, err
+	}
+
+	created, err := s.holdRepo.GetHoldByID(ctx, hold.ID.String())
+	if err != nil {
+		slog.ErrorContext(ctx, "[HoldService][CreateHold] failed to get queue position", "error", err, "id", hold.ID)
+		return res, err
+	}
+
+	res.ID = hold.ID
+	if created != nil {
+		res.Position = created.Position
+	}
+
+	return res, nil
+}
+
+func (s *holdService) GetHolds(ctx context.Context, request payload.GetHoldsRequest) (res payload.GetHoldsResponse, err error) {
+	request.Offset = (request.Page - 1) * request.Limit
+
+	// get holds with pagination
+	holds, err := s.holdRepo.GetHolds(ctx, request)
+	if err != nil {
+		slog.ErrorContext(ctx, "[HoldService][GetHolds] failed to get holds", "error", err)
+		return res, err
+	}
+
+	// get total count of holds matching the filters
+	totalCount, err := s.holdRepo.GetHoldsCount(ctx, request)
+	if err != nil {
+		slog.ErrorContext(ctx, "[HoldService][GetHolds] failed to get holds count", "error", err)
+		return res, err
+	}
+
+	totalPages := int(math.Ceil(float64(totalCount) / float64(request.Limit)))
+
+	holdResponses := make([]payload.HoldResponse, len(holds))
+	for i, hold := range holds {
+		holdResponses[i] = toHoldResponse(hold)
+	}
+
+	res.Holds = holdResponses
+	res.Pagination = payload.Pagination{
+		Page:      request.Page,
+		Limit:     request.Limit,
+		TotalPage: totalPages,
+		TotalItem: totalCount,
+	}
+
+	return res, nil
+}
+
+func (s *holdService) GetHoldByID(ctx context.Context, id string) (res payload.GetHoldByIDResponse, err error) {
+	hold, err := s.holdRepo.GetHoldByID(ctx, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "[HoldService][GetHoldByID] failed to get hold by ID", "error", err, "id", id)
+		return res, err
+	}
+
+	if hold == nil {
+		return res, errorcustom.ErrHoldNotFound
+	}
+
+	res.HoldResponse = toHoldResponse(*hold)
+
+	return res, nil
+}
+
+func (s *holdService) CancelHold(ctx context.Context, request payload.CancelHoldRequest) (err error) {
+	hold, err := s.holdRepo.GetHoldByID(ctx, request.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[HoldService][CancelHold] failed to check hold existence", "error", err, "id", request.ID)
+		return err
+	}
+
+	if hold == nil {
+		return errorcustom.ErrHoldNotFound
+	}
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		cancelled, err := s.holdRepo.CloseHold(ctx, request.ID, model.HoldStatusCancelled, time.Now())
+		if err != nil {
+			return err
+		}
+
+		if cancelled == nil {
+			return errorcustom.ErrHoldNotActive
+		}
+
+		// a ready hold was sitting on a copy, hand it to whoever is next
+		if cancelled.CopyID != nil {
+			_, err = releaseCopy(ctx, s.holdRepo, s.copyRepo, cancelled.BookID, *cancelled.CopyID, s.cfg.HoldPickupDays)
+		}
+
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrHoldNotActive) {
+			return err
+		}
+		slog.ErrorContext(ctx, "[HoldService][CancelHold] failed to cancel hold", "error", err, "id", request.ID)
+		return err
+	}
+
+	return nil
+}
+
+func (s *holdService) ExpireHolds(ctx context.Context) (int, error) {
+	now := time.Now()
+
+	holds, err := s.holdRepo.GetExpiredHolds(ctx, now)
+	if err != nil {
+		slog.ErrorContext(ctx, "[HoldService][ExpireHolds] failed to get expired holds", "error", err)
+		return 0, err
+	}
+
+	expiredCount := 0
+	var errs []error
+	for _, hold := range holds {
+		expired := false
+
+		// each hold is expired in its own transaction so one failure does not hold back the rest
+		err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			closed, err := s.holdRepo.ExpireHold(ctx, hold.ID.String(), now)
+			if err != nil || closed == nil || closed.CopyID == nil {
+				return err
+			}
+
+			expired = true
+			_, err = releaseCopy(ctx, s.holdRepo, s.copyRepo, closed.BookID, *closed.CopyID, s.cfg.HoldPickupDays)
+
+			return err
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "[HoldService][ExpireHolds] failed to expire hold", "error", err, "id", hold.ID)
+			errs = append(errs, fmt.Errorf("hold %s: %w", hold.ID, err))
+			continue
+		}
+
+		if expired {
+			expiredCount++
+		}
+	}
+
+	return expiredCount, errors.Join(errs...)
+}
+
+// releaseCopy sets a copy that came back to the library aside for the next waiting hold on its
+// book, or puts it back on the shelf when nobody is queueing. It returns the hold that was served.
+func releaseCopy(
+	ctx context.Context,
+	holdRepo repository.HoldRepository,
+	copyRepo repository.BookCopyRepository,
+	bookID uuid.UUID,
+	copyID uuid.UUID,
+	pickupDays int,
+) (*model.Hold, error) {
+	now := time.Now()
+
+	hold, err := holdRepo.AssignNextHold(ctx, bookID.String(), copyID.String(), now, now.AddDate(0, 0, pickupDays))
+	if err != nil {
+		return nil, err
+	}
+
+	status := model.BookCopyStatusAvailable
+	if hold != nil {
+		status = model.BookCopyStatusOnHold
+	}
+
+	err = copyRepo.UpdateBookCopyStatus(ctx, copyID.String(), status)
+	if err != nil {
+		return nil, err
+	}
+
+	return hold, nil
+}
+
+func toHoldResponse(hold model.Hold) payload.HoldResponse {
+	return payload.HoldResponse{
+		ID:         hold.ID,
+		BookID:     hold.BookID,
+		BookTitle:  hold.BookTitle,
+		MemberID:   hold.MemberID,
+		MemberName: hold.MemberName,
+		CopyID:     hold.CopyID,
+		Barcode:    hold.Barcode,
+		Status:     hold.Status,
+		Position:   hold.Position,
+		PlacedAt:   hold.PlacedAt,
+		ReadyAt:    hold.ReadyAt,
+		ExpiresAt:  hold.ExpiresAt,
+		ClosedAt:   hold.ClosedAt,
+	}
+}
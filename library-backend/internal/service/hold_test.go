@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"library-backend/errorcustom"
+	"library-backend/internal/config"
+	"library-backend/internal/model"
+	"library-backend/internal/payload"
+	"library-backend/internal/repository/mock"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+)
+
+func Test_holdService_CreateHold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHoldRepo := mock.NewMockHoldRepository(ctrl)
+	mockBookRepo := mock.NewMockBookRepository(ctrl)
+	mockMemberRepo := mock.NewMockMemberRepository(ctrl)
+	service := NewHoldService(&config.Config{HoldPickupDays: 3}, mock.NewMockTransactor(ctrl), mockHoldRepo, mockBookRepo, mock.NewMockBookCopyRepository(ctrl), mockMemberRepo)
+
+	ctx := context.Background()
+	bookID := uuid.New()
+	memberID := uuid.New()
+
+	checkedOutBook := &model.Book{ID: bookID, Title: "Dune", TotalCopies: 2, AvailableCopies: 0}
+	shelvedBook := &model.Book{ID: bookID, Title: "Dune", TotalCopies: 2, AvailableCopies: 1}
+	activeMember := &model.Member{ID: memberID, Name: "Jane Doe", Status: model.MemberStatusActive}
+
+	request := payload.CreateHoldRequest{
+		BookID:   bookID.String(),
+		MemberID: memberID.String(),
+	}
+
+	tests := []struct {
+		name         string
+		mockFunc     func()
+		wantErr      bool
+		errorMsg     string
+		wantPosition int
+	}{
+		{
+			name: "success",
+			mockFunc: func() {
+				mockBookRepo.EXPECT().GetBookByID(ctx, bookID.String()).Return(checkedOutBook, nil)
+				mockMemberRepo.EXPECT().GetMemberByID(ctx, memberID.String()).Return(activeMember, nil)
+				mockHoldRepo.EXPECT().CreateHold(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, hold model.Hold) error {
+					if hold.Status != model.HoldStatusWaiting {
+						t.Errorf("holdService.CreateHold() expected a waiting hold, got %q", hold.Status)
+					}
+					return nil
+				})
+				mockHoldRepo.EXPECT().GetHoldByID(ctx, gomock.Any()).Return(&model.Hold{Status: model.HoldStatusWaiting, Position: 3}, nil)
+			},
+			wantErr:      false,
+			wantPosition: 3,
+		},
+		{
+			name: "copy still on the shelf",
+			mockFunc: func() {
+				mockBookRepo.EXPECT().GetBookByID(ctx, bookID.String()).Return(shelvedBook, nil)
+			},
+			wantErr:  true,
+			errorMsg: errorcustom.ErrHoldNotNeeded.Error(),
+		},
+		{
+			name: "book not found",
+			mockFunc: func() {
+				mockBookRepo.EXPECT().GetBookByID(ctx, bookID.String()).Return(nil, nil)
+			},
+			wantErr:  true,
+			errorMsg: errorcustom.ErrBookNotFound.Error(),
+		},
+		{
+			name: "member already queueing",
+			mockFunc: func() {
+				mockBookRepo.EXPECT().GetBookByID(ctx, bookID.String()).Return(checkedOutBook, nil)
+				mockMemberRepo.EXPECT().GetMemberByID(ctx, memberID.String()).Return(activeMember, nil)
+				mockHoldRepo.EXPECT().CreateHold(ctx, gomock.Any()).Return(errors.New("ERROR: duplicate key value violates unique constraint \"holds_active_member_book_key\" (SQLSTATE 23505)"))
+			},
+			wantErr:  true,
+			errorMsg: errorcustom.ErrHoldAlreadyExists.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+			gotRes, err := service.CreateHold(ctx, request)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("holdService.CreateHold() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.errorMsg != "" && err != nil && err.Error() != tt.errorMsg {
+				t.Errorf("holdService.CreateHold() error = %v, want %v", err.Error(), tt.errorMsg)
+			}
+			if !tt.wantErr && gotRes.Position != tt.wantPosition {
+				t.Errorf("holdService.CreateHold() position = %v, want %v", gotRes.Position, tt.wantPosition)
+			}
+		})
+	}
+}
+
+func Test_holdService_CancelHold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactor := mock.NewMockTransactor(ctrl)
+	mockHoldRepo := mock.NewMockHoldRepository(ctrl)
+	mockCopyRepo := mock.NewMockBookCopyRepository(ctrl)
+	service := NewHoldService(&config.Config{HoldPickupDays: 3}, mockTransactor, mockHoldRepo, mock.NewMockBookRepository(ctrl), mockCopyRepo, mock.NewMockMemberRepository(ctrl))
+
+	ctx := context.Background()
+	holdID := uuid.New()
+	bookID := uuid.New()
+	copyID := uuid.New()
+
+	waitingHold := &model.Hold{ID: holdID, BookID: bookID, Status: model.HoldStatusWaiting}
+	readyHold := &model.Hold{ID: holdID, BookID: bookID, CopyID: &copyID, Status: model.HoldStatusReady}
+
+	tests := []struct {
+		name     string
+		mockFunc func()
+		wantErr  bool
+		errorMsg string
+	}{
+		{
+			name: "waiting hold leaves the queue",
+			mockFunc: func() {
+				mockHoldRepo.EXPECT().GetHoldByID(ctx, holdID.String()).Return(waitingHold, nil)
+				runInTransaction(mockTransactor)
+				mockHoldRepo.EXPECT().CloseHold(ctx, holdID.String(), model.HoldStatusCancelled, gomock.Any()).Return(waitingHold, nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "ready hold passes its copy on",
+			mockFunc: func() {
+				mockHoldRepo.EXPECT().GetHoldByID(ctx, holdID.String()).Return(readyHold, nil)
+				runInTransaction(mockTransactor)
+				mockHoldRepo.EXPECT().CloseHold(ctx, holdID.String(), model.HoldStatusCancelled, gomock.Any()).Return(readyHold, nil)
+				mockHoldRepo.EXPECT().AssignNextHold(ctx, bookID.String(), copyID.String(), gomock.Any(), gomock.Any()).Return(nil, nil)
+				mockCopyRepo.EXPECT().UpdateBookCopyStatus(ctx, copyID.String(), model.BookCopyStatusAvailable).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "hold already closed",
+			mockFunc: func() {
+				mockHoldRepo.EXPECT().GetHoldByID(ctx, holdID.String()).Return(&model.Hold{ID: holdID, Status: model.HoldStatusFulfilled}, nil)
+				runInTransaction(mockTransactor)
+				mockHoldRepo.EXPECT().CloseHold(ctx, holdID.String(), model.HoldStatusCancelled, gomock.Any()).Return(nil, nil)
+			},
+			wantErr:  true,
+			errorMsg: errorcustom.ErrHoldNotActive.Error(),
+		},
+		{
+			name: "hold not found",
+			mockFunc: func() {
+				mockHoldRepo.EXPECT().GetHoldByID(ctx, holdID.String()).Return(nil, nil)
+			},
+			wantErr:  true,
+			errorMsg: errorcustom.ErrHoldNotFound.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+			err := service.CancelHold(ctx, payload.CancelHoldRequest{ID: holdID.String()})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("holdService.CancelHold() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.errorMsg != "" && err != nil && err.Error() != tt.errorMsg {
+				t.Errorf("holdService.CancelHold() error = %v, want %v", err.Error(), tt.errorMsg)
+			}
+		})
+	}
+}
+
+func Test_holdService_ExpireHolds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactor := mock.NewMockTransactor(ctrl)
+	mockHoldRepo := mock.NewMockHoldRepository(ctrl)
+	mockCopyRepo := mock.NewMockBookCopyRepository(ctrl)
+	service := NewHoldService(&config.Config{HoldPickupDays: 3}, mockTransactor, mockHoldRepo, mock.NewMockBookRepository(ctrl), mockCopyRepo, mock.NewMockMemberRepository(ctrl))
+
+	ctx := context.Background()
+	bookID := uuid.New()
+	copyID := uuid.New()
+
+	lapsedHold := model.Hold{ID: uuid.New(), BookID: bookID, CopyID: &copyID, Status: model.HoldStatusReady}
+	pickedUpHold := model.Hold{ID: uuid.New(), BookID: bookID, CopyID: &copyID, Status: model.HoldStatusReady}
+	nextHold := &model.Hold{ID: uuid.New(), BookID: bookID, CopyID: &copyID, Status: model.HoldStatusReady}
+
+	mockHoldRepo.EXPECT().GetExpiredHolds(ctx, gomock.Any()).Return([]model.Hold{lapsedHold, pickedUpHold}, nil)
+	runInTransaction(mockTransactor).Times(2)
+	mockHoldRepo.EXPECT().ExpireHold(ctx, lapsedHold.ID.String(), gomock.Any()).Return(&lapsedHold, nil)
+	mockHoldRepo.EXPECT().AssignNextHold(ctx, bookID.String(), copyID.String(), gomock.Any(), gomock.Any()).Return(nextHold, nil)
+	mockCopyRepo.EXPECT().UpdateBookCopyStatus(ctx, copyID.String(), model.BookCopyStatusOnHold).Return(nil)
+	// checked out between the listing and the update
+	mockHoldRepo.EXPECT().ExpireHold(ctx, pickedUpHold.ID.String(), gomock.Any()).Return(nil, nil)
+
+	expired, err := service.ExpireHolds(ctx)
+	if err != nil {
+		t.Fatalf("holdService.ExpireHolds() unexpected error = %v", err)
+	}
+	if expired != 1 {
+		t.Errorf("holdService.ExpireHolds() expired = %v, want 1", expired)
+	}
+}
+
+func Test_holdService_ExpireHolds_failure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactor := mock.NewMockTransactor(ctrl)
+	mockHoldRepo := mock.NewMockHoldRepository(ctrl)
+	mockCopyRepo := mock.NewMockBookCopyRepository(ctrl)
+	service := NewHoldService(&config.Config{HoldPickupDays: 3}, mockTransactor, mockHoldRepo, mock.NewMockBookRepository(ctrl), mockCopyRepo, mock.NewMockMemberRepository(ctrl))
+
+	ctx := context.Background()
+	bookID := uuid.New()
+	failingCopyID := uuid.New()
+	copyID := uuid.New()
+
+	failingHold := model.Hold{ID: uuid.New(), BookID: bookID, CopyID: &failingCopyID, Status: model.HoldStatusReady}
+	lapsedHold := model.Hold{ID: uuid.New(), BookID: bookID, CopyID: &copyID, Status: model.HoldStatusReady}
+	dbErr := errors.New("connection reset")
+
+	mockHoldRepo.EXPECT().GetExpiredHolds(ctx, gomock.Any()).Return([]model.Hold{failingHold, lapsedHold}, nil)
+	runInTransaction(mockTransactor).Times(2)
+	mockHoldRepo.EXPECT().ExpireHold(ctx, failingHold.ID.String(), gomock.Any()).Return(&failingHold, nil)
+	mockHoldRepo.EXPECT().AssignNextHold(ctx, bookID.String(), failingCopyID.String(), gomock.Any(), gomock.Any()).Return(nil, dbErr)
+	// the failure above must not keep the next hold from expiring
+	mockHoldRepo.EXPECT().ExpireHold(ctx, lapsedHold.ID.String(), gomock.Any()).Return(&lapsedHold, nil)
+	mockHoldRepo.EXPECT().AssignNextHold(ctx, bookID.String(), copyID.String(), gomock.Any(), gomock.Any()).Return(nil, nil)
+	mockCopyRepo.EXPECT().UpdateBookCopyStatus(ctx, copyID.String(), model.BookCopyStatusAvailable).Return(nil)
+
+	expired, err := service.ExpireHolds(ctx)
+	if !errors.Is(err, dbErr) {
+		t.Errorf("holdService.ExpireHolds() error = %v, want %v", err, dbErr)
+	}
+	if expired != 1 {
+		t.Errorf("holdService.ExpireHolds() expired = %v, want 1", expired)
+	}
+}
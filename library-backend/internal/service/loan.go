@@ -0,0 +1,395 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"library-backend/errorcustom"
+	"library-backend/internal/config"
+	"library-backend/internal/model"
+	"library-backend/internal/payload"
+	"library-backend/internal/repository"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	mostBorrowedBooksLimit = 10
+	borrowingReportMonths  = 12
+)
+
+type LoanService interface {
+	CreateLoan(ctx context.Context, request payload.CreateLoanRequest) (payload.CreateLoanResponse, error)
+	GetLoans(ctx context.Context, request payload.GetLoansRequest) (payload.GetLoansResponse, error)
+	GetLoanByID(ctx context.Context, id string) (payload.GetLoanByIDResponse, error)
+	ReturnLoan(ctx context.Context, request payload.ReturnLoanRequest) error
+	GetBorrowingReport(ctx context.Context) (payload.BorrowingReportResponse, error)
+}
+
+type loanService struct {
+	cfg        *config.Config
+	transactor repository.Transactor
+	loanRepo   repository.LoanRepository
+	bookRepo   repository.BookRepository
+	copyRepo   repository.BookCopyRepository
+	memberRepo repository.MemberRepository
+	holdRepo   repository.HoldRepository
+	fineRepo   repository.FineRepository
+}
+
+func NewLoanService(
+	cfg *config.Config,
+	transactor repository.Transactor,
+	loanRepo repository.LoanRepository,
+	bookRepo repository.BookRepository,
+	copyRepo repository.BookCopyRepository,
+	memberRepo repository.MemberRepository,
+	holdRepo repository.HoldRepository,
+	fineRepo repository.FineRepository,
+) LoanService {
+	return &loanService{
+		cfg:        cfg,
+		transactor: transactor,
+		loanRepo:   loanRepo,
+		bookRepo:   bookRepo,
+		copyRepo:   copyRepo,
+		memberRepo: memberRepo,
+		holdRepo:   holdRepo,
+		fineRepo:   fineRepo,
+	}
+}
+
+func (s *loanService) CreateLoan(ctx context.Context, request payload.CreateLoanRequest) (res payload.CreateLoanResponse, err error) {
+	book, err := s.bookRepo.GetBookByID(ctx, request.BookID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[LoanService][CreateLoan] failed to get book", "error", err, "book_id", request.BookID)
+		return res, err
+	}
+
+	if book == nil {
+		return res, errorcustom.ErrBookNotFound
+	}
+
+	member, err := s.memberRepo.GetMemberByID(ctx, request.MemberID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[LoanService][CreateLoan] failed to get member", "error", err, "member_id", request.MemberID)
+		return res, err
+	}
+
+	if member == nil {
+		return res, errorcustom.ErrMemberNotFound
+	}
+
+	if member.Status != model.MemberStatusActive {
+		return res, errorcustom.ErrMemberNotActive
+	}
+
+	if request.CopyID != "" {
+		bookCopy, err := s.copyRepo.GetBookCopyByID(ctx, request.CopyID)
+		if err != nil {
+			slog.ErrorContext(ctx, "[LoanService][CreateLoan] failed to get book copy", "error", err, "copy_id", request.CopyID)
+			return res, err
+		}
+
+		if bookCopy == nil || bookCopy.BookID != book.ID {
+			return res, errorcustom.ErrBookCopyNotFound
+		}
+	}
+
+	hold, err := s.holdRepo.GetActiveHold(ctx, request.BookID, request.MemberID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[LoanService][CreateLoan] failed to get active hold", "error", err, "book_id", request.BookID, "member_id", request.MemberID)
+		return res, err
+	}
+
+	now := time.Now()
+	dueAt := now.AddDate(0, 0, s.cfg.LoanPeriodDays)
+	if request.DueAt != nil {
+		if !request.DueAt.After(now) {
+			return res, errorcustom.ErrInvalidDueDate
+		}
+		dueAt = *request.DueAt
+	}
+
+	loan := model.Loan{
+		ID:         uuid.New(),
+		BookID:     book.ID,
+		MemberID:   member.ID,
+		BorrowedAt: now,
+		DueAt:      dueAt,
+	}
+
+	// flagging the copy and recording the loan must succeed or fail together, otherwise a copy
+	// could be stuck on loan without a loan pointing at it
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		bookCopy, err := s.claimCopy(ctx, request, hold, now)
+		if err != nil {
+			return err
+		}
+
+		loan.CopyID = bookCopy.ID
+		loan.Barcode = bookCopy.Barcode
+
+		err = s.loanRepo.CreateLoan(ctx, loan)
+		if err != nil {
+			return err
+		}
+
+		// the member got the book, a hold still waiting in the queue has served its purpose
+		if hold != nil && hold.Status == model.HoldStatusWaiting {
+			_, err = s.holdRepo.CloseHold(ctx, hold.ID.String(), model.HoldStatusFulfilled, now)
+		}
+
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, errorcustom.ErrBookNotAvailable) || errors.Is(err, errorcustom.ErrBookCopyNotAvailable) {
+			return res, err
+		}
+		if strings.Contains(err.Error(), "ERROR: duplicate key value violates unique constraint \"loans_active_copy_key\"") {
+			return res, errorcustom.ErrBookCopyNotAvailable
+		}
+		slog.ErrorContext(ctx, "[LoanService][CreateLoan] failed to create loan", "error", err)
+		return res, err
+	}
+
+	res.ID = loan.ID
+	res.CopyID = loan.CopyID
+	res.Barcode = loan.Barcode
+	res.DueAt = loan.DueAt
+
+	return res, nil
+}
+
+func (s *loanService) GetLoans(ctx context.Context, request payload.GetLoansRequest) (res payload.GetLoansResponse, err error) {
+	request.Offset = (request.Page - 1) * request.Limit
+
+	// get loans with pagination
+	loans, err := s.loanRepo.GetLoans(ctx, request)
+	if err != nil {
+		slog.ErrorContext(ctx, "[LoanService][GetLoans] failed to get loans", "error", err)
+		return res, err
+	}
+
+	// get total count of loans matching the filters
+	totalCount, err := s.loanRepo.GetLoansCount(ctx, request)
+	if err != nil {
+		slog.ErrorContext(ctx, "[LoanService][GetLoans] failed to get loans count", "error", err)
+		return res, err
+	}
+
+	totalPages := int(math.Ceil(float64(totalCount) / float64(request.Limit)))
+
+	loanResponses := make([]payload.LoanResponse, len(loans))
+	for i, loan := range loans {
+		loanResponses[i] = toLoanResponse(loan)
+	}
+
+	res.Loans = loanResponses
+	res.Pagination = payload.Pagination{
+		Page:      request.Page,
+		Limit:     request.Limit,
+		TotalPage: totalPages,
+		TotalItem: totalCount,
+	}
+
+	return res, nil
+}
+
+func (s *loanService) GetLoanByID(ctx context.Context, id string) (res payload.GetLoanByIDResponse, err error) {
+	loan, err := s.loanRepo.GetLoanByID(ctx, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "[LoanService][GetLoanByID] failed to get loan by ID", "error", err, "id", id)
+		return res, err
+	}
+
+	if loan == nil {
+		return res, errorcustom.ErrLoanNotFound
+	}
+
+	res.LoanResponse = toLoanResponse(*loan)
+
+	return res, nil
+}
+
+func (s *loanService) ReturnLoan(ctx context.Context, request payload.ReturnLoanRequest) (err error) {
+	loan, err := s.loanRepo.GetLoanByID(ctx, request.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[LoanService][ReturnLoan] failed to check loan existence", "error", err, "id", request.ID)
+		return err
+	}
+
+	if loan == nil {
+		return errorcustom.ErrLoanNotFound
+	}
+
+	if loan.ReturnedAt != nil {
+		return errorcustom.ErrLoanAlreadyReturned
+	}
+
+	returnedAt := time.Now()
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := s.loanRepo.ReturnLoan(ctx, request.ID, returnedAt)
+		if err != nil {
+			return err
+		}
+
+		// late returns are charged in the same transaction so a fine is never lost or doubled
+		if charge := overdueCharge(s.cfg, *loan, returnedAt); charge != nil {
+			if err := s.fineRepo.CreateFineEntry(ctx, *charge); err != nil {
+				return err
+			}
+		}
+
+		// the first member queueing for the book gets the copy before it goes back on the shelf
+		_, err = releaseCopy(ctx, s.holdRepo, s.copyRepo, loan.BookID, loan.CopyID, s.cfg.HoldPickupDays)
+
+		return err
+	})
+	if err != nil {
+		// someone else returned it between the check and the update
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorcustom.ErrLoanAlreadyReturned
+		}
+		slog.ErrorContext(ctx, "[LoanService][ReturnLoan] failed to return loan", "error", err, "id", request.ID)
+		return err
+	}
+
+	return nil
+}
+
+func (s *loanService) GetBorrowingReport(ctx context.Context) (res payload.BorrowingReportResponse, err error) {
+	summary, err := s.loanRepo.GetLoanSummary(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "[LoanService][GetBorrowingReport] failed to get loan summary", "error", err)
+		return res, err
+	}
+
+	mostBorrowed, err := s.loanRepo.GetMostBorrowedBooks(ctx, mostBorrowedBooksLimit)
+	if err != nil {
+		slog.ErrorContext(ctx, "[LoanService][GetBorrowingReport] failed to get most borrowed books", "error", err)
+		return res, err
+	}
+
+	monthly, err := s.loanRepo.GetMonthlyLoanCounts(ctx, borrowingReportMonths)
+	if err != nil {
+		slog.ErrorContext(ctx, "[LoanService][GetBorrowingReport] failed to get monthly loan counts", "error", err)
+		return res, err
+	}
+
+	res.TotalLoans = summary.TotalLoans
+	res.ActiveLoans = summary.ActiveLoans
+	res.OverdueLoans = summary.OverdueLoans
+	res.ReturnedLoans = summary.ReturnedLoans
+
+	res.MostBorrowed = make([]payload.BookLoanStat, len(mostBorrowed))
+	for i, count := range mostBorrowed {
+		res.MostBorrowed[i] = payload.BookLoanStat{
+			BookID:    count.BookID,
+			Title:     count.Title,
+			Author:    count.Author,
+			LoanCount: count.LoanCount,
+		}
+	}
+
+	res.Monthly = make([]payload.MonthlyLoanStat, len(monthly))
+	for i, count := range monthly {
+		res.Monthly[i] = payload.MonthlyLoanStat{
+			Month:    count.Month,
+			Borrowed: count.Borrowed,
+			Returned: count.Returned,
+		}
+	}
+
+	return res, nil
+}
+
+// claimCopy flags the requested copy, or any available copy of the requested book, as on loan.
+// A member picking up a ready hold gets the copy that was set aside for them, a member asking for
+// another copy gives the held one up.
+func (s *loanService) claimCopy(ctx context.Context, request payload.CreateLoanRequest, hold *model.Hold, now time.Time) (*model.BookCopy, error) {
+	if hold != nil && hold.Status == model.HoldStatusReady && hold.CopyID != nil && hold.ExpiresAt.After(now) &&
+		(request.CopyID == "" || strings.EqualFold(request.CopyID, hold.CopyID.String())) {
+		fulfilled, err := s.holdRepo.CloseHold(ctx, hold.ID.String(), model.HoldStatusFulfilled, now)
+		if err != nil {
+			return nil, err
+		}
+
+		// the hold was cancelled or expired in the meantime, the copy is no longer ours
+		if fulfilled == nil {
+			return nil, errorcustom.ErrBookNotAvailable
+		}
+
+		bookCopy, err := s.copyRepo.ClaimHeldCopy(ctx, hold.CopyID.String())
+		if err != nil {
+			return nil, err
+		}
+
+		if bookCopy == nil {
+			return nil, errorcustom.ErrBookCopyNotAvailable
+		}
+
+		return bookCopy, nil
+	}
+
+	if request.CopyID != "" {
+		bookCopy, err := s.copyRepo.ClaimCopy(ctx, request.CopyID)
+		if err != nil {
+			return nil, err
+		}
+
+		if bookCopy == nil {
+			return nil, errorcustom.ErrBookCopyNotAvailable
+		}
+
+		// a member taking another copy than the one set aside for them no longer needs it, the
+		// held copy goes to the next member in the queue instead of waiting for the hold to expire
+		if hold != nil && hold.Status == model.HoldStatusReady && hold.CopyID != nil {
+			fulfilled, err := s.holdRepo.CloseHold(ctx, hold.ID.String(), model.HoldStatusFulfilled, now)
+			if err != nil {
+				return nil, err
+			}
+
+			if fulfilled != nil && fulfilled.CopyID != nil {
+				_, err = releaseCopy(ctx, s.holdRepo, s.copyRepo, fulfilled.BookID, *fulfilled.CopyID, s.cfg.HoldPickupDays)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		return bookCopy, nil
+	}
+
+	bookCopy, err := s.copyRepo.ClaimAvailableCopy(ctx, request.BookID)
+	if err != nil {
+		return nil, err
+	}
+
+	if bookCopy == nil {
+		return nil, errorcustom.ErrBookNotAvailable
+	}
+
+	return bookCopy, nil
+}
+
+func toLoanResponse(loan model.Loan) payload.LoanResponse {
+	return payload.LoanResponse{
+		ID:         loan.ID,
+		BookID:     loan.BookID,
+		BookTitle:  loan.BookTitle,
+		CopyID:     loan.CopyID,
+		Barcode:    loan.Barcode,
+		MemberID:   loan.MemberID,
+		MemberName: loan.MemberName,
+		BorrowedAt: loan.BorrowedAt,
+		DueAt:      loan.DueAt,
+		ReturnedAt: loan.ReturnedAt,
+		Status:     loan.Status,
+	}
+}
@@ -0,0 +1,420 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"library-backend/errorcustom"
+	"library-backend/internal/config"
+	"library-backend/internal/model"
+	"library-backend/internal/payload"
+	"library-backend/internal/repository/mock"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+)
+
+// runInTransaction makes a mocked Transactor simply run the transactional function.
+func runInTransaction(mockTransactor *mock.MockTransactor) *gomock.Call {
+	return mockTransactor.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+}
+
+func Test_loanService_CreateLoan(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactor := mock.NewMockTransactor(ctrl)
+	mockLoanRepo := mock.NewMockLoanRepository(ctrl)
+	mockBookRepo := mock.NewMockBookRepository(ctrl)
+	mockCopyRepo := mock.NewMockBookCopyRepository(ctrl)
+	mockMemberRepo := mock.NewMockMemberRepository(ctrl)
+	mockHoldRepo := mock.NewMockHoldRepository(ctrl)
+	service := NewLoanService(&config.Config{LoanPeriodDays: 14, HoldPickupDays: 3}, mockTransactor, mockLoanRepo, mockBookRepo, mockCopyRepo, mockMemberRepo, mockHoldRepo, mock.NewMockFineRepository(ctrl))
+
+	ctx := context.Background()
+	bookID := uuid.New()
+	memberID := uuid.New()
+	copyID := uuid.New()
+	otherCopyID := uuid.New()
+
+	availableCopy := &model.BookCopy{ID: copyID, BookID: bookID, Barcode: "LIB-0001", Status: model.BookCopyStatusOnLoan}
+
+	heldCopy := &model.BookCopy{ID: uuid.New(), BookID: bookID, Barcode: "LIB-0002", Status: model.BookCopyStatusOnLoan}
+	pickupBy := time.Now().Add(48 * time.Hour)
+	lapsedAt := time.Now().Add(-time.Hour)
+	readyHold := &model.Hold{ID: uuid.New(), BookID: bookID, MemberID: memberID, CopyID: &heldCopy.ID, Status: model.HoldStatusReady, ExpiresAt: &pickupBy}
+	lapsedHold := &model.Hold{ID: uuid.New(), BookID: bookID, MemberID: memberID, CopyID: &heldCopy.ID, Status: model.HoldStatusReady, ExpiresAt: &lapsedAt}
+	waitingHold := &model.Hold{ID: uuid.New(), BookID: bookID, MemberID: memberID, Status: model.HoldStatusWaiting}
+
+	sampleBook := &model.Book{ID: bookID, Title: "Effective Java"}
+	activeMember := &model.Member{ID: memberID, Name: "Jane Doe", Status: model.MemberStatusActive}
+	suspendedMember := &model.Member{ID: memberID, Name: "Jane Doe", Status: model.MemberStatusSuspended}
+
+	request := payload.CreateLoanRequest{
+		BookID:   bookID.String(),
+		MemberID: memberID.String(),
+	}
+	pastDue := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name     string
+		mockFunc func()
+		request  payload.CreateLoanRequest
+		wantErr  bool
+		errorMsg string
+	}{
+		{
+			name: "success with default loan period",
+			mockFunc: func() {
+				mockBookRepo.EXPECT().GetBookByID(ctx, bookID.String()).Return(sampleBook, nil)
+				mockMemberRepo.EXPECT().GetMemberByID(ctx, memberID.String()).Return(activeMember, nil)
+				mockHoldRepo.EXPECT().GetActiveHold(ctx, bookID.String(), memberID.String()).Return(nil, nil)
+				runInTransaction(mockTransactor)
+				mockCopyRepo.EXPECT().ClaimAvailableCopy(ctx, bookID.String()).Return(availableCopy, nil)
+				mockLoanRepo.EXPECT().CreateLoan(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, loan model.Loan) error {
+					if days := loan.DueAt.Sub(loan.BorrowedAt).Hours() / 24; days < 13.9 || days > 14.1 {
+						t.Errorf("loanService.CreateLoan() expected a 14 day loan, got %.1f days", days)
+					}
+					if loan.CopyID != copyID {
+						t.Errorf("loanService.CreateLoan() expected copy %v, got %v", copyID, loan.CopyID)
+					}
+					return nil
+				})
+			},
+			request: request,
+			wantErr: false,
+		},
+		{
+			name: "success with a specific copy",
+			mockFunc: func() {
+				mockBookRepo.EXPECT().GetBookByID(ctx, bookID.String()).Return(sampleBook, nil)
+				mockMemberRepo.EXPECT().GetMemberByID(ctx, memberID.String()).Return(activeMember, nil)
+				mockCopyRepo.EXPECT().GetBookCopyByID(ctx, copyID.String()).Return(availableCopy, nil)
+				mockHoldRepo.EXPECT().GetActiveHold(ctx, bookID.String(), memberID.String()).Return(nil, nil)
+				runInTransaction(mockTransactor)
+				mockCopyRepo.EXPECT().ClaimCopy(ctx, copyID.String()).Return(availableCopy, nil)
+				mockLoanRepo.EXPECT().CreateLoan(ctx, gomock.Any()).Return(nil)
+			},
+			request: payload.CreateLoanRequest{
+				BookID:   bookID.String(),
+				CopyID:   copyID.String(),
+				MemberID: memberID.String(),
+			},
+			wantErr: false,
+		},
+		{
+			name: "copy belongs to another book",
+			mockFunc: func() {
+				mockBookRepo.EXPECT().GetBookByID(ctx, bookID.String()).Return(sampleBook, nil)
+				mockMemberRepo.EXPECT().GetMemberByID(ctx, memberID.String()).Return(activeMember, nil)
+				mockCopyRepo.EXPECT().GetBookCopyByID(ctx, otherCopyID.String()).Return(&model.BookCopy{ID: otherCopyID, BookID: uuid.New()}, nil)
+			},
+			request: payload.CreateLoanRequest{
+				BookID:   bookID.String(),
+				CopyID:   otherCopyID.String(),
+				MemberID: memberID.String(),
+			},
+			wantErr:  true,
+			errorMsg: errorcustom.ErrBookCopyNotFound.Error(),
+		},
+		{
+			name: "book not found",
+			mockFunc: func() {
+				mockBookRepo.EXPECT().GetBookByID(ctx, bookID.String()).Return(nil, nil)
+			},
+			request:  request,
+			wantErr:  true,
+			errorMsg: errorcustom.ErrBookNotFound.Error(),
+		},
+		{
+			name: "member not active",
+			mockFunc: func() {
+				mockBookRepo.EXPECT().GetBookByID(ctx, bookID.String()).Return(sampleBook, nil)
+				mockMemberRepo.EXPECT().GetMemberByID(ctx, memberID.String()).Return(suspendedMember, nil)
+			},
+			request:  request,
+			wantErr:  true,
+			errorMsg: errorcustom.ErrMemberNotActive.Error(),
+		},
+		{
+			name: "every copy already on loan",
+			mockFunc: func() {
+				mockBookRepo.EXPECT().GetBookByID(ctx, bookID.String()).Return(sampleBook, nil)
+				mockMemberRepo.EXPECT().GetMemberByID(ctx, memberID.String()).Return(activeMember, nil)
+				mockHoldRepo.EXPECT().GetActiveHold(ctx, bookID.String(), memberID.String()).Return(nil, nil)
+				runInTransaction(mockTransactor)
+				mockCopyRepo.EXPECT().ClaimAvailableCopy(ctx, bookID.String()).Return(nil, nil)
+			},
+			request:  request,
+			wantErr:  true,
+			errorMsg: errorcustom.ErrBookNotAvailable.Error(),
+		},
+		{
+			name: "concurrent checkout hits unique index",
+			mockFunc: func() {
+				mockBookRepo.EXPECT().GetBookByID(ctx, bookID.String()).Return(sampleBook, nil)
+				mockMemberRepo.EXPECT().GetMemberByID(ctx, memberID.String()).Return(activeMember, nil)
+				mockHoldRepo.EXPECT().GetActiveHold(ctx, bookID.String(), memberID.String()).Return(nil, nil)
+				runInTransaction(mockTransactor)
+				mockCopyRepo.EXPECT().ClaimAvailableCopy(ctx, bookID.String()).Return(availableCopy, nil)
+				mockLoanRepo.EXPECT().CreateLoan(ctx, gomock.Any()).Return(errors.New("ERROR: duplicate key value violates unique constraint \"loans_active_copy_key\" (SQLSTATE 23505)"))
+			},
+			request:  request,
+			wantErr:  true,
+			errorMsg: errorcustom.ErrBookCopyNotAvailable.Error(),
+		},
+		{
+			name: "member picks up a ready hold",
+			mockFunc: func() {
+				mockBookRepo.EXPECT().GetBookByID(ctx, bookID.String()).Return(sampleBook, nil)
+				mockMemberRepo.EXPECT().GetMemberByID(ctx, memberID.String()).Return(activeMember, nil)
+				mockHoldRepo.EXPECT().GetActiveHold(ctx, bookID.String(), memberID.String()).Return(readyHold, nil)
+				runInTransaction(mockTransactor)
+				mockHoldRepo.EXPECT().CloseHold(ctx, readyHold.ID.String(), model.HoldStatusFulfilled, gomock.Any()).Return(readyHold, nil)
+				mockCopyRepo.EXPECT().ClaimHeldCopy(ctx, heldCopy.ID.String()).Return(heldCopy, nil)
+				mockLoanRepo.EXPECT().CreateLoan(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, loan model.Loan) error {
+					if loan.CopyID != heldCopy.ID {
+						t.Errorf("loanService.CreateLoan() expected held copy %v, got %v", heldCopy.ID, loan.CopyID)
+					}
+					return nil
+				})
+			},
+			request: request,
+			wantErr: false,
+		},
+		{
+			name: "member takes another copy than the one on hold",
+			mockFunc: func() {
+				mockBookRepo.EXPECT().GetBookByID(ctx, bookID.String()).Return(sampleBook, nil)
+				mockMemberRepo.EXPECT().GetMemberByID(ctx, memberID.String()).Return(activeMember, nil)
+				mockCopyRepo.EXPECT().GetBookCopyByID(ctx, copyID.String()).Return(availableCopy, nil)
+				mockHoldRepo.EXPECT().GetActiveHold(ctx, bookID.String(), memberID.String()).Return(readyHold, nil)
+				runInTransaction(mockTransactor)
+				mockCopyRepo.EXPECT().ClaimCopy(ctx, copyID.String()).Return(availableCopy, nil)
+				mockHoldRepo.EXPECT().CloseHold(ctx, readyHold.ID.String(), model.HoldStatusFulfilled, gomock.Any()).Return(readyHold, nil)
+				mockHoldRepo.EXPECT().AssignNextHold(ctx, bookID.String(), heldCopy.ID.String(), gomock.Any(), gomock.Any()).Return(nil, nil)
+				mockCopyRepo.EXPECT().UpdateBookCopyStatus(ctx, heldCopy.ID.String(), model.BookCopyStatusAvailable).Return(nil)
+				mockLoanRepo.EXPECT().CreateLoan(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, loan model.Loan) error {
+					if loan.CopyID != copyID {
+						t.Errorf("loanService.CreateLoan() expected copy %v, got %v", copyID, loan.CopyID)
+					}
+					return nil
+				})
+			},
+			request: payload.CreateLoanRequest{
+				BookID:   bookID.String(),
+				CopyID:   copyID.String(),
+				MemberID: memberID.String(),
+			},
+			wantErr: false,
+		},
+		{
+			name: "hold expired before pickup",
+			mockFunc: func() {
+				mockBookRepo.EXPECT().GetBookByID(ctx, bookID.String()).Return(sampleBook, nil)
+				mockMemberRepo.EXPECT().GetMemberByID(ctx, memberID.String()).Return(activeMember, nil)
+				mockHoldRepo.EXPECT().GetActiveHold(ctx, bookID.String(), memberID.String()).Return(lapsedHold, nil)
+				runInTransaction(mockTransactor)
+				mockCopyRepo.EXPECT().ClaimAvailableCopy(ctx, bookID.String()).Return(nil, nil)
+			},
+			request:  request,
+			wantErr:  true,
+			errorMsg: errorcustom.ErrBookNotAvailable.Error(),
+		},
+		{
+			name: "waiting hold is fulfilled by a regular checkout",
+			mockFunc: func() {
+				mockBookRepo.EXPECT().GetBookByID(ctx, bookID.String()).Return(sampleBook, nil)
+				mockMemberRepo.EXPECT().GetMemberByID(ctx, memberID.String()).Return(activeMember, nil)
+				mockHoldRepo.EXPECT().GetActiveHold(ctx, bookID.String(), memberID.String()).Return(waitingHold, nil)
+				runInTransaction(mockTransactor)
+				mockCopyRepo.EXPECT().ClaimAvailableCopy(ctx, bookID.String()).Return(availableCopy, nil)
+				mockLoanRepo.EXPECT().CreateLoan(ctx, gomock.Any()).Return(nil)
+				mockHoldRepo.EXPECT().CloseHold(ctx, waitingHold.ID.String(), model.HoldStatusFulfilled, gomock.Any()).Return(waitingHold, nil)
+			},
+			request: request,
+			wantErr: false,
+		},
+		{
+			name: "due date in the past",
+			mockFunc: func() {
+				mockBookRepo.EXPECT().GetBookByID(ctx, bookID.String()).Return(sampleBook, nil)
+				mockMemberRepo.EXPECT().GetMemberByID(ctx, memberID.String()).Return(activeMember, nil)
+				mockHoldRepo.EXPECT().GetActiveHold(ctx, bookID.String(), memberID.String()).Return(nil, nil)
+			},
+			request: payload.CreateLoanRequest{
+				BookID:   bookID.String(),
+				MemberID: memberID.String(),
+				DueAt:    &pastDue,
+			},
+			wantErr:  true,
+			errorMsg: errorcustom.ErrInvalidDueDate.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+			gotRes, err := service.CreateLoan(ctx, tt.request)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("loanService.CreateLoan() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.errorMsg != "" && err != nil && err.Error() != tt.errorMsg {
+				t.Errorf("loanService.CreateLoan() error = %v, want %v", err.Error(), tt.errorMsg)
+			}
+			if !tt.wantErr && gotRes.ID == uuid.Nil {
+				t.Errorf("loanService.CreateLoan() expected valid ID, got nil")
+			}
+		})
+	}
+}
+
+func Test_loanService_ReturnLoan(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactor := mock.NewMockTransactor(ctrl)
+	mockLoanRepo := mock.NewMockLoanRepository(ctrl)
+	mockCopyRepo := mock.NewMockBookCopyRepository(ctrl)
+	mockHoldRepo := mock.NewMockHoldRepository(ctrl)
+	mockFineRepo := mock.NewMockFineRepository(ctrl)
+	cfg := &config.Config{LoanPeriodDays: 14, HoldPickupDays: 3, FinePerDay: 25, FineMaxPerLoan: 1000}
+	service := NewLoanService(cfg, mockTransactor, mockLoanRepo, mock.NewMockBookRepository(ctrl), mockCopyRepo, mock.NewMockMemberRepository(ctrl), mockHoldRepo, mockFineRepo)
+
+	ctx := context.Background()
+	loanID := uuid.New().String()
+	bookID := uuid.New()
+	copyID := uuid.New()
+	returnedAt := time.Now()
+
+	memberID := uuid.New()
+	activeLoan := &model.Loan{ID: uuid.MustParse(loanID), BookID: bookID, CopyID: copyID, MemberID: memberID, DueAt: time.Now().Add(72 * time.Hour), Status: model.LoanStatusActive}
+	lateLoan := &model.Loan{ID: uuid.MustParse(loanID), BookID: bookID, CopyID: copyID, MemberID: memberID, DueAt: time.Now().Add(-50 * time.Hour), Status: model.LoanStatusOverdue}
+	returnedLoan := &model.Loan{ID: uuid.MustParse(loanID), Status: model.LoanStatusReturned, ReturnedAt: &returnedAt}
+
+	tests := []struct {
+		name     string
+		mockFunc func()
+		wantErr  bool
+		errorMsg string
+	}{
+		{
+			name: "success",
+			mockFunc: func() {
+				mockLoanRepo.EXPECT().GetLoanByID(ctx, loanID).Return(activeLoan, nil)
+				runInTransaction(mockTransactor)
+				mockLoanRepo.EXPECT().ReturnLoan(ctx, loanID, gomock.Any()).Return(nil)
+				mockHoldRepo.EXPECT().AssignNextHold(ctx, bookID.String(), copyID.String(), gomock.Any(), gomock.Any()).Return(nil, nil)
+				mockCopyRepo.EXPECT().UpdateBookCopyStatus(ctx, copyID.String(), model.BookCopyStatusAvailable).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "copy set aside for the next hold",
+			mockFunc: func() {
+				mockLoanRepo.EXPECT().GetLoanByID(ctx, loanID).Return(activeLoan, nil)
+				runInTransaction(mockTransactor)
+				mockLoanRepo.EXPECT().ReturnLoan(ctx, loanID, gomock.Any()).Return(nil)
+				mockHoldRepo.EXPECT().AssignNextHold(ctx, bookID.String(), copyID.String(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _, _ string, readyAt, expiresAt time.Time) (*model.Hold, error) {
+						if days := expiresAt.Sub(readyAt).Hours() / 24; days < 2.9 || days > 3.1 {
+							t.Errorf("loanService.ReturnLoan() expected a 3 day pickup window, got %.1f days", days)
+						}
+						return &model.Hold{ID: uuid.New(), BookID: bookID, CopyID: &copyID, Status: model.HoldStatusReady}, nil
+					})
+				mockCopyRepo.EXPECT().UpdateBookCopyStatus(ctx, copyID.String(), model.BookCopyStatusOnHold).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "late return is charged a fine",
+			mockFunc: func() {
+				mockLoanRepo.EXPECT().GetLoanByID(ctx, loanID).Return(lateLoan, nil)
+				runInTransaction(mockTransactor)
+				mockLoanRepo.EXPECT().ReturnLoan(ctx, loanID, gomock.Any()).Return(nil)
+				mockFineRepo.EXPECT().CreateFineEntry(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, entry model.FineEntry) error {
+					// 50 hours late counts as 3 started days
+					if entry.EntryType != model.FineEntryTypeCharge || entry.Amount != 75 || entry.MemberID != memberID {
+						t.Errorf("loanService.ReturnLoan() unexpected fine entry %+v", entry)
+					}
+					return nil
+				})
+				mockHoldRepo.EXPECT().AssignNextHold(ctx, bookID.String(), copyID.String(), gomock.Any(), gomock.Any()).Return(nil, nil)
+				mockCopyRepo.EXPECT().UpdateBookCopyStatus(ctx, copyID.String(), model.BookCopyStatusAvailable).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "loan not found",
+			mockFunc: func() {
+				mockLoanRepo.EXPECT().GetLoanByID(ctx, loanID).Return(nil, nil)
+			},
+			wantErr:  true,
+			errorMsg: errorcustom.ErrLoanNotFound.Error(),
+		},
+		{
+			name: "loan already returned",
+			mockFunc: func() {
+				mockLoanRepo.EXPECT().GetLoanByID(ctx, loanID).Return(returnedLoan, nil)
+			},
+			wantErr:  true,
+			errorMsg: errorcustom.ErrLoanAlreadyReturned.Error(),
+		},
+		{
+			name: "repository return error",
+			mockFunc: func() {
+				mockLoanRepo.EXPECT().GetLoanByID(ctx, loanID).Return(activeLoan, nil)
+				runInTransaction(mockTransactor)
+				mockLoanRepo.EXPECT().ReturnLoan(ctx, loanID, gomock.Any()).Return(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+			err := service.ReturnLoan(ctx, payload.ReturnLoanRequest{ID: loanID})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("loanService.ReturnLoan() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.errorMsg != "" && err != nil && err.Error() != tt.errorMsg {
+				t.Errorf("loanService.ReturnLoan() error = %v, want %v", err.Error(), tt.errorMsg)
+			}
+		})
+	}
+}
+
+func Test_loanService_GetBorrowingReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLoanRepo := mock.NewMockLoanRepository(ctrl)
+	service := NewLoanService(&config.Config{LoanPeriodDays: 14, HoldPickupDays: 3}, mock.NewMockTransactor(ctrl), mockLoanRepo, mock.NewMockBookRepository(ctrl), mock.NewMockBookCopyRepository(ctrl), mock.NewMockMemberRepository(ctrl), mock.NewMockHoldRepository(ctrl), mock.NewMockFineRepository(ctrl))
+
+	ctx := context.Background()
+
+	mockLoanRepo.EXPECT().GetLoanSummary(ctx).Return(model.LoanSummary{TotalLoans: 5, ActiveLoans: 2, OverdueLoans: 1, ReturnedLoans: 2}, nil)
+	mockLoanRepo.EXPECT().GetMostBorrowedBooks(ctx, mostBorrowedBooksLimit).Return([]model.BookLoanCount{{BookID: uuid.New(), Title: "Dune", LoanCount: 3}}, nil)
+	mockLoanRepo.EXPECT().GetMonthlyLoanCounts(ctx, borrowingReportMonths).Return([]model.MonthlyLoanCount{{Month: "2025-08", Borrowed: 5, Returned: 2}}, nil)
+
+	gotRes, err := service.GetBorrowingReport(ctx)
+	if err != nil {
+		t.Fatalf("loanService.GetBorrowingReport() unexpected error = %v", err)
+	}
+	if gotRes.TotalLoans != 5 || gotRes.OverdueLoans != 1 {
+		t.Errorf("loanService.GetBorrowingReport() unexpected totals %+v", gotRes)
+	}
+	if len(gotRes.MostBorrowed) != 1 || gotRes.MostBorrowed[0].Title != "Dune" {
+		t.Errorf("loanService.GetBorrowingReport() unexpected most borrowed %+v", gotRes.MostBorrowed)
+	}
+	if len(gotRes.Monthly) != 1 || gotRes.Monthly[0].Borrowed != 5 {
+		t.Errorf("loanService.GetBorrowingReport() unexpected monthly %+v", gotRes.Monthly)
+	}
+}
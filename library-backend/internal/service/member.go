@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"library-backend/errorcustom"
+	"library-backend/internal/model"
+	"library-backend/internal/payload"
+	"library-backend/internal/repository"
+	"log/slog"
+	"math"
+	"strings"
+)
+
+type MemberService interface {
+	CreateMember(ctx context.Context, request payload.CreateMemberRequest) (payload.CreateMemberResponse, error)
+	GetMembers(ctx context.Context, request payload.GetMembersRequest) (payload.GetMembersResponse, error)
+	GetMemberByID(ctx context.Context, id string) (payload.GetMemberByIDResponse, error)
+	UpdateMember(ctx context.Context, request payload.UpdateMemberRequest) error
+	DeleteMember(ctx context.Context, request payload.DeleteMemberRequest) error
+}
+
+type memberService struct {
+	memberRepo repository.MemberRepository
+}
+
+func NewMemberService(memberRepo repository.MemberRepository) MemberService {
+	return &memberService{memberRepo: memberRepo}
+}
+
+func (s *memberService) CreateMember(ctx context.Context, request payload.CreateMemberRequest) (res payload.CreateMemberResponse, err error) {
+	member := request.ToModel()
+
+	// new members are active unless told otherwise
+	if member.Status == "" {
+		member.Status = model.MemberStatusActive
+	}
+
+	err = s.memberRepo.CreateMember(ctx, member)
+	if err != nil {
+		if strings.Contains(err.Error(), "ERROR: duplicate key value violates unique constraint \"members_email_key\"") {
+			return res, errorcustom.ErrMemberAlreadyExists
+		}
+		slog.ErrorContext(ctx, "[MemberService][CreateMember] failed to create member", "error", err)
+		return res, err
+	}
+
+	res.ID = member.ID
+
+	return res, nil
+}
+
+func (s *memberService) GetMembers(ctx context.Context, request payload.GetMembersRequest) (res payload.GetMembersResponse, err error) {
+	request.Offset = (request.Page - 1) * request.Limit
+
+	// get members with pagination
+	members, err := s.memberRepo.GetMembers(ctx, request)
+	if err != nil {
+		slog.ErrorContext(ctx, "[MemberService][GetMembers] failed to get members", "error", err)
+		return res, err
+	}
+
+	// get total count of members matching the filters
+	totalCount, err := s.memberRepo.GetMembersCount(ctx, request)
+	if err != nil {
+		slog.ErrorContext(ctx, "[MemberService][GetMembers] failed to get members count", "error", err)
+		return res, err
+	}
+
+	totalPages := int(math.Ceil(float64(totalCount) / float64(request.Limit)))
+
+	memberResponses := make([]payload.MemberResponse, len(members))
+	for i, member := range members {
+		memberResponses[i] = toMemberResponse(member)
+	}
+
+	res.Members = memberResponses
+	res.Pagination = payload.Pagination{
+		Page:      request.Page,
+		Limit:     request.Limit,
+		TotalPage: totalPages,
+		TotalItem: totalCount,
+	}
+
+	return res, nil
+}
+
+func (s *memberService) GetMemberByID(ctx context.Context, id string) (res payload.GetMemberByIDResponse, err error) {
+	member, err := s.memberRepo.GetMemberByID(ctx, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "[MemberService][GetMemberByID] failed to get member by ID", "error", err, "id", id)
+		return res, err
+	}
+
+	if member == nil {
+		return res, errorcustom.ErrMemberNotFound
+	}
+
+	res.MemberResponse = toMemberResponse(*member)
+
+	return res, nil
+}
+
+func (s *memberService) UpdateMember(ctx context.Context, request payload.UpdateMemberRequest) (err error) {
+	// Check if member exists
+	member, err := s.memberRepo.GetMemberByID(ctx, request.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[MemberService][UpdateMember] failed to check member existence", "error", err, "id", request.ID)
+		return err
+	}
+
+	if member == nil {
+		return errorcustom.ErrMemberNotFound
+	}
+
+	// Build update map
+	updates := buildUpdateMap(request)
+	if len(updates) == 0 {
+		return errors.New("no fields to update")
+	}
+
+	// Update the member
+	err = s.memberRepo.UpdateMember(ctx, request.ID, updates)
+	if err != nil {
+		if strings.Contains(err.Error(), "ERROR: duplicate key value violates unique constraint \"members_email_key\"") {
+			return errorcustom.ErrMemberAlreadyExists
+		}
+		slog.ErrorContext(ctx, "[MemberService][UpdateMember] failed to update member", "error", err, "id", request.ID)
+		return err
+	}
+
+	return nil
+}
+
+func (s *memberService) DeleteMember(ctx context.Context, request payload.DeleteMemberRequest) (err error) {
+	// Check if member exists first
+	member, err := s.memberRepo.GetMemberByID(ctx, request.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[MemberService][DeleteMember] failed to check member existence", "error", err, "id", request.ID)
+		return err
+	}
+
+	if member == nil {
+		return errorcustom.ErrMemberNotFound
+	}
+
+	// Soft delete the member
+	err = s.memberRepo.DeleteMember(ctx, request.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[MemberService][DeleteMember] failed to delete member", "error", err, "id", request.ID)
+		return err
+	}
+
+	return nil
+}
+
+func toMemberResponse(member model.Member) payload.MemberResponse {
+	return payload.MemberResponse{
+		ID:        member.ID,
+		Name:      member.Name,
+		Email:     member.Email,
+		Phone:     member.Phone,
+		Address:   member.Address,
+		Status:    member.Status,
+		CreatedAt: member.CreatedAt,
+		UpdatedAt: member.UpdatedAt,
+	}
+}
@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"library-backend/errorcustom"
+	"library-backend/internal/model"
+	"library-backend/internal/payload"
+	"library-backend/internal/repository/mock"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+)
+
+func Test_memberService_CreateMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockMemberRepository(ctrl)
+	service := NewMemberService(mockRepo)
+
+	ctx := context.Background()
+	request := payload.CreateMemberRequest{
+		Name:  "Jane Doe",
+		Email: "jane@example.com",
+		Phone: "+62811111111",
+	}
+
+	tests := []struct {
+		name     string
+		mockFunc func()
+		request  payload.CreateMemberRequest
+		wantErr  bool
+		errorMsg string
+	}{
+		{
+			name: "success with default status",
+			mockFunc: func() {
+				mockRepo.EXPECT().CreateMember(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, member model.Member) error {
+					if member.Status != "active" {
+						t.Errorf("memberService.CreateMember() expected default status active, got %q", member.Status)
+					}
+					return nil
+				})
+			},
+			request: request,
+			wantErr: false,
+		},
+		{
+			name: "duplicate email",
+			mockFunc: func() {
+				mockRepo.EXPECT().CreateMember(ctx, gomock.Any()).Return(errors.New("ERROR: duplicate key value violates unique constraint \"members_email_key\" (SQLSTATE 23505)"))
+			},
+			request:  request,
+			wantErr:  true,
+			errorMsg: errorcustom.ErrMemberAlreadyExists.Error(),
+		},
+		{
+			name: "repository error",
+			mockFunc: func() {
+				mockRepo.EXPECT().CreateMember(ctx, gomock.Any()).Return(errors.New("db error"))
+			},
+			request: request,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+			gotRes, err := service.CreateMember(ctx, tt.request)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("memberService.CreateMember() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.errorMsg != "" && err != nil && err.Error() != tt.errorMsg {
+				t.Errorf("memberService.CreateMember() error = %v, want %v", err.Error(), tt.errorMsg)
+			}
+			if !tt.wantErr && gotRes.ID == uuid.Nil {
+				t.Errorf("memberService.CreateMember() expected valid ID, got nil")
+			}
+		})
+	}
+}
+
+func Test_memberService_GetMembers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockMemberRepository(ctrl)
+	service := NewMemberService(mockRepo)
+
+	ctx := context.Background()
+	now := time.Now()
+
+	sampleMembers := []model.Member{
+		{
+			ID:        uuid.New(),
+			Name:      "Jane Doe",
+			Email:     "jane@example.com",
+			Status:    "active",
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+	}
+
+	expectedReq := payload.GetMembersRequest{
+		PaginationRequest: payload.PaginationRequest{Page: 2, Limit: 5},
+		Offset:            5,
+		Search:            "jane",
+	}
+
+	tests := []struct {
+		name          string
+		mockFunc      func()
+		request       payload.GetMembersRequest
+		wantErr       bool
+		wantTotalPage int
+	}{
+		{
+			name: "success",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetMembers(ctx, expectedReq).Return(sampleMembers, nil)
+				mockRepo.EXPECT().GetMembersCount(ctx, expectedReq).Return(6, nil)
+			},
+			request: payload.GetMembersRequest{
+				PaginationRequest: payload.PaginationRequest{Page: 2, Limit: 5},
+				Search:            "jane",
+			},
+			wantErr:       false,
+			wantTotalPage: 2,
+		},
+		{
+			name: "get members error",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetMembers(ctx, expectedReq).Return(nil, errors.New("db error"))
+			},
+			request: payload.GetMembersRequest{
+				PaginationRequest: payload.PaginationRequest{Page: 2, Limit: 5},
+				Search:            "jane",
+			},
+			wantErr: true,
+		},
+		{
+			name: "get members count error",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetMembers(ctx, expectedReq).Return(sampleMembers, nil)
+				mockRepo.EXPECT().GetMembersCount(ctx, expectedReq).Return(0, errors.New("db error"))
+			},
+			request: payload.GetMembersRequest{
+				PaginationRequest: payload.PaginationRequest{Page: 2, Limit: 5},
+				Search:            "jane",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+			gotRes, err := service.GetMembers(ctx, tt.request)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("memberService.GetMembers() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && len(gotRes.Members) != len(sampleMembers) {
+				t.Errorf("memberService.GetMembers() expected %d members, got %d", len(sampleMembers), len(gotRes.Members))
+			}
+			if !tt.wantErr && gotRes.Pagination.TotalPage != tt.wantTotalPage {
+				t.Errorf("memberService.GetMembers() expected %d total pages, got %d", tt.wantTotalPage, gotRes.Pagination.TotalPage)
+			}
+		})
+	}
+}
+
+func Test_memberService_UpdateMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockMemberRepository(ctrl)
+	service := NewMemberService(mockRepo)
+
+	ctx := context.Background()
+	memberID := uuid.New().String()
+
+	sampleMember := &model.Member{
+		ID:     uuid.MustParse(memberID),
+		Name:   "Jane Doe",
+		Email:  "jane@example.com",
+		Status: "active",
+	}
+
+	status := "suspended"
+
+	tests := []struct {
+		name     string
+		mockFunc func()
+		request  payload.UpdateMemberRequest
+		wantErr  bool
+		errorMsg string
+	}{
+		{
+			name: "success",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetMemberByID(ctx, memberID).Return(sampleMember, nil)
+				mockRepo.EXPECT().UpdateMember(ctx, memberID, map[string]any{"status": "suspended"}).Return(nil)
+			},
+			request: payload.UpdateMemberRequest{
+				ID:     memberID,
+				Status: &status,
+			},
+			wantErr: false,
+		},
+		{
+			name: "member not found",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetMemberByID(ctx, memberID).Return(nil, nil)
+			},
+			request: payload.UpdateMemberRequest{
+				ID:     memberID,
+				Status: &status,
+			},
+			wantErr:  true,
+			errorMsg: errorcustom.ErrMemberNotFound.Error(),
+		},
+		{
+			name: "no fields to update",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetMemberByID(ctx, memberID).Return(sampleMember, nil)
+			},
+			request: payload.UpdateMemberRequest{
+				ID: memberID,
+			},
+			wantErr:  true,
+			errorMsg: "no fields to update",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+			err := service.UpdateMember(ctx, tt.request)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("memberService.UpdateMember() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.errorMsg != "" && err != nil && err.Error() != tt.errorMsg {
+				t.Errorf("memberService.UpdateMember() error = %v, want %v", err.Error(), tt.errorMsg)
+			}
+		})
+	}
+}
+
+func Test_memberService_DeleteMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockMemberRepository(ctrl)
+	service := NewMemberService(mockRepo)
+
+	ctx := context.Background()
+	memberID := uuid.New().String()
+
+	sampleMember := &model.Member{
+		ID:   uuid.MustParse(memberID),
+		Name: "Jane Doe",
+	}
+
+	tests := []struct {
+		name     string
+		mockFunc func()
+		request  payload.DeleteMemberRequest
+		wantErr  bool
+		errorMsg string
+	}{
+		{
+			name: "success",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetMemberByID(ctx, memberID).Return(sampleMember, nil)
+				mockRepo.EXPECT().DeleteMember(ctx, memberID).Return(nil)
+			},
+			request: payload.DeleteMemberRequest{ID: memberID},
+			wantErr: false,
+		},
+		{
+			name: "member not found",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetMemberByID(ctx, memberID).Return(nil, nil)
+			},
+			request:  payload.DeleteMemberRequest{ID: memberID},
+			wantErr:  true,
+			errorMsg: errorcustom.ErrMemberNotFound.Error(),
+		},
+		{
+			name: "repository delete error",
+			mockFunc: func() {
+				mockRepo.EXPECT().GetMemberByID(ctx, memberID).Return(sampleMember, nil)
+				mockRepo.EXPECT().DeleteMember(ctx, memberID).Return(errors.New("delete error"))
+			},
+			request: payload.DeleteMemberRequest{ID: memberID},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+			err := service.DeleteMember(ctx, tt.request)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("memberService.DeleteMember() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.errorMsg != "" && err != nil && err.Error() != tt.errorMsg {
+				t.Errorf("memberService.DeleteMember() error = %v, want %v", err.Error(), tt.errorMsg)
+			}
+		})
+	}
+}
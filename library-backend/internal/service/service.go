@@ -1,21 +1,123 @@
 package service
 
 import (
+	"library-backend/internal/auth"
 	"library-backend/internal/config"
 	"library-backend/internal/repository"
+	"reflect"
+	"strings"
 )
 
 type Service struct {
-	BookService BookService
+	BookService     BookService
+	MemberService   MemberService
+	LoanService     LoanService
+	BookCopyService BookCopyService
+	HoldService     HoldService
+	FineService     FineService
+	AuthService     AuthService
+	UserService     UserService
+	APIKeyService   APIKeyService
+	AuditService    AuditService
+	CatalogService  CatalogService
 }
 
 type Option struct {
 	Config     *config.Config
+	Tokens     *auth.TokenManager
 	Repository *repository.Repository
 }
 
 func InitiateService(opt Option) *Service {
+	bookService := NewBookService(
+		opt.Repository.Transactor,
+		opt.Repository.BookRepository,
+		opt.Repository.AuditRepository,
+	)
+
 	return &Service{
-		BookService: NewBookService(opt.Repository.BookRepository),
+		BookService:   bookService,
+		MemberService: NewMemberService(opt.Repository.MemberRepository),
+		LoanService: NewLoanService(
+			opt.Config,
+			opt.Repository.Transactor,
+			opt.Repository.LoanRepository,
+			opt.Repository.BookRepository,
+			opt.Repository.BookCopyRepository,
+			opt.Repository.MemberRepository,
+			opt.Repository.HoldRepository,
+			opt.Repository.FineRepository,
+		),
+		BookCopyService: NewBookCopyService(
+			opt.Config,
+			opt.Repository.Transactor,
+			opt.Repository.BookCopyRepository,
+			opt.Repository.BookRepository,
+			opt.Repository.HoldRepository,
+		),
+		HoldService: NewHoldService(
+			opt.Config,
+			opt.Repository.Transactor,
+			opt.Repository.HoldRepository,
+			opt.Repository.BookRepository,
+			opt.Repository.BookCopyRepository,
+			opt.Repository.MemberRepository,
+		),
+		FineService: NewFineService(
+			opt.Repository.Transactor,
+			opt.Repository.FineRepository,
+			opt.Repository.MemberRepository,
+			opt.Repository.LoanRepository,
+		),
+		AuthService: NewAuthService(
+			opt.Tokens,
+			opt.Repository.Transactor,
+			opt.Repository.UserRepository,
+			opt.Repository.RefreshTokenRepository,
+		),
+		UserService: NewUserService(
+			opt.Repository.Transactor,
+			opt.Repository.UserRepository,
+			opt.Repository.RefreshTokenRepository,
+		),
+		APIKeyService:  NewAPIKeyService(opt.Repository.APIKeyRepository),
+		AuditService:   NewAuditService(opt.Repository.AuditRepository),
+		CatalogService: NewCatalogService(bookService, opt.Repository.BookRepository),
 	}
 }
+
+// buildUpdateMap turns the non-nil pointer fields of a partial update request into a column/value map
+// keyed by the field json name, skipping the id field.
+func buildUpdateMap(request any) map[string]any {
+	updates := make(map[string]any)
+
+	v := reflect.ValueOf(request)
+	t := reflect.TypeOf(request)
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		fieldType := t.Field(i)
+
+		jsonTag := fieldType.Tag.Get("json")
+		if jsonTag == "" || jsonTag == "-" {
+			continue
+		}
+
+		// Extract field name from json tag (remove ",omitempty" etc.)
+		fieldName := jsonTag
+		if commaIdx := strings.Index(jsonTag, ","); commaIdx != -1 {
+			fieldName = jsonTag[:commaIdx]
+		}
+
+		// Skip ID field as it shouldn't be updated
+		if fieldName == "id" {
+			continue
+		}
+
+		if field.Kind() == reflect.Ptr && !field.IsNil() {
+			updates[fieldName] = field.Elem().Interface()
+		}
+	}
+
+	return updates
+}
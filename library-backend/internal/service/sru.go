@@ -0,0 +1,350 @@
+package service
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"library-backend/internal/model"
+	"library-backend/internal/payload"
+	"library-backend/pkg/cql"
+	"library-backend/pkg/marc"
+	"library-backend/pkg/sru"
+	"log/slog"
+	"strconv"
+)
+
+// sruDatabase is the path of the SRU endpoint, explain names it as the database.
+const sruDatabase = "v1/sru"
+
+const (
+	sruDefaultRecords = 10
+	sruMaxRecords     = 100
+	sruDefaultTerms   = 20
+	sruMaxTerms       = 100
+)
+
+// sruRecordSchemas maps the recordSchema parameter, a short name or an identifier, onto the schema.
+var sruRecordSchemas = map[string]string{
+	"":                sru.SchemaDC,
+	"dc":              sru.SchemaDC,
+	sru.SchemaDC:      sru.SchemaDC,
+	"marcxml":         sru.SchemaMARCXML,
+	sru.SchemaMARCXML: sru.SchemaMARCXML,
+}
+
+// sruQueryDiagnostics maps the parts of a query the books cannot be searched by onto diagnostics.
+var sruQueryDiagnostics = map[error]int{
+	cql.ErrUnsupportedIndex:            sru.DiagUnsupportedIndex,
+	cql.ErrUnsupportedRelation:         sru.DiagUnsupportedRelation,
+	cql.ErrUnsupportedRelationModifier: sru.DiagUnsupportedRelationModifier,
+	cql.ErrUnsupportedBoolean:          sru.DiagUnsupportedBoolean,
+	cql.ErrUnsupportedBooleanModifier:  sru.DiagUnsupportedBooleanModifier,
+	cql.ErrUnsupportedMasking:          sru.DiagMaskingNotSupported,
+	cql.ErrEmptyTerm:                   sru.DiagEmptyTermUnsupported,
+	cql.ErrInvalidTerm:                 sru.DiagInvalidTerm,
+	cql.ErrUnsupportedSort:             sru.DiagUnsupportedSortPath,
+}
+
+// the context sets and indexes of the book search, as the book repository maps them onto columns
+var (
+	sruContextSets = []sru.ContextSet{
+		{Name: "cql", Identifier: "info:srw/cql-context-set/1/cql-v1.2"},
+		{Name: "dc", Identifier: "info:srw/cql-context-set/1/dc-v1.1"},
+		{Name: "bath", Identifier: "http://zing.z3950.org/cql/bath/2.0/"},
+		{Name: "rec", Identifier: "info:srw/cql-context-set/2/rec-1.1"},
+		{Name: "sort", Identifier: "info:srw/cql-context-set/1/sort-v1.0"},
+	}
+
+	sruIndexes = []sru.Index{
+		{Title: "Any field", Names: []sru.IndexName{{Set: "cql", Name: "serverChoice"}, {Set: "cql", Name: "anywhere"}}},
+		{Title: "Every record", Names: []sru.IndexName{{Set: "cql", Name: "allRecords"}}},
+		{Title: "Title", Names: []sru.IndexName{{Set: "dc", Name: "title"}}},
+		{Title: "Author", Names: []sru.IndexName{{Set: "dc", Name: "creator"}}},
+		{Title: "Publisher", Names: []sru.IndexName{{Set: "dc", Name: "publisher"}}},
+		{Title: "Year of publication", Names: []sru.IndexName{{Set: "dc", Name: "date"}}},
+		{Title: "Category", Names: []sru.IndexName{{Set: "dc", Name: "subject"}}},
+		{Title: "ISBN", Names: []sru.IndexName{{Set: "dc", Name: "identifier"}, {Set: "bath", Name: "isbn"}}},
+		{Title: "Book ID", Names: []sru.IndexName{{Set: "rec", Name: "identifier"}}},
+	}
+)
+
+// SearchRetrieve runs a CQL query over the books and returns a page of them as Dublin Core or
+// MARCXML records. Bad parameters and queries are reported as diagnostics, the error is only set
+// when the search itself fails.
+func (s *catalogService) SearchRetrieve(ctx context.Context, request payload.SRURequest) (sru.SearchRetrieveResponse, error) {
+	var res sru.SearchRetrieveResponse
+
+	fail := func(code int, details string) (sru.SearchRetrieveResponse, error) {
+		res.Diagnostics = append(res.Diagnostics, sru.NewDiagnostic(code, details))
+		return res, nil
+	}
+
+	if !sruVersionSupported(request.Version) {
+		return fail(sru.DiagUnsupportedVersion, sru.Version)
+	}
+
+	if request.Query == "" {
+		return fail(sru.DiagMandatoryParameterMissing, "query")
+	}
+
+	start, ok := sruIntParam(request.StartRecord, 1)
+	if !ok || start < 1 {
+		return fail(sru.DiagUnsupportedParameterValue, "startRecord")
+	}
+
+	maximum, ok := sruIntParam(request.MaximumRecords, sruDefaultRecords)
+	if !ok || maximum < 0 || maximum > sruMaxRecords {
+		return fail(sru.DiagUnsupportedParameterValue, "maximumRecords")
+	}
+
+	schema, ok := sruRecordSchemas[request.RecordSchema]
+	if !ok {
+		return fail(sru.DiagUnknownSchema, request.RecordSchema)
+	}
+
+	escaping := request.RecordXMLEscaping
+	if escaping == "" {
+		escaping = sru.EscapingXML
+	}
+	if escaping != sru.EscapingXML && escaping != sru.EscapingString {
+		return fail(sru.DiagUnsupportedXMLEscaping, escaping)
+	}
+
+	query, err := cql.Parse(request.Query)
+	if err != nil {
+		return fail(sruQueryDiagnostic(err))
+	}
+
+	total, err := s.bookRepo.SearchBooksCount(ctx, query)
+	if err != nil {
+		if code, details := sruQueryDiagnostic(err); code != sru.DiagGeneralSystemError {
+			return fail(code, details)
+		}
+		slog.ErrorContext(ctx, "[CatalogService][SearchRetrieve] failed to count books", "error", err, "query", request.Query)
+		return res, err
+	}
+
+	res.NumberOfRecords = total
+
+	// a client can ask for the count alone
+	if maximum == 0 || total == 0 {
+		return res, nil
+	}
+
+	if start > total {
+		return fail(sru.DiagFirstRecordOutOfRange, strconv.Itoa(start))
+	}
+
+	books, err := s.bookRepo.SearchBooks(ctx, query, maximum, start-1)
+	if err != nil {
+		if code, details := sruQueryDiagnostic(err); code != sru.DiagGeneralSystemError {
+			return fail(code, details)
+		}
+		slog.ErrorContext(ctx, "[CatalogService][SearchRetrieve] failed to search books", "error", err, "query", request.Query)
+		return res, err
+	}
+
+	for i, book := range books {
+		record, err := sruBookRecord(book, schema, escaping, start+i)
+		if err != nil {
+			slog.ErrorContext(ctx, "[CatalogService][SearchRetrieve] failed to encode record", "error", err, "id", book.ID)
+			return res, err
+		}
+		res.Records = append(res.Records, record)
+	}
+
+	if next := start + len(books); next <= total {
+		res.NextRecordPosition = next
+	}
+
+	return res, nil
+}
+
+// Scan browses the values of an index from the term of the scan clause, with the number of books
+// each is found in. responsePosition is the place of the term in the list, 1 starts the list with
+// it and larger positions show the values before it too.
+func (s *catalogService) Scan(ctx context.Context, request payload.SRURequest) (sru.ScanResponse, error) {
+	var res sru.ScanResponse
+
+	fail := func(code int, details string) (sru.ScanResponse, error) {
+		res.Diagnostics = append(res.Diagnostics, sru.NewDiagnostic(code, details))
+		return res, nil
+	}
+
+	if !sruVersionSupported(request.Version) {
+		return fail(sru.DiagUnsupportedVersion, sru.Version)
+	}
+
+	if request.ScanClause == "" {
+		return fail(sru.DiagMandatoryParameterMissing, "scanClause")
+	}
+
+	maximum, ok := sruIntParam(request.MaximumTerms, sruDefaultTerms)
+	if !ok || maximum < 1 || maximum > sruMaxTerms {
+		return fail(sru.DiagUnsupportedParameterValue, "maximumTerms")
+	}
+
+	position, ok := sruIntParam(request.ResponsePosition, 1)
+	if !ok || position < 0 || position > maximum+1 {
+		return fail(sru.DiagResponsePositionOutOfRange, request.ResponsePosition)
+	}
+
+	query, err := cql.Parse(request.ScanClause)
+	if err != nil {
+		return fail(sruQueryDiagnostic(err))
+	}
+
+	clause, ok := query.Root.(*cql.SearchClause)
+	if !ok || len(query.SortKeys) > 0 {
+		return fail(sru.DiagQuerySyntaxError, "a scan clause is a single index, relation and term")
+	}
+
+	if relation := clause.Relation.Comparitor; relation != "=" && relation != "==" {
+		return fail(sru.DiagUnsupportedRelation, relation)
+	}
+
+	if len(clause.Relation.Modifiers) > 0 {
+		return fail(sru.DiagUnsupportedRelationModifier, clause.Relation.Modifiers[0].Name)
+	}
+
+	// position 0 puts the term just before the list, so the list starts from it as for 1
+	before := max(position-1, 0)
+	terms, err := s.bookRepo.ScanBooks(ctx, clause.Index, cql.Unescape(clause.Term), before, maximum-before)
+	if err != nil {
+		if code, details := sruQueryDiagnostic(err); code != sru.DiagGeneralSystemError {
+			return fail(code, details)
+		}
+		slog.ErrorContext(ctx, "[CatalogService][Scan] failed to scan books", "error", err, "scan_clause", request.ScanClause)
+		return res, err
+	}
+
+	for _, term := range terms {
+		res.Terms = append(res.Terms, sru.Term{Value: term.Value, NumberOfRecords: term.Count})
+	}
+
+	return res, nil
+}
+
+// Explain describes the SRU server: where it is, the indexes the books are searched by and the
+// record schemas they are returned in.
+func (s *catalogService) Explain(ctx context.Context, request payload.SRURequest) (sru.ExplainResponse, error) {
+	var res sru.ExplainResponse
+
+	if operation := request.ResolvedOperation(); operation != payload.SRUOperationExplain {
+		res.Diagnostics = append(res.Diagnostics, sru.NewDiagnostic(sru.DiagUnsupportedOperation, operation))
+	}
+
+	if !sruVersionSupported(request.Version) {
+		res.Diagnostics = append(res.Diagnostics, sru.NewDiagnostic(sru.DiagUnsupportedVersion, sru.Version))
+	}
+
+	explain := sru.Explain{
+		ServerInfo: sru.ServerInfo{
+			Protocol:  "SRU",
+			Version:   sru.Version,
+			Transport: "http",
+			Host:      request.Host,
+			Port:      request.Port,
+			Database:  sruDatabase,
+		},
+		DatabaseInfo: sru.DatabaseInfo{
+			Title:       "Library catalog",
+			Description: "The books of the library, by title, author, publisher, year of publication, category and ISBN",
+		},
+		IndexInfo: sru.IndexInfo{Sets: sruContextSets, Indexes: sruIndexes},
+		Schemas: []sru.Schema{
+			{Identifier: sru.SchemaDC, Name: "dc", Title: "Dublin Core"},
+			{Identifier: sru.SchemaMARCXML, Name: "marcxml", Title: "MARC 21 in MARCXML"},
+		},
+		ConfigInfo: sru.ConfigInfo{
+			Defaults: []sru.Setting{
+				{Type: "numberOfRecords", Value: strconv.Itoa(sruDefaultRecords)},
+				{Type: "contextSet", Value: "dc"},
+				{Type: "index", Value: cql.ServerChoice},
+			},
+			Settings: []sru.Setting{
+				{Type: "maximumRecords", Value: strconv.Itoa(sruMaxRecords)},
+				{Type: "maximumTerms", Value: strconv.Itoa(sruMaxTerms)},
+			},
+		},
+	}
+
+	for _, relation := range []string{"=", "==", "<>", "<", ">", "<=", ">=", "adj", "any", "all", "within"} {
+		explain.ConfigInfo.Supports = append(explain.ConfigInfo.Supports, sru.Setting{Type: "relation", Value: relation})
+	}
+	explain.ConfigInfo.Supports = append(explain.ConfigInfo.Supports, sru.Setting{Type: "sort"})
+
+	data, err := xml.Marshal(explain)
+	if err != nil {
+		slog.ErrorContext(ctx, "[CatalogService][Explain] failed to encode explain record", "error", err)
+		return res, err
+	}
+
+	res.Record = sru.NewRecord(sru.SchemaExplain, data, sru.EscapingXML, 0)
+
+	return res, nil
+}
+
+// sruVersionSupported reports whether the requested version is answered, the responses are SRU 2.0
+// ones.
+func sruVersionSupported(version string) bool {
+	return version == "" || version == sru.Version
+}
+
+// sruIntParam reads an integer parameter, def when it is not given.
+func sruIntParam(value string, def int) (int, bool) {
+	if value == "" {
+		return def, true
+	}
+
+	n, err := strconv.Atoi(value)
+	return n, err == nil
+}
+
+// sruQueryDiagnostic returns the diagnostic of a query that could not be parsed or run, a general
+// system error for any other error.
+func sruQueryDiagnostic(err error) (int, string) {
+	var syntaxErr *cql.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return sru.DiagQuerySyntaxError, fmt.Sprintf("%s at offset %d", syntaxErr.Msg, syntaxErr.Pos)
+	}
+
+	var cqlErr *cql.Error
+	if errors.As(err, &cqlErr) {
+		if code, ok := sruQueryDiagnostics[cqlErr.Err]; ok {
+			return code, cqlErr.Detail
+		}
+	}
+
+	return sru.DiagGeneralSystemError, ""
+}
+
+// sruBookRecord is a book as a record of the schema, at its position in the result set.
+func sruBookRecord(book model.Book, schema, escaping string, position int) (sru.Record, error) {
+	var data []byte
+	var err error
+
+	if schema == sru.SchemaMARCXML {
+		data, err = marc.MarshalXML(bookToMARC(toBookResponse(book)))
+	} else {
+		data, err = sru.MarshalDublinCore(bookToDublinCore(book))
+	}
+	if err != nil {
+		return sru.Record{}, err
+	}
+
+	return sru.NewRecord(schema, data, escaping, position), nil
+}
+
+func bookToDublinCore(book model.Book) sru.DublinCore {
+	return sru.DublinCore{
+		Title:       book.Title,
+		Creators:    []string{book.Author},
+		Publisher:   book.Publisher,
+		Date:        strconv.Itoa(book.YearOfPublication),
+		Subjects:    []string{opdsCategoryLabel(book.Category)},
+		Type:        "Text",
+		Identifiers: []string{"urn:isbn:" + book.ISBN13, "urn:uuid:" + book.ID.String()},
+	}
+}
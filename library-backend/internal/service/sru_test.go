@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"library-backend/internal/model"
+	"library-backend/internal/payload"
+	"library-backend/internal/repository/mock"
+	"library-backend/pkg/cql"
+	"library-backend/pkg/sru"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+)
+
+func Test_catalogService_SearchRetrieve(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockBookRepository(ctrl)
+	service := NewCatalogService(NewBookService(mock.NewMockTransactor(ctrl), mockRepo, mock.NewMockAuditRepository(ctrl)), mockRepo)
+
+	ctx := context.Background()
+	books := []model.Book{
+		{ID: uuid.New(), ISBN13: "9780134190440", Title: "The Go Programming Language", Author: "Alan Donovan", Publisher: "Addison-Wesley", YearOfPublication: 2015, Category: "technology"},
+		{ID: uuid.New(), ISBN13: "9781617291784", Title: "Go in Action", Author: "William Kennedy", Publisher: "Manning", YearOfPublication: 2016, Category: "technology"},
+	}
+
+	t.Run("dublin core page", func(t *testing.T) {
+		request := payload.SRURequest{Query: `dc.title any "go" and dc.date > 2010`, StartRecord: "3", MaximumRecords: "2"}
+
+		mockRepo.EXPECT().SearchBooksCount(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, query *cql.Query) (int, error) {
+			if b, ok := query.Root.(*cql.Boolean); !ok || b.Op != "and" {
+				t.Errorf("catalogService.SearchRetrieve() unexpected query %+v", query.Root)
+			}
+			return 5, nil
+		})
+		mockRepo.EXPECT().SearchBooks(ctx, gomock.Any(), 2, 2).Return(books, nil)
+
+		res, err := service.SearchRetrieve(ctx, request)
+		if err != nil {
+			t.Fatalf("catalogService.SearchRetrieve() error = %v", err)
+		}
+
+		if res.NumberOfRecords != 5 || len(res.Records) != 2 || res.NextRecordPosition != 5 || len(res.Diagnostics) != 0 {
+			t.Fatalf("catalogService.SearchRetrieve() = %+v", res)
+		}
+
+		record := res.Records[1]
+		if record.Schema != sru.SchemaDC || record.Position != 4 {
+			t.Errorf("catalogService.SearchRetrieve() record = %+v", record)
+		}
+
+		raw, _ := sru.Marshal(res)
+		for _, want := range []string{"<dc:title>Go in Action</dc:title>", "<dc:subject>Technology</dc:subject>", "<dc:identifier>urn:isbn:9781617291784</dc:identifier>"} {
+			if !strings.Contains(string(raw), want) {
+				t.Errorf("catalogService.SearchRetrieve() = %s, want it to contain %s", raw, want)
+			}
+		}
+	})
+
+	t.Run("marcxml last page", func(t *testing.T) {
+		request := payload.SRURequest{Query: "go", RecordSchema: "marcxml", RecordXMLEscaping: "string"}
+
+		mockRepo.EXPECT().SearchBooksCount(ctx, gomock.Any()).Return(2, nil)
+		mockRepo.EXPECT().SearchBooks(ctx, gomock.Any(), 10, 0).Return(books, nil)
+
+		res, err := service.SearchRetrieve(ctx, request)
+		if err != nil {
+			t.Fatalf("catalogService.SearchRetrieve() error = %v", err)
+		}
+
+		if res.NextRecordPosition != 0 || len(res.Records) != 2 {
+			t.Fatalf("catalogService.SearchRetrieve() = %+v", res)
+		}
+
+		record := res.Records[0]
+		if record.Schema != sru.SchemaMARCXML || record.XMLEscaping != sru.EscapingString || !strings.Contains(record.Data.Text, "9780134190440") {
+			t.Errorf("catalogService.SearchRetrieve() record = %+v", record)
+		}
+	})
+
+	t.Run("count only", func(t *testing.T) {
+		mockRepo.EXPECT().SearchBooksCount(ctx, gomock.Any()).Return(7, nil)
+
+		res, err := service.SearchRetrieve(ctx, payload.SRURequest{Query: "go", MaximumRecords: "0"})
+		if err != nil || res.NumberOfRecords != 7 || len(res.Records) != 0 {
+			t.Errorf("catalogService.SearchRetrieve() = %+v, %v", res, err)
+		}
+	})
+
+	t.Run("diagnostics", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			request payload.SRURequest
+			setup   func()
+			wantURI string
+		}{
+			{"unsupported version", payload.SRURequest{Query: "go", Version: "1.1"}, nil, "info:srw/diagnostic/1/5"},
+			{"bad start record", payload.SRURequest{Query: "go", StartRecord: "0"}, nil, "info:srw/diagnostic/1/6"},
+			{"too many records", payload.SRURequest{Query: "go", MaximumRecords: "500"}, nil, "info:srw/diagnostic/1/6"},
+			{"unknown schema", payload.SRURequest{Query: "go", RecordSchema: "mods"}, nil, "info:srw/diagnostic/1/66"},
+			{"unsupported escaping", payload.SRURequest{Query: "go", RecordXMLEscaping: "json"}, nil, "info:srw/diagnostic/1/71"},
+			{"syntax error", payload.SRURequest{Query: "(go"}, nil, "info:srw/diagnostic/1/10"},
+			{"unsupported index", payload.SRURequest{Query: "dc.format = pdf"}, func() {
+				mockRepo.EXPECT().SearchBooksCount(ctx, gomock.Any()).Return(0, &cql.Error{Err: cql.ErrUnsupportedIndex, Detail: "dc.format"})
+			}, "info:srw/diagnostic/1/16"},
+			{"start past the results", payload.SRURequest{Query: "go", StartRecord: "4"}, func() {
+				mockRepo.EXPECT().SearchBooksCount(ctx, gomock.Any()).Return(3, nil)
+			}, "info:srw/diagnostic/1/61"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if tt.setup != nil {
+					tt.setup()
+				}
+
+				res, err := service.SearchRetrieve(ctx, tt.request)
+				if err != nil {
+					t.Fatalf("catalogService.SearchRetrieve() error = %v", err)
+				}
+
+				if len(res.Diagnostics) != 1 || res.Diagnostics[0].URI != tt.wantURI {
+					t.Errorf("catalogService.SearchRetrieve() diagnostics = %+v, want %s", res.Diagnostics, tt.wantURI)
+				}
+			})
+		}
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		mockRepo.EXPECT().SearchBooksCount(ctx, gomock.Any()).Return(0, errors.New("connection refused"))
+
+		if _, err := service.SearchRetrieve(ctx, payload.SRURequest{Query: "go"}); err == nil {
+			t.Error("catalogService.SearchRetrieve() error = nil, want an error")
+		}
+	})
+}
+
+func Test_catalogService_Scan(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockBookRepository(ctrl)
+	service := NewCatalogService(NewBookService(mock.NewMockTransactor(ctrl), mockRepo, mock.NewMockAuditRepository(ctrl)), mockRepo)
+
+	ctx := context.Background()
+
+	t.Run("terms around the scan term", func(t *testing.T) {
+		mockRepo.EXPECT().ScanBooks(ctx, "dc.creator", "tolkien, j", 2, 3).Return([]payload.BookIndexTerm{
+			{Value: "Herbert", Count: 2},
+			{Value: "Le Guin", Count: 1},
+			{Value: "Tolkien", Count: 4},
+		}, nil)
+
+		res, err := service.Scan(ctx, payload.SRURequest{ScanClause: `dc.creator = "tolkien, j"`, ResponsePosition: "3", MaximumTerms: "5"})
+		if err != nil {
+			t.Fatalf("catalogService.Scan() error = %v", err)
+		}
+
+		if len(res.Terms) != 3 || res.Terms[2] != (sru.Term{Value: "Tolkien", NumberOfRecords: 4}) {
+			t.Errorf("catalogService.Scan() = %+v", res)
+		}
+	})
+
+	t.Run("diagnostics", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			request payload.SRURequest
+			setup   func()
+			wantURI string
+		}{
+			{"missing scan clause", payload.SRURequest{Operation: "scan"}, nil, "info:srw/diagnostic/1/7"},
+			{"too many terms", payload.SRURequest{ScanClause: "dc.title = a", MaximumTerms: "1000"}, nil, "info:srw/diagnostic/1/6"},
+			{"position past the terms", payload.SRURequest{ScanClause: "dc.title = a", MaximumTerms: "5", ResponsePosition: "7"}, nil, "info:srw/diagnostic/1/120"},
+			{"boolean clause", payload.SRURequest{ScanClause: "dc.title = a or dc.title = b"}, nil, "info:srw/diagnostic/1/10"},
+			{"range relation", payload.SRURequest{ScanClause: "dc.date > 2000"}, nil, "info:srw/diagnostic/1/19"},
+			{"full-text index", payload.SRURequest{ScanClause: "dune"}, func() {
+				mockRepo.EXPECT().ScanBooks(ctx, cql.ServerChoice, "dune", 0, 20).Return(nil, &cql.Error{Err: cql.ErrUnsupportedIndex, Detail: cql.ServerChoice})
+			}, "info:srw/diagnostic/1/16"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if tt.setup != nil {
+					tt.setup()
+				}
+
+				res, err := service.Scan(ctx, tt.request)
+				if err != nil {
+					t.Fatalf("catalogService.Scan() error = %v", err)
+				}
+
+				if len(res.Diagnostics) != 1 || res.Diagnostics[0].URI != tt.wantURI {
+					t.Errorf("catalogService.Scan() diagnostics = %+v, want %s", res.Diagnostics, tt.wantURI)
+				}
+			})
+		}
+	})
+}
+
+func Test_catalogService_Explain(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewCatalogService(NewBookService(mock.NewMockTransactor(ctrl), mock.NewMockBookRepository(ctrl), mock.NewMockAuditRepository(ctrl)), mock.NewMockBookRepository(ctrl))
+
+	ctx := context.Background()
+
+	res, err := service.Explain(ctx, payload.SRURequest{Host: "library.example.com", Port: 443})
+	if err != nil {
+		t.Fatalf("catalogService.Explain() error = %v", err)
+	}
+
+	if len(res.Diagnostics) != 0 || res.Record.Schema != sru.SchemaExplain {
+		t.Fatalf("catalogService.Explain() = %+v", res)
+	}
+
+	for _, want := range []string{
+		"<host>library.example.com</host><port>443</port><database>v1/sru</database>",
+		`<name set="dc">title</name>`,
+		`<name set="bath">isbn</name>`,
+		`<schema identifier="` + sru.SchemaMARCXML + `" name="marcxml">`,
+	} {
+		if !strings.Contains(res.Record.Data.XML, want) {
+			t.Errorf("catalogService.Explain() = %s, want it to contain %s", res.Record.Data.XML, want)
+		}
+	}
+
+	res, err = service.Explain(ctx, payload.SRURequest{Operation: "update"})
+	if err != nil || len(res.Diagnostics) != 1 || res.Diagnostics[0].URI != "info:srw/diagnostic/1/4" {
+		t.Errorf("catalogService.Explain() unsupported operation = %+v, %v", res.Diagnostics, err)
+	}
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"library-backend/errorcustom"
+	"library-backend/internal/auth"
+	"library-backend/internal/model"
+	"library-backend/internal/payload"
+	"library-backend/internal/repository"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+)
+
+type UserService interface {
+	CreateUser(ctx context.Context, request payload.CreateUserRequest) (payload.CreateUserResponse, error)
+	GetUsers(ctx context.Context, request payload.GetUsersRequest) (payload.GetUsersResponse, error)
+	GetUserByID(ctx context.Context, id string) (payload.GetUserByIDResponse, error)
+	UpdateUser(ctx context.Context, request payload.UpdateUserRequest) error
+	DeleteUser(ctx context.Context, request payload.DeleteUserRequest) error
+}
+
+type userService struct {
+	transactor  repository.Transactor
+	userRepo    repository.UserRepository
+	refreshRepo repository.RefreshTokenRepository
+}
+
+func NewUserService(
+	transactor repository.Transactor,
+	userRepo repository.UserRepository,
+	refreshRepo repository.RefreshTokenRepository,
+) UserService {
+	return &userService{
+		transactor:  transactor,
+		userRepo:    userRepo,
+		refreshRepo: refreshRepo,
+	}
+}
+
+func (s *userService) CreateUser(ctx context.Context, request payload.CreateUserRequest) (res payload.CreateUserResponse, err error) {
+	user := request.ToModel()
+
+	if user.Status == "" {
+		user.Status = model.UserStatusActive
+	}
+
+	if user.Role == "" {
+		user.Role = model.UserRoleLibrarian
+	}
+
+	user.PasswordHash, err = auth.HashPassword(request.Password)
+	if err != nil {
+		slog.ErrorContext(ctx, "[UserService][CreateUser] failed to hash password", "error", err)
+		return res, err
+	}
+
+	err = s.userRepo.CreateUser(ctx, user)
+	if err != nil {
+		if strings.Contains(err.Error(), "ERROR: duplicate key value violates unique constraint \"users_email_key\"") {
+			return res, errorcustom.ErrUserAlreadyExists
+		}
+		slog.ErrorContext(ctx, "[UserService][CreateUser] failed to create user", "error", err)
+		return res, err
+	}
+
+	res.ID = user.ID
+
+	return res, nil
+}
+
+func (s *userService) GetUsers(ctx context.Context, request payload.GetUsersRequest) (res payload.GetUsersResponse, err error) {
+	request.Offset = (request.Page - 1) * request.Limit
+
+	users, err := s.userRepo.GetUsers(ctx, request)
+	if err != nil {
+		slog.ErrorContext(ctx, "[UserService][GetUsers] failed to get users", "error", err)
+		return res, err
+	}
+
+	totalCount, err := s.userRepo.GetUsersCount(ctx, request)
+	if err != nil {
+		slog.ErrorContext(ctx, "[UserService][GetUsers] failed to get users count", "error", err)
+		return res, err
+	}
+
+	totalPages := int(math.Ceil(float64(totalCount) / float64(request.Limit)))
+
+	userResponses := make([]payload.UserResponse, len(users))
+	for i, user := range users {
+		userResponses[i] = toUserResponse(user)
+	}
+
+	res.Users = userResponses
+	res.Pagination = payload.Pagination{
+		Page:      request.Page,
+		Limit:     request.Limit,
+		TotalPage: totalPages,
+		TotalItem: totalCount,
+	}
+
+	return res, nil
+}
+
+func (s *userService) GetUserByID(ctx context.Context, id string) (res payload.GetUserByIDResponse, err error) {
+	user, err := s.userRepo.GetUserByID(ctx, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "[UserService][GetUserByID] failed to get user by ID", "error", err, "id", id)
+		return res, err
+	}
+
+	if user == nil {
+		return res, errorcustom.ErrUserNotFound
+	}
+
+	res.UserResponse = toUserResponse(*user)
+
+	return res, nil
+}
+
+// UpdateUser applies a partial update, a new password, a new role or disabling the account also
+// signs the user out of every session.
+func (s *userService) UpdateUser(ctx context.Context, request payload.UpdateUserRequest) (err error) {
+	// an admin demoting or disabling themselves could leave nobody able to manage users
+	if caller, ok := auth.UserFromContext(ctx); ok && strings.EqualFold(caller.ID.String(), request.ID) {
+		if (request.Role != nil && *request.Role != caller.Role) ||
+			(request.Status != nil && *request.Status == model.UserStatusDisabled) {
+			return errorcustom.ErrCannotChangeOwnRole
+		}
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, request.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[UserService][UpdateUser] failed to check user existence", "error", err, "id", request.ID)
+		return err
+	}
+
+	if user == nil {
+		return errorcustom.ErrUserNotFound
+	}
+
+	updates := buildUpdateMap(request)
+	if len(updates) == 0 {
+		return errors.New("no fields to update")
+	}
+
+	// access tokens carry the role, signing out makes the new one apply at the next login
+	signOut := (request.Status != nil && *request.Status == model.UserStatusDisabled) ||
+		(request.Role != nil && *request.Role != user.Role)
+
+	if request.Password != nil {
+		delete(updates, "password")
+
+		updates["password_hash"], err = auth.HashPassword(*request.Password)
+		if err != nil {
+			slog.ErrorContext(ctx, "[UserService][UpdateUser] failed to hash password", "error", err, "id", request.ID)
+			return err
+		}
+
+		signOut = true
+	}
+
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := s.userRepo.UpdateUser(ctx, request.ID, updates)
+		if err != nil {
+			if strings.Contains(err.Error(), "ERROR: duplicate key value violates unique constraint \"users_email_key\"") {
+				return errorcustom.ErrUserAlreadyExists
+			}
+			slog.ErrorContext(ctx, "[UserService][UpdateUser] failed to update user", "error", err, "id", request.ID)
+			return err
+		}
+
+		if !signOut {
+			return nil
+		}
+
+		err = s.refreshRepo.RevokeUserRefreshTokens(ctx, request.ID, time.Now())
+		if err != nil {
+			slog.ErrorContext(ctx, "[UserService][UpdateUser] failed to revoke refresh tokens", "error", err, "id", request.ID)
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (s *userService) DeleteUser(ctx context.Context, request payload.DeleteUserRequest) (err error) {
+	// keep at least the caller around, otherwise the last user could lock everyone out
+	if caller, ok := auth.UserFromContext(ctx); ok && strings.EqualFold(caller.ID.String(), request.ID) {
+		return errorcustom.ErrCannotDeleteSelf
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, request.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "[UserService][DeleteUser] failed to check user existence", "error", err, "id", request.ID)
+		return err
+	}
+
+	if user == nil {
+		return errorcustom.ErrUserNotFound
+	}
+
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := s.userRepo.DeleteUser(ctx, request.ID)
+		if err != nil {
+			slog.ErrorContext(ctx, "[UserService][DeleteUser] failed to delete user", "error", err, "id", request.ID)
+			return err
+		}
+
+		err = s.refreshRepo.RevokeUserRefreshTokens(ctx, request.ID, time.Now())
+		if err != nil {
+			slog.ErrorContext(ctx, "[UserService][DeleteUser] failed to revoke refresh tokens", "error", err, "id", request.ID)
+			return err
+		}
+
+		return nil
+	})
+}
+
+func toUserResponse(user model.User) payload.UserResponse {
+	return payload.UserResponse{
+		ID:          user.ID,
+		Name:        user.Name,
+		Email:       user.Email,
+		Role:        user.Role,
+		Status:      user.Status,
+		LastLoginAt: user.LastLoginAt,
+		CreatedAt:   user.CreatedAt,
+		UpdatedAt:   user.UpdatedAt,
+	}
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"library-backend/errorcustom"
+	"library-backend/internal/auth"
+	"library-backend/internal/model"
+	"library-backend/internal/payload"
+	"library-backend/internal/repository/mock"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+)
+
+func Test_userService_CreateUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	service := NewUserService(mock.NewMockTransactor(ctrl), mockUserRepo, mock.NewMockRefreshTokenRepository(ctrl))
+
+	ctx := context.Background()
+	request := payload.CreateUserRequest{Name: "Admin", Email: "admin@library.test", Password: "correct horse"}
+
+	tests := []struct {
+		name     string
+		mockFunc func()
+		wantErr  error
+	}{
+		{
+			name: "success stores a password hash and defaults to librarian",
+			mockFunc: func() {
+				mockUserRepo.EXPECT().CreateUser(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, user model.User) error {
+					if user.Status != model.UserStatusActive || user.Role != model.UserRoleLibrarian || !auth.CheckPassword(user.PasswordHash, request.Password) {
+						t.Errorf("userService.CreateUser() unexpected user %+v", user)
+					}
+					return nil
+				})
+			},
+		},
+		{
+			name: "duplicate email",
+			mockFunc: func() {
+				mockUserRepo.EXPECT().CreateUser(ctx, gomock.Any()).
+					Return(errors.New("ERROR: duplicate key value violates unique constraint \"users_email_key\" (SQLSTATE 23505)"))
+			},
+			wantErr: errorcustom.ErrUserAlreadyExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+			_, err := service.CreateUser(ctx, request)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("userService.CreateUser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_userService_UpdateUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactor := mock.NewMockTransactor(ctrl)
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockRefreshRepo := mock.NewMockRefreshTokenRepository(ctrl)
+	service := NewUserService(mockTransactor, mockUserRepo, mockRefreshRepo)
+
+	ctx := context.Background()
+	user := &model.User{ID: uuid.New(), Name: "Admin", Role: model.UserRoleLibrarian, Status: model.UserStatusActive}
+	name := "Head Librarian"
+	password := "a new password"
+	admin := model.UserRoleAdmin
+	disabled := model.UserStatusDisabled
+
+	tests := []struct {
+		name     string
+		mockFunc func()
+		request  payload.UpdateUserRequest
+		wantErr  error
+	}{
+		{
+			name: "rename keeps sessions",
+			mockFunc: func() {
+				mockUserRepo.EXPECT().GetUserByID(ctx, user.ID.String()).Return(user, nil)
+				runInTransaction(mockTransactor)
+				mockUserRepo.EXPECT().UpdateUser(ctx, user.ID.String(), map[string]any{"name": name}).Return(nil)
+			},
+			request: payload.UpdateUserRequest{ID: user.ID.String(), Name: &name},
+		},
+		{
+			name: "new password is hashed and signs the user out",
+			mockFunc: func() {
+				mockUserRepo.EXPECT().GetUserByID(ctx, user.ID.String()).Return(user, nil)
+				runInTransaction(mockTransactor)
+				mockUserRepo.EXPECT().UpdateUser(ctx, user.ID.String(), gomock.Any()).DoAndReturn(func(_ context.Context, _ string, updates map[string]any) error {
+					hash, _ := updates["password_hash"].(string)
+					if _, ok := updates["password"]; ok || !auth.CheckPassword(hash, password) {
+						t.Errorf("userService.UpdateUser() unexpected updates %v", updates)
+					}
+					return nil
+				})
+				mockRefreshRepo.EXPECT().RevokeUserRefreshTokens(ctx, user.ID.String(), gomock.Any()).Return(nil)
+			},
+			request: payload.UpdateUserRequest{ID: user.ID.String(), Password: &password},
+		},
+		{
+			name: "new role signs the user out",
+			mockFunc: func() {
+				mockUserRepo.EXPECT().GetUserByID(ctx, user.ID.String()).Return(user, nil)
+				runInTransaction(mockTransactor)
+				mockUserRepo.EXPECT().UpdateUser(ctx, user.ID.String(), map[string]any{"role": admin}).Return(nil)
+				mockRefreshRepo.EXPECT().RevokeUserRefreshTokens(ctx, user.ID.String(), gomock.Any()).Return(nil)
+			},
+			request: payload.UpdateUserRequest{ID: user.ID.String(), Role: &admin},
+		},
+		{
+			name: "user not found",
+			mockFunc: func() {
+				mockUserRepo.EXPECT().GetUserByID(ctx, user.ID.String()).Return(nil, nil)
+			},
+			request: payload.UpdateUserRequest{ID: user.ID.String(), Name: &name},
+			wantErr: errorcustom.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+			err := service.UpdateUser(ctx, tt.request)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("userService.UpdateUser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("cannot change own role or disable yourself", func(t *testing.T) {
+		selfCtx := auth.WithUser(ctx, auth.User{ID: user.ID, Role: user.Role})
+
+		for _, request := range []payload.UpdateUserRequest{
+			{ID: user.ID.String(), Role: &admin},
+			{ID: user.ID.String(), Status: &disabled},
+		} {
+			err := service.UpdateUser(selfCtx, request)
+			if !errors.Is(err, errorcustom.ErrCannotChangeOwnRole) {
+				t.Errorf("userService.UpdateUser() error = %v, wantErr %v", err, errorcustom.ErrCannotChangeOwnRole)
+			}
+		}
+	})
+}
+
+func Test_userService_DeleteUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactor := mock.NewMockTransactor(ctrl)
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockRefreshRepo := mock.NewMockRefreshTokenRepository(ctrl)
+	service := NewUserService(mockTransactor, mockUserRepo, mockRefreshRepo)
+
+	caller := auth.User{ID: uuid.New(), Email: "admin@library.test"}
+	ctx := auth.WithUser(context.Background(), caller)
+	user := &model.User{ID: uuid.New(), Name: "Former Librarian", Status: model.UserStatusActive}
+
+	tests := []struct {
+		name     string
+		mockFunc func()
+		id       string
+		wantErr  error
+	}{
+		{
+			name: "success",
+			mockFunc: func() {
+				mockUserRepo.EXPECT().GetUserByID(ctx, user.ID.String()).Return(user, nil)
+				runInTransaction(mockTransactor)
+				mockUserRepo.EXPECT().DeleteUser(ctx, user.ID.String()).Return(nil)
+				mockRefreshRepo.EXPECT().RevokeUserRefreshTokens(ctx, user.ID.String(), gomock.Any()).Return(nil)
+			},
+			id: user.ID.String(),
+		},
+		{
+			name:     "cannot delete yourself",
+			mockFunc: func() {},
+			id:       caller.ID.String(),
+			wantErr:  errorcustom.ErrCannotDeleteSelf,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+			err := service.DeleteUser(ctx, payload.DeleteUserRequest{ID: tt.id})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("userService.DeleteUser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -59,3 +59,17 @@ func ErrNotFoundResponse(c *fiber.Ctx) error {
 		Message: "Not found",
 	})
 }
+
+func ErrUnauthorizedResponse(c *fiber.Ctx, err string) error {
+	return c.Status(fiber.StatusUnauthorized).JSON(payload.Response{
+		Success: false,
+		Message: err,
+	})
+}
+
+func ErrForbiddenResponse(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusForbidden).JSON(payload.Response{
+		Success: false,
+		Message: "Forbidden",
+	})
+}
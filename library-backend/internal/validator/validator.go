@@ -3,6 +3,7 @@ package validator
 import (
 	"errors"
 	"library-backend/internal/payload"
+	"library-backend/pkg/isbn"
 	"reflect"
 	"strings"
 
@@ -20,6 +21,10 @@ func init() {
 	uni := ut.New(english, english)
 	TranslatorInst, _ = uni.GetTranslator("en")
 	_ = en_translations.RegisterDefaultTranslations(Validate, TranslatorInst)
+	// isbn accepts the same ISBN-10 and ISBN-13 forms the books are normalized from
+	_ = Validate.RegisterValidation("isbn", func(fl validator.FieldLevel) bool {
+		return isbn.Valid(fl.Field().String())
+	})
 	// register tag e.Field() use json tag
 	Validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
 		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
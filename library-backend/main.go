@@ -8,6 +8,15 @@ import "library-backend/cmd"
 // @contact.name Feildrix Liemdra
 // @contact.email feildrixliemdra@gmail.com
 // @BasePath /
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Type "Bearer" followed by a space and the access token from /v1/auth/login.
+
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name Authorization
+// @description Type "ApiKey" followed by a space and an API key from /v1/api-keys.
 func main() {
 	cmd.Start()
 }
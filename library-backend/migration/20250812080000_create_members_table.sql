@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Create members table
+CREATE TABLE IF NOT EXISTS members (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    phone VARCHAR(30) NOT NULL DEFAULT '',
+    address TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Create index
+-- email only has to be unique among live members so a deleted member can sign up again
+CREATE UNIQUE INDEX IF NOT EXISTS members_email_key ON members(email) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_members_name ON members(name);
+CREATE INDEX IF NOT EXISTS idx_members_status ON members(status);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS members;
+DROP INDEX IF EXISTS members_email_key;
+DROP INDEX IF EXISTS idx_members_name;
+DROP INDEX IF EXISTS idx_members_status;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Create loans table
+CREATE TABLE IF NOT EXISTS loans (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    book_id UUID NOT NULL REFERENCES books(id),
+    member_id UUID NOT NULL REFERENCES members(id),
+    borrowed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    due_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    returned_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create index
+-- a book can only be out on a single loan at a time
+CREATE UNIQUE INDEX IF NOT EXISTS loans_active_book_key ON loans(book_id) WHERE returned_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_loans_member_id ON loans(member_id);
+CREATE INDEX IF NOT EXISTS idx_loans_due_at ON loans(due_at) WHERE returned_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS loans;
+DROP INDEX IF EXISTS loans_active_book_key;
+DROP INDEX IF EXISTS idx_loans_member_id;
+DROP INDEX IF EXISTS idx_loans_due_at;
+-- +goose StatementEnd
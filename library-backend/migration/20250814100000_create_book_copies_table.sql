@@ -0,0 +1,53 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Create book copies table
+CREATE TABLE IF NOT EXISTS book_copies (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    book_id UUID NOT NULL REFERENCES books(id) ON DELETE CASCADE,
+    barcode VARCHAR(50) NOT NULL,
+    shelf_location VARCHAR(100) NOT NULL DEFAULT '',
+    condition VARCHAR(20) NOT NULL DEFAULT 'good',
+    status VARCHAR(20) NOT NULL DEFAULT 'available',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Create index
+CREATE UNIQUE INDEX IF NOT EXISTS book_copies_barcode_key ON book_copies(barcode) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_book_copies_book_id_status ON book_copies(book_id, status);
+
+-- every existing book starts with a single copy so circulation keeps working
+INSERT INTO book_copies (book_id, barcode)
+SELECT id, 'LIB-' || UPPER(SUBSTRING(REPLACE(id::text, '-', '') FROM 1 FOR 12))
+FROM books;
+
+-- loans now point at the physical copy that left the shelf
+ALTER TABLE loans ADD COLUMN copy_id UUID REFERENCES book_copies(id);
+
+UPDATE loans l
+SET copy_id = c.id
+FROM book_copies c
+WHERE c.book_id = l.book_id;
+
+ALTER TABLE loans ALTER COLUMN copy_id SET NOT NULL;
+
+UPDATE book_copies c
+SET status = 'on_loan'
+FROM loans l
+WHERE l.copy_id = c.id AND l.returned_at IS NULL;
+
+-- a copy, not the whole book, can only be out on a single loan at a time
+DROP INDEX IF EXISTS loans_active_book_key;
+CREATE UNIQUE INDEX IF NOT EXISTS loans_active_copy_key ON loans(copy_id) WHERE returned_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_loans_book_id ON loans(book_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_loans_book_id;
+DROP INDEX IF EXISTS loans_active_copy_key;
+CREATE UNIQUE INDEX IF NOT EXISTS loans_active_book_key ON loans(book_id) WHERE returned_at IS NULL;
+ALTER TABLE loans DROP COLUMN IF EXISTS copy_id;
+DROP TABLE IF EXISTS book_copies;
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Create holds table
+CREATE TABLE IF NOT EXISTS holds (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    book_id UUID NOT NULL REFERENCES books(id),
+    member_id UUID NOT NULL REFERENCES members(id),
+    copy_id UUID REFERENCES book_copies(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'waiting',
+    placed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ready_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    closed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create index
+-- a member can only queue once per book at a time
+CREATE UNIQUE INDEX IF NOT EXISTS holds_active_member_book_key ON holds(book_id, member_id) WHERE status IN ('waiting', 'ready');
+CREATE INDEX IF NOT EXISTS idx_holds_queue ON holds(book_id, placed_at, id) WHERE status = 'waiting';
+CREATE INDEX IF NOT EXISTS idx_holds_member_id ON holds(member_id);
+CREATE INDEX IF NOT EXISTS idx_holds_expires_at ON holds(expires_at) WHERE status = 'ready';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- copies waiting on the pickup shelf go back to circulation
+UPDATE book_copies SET status = 'available' WHERE status = 'on_hold';
+
+DROP TABLE IF EXISTS holds;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Create fine entries table, a member's balance is the sum of charges minus waivers and payments
+CREATE TABLE IF NOT EXISTS fine_entries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    member_id UUID NOT NULL REFERENCES members(id),
+    loan_id UUID REFERENCES loans(id),
+    entry_type VARCHAR(20) NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create index
+-- a loan is charged once, when it comes back late
+CREATE UNIQUE INDEX IF NOT EXISTS fine_entries_loan_charge_key ON fine_entries(loan_id) WHERE entry_type = 'charge';
+CREATE INDEX IF NOT EXISTS idx_fine_entries_member_id ON fine_entries(member_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS fine_entries;
+-- +goose StatementEnd
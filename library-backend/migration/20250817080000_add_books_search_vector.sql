@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- searchable document of a book, title and ISBN weigh most, then author, then publisher
+ALTER TABLE books ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', COALESCE(title, '')), 'A') ||
+    setweight(to_tsvector('english', COALESCE(isbn, '')), 'A') ||
+    setweight(to_tsvector('english', COALESCE(author, '')), 'B') ||
+    setweight(to_tsvector('english', COALESCE(publisher, '')), 'C')
+) STORED;
+
+-- Create index
+CREATE INDEX IF NOT EXISTS idx_books_search_vector ON books USING GIN(search_vector);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_books_search_vector;
+ALTER TABLE books DROP COLUMN IF EXISTS search_vector;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- backs the default listing order and cursor pagination, which seeks on (updated_at, id)
+CREATE INDEX IF NOT EXISTS idx_books_updated_at_id ON books(updated_at DESC, id DESC) WHERE deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_books_updated_at_id;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'librarian';
+
+-- accounts created before roles existed could manage everything, they stay admins
+UPDATE users SET role = 'admin';
+
+-- Create index
+CREATE INDEX IF NOT EXISTS idx_users_role ON users(role);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_users_role;
+ALTER TABLE users DROP COLUMN IF EXISTS role;
+-- +goose StatementEnd
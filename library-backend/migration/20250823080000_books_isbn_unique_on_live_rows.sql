@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the ISBN only has to be unique among live books, so a book can be created again after it was
+-- deleted. The index keeps the constraint name so duplicate errors read the same.
+ALTER TABLE books DROP CONSTRAINT IF EXISTS books_isbn_key;
+CREATE UNIQUE INDEX IF NOT EXISTS books_isbn_key ON books(isbn) WHERE deleted_at IS NULL;
+
+-- Create index
+CREATE INDEX IF NOT EXISTS idx_books_deleted_isbn ON books(isbn, deleted_at DESC) WHERE deleted_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- fails while a deleted book shares its ISBN with another book, purge those first
+DROP INDEX IF EXISTS idx_books_deleted_isbn;
+DROP INDEX IF EXISTS books_isbn_key;
+ALTER TABLE books ADD CONSTRAINT books_isbn_key UNIQUE (isbn);
+-- +goose StatementEnd
@@ -0,0 +1,63 @@
+-- +goose Up
+-- +goose StatementBegin
+-- canonical ISBN-13 of a book, isbn keeps the form it was entered in for display
+ALTER TABLE books ADD COLUMN IF NOT EXISTS isbn13 VARCHAR(13);
+
+-- existing ISBNs were validated on the way in, an ISBN-10 gets the 978 prefix and a new check digit
+WITH cleaned AS (
+    SELECT id, regexp_replace(upper(isbn), '[^0-9X]', '', 'g') AS digits FROM books
+), prefixed AS (
+    SELECT id, CASE WHEN length(digits) = 10 THEN '978' || left(digits, 9) ELSE digits END AS digits FROM cleaned
+)
+UPDATE books b SET isbn13 = CASE
+    WHEN length(p.digits) = 12 THEN p.digits || ((10 - (
+        SELECT SUM(substr(p.digits, i, 1)::int * CASE WHEN i % 2 = 1 THEN 1 ELSE 3 END)
+        FROM generate_series(1, 12) AS i
+    ) % 10) % 10)::text
+    ELSE p.digits
+END
+FROM prefixed p
+WHERE b.id = p.id;
+
+ALTER TABLE books ALTER COLUMN isbn13 SET NOT NULL;
+
+-- uniqueness moves to the canonical form, this fails while the same live book is stored twice
+-- under different forms, delete one of them first
+DROP INDEX IF EXISTS books_isbn_key;
+DROP INDEX IF EXISTS idx_books_deleted_isbn;
+CREATE UNIQUE INDEX IF NOT EXISTS books_isbn13_key ON books(isbn13) WHERE deleted_at IS NULL;
+
+-- Create index
+CREATE INDEX IF NOT EXISTS idx_books_deleted_isbn13 ON books(isbn13, deleted_at DESC) WHERE deleted_at IS NOT NULL;
+
+-- the search vector also carries the canonical form, so ISBNs are found however they were typed
+DROP INDEX IF EXISTS idx_books_search_vector;
+ALTER TABLE books DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE books ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', COALESCE(title, '')), 'A') ||
+    setweight(to_tsvector('english', COALESCE(isbn, '')), 'A') ||
+    setweight(to_tsvector('english', COALESCE(isbn13, '')), 'A') ||
+    setweight(to_tsvector('english', COALESCE(author, '')), 'B') ||
+    setweight(to_tsvector('english', COALESCE(publisher, '')), 'C')
+) STORED;
+CREATE INDEX IF NOT EXISTS idx_books_search_vector ON books USING GIN(search_vector);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_books_search_vector;
+ALTER TABLE books DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE books ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', COALESCE(title, '')), 'A') ||
+    setweight(to_tsvector('english', COALESCE(isbn, '')), 'A') ||
+    setweight(to_tsvector('english', COALESCE(author, '')), 'B') ||
+    setweight(to_tsvector('english', COALESCE(publisher, '')), 'C')
+) STORED;
+CREATE INDEX IF NOT EXISTS idx_books_search_vector ON books USING GIN(search_vector);
+
+DROP INDEX IF EXISTS idx_books_deleted_isbn13;
+DROP INDEX IF EXISTS books_isbn13_key;
+CREATE UNIQUE INDEX IF NOT EXISTS books_isbn_key ON books(isbn) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_books_deleted_isbn ON books(isbn, deleted_at DESC) WHERE deleted_at IS NOT NULL;
+ALTER TABLE books DROP COLUMN IF EXISTS isbn13;
+-- +goose StatementEnd
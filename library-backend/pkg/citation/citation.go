@@ -0,0 +1,189 @@
+// Package citation formats books as citations for reference managers: BibTeX, RIS and CSL-JSON.
+//
+// A Work holds what a citation of a book needs. Its authors are split into family and given names
+// by ParseNames, from the single author string a catalog usually keeps.
+package citation
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Work is a book to cite.
+type Work struct {
+	// ID identifies the work in the output, the CSL-JSON id and the RIS ID
+	ID        string
+	Title     string
+	Authors   []Name
+	Publisher string
+	Year      int
+	ISBN      string
+}
+
+// Name is the name of an author. A name that cannot be split, such as an organisation, only has a
+// Family name and is kept as is.
+type Name struct {
+	Family string
+	Given  string
+	// Suffix is a generational suffix such as "Jr." or "III"
+	Suffix string
+}
+
+// nameParticles start a family name when they come before it, "Ludwig van Beethoven" is
+// "van Beethoven".
+var nameParticles = map[string]bool{
+	"da": true, "de": true, "del": true, "della": true, "der": true, "di": true, "du": true,
+	"la": true, "le": true, "ten": true, "ter": true, "van": true, "von": true, "zu": true,
+}
+
+var nameSuffixes = map[string]bool{
+	"jr": true, "jr.": true, "sr": true, "sr.": true, "ii": true, "iii": true, "iv": true,
+}
+
+// ParseNames splits an author string into names. Several authors are separated by ";", " and " or
+// " & ". Each name is either inverted, "Bloch, Joshua" or "King, Martin Luther, Jr.", or in direct
+// order, "Joshua Bloch", where the last word is the family name together with any particle before
+// it.
+func ParseNames(authors string) []Name {
+	var parts []string
+	if strings.Contains(authors, ";") {
+		parts = strings.Split(authors, ";")
+	} else {
+		parts = splitAny(authors, " and ", " & ")
+	}
+
+	var names []Name
+	for _, part := range parts {
+		part = strings.Join(strings.Fields(part), " ")
+		if part != "" {
+			names = append(names, parseName(part))
+		}
+	}
+
+	return names
+}
+
+func splitAny(s string, separators ...string) []string {
+	parts := []string{s}
+	for _, separator := range separators {
+		var split []string
+		for _, part := range parts {
+			split = append(split, strings.Split(part, separator)...)
+		}
+		parts = split
+	}
+
+	return parts
+}
+
+func parseName(name string) Name {
+	// inverted: "Family, Given" or "Family, Given, Suffix"
+	if family, rest, ok := strings.Cut(name, ","); ok {
+		given, suffix, _ := strings.Cut(rest, ",")
+		given, suffix = strings.TrimSpace(given), strings.TrimSpace(suffix)
+
+		// "Martin Luther King, Jr." is a direct order name with a suffix
+		if suffix == "" && nameSuffixes[strings.ToLower(given)] {
+			parsed := parseName(family)
+			parsed.Suffix = given
+			return parsed
+		}
+
+		return Name{Family: strings.TrimSpace(family), Given: given, Suffix: suffix}
+	}
+
+	words := strings.Fields(name)
+
+	var suffix string
+	if len(words) > 2 && nameSuffixes[strings.ToLower(words[len(words)-1])] {
+		suffix = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+
+	if len(words) == 1 {
+		return Name{Family: words[0], Suffix: suffix}
+	}
+
+	familyStart := len(words) - 1
+	for familyStart > 1 && nameParticles[strings.ToLower(words[familyStart-1])] {
+		familyStart--
+	}
+
+	return Name{
+		Family: strings.Join(words[familyStart:], " "),
+		Given:  strings.Join(words[:familyStart], " "),
+		Suffix: suffix,
+	}
+}
+
+// Inverted is the name as "Family, Given" or "Family, Suffix, Given", the form BibTeX and RIS read.
+func (n Name) Inverted() string {
+	parts := []string{n.Family}
+	if n.Suffix != "" {
+		parts = append(parts, n.Suffix)
+	}
+	if n.Given != "" {
+		parts = append(parts, n.Given)
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// Key is a citation key for the work, the first author's family name, the year and the first word
+// of the title, as in "bloch2017effective". Only ASCII letters and digits are kept.
+func Key(work Work) string {
+	var b strings.Builder
+
+	if len(work.Authors) > 0 {
+		writeKeyPart(&b, work.Authors[0].Family)
+	}
+
+	if work.Year > 0 {
+		writeKeyPart(&b, strconv.Itoa(work.Year))
+	}
+
+	for _, word := range strings.Fields(work.Title) {
+		if !titleStopWords[strings.ToLower(word)] {
+			writeKeyPart(&b, word)
+			break
+		}
+	}
+
+	if b.Len() == 0 {
+		return "book"
+	}
+
+	return b.String()
+}
+
+var titleStopWords = map[string]bool{"a": true, "an": true, "the": true, "on": true, "of": true}
+
+func writeKeyPart(b *strings.Builder, s string) {
+	for _, r := range strings.ToLower(foldAccents(s)) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+		}
+	}
+}
+
+// foldAccents replaces the common accented Latin letters with their base letter, so "Gödel" keys
+// as "godel" rather than "gdel".
+func foldAccents(s string) string {
+	return accentFolder.Replace(s)
+}
+
+var accentFolder = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ä", "a", "ã", "a", "å", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "ö", "o", "õ", "o", "ø", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n", "ý", "y", "ß", "ss",
+	"Á", "A", "À", "A", "Â", "A", "Ä", "A", "Ã", "A", "Å", "A",
+	"É", "E", "È", "E", "Ê", "E", "Ë", "E",
+	"Í", "I", "Ì", "I", "Î", "I", "Ï", "I",
+	"Ó", "O", "Ò", "O", "Ô", "O", "Ö", "O", "Õ", "O", "Ø", "O",
+	"Ú", "U", "Ù", "U", "Û", "U", "Ü", "U",
+	"Ç", "C", "Ñ", "N", "Ý", "Y",
+)
@@ -0,0 +1,104 @@
+package citation
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseNames(t *testing.T) {
+	tests := []struct {
+		authors string
+		want    []Name
+	}{
+		{"Joshua Bloch", []Name{{Family: "Bloch", Given: "Joshua"}}},
+		{"Bloch, Joshua", []Name{{Family: "Bloch", Given: "Joshua"}}},
+		{"Ludwig van Beethoven", []Name{{Family: "van Beethoven", Given: "Ludwig"}}},
+		{"Martin Luther King, Jr.", []Name{{Family: "King", Given: "Martin Luther", Suffix: "Jr."}}},
+		{"King, Martin Luther, Jr.", []Name{{Family: "King", Given: "Martin Luther", Suffix: "Jr."}}},
+		{"Plato", []Name{{Family: "Plato"}}},
+		{"Brian W. Kernighan and Dennis M. Ritchie", []Name{{Family: "Kernighan", Given: "Brian W."}, {Family: "Ritchie", Given: "Dennis M."}}},
+		{"Gamma, Erich; Helm, Richard", []Name{{Family: "Gamma", Given: "Erich"}, {Family: "Helm", Given: "Richard"}}},
+		{"Abelson & Sussman", []Name{{Family: "Abelson"}, {Family: "Sussman"}}},
+		{"  ", nil},
+	}
+
+	for _, tt := range tests {
+		if got := ParseNames(tt.authors); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseNames(%q) = %+v, want %+v", tt.authors, got, tt.want)
+		}
+	}
+}
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		work Work
+		want string
+	}{
+		{Work{Title: "Effective Java", Authors: ParseNames("Joshua Bloch"), Year: 2017}, "bloch2017effective"},
+		{Work{Title: "The C Programming Language", Authors: ParseNames("Kernighan, Brian"), Year: 1988}, "kernighan1988c"},
+		{Work{Title: "Über Gödel", Authors: ParseNames("Kurt Gödel"), Year: 1931}, "godel1931uber"},
+		{Work{}, "book"},
+	}
+
+	for _, tt := range tests {
+		if got := Key(tt.work); got != tt.want {
+			t.Errorf("Key(%+v) = %q, want %q", tt.work, got, tt.want)
+		}
+	}
+}
+
+func TestBibTeX(t *testing.T) {
+	works := []Work{
+		{ID: "1", Title: "Effective Java", Authors: ParseNames("Joshua Bloch"), Publisher: "Addison-Wesley", Year: 2017, ISBN: "9780134685991"},
+		{ID: "2", Title: "Effective Java 100% & more_", Authors: []Name{{Family: "Bloch", Given: "Joshua"}, {Family: "Apache Software Foundation"}}, Year: 2017},
+	}
+
+	got := BibTeX(works)
+
+	for _, want := range []string{
+		"@book{bloch2017effective,\n  author = {Bloch, Joshua},\n  title = {Effective Java},\n  publisher = {Addison-Wesley},\n  year = {2017},\n  isbn = {9780134685991},\n}\n",
+		"@book{bloch2017effectivea,\n",
+		`title = {Effective Java 100\% \& more\_}`,
+		`author = {Bloch, Joshua and {Apache Software Foundation}}`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("BibTeX() = %s, want it to contain %s", got, want)
+		}
+	}
+}
+
+func TestRIS(t *testing.T) {
+	got := RIS([]Work{{ID: "1", Title: "Why We Can't Wait\nEssays", Authors: ParseNames("Martin Luther King, Jr."), Year: 1964}})
+
+	want := "TY  - BOOK\r\nAU  - King, Jr., Martin Luther\r\nTI  - Why We Can't Wait Essays\r\nPY  - 1964\r\nID  - 1\r\nER  - \r\n"
+	if got != want {
+		t.Errorf("RIS() = %q, want %q", got, want)
+	}
+}
+
+func TestCSLJSON(t *testing.T) {
+	raw, err := CSLJSON([]Work{{ID: "1", Title: "Dune", Authors: ParseNames("Frank Herbert; Plato"), Publisher: "Chilton", Year: 1965, ISBN: "9780441172719"}})
+	if err != nil {
+		t.Fatalf("CSLJSON() error = %v", err)
+	}
+
+	var got []map[string]any
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	want := []map[string]any{{
+		"id":        "1",
+		"type":      "book",
+		"title":     "Dune",
+		"author":    []any{map[string]any{"family": "Herbert", "given": "Frank"}, map[string]any{"literal": "Plato"}},
+		"publisher": "Chilton",
+		"issued":    map[string]any{"date-parts": []any{[]any{1965.0}}},
+		"ISBN":      "9780441172719",
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CSLJSON() = %s, want %+v", raw, want)
+	}
+}
@@ -0,0 +1,183 @@
+package citation
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// BibTeX formats the works as @book entries. Keys come from Key, with "a", "b", ... appended when
+// two works would share one. Values are escaped for LaTeX, non-ASCII letters are kept as UTF-8,
+// which biber and current BibTeX read.
+func BibTeX(works []Work) string {
+	var b strings.Builder
+	used := make(map[string]int)
+
+	for i, work := range works {
+		key := Key(work)
+		if n := used[key]; n > 0 {
+			used[key]++
+			key += keySuffix(n)
+		} else {
+			used[key] = 1
+		}
+
+		if i > 0 {
+			b.WriteString("\n")
+		}
+
+		b.WriteString("@book{" + key + ",\n")
+		writeBibTeXField(&b, "author", bibTeXAuthors(work.Authors))
+		writeBibTeXField(&b, "title", escapeLaTeX(work.Title))
+		writeBibTeXField(&b, "publisher", escapeLaTeX(work.Publisher))
+		if work.Year > 0 {
+			writeBibTeXField(&b, "year", strconv.Itoa(work.Year))
+		}
+		writeBibTeXField(&b, "isbn", escapeLaTeX(work.ISBN))
+		b.WriteString("}\n")
+	}
+
+	return b.String()
+}
+
+// keySuffix tells apart the nth extra work with the same key, "a" for the first, "aa" after "z".
+func keySuffix(n int) string {
+	var suffix string
+	for n--; n >= 0; n = n/26 - 1 {
+		suffix = string(rune('a'+n%26)) + suffix
+	}
+
+	return suffix
+}
+
+func writeBibTeXField(b *strings.Builder, name, value string) {
+	if value == "" {
+		return
+	}
+
+	b.WriteString("  " + name + " = {" + value + "},\n")
+}
+
+// bibTeXAuthors joins the names with "and". An organisation is braced so BibTeX does not split it
+// into first and last names.
+func bibTeXAuthors(names []Name) string {
+	authors := make([]string, len(names))
+	for i, name := range names {
+		if name.Given == "" && name.Suffix == "" && strings.Contains(name.Family, " ") {
+			authors[i] = "{" + escapeLaTeX(name.Family) + "}"
+			continue
+		}
+
+		authors[i] = escapeLaTeX(name.Inverted())
+	}
+
+	return strings.Join(authors, " and ")
+}
+
+var latexEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`&`, `\&`,
+	`%`, `\%`,
+	`$`, `\$`,
+	`#`, `\#`,
+	`_`, `\_`,
+	`~`, `\textasciitilde{}`,
+	`^`, `\textasciicircum{}`,
+)
+
+// escapeLaTeX escapes the characters LaTeX gives a meaning to, and puts the value on one line.
+func escapeLaTeX(s string) string {
+	return latexEscaper.Replace(singleLine(s))
+}
+
+// RIS formats the works as BOOK references.
+func RIS(works []Work) string {
+	var b strings.Builder
+
+	for _, work := range works {
+		writeRISTag(&b, "TY", "BOOK")
+		for _, name := range work.Authors {
+			writeRISTag(&b, "AU", name.Inverted())
+		}
+		writeRISTag(&b, "TI", work.Title)
+		writeRISTag(&b, "PB", work.Publisher)
+		if work.Year > 0 {
+			writeRISTag(&b, "PY", strconv.Itoa(work.Year))
+		}
+		writeRISTag(&b, "SN", work.ISBN)
+		writeRISTag(&b, "ID", work.ID)
+		b.WriteString("ER  - \r\n")
+	}
+
+	return b.String()
+}
+
+// writeRISTag writes a tag line. RIS has no escaping, a value only has to stay on its line.
+func writeRISTag(b *strings.Builder, tag, value string) {
+	value = singleLine(value)
+	if value == "" {
+		return
+	}
+
+	b.WriteString(tag + "  - " + value + "\r\n")
+}
+
+func singleLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// cslItem is a work in CSL-JSON, the format of the Citation Style Language processors.
+type cslItem struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Title     string    `json:"title,omitempty"`
+	Author    []cslName `json:"author,omitempty"`
+	Publisher string    `json:"publisher,omitempty"`
+	Issued    *cslDate  `json:"issued,omitempty"`
+	ISBN      string    `json:"ISBN,omitempty"`
+}
+
+type cslName struct {
+	Family  string `json:"family,omitempty"`
+	Given   string `json:"given,omitempty"`
+	Suffix  string `json:"suffix,omitempty"`
+	Literal string `json:"literal,omitempty"`
+}
+
+type cslDate struct {
+	DateParts [][]int `json:"date-parts"`
+}
+
+// CSLJSON formats the works as a CSL-JSON array.
+func CSLJSON(works []Work) ([]byte, error) {
+	items := make([]cslItem, len(works))
+
+	for i, work := range works {
+		item := cslItem{
+			ID:        work.ID,
+			Type:      "book",
+			Title:     work.Title,
+			Publisher: work.Publisher,
+			ISBN:      work.ISBN,
+		}
+
+		for _, name := range work.Authors {
+			// a name that was not split is kept whole rather than taken for a family name
+			if name.Given == "" && name.Suffix == "" {
+				item.Author = append(item.Author, cslName{Literal: name.Family})
+				continue
+			}
+			item.Author = append(item.Author, cslName{Family: name.Family, Given: name.Given, Suffix: name.Suffix})
+		}
+
+		if work.Year > 0 {
+			item.Issued = &cslDate{DateParts: [][]int{{work.Year}}}
+		}
+
+		items[i] = item
+	}
+
+	return json.MarshalIndent(items, "", "  ")
+}
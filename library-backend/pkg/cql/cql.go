@@ -0,0 +1,388 @@
+// Package cql parses queries in the Contextual Query Language, the query language of SRU, and
+// translates them into SQL conditions.
+//
+// A query is a tree of search clauses joined by booleans, optionally followed by sort keys:
+//
+//	dc.title any "go rust" and dc.date > 2010 sortBy dc.title/sort.descending
+//
+// Parse reads a query into that tree. A Schema maps the index names of a query onto columns and
+// turns the tree into squirrel conditions.
+package cql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ServerChoice is the index of a search term given without one, the server picks the fields it
+// searches.
+const ServerChoice = "cql.serverChoice"
+
+// Query is a parsed CQL query.
+type Query struct {
+	Root     Node
+	SortKeys []SortKey
+}
+
+// Node is a *Boolean or a *SearchClause.
+type Node interface {
+	node()
+}
+
+// Boolean joins two parts of a query with and, or, not or prox.
+type Boolean struct {
+	// Op is the lower-case operator
+	Op        string
+	Modifiers []Modifier
+	Left      Node
+	Right     Node
+}
+
+// SearchClause searches an index for a term.
+type SearchClause struct {
+	Index    string
+	Relation Relation
+	// Term is the search term as written, without its quotes and with its backslash escapes
+	Term string
+}
+
+// Relation compares an index with a term, either a symbol such as "=" or "<>" or a lower-case name
+// such as "any".
+type Relation struct {
+	Comparitor string
+	Modifiers  []Modifier
+}
+
+// Modifier refines a relation, a boolean or a sort key, as in "/sort.descending" or "/distance<3".
+// Its Comparitor and Value are only set when it has a value.
+type Modifier struct {
+	// Name is lower-case
+	Name       string
+	Comparitor string
+	Value      string
+}
+
+// SortKey is an index the results are sorted on.
+type SortKey struct {
+	Index     string
+	Modifiers []Modifier
+}
+
+func (*Boolean) node()      {}
+func (*SearchClause) node() {}
+
+// SyntaxError is a query that is not valid CQL.
+type SyntaxError struct {
+	// Pos is the byte offset in the query the error was found at
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("cql: %s at offset %d", e.Msg, e.Pos)
+}
+
+var booleans = map[string]bool{"and": true, "or": true, "not": true, "prox": true}
+
+// Parse parses a CQL 1.2 query. Booleans have equal precedence and group from the left, so
+// "a or b and c" is "(a or b) and c". Prefix assignments, such as >dc="info:srw/cql-context-set/1/dc-v1.1",
+// are read but not kept, index names are always resolved by their own prefix.
+func Parse(query string) (*Query, error) {
+	tokens, err := lex(query)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+
+	root, err := p.query()
+	if err != nil {
+		return nil, err
+	}
+
+	q := &Query{Root: root}
+
+	if p.isWord("sortby") {
+		p.next()
+		if q.SortKeys, err = p.sortKeys(); err != nil {
+			return nil, err
+		}
+	}
+
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, p.errorf(t, "unexpected %q", t.text)
+	}
+
+	return q, nil
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokLParen
+	tokRParen
+	tokSlash
+	tokComparitor
+	tokWord
+	tokString
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// wordBreaks are the characters that end an unquoted word.
+const wordBreaks = " \t\r\n()/=<>\""
+
+func lex(s string) ([]token, error) {
+	var tokens []token
+
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokLParen, text: "(", pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokRParen, text: ")", pos: i})
+			i++
+		case c == '/':
+			tokens = append(tokens, token{kind: tokSlash, text: "/", pos: i})
+			i++
+		case c == '=' || c == '<' || c == '>':
+			n := 1
+			if i+1 < len(s) {
+				switch s[i : i+2] {
+				case "==", "<=", ">=", "<>":
+					n = 2
+				}
+			}
+			tokens = append(tokens, token{kind: tokComparitor, text: s[i : i+n], pos: i})
+			i += n
+		case c == '"':
+			j := i + 1
+			for j < len(s) && s[j] != '"' {
+				if s[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(s) {
+				return nil, &SyntaxError{Pos: i, Msg: "unterminated quoted term"}
+			}
+			tokens = append(tokens, token{kind: tokString, text: s[i+1 : j], pos: i})
+			i = j + 1
+		default:
+			j := i
+			for j < len(s) && !strings.ContainsRune(wordBreaks, rune(s[j])) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokWord, text: s[i:j], pos: i})
+			i = j
+		}
+	}
+
+	return append(tokens, token{kind: tokEOF, pos: len(s)}), nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+
+	return t
+}
+
+func (p *parser) isWord(word string) bool {
+	t := p.peek()
+	return t.kind == tokWord && strings.EqualFold(t.text, word)
+}
+
+func (p *parser) isComparitor(comparitor string) bool {
+	t := p.peek()
+	return t.kind == tokComparitor && t.text == comparitor
+}
+
+func (p *parser) errorf(t token, format string, args ...any) error {
+	return &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func isTerm(t token) bool {
+	return t.kind == tokWord || t.kind == tokString
+}
+
+// query is a scoped clause after any prefix assignments, the top of a query or of a parenthesised
+// part of one.
+func (p *parser) query() (Node, error) {
+	for p.isComparitor(">") {
+		p.next()
+		if t := p.next(); !isTerm(t) {
+			return nil, p.errorf(t, "expected a context set identifier")
+		}
+		if p.isComparitor("=") {
+			p.next()
+			if t := p.next(); !isTerm(t) {
+				return nil, p.errorf(t, "expected a context set identifier")
+			}
+		}
+	}
+
+	return p.scopedClause()
+}
+
+func (p *parser) scopedClause() (Node, error) {
+	left, err := p.searchClause()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t := p.peek()
+		if t.kind != tokWord || !booleans[strings.ToLower(t.text)] {
+			return left, nil
+		}
+		p.next()
+
+		modifiers, err := p.modifiers()
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := p.searchClause()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &Boolean{Op: strings.ToLower(t.text), Modifiers: modifiers, Left: left, Right: right}
+	}
+}
+
+func (p *parser) searchClause() (Node, error) {
+	t := p.next()
+
+	if t.kind == tokLParen {
+		node, err := p.query()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, p.errorf(closing, "expected )")
+		}
+		return node, nil
+	}
+
+	if !isTerm(t) {
+		return nil, p.errorf(t, "expected a search term")
+	}
+
+	// a word followed by a relation is an index, anything else is a term on its own
+	if t.kind != tokWord || !p.startsRelation() {
+		return &SearchClause{Index: ServerChoice, Relation: Relation{Comparitor: "="}, Term: t.text}, nil
+	}
+
+	relation, err := p.relation()
+	if err != nil {
+		return nil, err
+	}
+
+	term := p.next()
+	if !isTerm(term) {
+		return nil, p.errorf(term, "expected a search term")
+	}
+
+	return &SearchClause{Index: t.text, Relation: relation, Term: term.text}, nil
+}
+
+func (p *parser) startsRelation() bool {
+	t := p.peek()
+
+	switch t.kind {
+	case tokComparitor:
+		return true
+	case tokWord:
+		word := strings.ToLower(t.text)
+		return !booleans[word] && word != "sortby"
+	default:
+		return false
+	}
+}
+
+func (p *parser) relation() (Relation, error) {
+	t := p.next()
+
+	comparitor := t.text
+	if t.kind == tokWord {
+		comparitor = strings.ToLower(comparitor)
+	}
+
+	modifiers, err := p.modifiers()
+	if err != nil {
+		return Relation{}, err
+	}
+
+	return Relation{Comparitor: comparitor, Modifiers: modifiers}, nil
+}
+
+func (p *parser) modifiers() ([]Modifier, error) {
+	var modifiers []Modifier
+
+	for p.peek().kind == tokSlash {
+		p.next()
+
+		name := p.next()
+		if name.kind != tokWord {
+			return nil, p.errorf(name, "expected a modifier name")
+		}
+
+		modifier := Modifier{Name: strings.ToLower(name.text)}
+
+		if p.peek().kind == tokComparitor {
+			modifier.Comparitor = p.next().text
+
+			value := p.next()
+			if !isTerm(value) {
+				return nil, p.errorf(value, "expected a modifier value")
+			}
+			modifier.Value = value.text
+		}
+
+		modifiers = append(modifiers, modifier)
+	}
+
+	return modifiers, nil
+}
+
+func (p *parser) sortKeys() ([]SortKey, error) {
+	var keys []SortKey
+
+	for isTerm(p.peek()) {
+		index := p.next()
+
+		modifiers, err := p.modifiers()
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, SortKey{Index: index.text, Modifiers: modifiers})
+	}
+
+	if len(keys) == 0 {
+		return nil, p.errorf(p.peek(), "expected a sort key")
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,200 @@
+package cql
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		query string
+		want  *Query
+	}{
+		{
+			query: `dune`,
+			want:  &Query{Root: &SearchClause{Index: ServerChoice, Relation: Relation{Comparitor: "="}, Term: "dune"}},
+		},
+		{
+			query: `dc.title any "go rust" AND dc.date>2010`,
+			want: &Query{Root: &Boolean{
+				Op:    "and",
+				Left:  &SearchClause{Index: "dc.title", Relation: Relation{Comparitor: "any"}, Term: "go rust"},
+				Right: &SearchClause{Index: "dc.date", Relation: Relation{Comparitor: ">"}, Term: "2010"},
+			}},
+		},
+		{
+			// booleans group from the left, parentheses group first
+			query: `a or b and (c not d)`,
+			want: &Query{Root: &Boolean{
+				Op: "and",
+				Left: &Boolean{
+					Op:    "or",
+					Left:  &SearchClause{Index: ServerChoice, Relation: Relation{Comparitor: "="}, Term: "a"},
+					Right: &SearchClause{Index: ServerChoice, Relation: Relation{Comparitor: "="}, Term: "b"},
+				},
+				Right: &Boolean{
+					Op:    "not",
+					Left:  &SearchClause{Index: ServerChoice, Relation: Relation{Comparitor: "="}, Term: "c"},
+					Right: &SearchClause{Index: ServerChoice, Relation: Relation{Comparitor: "="}, Term: "d"},
+				},
+			}},
+		},
+		{
+			query: `>dc="info:srw/cql-context-set/1/dc-v1.1" title ==/respectCase "say \"hi\"" prox/distance<3 x sortBy dc.date/sort.descending title`,
+			want: &Query{
+				Root: &Boolean{
+					Op:        "prox",
+					Modifiers: []Modifier{{Name: "distance", Comparitor: "<", Value: "3"}},
+					Left:      &SearchClause{Index: "title", Relation: Relation{Comparitor: "==", Modifiers: []Modifier{{Name: "respectcase"}}}, Term: `say \"hi\"`},
+					Right:     &SearchClause{Index: ServerChoice, Relation: Relation{Comparitor: "="}, Term: "x"},
+				},
+				SortKeys: []SortKey{{Index: "dc.date", Modifiers: []Modifier{{Name: "sort.descending"}}}, {Index: "title"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.query)
+		if err != nil {
+			t.Errorf("Parse(%q) error = %v", tt.query, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestParse_syntaxErrors(t *testing.T) {
+	for _, query := range []string{``, `title =`, `"unterminated`, `(a or b`, `a and`, `a b`, `a sortBy`, `a)`, `title =/ x`} {
+		_, err := Parse(query)
+
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("Parse(%q) error = %v, want a *SyntaxError", query, err)
+		}
+	}
+}
+
+var testSchema = Schema{
+	DefaultSet:       "dc",
+	TextSearchConfig: "english",
+	Indexes: map[string]Index{
+		"cql.serverchoice": {Column: "search_vector", Type: FullText},
+		"dc.title":         {Column: "title", Type: Text},
+		"dc.date":          {Column: "year", Type: Number},
+		"dc.subject":       {Column: "category", Type: Exact},
+		"dc.identifier": {Column: "isbn", Type: Exact, Normalize: func(term string) (string, error) {
+			if len(term) != 13 {
+				return "", errors.New("not an ISBN-13")
+			}
+			return term, nil
+		}},
+	},
+}
+
+func TestSchema_Where(t *testing.T) {
+	tests := []struct {
+		query    string
+		wantSQL  string
+		wantArgs []any
+	}{
+		{`dune`, `search_vector @@ plainto_tsquery('english', $1)`, []any{"dune"}},
+		{`dc.title adj "c++ prim*"`, `title ~* $1`, []any{`(^|\W)c\+\+\s+prim\w*(\W|$)`}},
+		{`title = "^the?"`, `title ~* $1`, []any{`^the\w(\W|$)`}},
+		{`title any "go rust"`, `(title ~* $1 OR title ~* $2)`, []any{`(^|\W)go(\W|$)`, `(^|\W)rust(\W|$)`}},
+		{`title == "100% *"`, `title LIKE $1`, []any{`100\% %`}},
+		{`dc.date within "1990 2000" and subject <> novel`, `(year BETWEEN $1 AND $2 AND category <> $3)`, []any{1990, 2000, "novel"}},
+		{`dc.date >= 2010 not dc.subject any "horror mystery"`, `(year >= $1 AND NOT ((category = $2 OR category = $3)))`, []any{2010, "horror", "mystery"}},
+		{`dc.identifier = 9780134685991`, `isbn = $1`, []any{"9780134685991"}},
+		{`cql.allRecords = 1`, `TRUE`, nil},
+	}
+
+	for _, tt := range tests {
+		query, err := Parse(tt.query)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", tt.query, err)
+		}
+
+		where, err := testSchema.Where(query.Root)
+		if err != nil {
+			t.Errorf("Schema.Where(%q) error = %v", tt.query, err)
+			continue
+		}
+
+		sql, args, err := where.ToSql()
+		if err != nil {
+			t.Fatalf("ToSql() error = %v", err)
+		}
+
+		// the conditions are built with ? placeholders, as squirrel nests them
+		sql, _ = sq.Dollar.ReplacePlaceholders(sql)
+		if sql != tt.wantSQL || !reflect.DeepEqual(args, tt.wantArgs) {
+			t.Errorf("Schema.Where(%q) = %s %v, want %s %v", tt.query, sql, args, tt.wantSQL, tt.wantArgs)
+		}
+	}
+}
+
+func TestSchema_Where_errors(t *testing.T) {
+	tests := []struct {
+		query      string
+		wantErr    error
+		wantDetail string
+	}{
+		{`dc.author = x`, ErrUnsupportedIndex, "dc.author"},
+		{`title within "a b"`, ErrUnsupportedRelation, "within"},
+		{`title =/stem x`, ErrUnsupportedRelationModifier, "stem"},
+		{`a prox b`, ErrUnsupportedBoolean, "prox"},
+		{`a and/distance<3 b`, ErrUnsupportedBooleanModifier, "distance"},
+		{`dune*`, ErrUnsupportedMasking, "dune*"},
+		{`dc.date = 20th`, ErrInvalidTerm, "20th"},
+		{`dc.identifier = 123`, ErrInvalidTerm, "123"},
+		{`title = ""`, ErrEmptyTerm, "title"},
+	}
+
+	for _, tt := range tests {
+		query, err := Parse(tt.query)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", tt.query, err)
+		}
+
+		_, err = testSchema.Where(query.Root)
+
+		var cqlErr *Error
+		if !errors.Is(err, tt.wantErr) || !errors.As(err, &cqlErr) || cqlErr.Detail != tt.wantDetail {
+			t.Errorf("Schema.Where(%q) error = %v, want %v for %s", tt.query, err, tt.wantErr, tt.wantDetail)
+		}
+	}
+}
+
+func TestSchema_OrderBy(t *testing.T) {
+	query, err := Parse(`x sortBy date/sort.descending dc.title`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	order, err := testSchema.OrderBy(query.SortKeys)
+	if err != nil || !reflect.DeepEqual(order, []string{"year DESC", "title ASC"}) {
+		t.Errorf("Schema.OrderBy() = %v, %v", order, err)
+	}
+
+	for _, query := range []string{`x sortBy cql.serverChoice`, `x sortBy title/sort.missingLow`, `x sortBy author`} {
+		parsed, err := Parse(query)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := testSchema.OrderBy(parsed.SortKeys); !errors.Is(err, ErrUnsupportedSort) {
+			t.Errorf("Schema.OrderBy(%q) error = %v, want %v", query, err, ErrUnsupportedSort)
+		}
+	}
+}
+
+func TestUnescape(t *testing.T) {
+	if got := Unescape(`say \"hi\" \* \\`); got != `say "hi" * \` {
+		t.Errorf("Unescape() = %q", got)
+	}
+}
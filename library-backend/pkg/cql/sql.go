@@ -0,0 +1,472 @@
+package cql
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// the parts of a valid query a Schema cannot run, always wrapped in an *Error
+var (
+	ErrUnsupportedIndex            = errors.New("unsupported index")
+	ErrUnsupportedRelation         = errors.New("unsupported relation")
+	ErrUnsupportedRelationModifier = errors.New("unsupported relation modifier")
+	ErrUnsupportedBoolean          = errors.New("unsupported boolean operator")
+	ErrUnsupportedBooleanModifier  = errors.New("unsupported boolean modifier")
+	ErrUnsupportedMasking          = errors.New("masking characters not supported")
+	ErrEmptyTerm                   = errors.New("empty term unsupported")
+	ErrInvalidTerm                 = errors.New("term in invalid format for index or relation")
+	ErrUnsupportedSort             = errors.New("unsupported sort key")
+)
+
+// Error is a part of a query a Schema cannot run. Err is one of the Err values above and Detail
+// names the part of the query, such as the index.
+type Error struct {
+	Err    error
+	Detail string
+}
+
+func (e *Error) Error() string {
+	return "cql: " + e.Err.Error() + ": " + e.Detail
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// IndexType is how the terms of an index are matched against its column.
+type IndexType int
+
+const (
+	// Text indexes match words, case insensitively, within a text column. "=" and "adj" match the
+	// words of the term next to each other, "any" and "all" match any or all of them.
+	Text IndexType = iota
+	// Exact indexes hold whole values, such as identifiers and codes.
+	Exact
+	// Number indexes hold integers, "within" takes a range such as "1990 2000".
+	Number
+	// FullText indexes are a tsvector column matched with the Schema's text search configuration.
+	FullText
+)
+
+// Index is the column an index name of a query searches.
+type Index struct {
+	// Column is a column or an expression such as "id::text"
+	Column string
+	Type   IndexType
+	// Normalize turns a term of an Exact index into the form its column holds, an error makes the
+	// term invalid
+	Normalize func(term string) (string, error)
+}
+
+// Schema maps the index names of queries onto columns.
+type Schema struct {
+	// DefaultSet is the context set of index names given without one, "dc" reads "title" as "dc.title"
+	DefaultSet string
+	// Indexes are keyed by their lower-case name with its context set, such as "dc.title"
+	Indexes map[string]Index
+	// TextSearchConfig is the configuration the terms of FullText indexes are parsed with
+	TextSearchConfig string
+}
+
+// allRecords is the index of the cql context set that matches every record, as in
+// "cql.allRecords = 1".
+const allRecords = "cql.allrecords"
+
+// Index returns the index a query names, resolving names without a context set with DefaultSet.
+func (s Schema) Index(name string) (Index, bool) {
+	name = strings.ToLower(name)
+	if !strings.Contains(name, ".") && s.DefaultSet != "" {
+		name = strings.ToLower(s.DefaultSet) + "." + name
+	}
+
+	index, ok := s.Indexes[name]
+	return index, ok
+}
+
+// Where turns a query tree into a condition, failing with an *Error for the first part of it the
+// schema cannot run.
+func (s Schema) Where(node Node) (sq.Sqlizer, error) {
+	switch n := node.(type) {
+	case *Boolean:
+		if len(n.Modifiers) > 0 {
+			return nil, &Error{Err: ErrUnsupportedBooleanModifier, Detail: n.Modifiers[0].Name}
+		}
+
+		left, err := s.Where(n.Left)
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := s.Where(n.Right)
+		if err != nil {
+			return nil, err
+		}
+
+		switch n.Op {
+		case "and":
+			return sq.And{left, right}, nil
+		case "or":
+			return sq.Or{left, right}, nil
+		case "not":
+			return sq.And{left, sq.Expr("NOT (?)", right)}, nil
+		default:
+			return nil, &Error{Err: ErrUnsupportedBoolean, Detail: n.Op}
+		}
+	case *SearchClause:
+		return s.searchClause(n)
+	default:
+		return nil, &Error{Err: ErrUnsupportedIndex, Detail: "unknown query node"}
+	}
+}
+
+func (s Schema) searchClause(clause *SearchClause) (sq.Sqlizer, error) {
+	if strings.EqualFold(clause.Index, allRecords) {
+		return sq.Expr("TRUE"), nil
+	}
+
+	index, ok := s.Index(clause.Index)
+	if !ok {
+		return nil, &Error{Err: ErrUnsupportedIndex, Detail: clause.Index}
+	}
+
+	if len(clause.Relation.Modifiers) > 0 {
+		return nil, &Error{Err: ErrUnsupportedRelationModifier, Detail: clause.Relation.Modifiers[0].Name}
+	}
+
+	term := parseTerm(clause.Term)
+	words := term.words()
+	if len(words) == 0 {
+		return nil, &Error{Err: ErrEmptyTerm, Detail: clause.Index}
+	}
+
+	relation := strings.TrimPrefix(clause.Relation.Comparitor, "cql.")
+
+	// any and all match each word of the term on its own
+	if relation == "any" || relation == "all" {
+		conditions := make([]sq.Sqlizer, len(words))
+		for i, word := range words {
+			condition, err := s.match(index, "=", word)
+			if err != nil {
+				return nil, err
+			}
+			conditions[i] = condition
+		}
+
+		if relation == "any" {
+			return sq.Or(conditions), nil
+		}
+		return sq.And(conditions), nil
+	}
+
+	return s.match(index, relation, term)
+}
+
+func (s Schema) match(index Index, relation string, t term) (sq.Sqlizer, error) {
+	switch index.Type {
+	case Text:
+		return matchText(index.Column, relation, t)
+	case Exact:
+		return matchExact(index, relation, t)
+	case Number:
+		return matchNumber(index.Column, relation, t)
+	default:
+		return s.matchFullText(index.Column, relation, t)
+	}
+}
+
+func matchText(column, relation string, t term) (sq.Sqlizer, error) {
+	switch relation {
+	case "=", "adj":
+		return sq.Expr(column+" ~* ?", t.wordsRegexp()), nil
+	case "==":
+		if t.masked() {
+			return sq.Expr(column+" LIKE ?", t.likePattern()), nil
+		}
+		return sq.Eq{column: t.literal()}, nil
+	case "<>":
+		if t.masked() {
+			return sq.Expr(column+" NOT LIKE ?", t.likePattern()), nil
+		}
+		return sq.NotEq{column: t.literal()}, nil
+	case "<", ">", "<=", ">=":
+		if t.masked() {
+			return nil, &Error{Err: ErrUnsupportedMasking, Detail: t.literal()}
+		}
+		return sq.Expr(column+" "+relation+" ?", t.literal()), nil
+	default:
+		return nil, &Error{Err: ErrUnsupportedRelation, Detail: relation}
+	}
+}
+
+func matchExact(index Index, relation string, t term) (sq.Sqlizer, error) {
+	// masked terms are matched as patterns, as written
+	if t.masked() {
+		switch relation {
+		case "=", "==", "adj":
+			return sq.Expr(index.Column+" LIKE ?", t.likePattern()), nil
+		case "<>":
+			return sq.Expr(index.Column+" NOT LIKE ?", t.likePattern()), nil
+		default:
+			return nil, &Error{Err: ErrUnsupportedMasking, Detail: t.literal()}
+		}
+	}
+
+	value := t.literal()
+	if index.Normalize != nil {
+		normalized, err := index.Normalize(value)
+		if err != nil {
+			return nil, &Error{Err: ErrInvalidTerm, Detail: value}
+		}
+		value = normalized
+	}
+
+	switch relation {
+	case "=", "==", "adj":
+		return sq.Eq{index.Column: value}, nil
+	case "<>":
+		return sq.NotEq{index.Column: value}, nil
+	case "<", ">", "<=", ">=":
+		return sq.Expr(index.Column+" "+relation+" ?", value), nil
+	default:
+		return nil, &Error{Err: ErrUnsupportedRelation, Detail: relation}
+	}
+}
+
+func matchNumber(column, relation string, t term) (sq.Sqlizer, error) {
+	if t.masked() {
+		return nil, &Error{Err: ErrUnsupportedMasking, Detail: t.literal()}
+	}
+
+	if relation == "within" {
+		words := t.words()
+		if len(words) != 2 {
+			return nil, &Error{Err: ErrInvalidTerm, Detail: t.literal()}
+		}
+
+		low, lowErr := strconv.Atoi(words[0].literal())
+		high, highErr := strconv.Atoi(words[1].literal())
+		if lowErr != nil || highErr != nil {
+			return nil, &Error{Err: ErrInvalidTerm, Detail: t.literal()}
+		}
+
+		return sq.Expr(column+" BETWEEN ? AND ?", low, high), nil
+	}
+
+	value, err := strconv.Atoi(t.literal())
+	if err != nil {
+		return nil, &Error{Err: ErrInvalidTerm, Detail: t.literal()}
+	}
+
+	switch relation {
+	case "=", "==", "adj":
+		return sq.Eq{column: value}, nil
+	case "<>":
+		return sq.NotEq{column: value}, nil
+	case "<":
+		return sq.Lt{column: value}, nil
+	case ">":
+		return sq.Gt{column: value}, nil
+	case "<=":
+		return sq.LtOrEq{column: value}, nil
+	case ">=":
+		return sq.GtOrEq{column: value}, nil
+	default:
+		return nil, &Error{Err: ErrUnsupportedRelation, Detail: relation}
+	}
+}
+
+func (s Schema) matchFullText(column, relation string, t term) (sq.Sqlizer, error) {
+	if t.masked() {
+		return nil, &Error{Err: ErrUnsupportedMasking, Detail: t.literal()}
+	}
+
+	config := "'" + s.TextSearchConfig + "'"
+
+	switch relation {
+	case "=":
+		return sq.Expr(column+" @@ plainto_tsquery("+config+", ?)", t.literal()), nil
+	case "adj":
+		return sq.Expr(column+" @@ phraseto_tsquery("+config+", ?)", t.literal()), nil
+	default:
+		return nil, &Error{Err: ErrUnsupportedRelation, Detail: relation}
+	}
+}
+
+// OrderBy returns the ORDER BY expressions of sort keys, ascending unless a key has the
+// sort.descending modifier.
+func (s Schema) OrderBy(keys []SortKey) ([]string, error) {
+	var order []string
+
+	for _, key := range keys {
+		index, ok := s.Index(key.Index)
+		if !ok || index.Type == FullText {
+			return nil, &Error{Err: ErrUnsupportedSort, Detail: key.Index}
+		}
+
+		direction := "ASC"
+		for _, modifier := range key.Modifiers {
+			switch strings.TrimPrefix(modifier.Name, "sort.") {
+			case "ascending":
+				direction = "ASC"
+			case "descending":
+				direction = "DESC"
+			default:
+				return nil, &Error{Err: ErrUnsupportedSort, Detail: key.Index + "/" + modifier.Name}
+			}
+		}
+
+		order = append(order, index.Column+" "+direction)
+	}
+
+	return order, nil
+}
+
+// Unescape returns a term with its backslash escapes resolved, masking characters are taken as
+// written.
+func Unescape(s string) string {
+	return parseTerm(s).literal()
+}
+
+// term is a search term as characters, with the unescaped masking characters *, ? and ^ marked.
+type term []termChar
+
+type termChar struct {
+	r    rune
+	mask bool
+}
+
+func parseTerm(s string) term {
+	var t term
+	escaped := false
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			t = append(t, termChar{r: r})
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '*' || r == '?' || r == '^':
+			t = append(t, termChar{r: r, mask: true})
+		default:
+			t = append(t, termChar{r: r})
+		}
+	}
+
+	// a trailing backslash escapes nothing and is kept
+	if escaped {
+		t = append(t, termChar{r: '\\'})
+	}
+
+	return t
+}
+
+func (t term) masked() bool {
+	for _, c := range t {
+		if c.mask {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t term) literal() string {
+	var b strings.Builder
+	for _, c := range t {
+		b.WriteRune(c.r)
+	}
+
+	return b.String()
+}
+
+func (t term) words() []term {
+	var words []term
+	var word term
+
+	for _, c := range t {
+		if !c.mask && unicode.IsSpace(c.r) {
+			if len(word) > 0 {
+				words = append(words, word)
+			}
+			word = nil
+			continue
+		}
+		word = append(word, c)
+	}
+
+	if len(word) > 0 {
+		words = append(words, word)
+	}
+
+	return words
+}
+
+// wordsRegexp is a PostgreSQL regular expression matching the words of the term next to each other.
+// * and ? mask any number of word characters or a single one, a ^ at the start or end anchors the
+// term to the start or end of the value.
+func (t term) wordsRegexp() string {
+	var b strings.Builder
+
+	words := t.words()
+	first, last := words[0], words[len(words)-1]
+
+	if first[0].mask && first[0].r == '^' {
+		b.WriteString("^")
+	} else {
+		b.WriteString(`(^|\W)`)
+	}
+
+	for i, word := range words {
+		if i > 0 {
+			b.WriteString(`\s+`)
+		}
+
+		for _, c := range word {
+			switch {
+			case !c.mask:
+				b.WriteString(regexp.QuoteMeta(string(c.r)))
+			case c.r == '*':
+				b.WriteString(`\w*`)
+			case c.r == '?':
+				b.WriteString(`\w`)
+			}
+		}
+	}
+
+	if c := last[len(last)-1]; c.mask && c.r == '^' {
+		b.WriteString("$")
+	} else {
+		b.WriteString(`(\W|$)`)
+	}
+
+	return b.String()
+}
+
+// likePattern is a LIKE pattern of the whole term, * and ? masking any number of characters or a
+// single one.
+func (t term) likePattern() string {
+	var b strings.Builder
+
+	for _, c := range t {
+		switch {
+		case c.mask && c.r == '*':
+			b.WriteString("%")
+		case c.mask && c.r == '?':
+			b.WriteString("_")
+		case c.mask:
+			// a LIKE pattern is anchored at both ends already
+		case c.r == '%' || c.r == '_' || c.r == '\\':
+			b.WriteString(`\` + string(c.r))
+		default:
+			b.WriteRune(c.r)
+		}
+	}
+
+	return b.String()
+}
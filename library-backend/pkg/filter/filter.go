@@ -0,0 +1,377 @@
+// Package filter parses filter expressions, the conditions of a list API written in a small
+// language of comparisons joined by and, or and not:
+//
+//	category in ('fantasy', 'novel') and year_of_publication >= 2000 or author ~ 'tolkien'
+//
+// Parse reads an expression into a tree, and a Schema checks it against the fields a list can be
+// filtered by before it is turned into SQL.
+package filter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Operators of a comparison.
+const (
+	OpEq         = "="
+	OpNotEq      = "!="
+	OpLt         = "<"
+	OpLtOrEq     = "<="
+	OpGt         = ">"
+	OpGtOrEq     = ">="
+	OpContains   = "~"
+	OpNotContain = "!~"
+	OpIn         = "in"
+	OpNotIn      = "not in"
+)
+
+// maxDepth bounds the nesting of an expression, so a hostile one cannot exhaust the stack.
+const maxDepth = 32
+
+// Expr is a *Logical, a *Not or a *Comparison.
+type Expr interface {
+	expr()
+}
+
+// Logical joins two expressions with and or or.
+type Logical struct {
+	// Op is "and" or "or"
+	Op    string
+	Left  Expr
+	Right Expr
+}
+
+// Not negates an expression.
+type Not struct {
+	Expr Expr
+}
+
+// Comparison compares a field with one value, or with a list of them for in and not in.
+type Comparison struct {
+	Field  string
+	Op     string
+	Values []Value
+}
+
+// Value is a literal, a quoted string or a number as written.
+type Value struct {
+	Text   string
+	Quoted bool
+}
+
+func (*Logical) expr()    {}
+func (*Not) expr()        {}
+func (*Comparison) expr() {}
+
+// SyntaxError is an expression that cannot be parsed.
+type SyntaxError struct {
+	// Pos is the byte offset in the expression the error was found at
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s at offset %d", e.Msg, e.Pos)
+}
+
+// Parse parses a filter expression. not binds tighter than and, which binds tighter than or, so
+// "a = 1 and b = 2 or c = 3" is "(a = 1 and b = 2) or c = 3". Keywords are case-insensitive,
+// strings are quoted with ' or " and a quote is escaped by doubling it.
+func Parse(expression string) (Expr, error) {
+	tokens, err := lex(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+
+	expr, err := p.or(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, p.errorf(t, "unexpected %q", t.text)
+	}
+
+	return expr, nil
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokLParen
+	tokRParen
+	tokComma
+	tokOperator
+	tokIdent
+	tokNumber
+	tokString
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func lex(s string) ([]token, error) {
+	var tokens []token
+
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokLParen, text: "(", pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokRParen, text: ")", pos: i})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{kind: tokComma, text: ",", pos: i})
+			i++
+		case strings.IndexByte("=!<>~", c) >= 0:
+			op, n := string(c), 1
+			if i+1 < len(s) {
+				switch two := s[i : i+2]; two {
+				case "!=", "<=", ">=", "!~":
+					op, n = two, 2
+				case "<>":
+					op, n = OpNotEq, 2
+				case "==":
+					op, n = OpEq, 2
+				}
+			}
+			if op == "!" {
+				return nil, &SyntaxError{Pos: i, Msg: `unexpected '!'`}
+			}
+			tokens = append(tokens, token{kind: tokOperator, text: op, pos: i})
+			i += n
+		case c == '\'' || c == '"':
+			var text strings.Builder
+			j := i + 1
+			for ; j < len(s); j++ {
+				if s[j] != c {
+					text.WriteByte(s[j])
+					continue
+				}
+				// a doubled quote is a quote in the string
+				if j+1 < len(s) && s[j+1] == c {
+					text.WriteByte(c)
+					j++
+					continue
+				}
+				break
+			}
+			if j >= len(s) {
+				return nil, &SyntaxError{Pos: i, Msg: "unterminated string"}
+			}
+			tokens = append(tokens, token{kind: tokString, text: text.String(), pos: i})
+			i = j + 1
+		case isDigit(c) || c == '-' && i+1 < len(s) && isDigit(s[i+1]):
+			j := i + 1
+			for j < len(s) && (isDigit(s[j]) || s[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: s[i:j], pos: i})
+			i = j
+		case isIdentStart(c):
+			j := i + 1
+			for j < len(s) && (isIdentStart(s[j]) || isDigit(s[j])) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: s[i:j], pos: i})
+			i = j
+		default:
+			return nil, &SyntaxError{Pos: i, Msg: fmt.Sprintf("unexpected %q", c)}
+		}
+	}
+
+	return append(tokens, token{kind: tokEOF, pos: len(s)}), nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+
+	return t
+}
+
+func (p *parser) isKeyword(keyword string) bool {
+	t := p.peek()
+	return t.kind == tokIdent && strings.EqualFold(t.text, keyword)
+}
+
+func (p *parser) errorf(t token, format string, args ...any) error {
+	return &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// or is a list of and expressions joined by or, grouped from the left.
+func (p *parser) or(depth int) (Expr, error) {
+	left, err := p.and(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isKeyword("or") {
+		p.next()
+
+		right, err := p.and(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = &Logical{Op: "or", Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+// and is a list of unary expressions joined by and, grouped from the left.
+func (p *parser) and(depth int) (Expr, error) {
+	left, err := p.unary(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isKeyword("and") {
+		p.next()
+
+		right, err := p.unary(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = &Logical{Op: "and", Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+// unary is a comparison or a parenthesised expression, either negated by not.
+func (p *parser) unary(depth int) (Expr, error) {
+	if depth > maxDepth {
+		return nil, p.errorf(p.peek(), "expression nested too deeply")
+	}
+
+	if p.isKeyword("not") {
+		p.next()
+
+		expr, err := p.unary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Expr: expr}, nil
+	}
+
+	if p.peek().kind == tokLParen {
+		p.next()
+
+		expr, err := p.or(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		if t := p.next(); t.kind != tokRParen {
+			return nil, p.errorf(t, "expected )")
+		}
+		return expr, nil
+	}
+
+	return p.comparison()
+}
+
+// comparison is a field, an operator and a value, or a field, in or not in and a list of values.
+func (p *parser) comparison() (Expr, error) {
+	field := p.next()
+	if field.kind != tokIdent || isKeyword(field.text) {
+		return nil, p.errorf(field, "expected a field name")
+	}
+
+	c := &Comparison{Field: field.text}
+
+	switch t := p.peek(); {
+	case t.kind == tokOperator:
+		p.next()
+		c.Op = t.text
+
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		c.Values = []Value{value}
+
+		return c, nil
+	case p.isKeyword("not"):
+		p.next()
+		if !p.isKeyword("in") {
+			return nil, p.errorf(p.peek(), "expected in after not")
+		}
+		c.Op = OpNotIn
+	case p.isKeyword("in"):
+		c.Op = OpIn
+	default:
+		return nil, p.errorf(t, "expected an operator after %s", field.text)
+	}
+
+	p.next()
+	if t := p.next(); t.kind != tokLParen {
+		return nil, p.errorf(t, "expected ( after %s", c.Op)
+	}
+
+	for {
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		c.Values = append(c.Values, value)
+
+		t := p.next()
+		if t.kind == tokRParen {
+			return c, nil
+		}
+		if t.kind != tokComma {
+			return nil, p.errorf(t, "expected , or )")
+		}
+	}
+}
+
+func (p *parser) value() (Value, error) {
+	switch t := p.next(); t.kind {
+	case tokString:
+		return Value{Text: t.text, Quoted: true}, nil
+	case tokNumber:
+		return Value{Text: t.text}, nil
+	default:
+		return Value{}, p.errorf(t, "expected a string or a number")
+	}
+}
+
+func isKeyword(word string) bool {
+	switch strings.ToLower(word) {
+	case "and", "or", "not", "in":
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,129 @@
+package filter
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       Expr
+	}{
+		{
+			expression: `title = 'Dune'`,
+			want:       &Comparison{Field: "title", Op: OpEq, Values: []Value{{Text: "Dune", Quoted: true}}},
+		},
+		{
+			// and binds tighter than or
+			expression: `category in ('fantasy','novel') and year_of_publication >= 2000 or author ~ 'tolkien'`,
+			want: &Logical{
+				Op: "or",
+				Left: &Logical{
+					Op:    "and",
+					Left:  &Comparison{Field: "category", Op: OpIn, Values: []Value{{Text: "fantasy", Quoted: true}, {Text: "novel", Quoted: true}}},
+					Right: &Comparison{Field: "year_of_publication", Op: OpGtOrEq, Values: []Value{{Text: "2000"}}},
+				},
+				Right: &Comparison{Field: "author", Op: OpContains, Values: []Value{{Text: "tolkien", Quoted: true}}},
+			},
+		},
+		{
+			expression: `NOT (publisher <> "O'Reilly" OR title !~ 'it''s') And category Not In ('horror')`,
+			want: &Logical{
+				Op: "and",
+				Left: &Not{Expr: &Logical{
+					Op:    "or",
+					Left:  &Comparison{Field: "publisher", Op: OpNotEq, Values: []Value{{Text: "O'Reilly", Quoted: true}}},
+					Right: &Comparison{Field: "title", Op: OpNotContain, Values: []Value{{Text: "it's", Quoted: true}}},
+				}},
+				Right: &Comparison{Field: "category", Op: OpNotIn, Values: []Value{{Text: "horror", Quoted: true}}},
+			},
+		},
+		{
+			expression: `year_of_publication<1900 or year_of_publication==-5`,
+			want: &Logical{
+				Op:    "or",
+				Left:  &Comparison{Field: "year_of_publication", Op: OpLt, Values: []Value{{Text: "1900"}}},
+				Right: &Comparison{Field: "year_of_publication", Op: OpEq, Values: []Value{{Text: "-5"}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.expression)
+		if err != nil {
+			t.Errorf("Parse(%q) error = %v", tt.expression, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.expression, got, tt.want)
+		}
+	}
+}
+
+func TestParse_syntaxErrors(t *testing.T) {
+	for _, expression := range []string{
+		``,
+		`title`,
+		`title =`,
+		`title = 'unterminated`,
+		`(title = 'a'`,
+		`title = 'a' and`,
+		`title = 'a')`,
+		`title ! 'a'`,
+		`and = 'a'`,
+		`category in 'fantasy'`,
+		`category in ('fantasy' 'novel')`,
+		`category not ('fantasy')`,
+		`title = author`,
+		`title = 'a' # comment`,
+		strings.Repeat("(", 40) + `title = 'a'` + strings.Repeat(")", 40),
+	} {
+		_, err := Parse(expression)
+
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("Parse(%q) error = %v, want a *SyntaxError", expression, err)
+		}
+	}
+}
+
+var testSchema = Schema{
+	"title":    {Type: String},
+	"year":     {Type: Number},
+	"added":    {Type: Date},
+	"category": {Type: Enum, Values: []string{"fantasy", "novel"}},
+}
+
+func TestSchema_Validate(t *testing.T) {
+	tests := []struct {
+		expression string
+		wantErr    string
+	}{
+		{`title ~ 'go' and not (year in (2001, 2002) or added >= '2024-01-31') and category != 'novel'`, ""},
+		{`title = 1984`, ""},
+		{`isbn = '9780134685991'`, "unknown field isbn"},
+		{`year ~ '19'`, "operator ~ cannot be used with year"},
+		{`added in ('2024-01-01')`, "operator in cannot be used with added"},
+		{`category < 'novel'`, "operator < cannot be used with category"},
+		{`year = '2001'`, `year: "2001" is not an integer`},
+		{`year = 20.5`, `year: "20.5" is not an integer`},
+		{`added = '2024-02-30'`, `added: "2024-02-30" is not a 'YYYY-MM-DD' date`},
+		{`category in ('fantasy', 'poetry')`, `category: "poetry" is not one of [fantasy novel]`},
+		{`year in (` + strings.Repeat("1, ", 100) + `1)`, "in takes at most 100 values"},
+	}
+
+	for _, tt := range tests {
+		expr, err := Parse(tt.expression)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", tt.expression, err)
+		}
+
+		err = testSchema.Validate(expr)
+		if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+			t.Errorf("Schema.Validate(%q) error = %v, want %q", tt.expression, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package filter
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Type is the type of a field, it decides the operators and values a field can be compared with.
+type Type int
+
+const (
+	// String fields take =, !=, the case-insensitive contains ~ and !~, in and not in
+	String Type = iota
+	// Number fields take integers with =, !=, <, <=, >, >=, in and not in
+	Number
+	// Date fields take 'YYYY-MM-DD' strings with =, !=, <, <=, > and >=, the date is the whole day
+	Date
+	// Enum fields take one of their values with =, !=, in and not in
+	Enum
+)
+
+// DateLayout is the layout of the values of Date fields.
+const DateLayout = "2006-01-02"
+
+// maxValues bounds the list of values of in and not in.
+const maxValues = 100
+
+var typeOperators = map[Type][]string{
+	String: {OpEq, OpNotEq, OpContains, OpNotContain, OpIn, OpNotIn},
+	Number: {OpEq, OpNotEq, OpLt, OpLtOrEq, OpGt, OpGtOrEq, OpIn, OpNotIn},
+	Date:   {OpEq, OpNotEq, OpLt, OpLtOrEq, OpGt, OpGtOrEq},
+	Enum:   {OpEq, OpNotEq, OpIn, OpNotIn},
+}
+
+// Field is a field an expression can compare.
+type Field struct {
+	Type Type
+	// Values are the values of an Enum field
+	Values []string
+}
+
+// Schema is the whitelist of the fields of a list, by name.
+type Schema map[string]Field
+
+// Validate checks that an expression only compares the fields of the schema, with the operators
+// and values of their types.
+func (s Schema) Validate(expr Expr) error {
+	switch e := expr.(type) {
+	case *Logical:
+		if err := s.Validate(e.Left); err != nil {
+			return err
+		}
+		return s.Validate(e.Right)
+	case *Not:
+		return s.Validate(e.Expr)
+	case *Comparison:
+		return s.validateComparison(e)
+	default:
+		return fmt.Errorf("unexpected expression %T", expr)
+	}
+}
+
+func (s Schema) validateComparison(c *Comparison) error {
+	field, ok := s[c.Field]
+	if !ok {
+		return fmt.Errorf("unknown field %s", c.Field)
+	}
+
+	if !slices.Contains(typeOperators[field.Type], c.Op) {
+		return fmt.Errorf("operator %s cannot be used with %s", c.Op, c.Field)
+	}
+
+	if len(c.Values) > maxValues {
+		return fmt.Errorf("%s takes at most %d values", c.Op, maxValues)
+	}
+
+	for _, value := range c.Values {
+		if err := field.validateValue(value); err != nil {
+			return fmt.Errorf("%s: %w", c.Field, err)
+		}
+	}
+
+	return nil
+}
+
+func (f Field) validateValue(value Value) error {
+	switch f.Type {
+	case Number:
+		if _, err := strconv.Atoi(value.Text); err != nil || value.Quoted {
+			return fmt.Errorf("%q is not an integer", value.Text)
+		}
+	case Date:
+		if _, err := time.Parse(DateLayout, value.Text); err != nil || !value.Quoted {
+			return fmt.Errorf("%q is not a 'YYYY-MM-DD' date", value.Text)
+		}
+	case Enum:
+		if !slices.Contains(f.Values, value.Text) {
+			return fmt.Errorf("%q is not one of %v", value.Text, f.Values)
+		}
+	}
+
+	return nil
+}
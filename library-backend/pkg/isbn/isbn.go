@@ -0,0 +1,184 @@
+// Package isbn validates ISBN-10 and ISBN-13 numbers and converts between them.
+//
+// Books are identified by their canonical form, the ISBN-13 digits without separators, so the same
+// book entered as "0-13-468599-7", "978-0134685991" or "9780134685991" is recognised as one.
+package isbn
+
+import (
+	"errors"
+	"strings"
+)
+
+// bookland is the only ISBN-13 prefix ISBN-10s were assigned under, ISBN-13s also use 979.
+const (
+	bookland      = "978"
+	bookland979   = "979"
+	eanAddOnShort = 2
+	eanAddOnLong  = 5
+)
+
+var (
+	ErrInvalid      = errors.New("invalid ISBN")
+	ErrNoISBN10Form = errors.New("ISBN-13 has no ISBN-10 form")
+	ErrNotBookland  = errors.New("barcode is not a book EAN-13")
+
+	separatorReplacer = strings.NewReplacer("-", "", " ", "")
+)
+
+// Clean strips the hyphens and spaces an ISBN is usually printed with and upper-cases the ISBN-10
+// check digit X. It does not validate.
+func Clean(s string) string {
+	return strings.ToUpper(separatorReplacer.Replace(strings.TrimSpace(s)))
+}
+
+// Valid reports whether s is an ISBN-10 or ISBN-13 with a correct check digit, separators allowed.
+func Valid(s string) bool {
+	s = Clean(s)
+
+	switch len(s) {
+	case 10:
+		return valid10(s)
+	case 13:
+		return valid13(s)
+	default:
+		return false
+	}
+}
+
+// Normalize returns the canonical ISBN-13 of an ISBN-10 or ISBN-13.
+func Normalize(s string) (string, error) {
+	s = Clean(s)
+
+	switch {
+	case len(s) == 13 && valid13(s):
+		return s, nil
+	case len(s) == 10 && valid10(s):
+		body := bookland + s[:9]
+		return body + string(checkDigit13(body)), nil
+	default:
+		return "", ErrInvalid
+	}
+}
+
+// FromBarcode returns the ISBN-13 of a scanned book barcode. Book barcodes are EAN-13s in the 978 and
+// 979 ranges, which are the ISBN-13 digits; scanners that read the 2 or 5 digit add-on printed next to
+// them append it, so it is dropped.
+func FromBarcode(s string) (string, error) {
+	s = Clean(s)
+
+	if !allDigits(s) {
+		return "", ErrInvalid
+	}
+
+	switch len(s) {
+	case 13, 13 + eanAddOnShort, 13 + eanAddOnLong:
+		s = s[:13]
+	default:
+		return "", ErrInvalid
+	}
+
+	if !strings.HasPrefix(s, bookland) && !strings.HasPrefix(s, bookland979) {
+		return "", ErrNotBookland
+	}
+
+	if !valid13(s) {
+		return "", ErrInvalid
+	}
+
+	return s, nil
+}
+
+// To10 returns the ISBN-10 of an ISBN-10 or ISBN-13. Only ISBN-13s in the 978 range have one.
+func To10(s string) (string, error) {
+	isbn13, err := Normalize(s)
+	if err != nil {
+		return "", err
+	}
+
+	if !strings.HasPrefix(isbn13, bookland) {
+		return "", ErrNoISBN10Form
+	}
+
+	body := isbn13[3:12]
+	return body + string(checkDigit10(body)), nil
+}
+
+// Equal reports whether a and b are the same book, whatever form either is written in. Invalid ISBNs
+// are never equal.
+func Equal(a, b string) bool {
+	na, err := Normalize(a)
+	if err != nil {
+		return false
+	}
+
+	nb, err := Normalize(b)
+	if err != nil {
+		return false
+	}
+
+	return na == nb
+}
+
+func valid10(s string) bool {
+	if !allDigits(s[:9]) {
+		return false
+	}
+
+	last := s[9]
+	if last != 'X' && !isDigit(last) {
+		return false
+	}
+
+	return checkDigit10(s[:9]) == last
+}
+
+func valid13(s string) bool {
+	if !strings.HasPrefix(s, bookland) && !strings.HasPrefix(s, bookland979) {
+		return false
+	}
+
+	return allDigits(s) && checkDigit13(s[:12]) == s[12]
+}
+
+// checkDigit10 computes the ISBN-10 check digit of 9 digits, weighted 10 down to 2, modulo 11.
+func checkDigit10(body string) byte {
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += int(body[i]-'0') * (10 - i)
+	}
+
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return 'X'
+	}
+
+	return byte('0' + check)
+}
+
+// checkDigit13 computes the ISBN-13 check digit of 12 digits, weighted alternately 1 and 3, modulo 10.
+func checkDigit13(body string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += int(body[i]-'0') * weight
+	}
+
+	return byte('0' + (10-sum%10)%10)
+}
+
+func allDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
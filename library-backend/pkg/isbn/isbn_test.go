@@ -0,0 +1,132 @@
+package isbn
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want bool
+	}{
+		{name: "isbn-13", in: "9780134685991", want: true},
+		{name: "isbn-13 with hyphens", in: "978-0-13-468599-1", want: true},
+		{name: "isbn-10", in: "0134685997", want: true},
+		{name: "isbn-10 with spaces", in: "0 13 468599 7", want: true},
+		{name: "isbn-10 with check digit x", in: "080442957x", want: true},
+		{name: "isbn-13 wrong check digit", in: "9780134685992", want: false},
+		{name: "isbn-10 wrong check digit", in: "0134685990", want: false},
+		{name: "x inside isbn-10", in: "01346X5997", want: false},
+		{name: "x in isbn-13", in: "978013468599X", want: false},
+		{name: "too short", in: "978013468599", want: false},
+		{name: "ean-13 outside the book ranges", in: "4006381333931", want: false},
+		{name: "empty", in: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Valid(tt.in); got != tt.want {
+				t.Errorf("Valid(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr error
+	}{
+		{name: "isbn-13", in: "9780132350884", want: "9780132350884"},
+		{name: "isbn-13 with hyphens", in: "978-0134685991", want: "9780134685991"},
+		{name: "isbn-10", in: "0-13-468599-7", want: "9780134685991"},
+		{name: "isbn-10 with check digit x", in: "0-8044-2957-X", want: "9780804429573"},
+		{name: "979 isbn-13", in: "979-10-90636-07-1", want: "9791090636071"},
+		{name: "invalid", in: "978-0134685992", wantErr: ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Normalize(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromBarcode(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr error
+	}{
+		{name: "ean-13", in: "9780134685991", want: "9780134685991"},
+		{name: "979 ean-13", in: "9791090636071", want: "9791090636071"},
+		{name: "with 5 digit price add-on", in: "978013468599154999", want: "9780134685991"},
+		{name: "with 2 digit add-on", in: "978013468599101", want: "9780134685991"},
+		{name: "not a book", in: "4006381333931", wantErr: ErrNotBookland},
+		{name: "wrong check digit", in: "9780134685992", wantErr: ErrInvalid},
+		{name: "isbn-10 is not a barcode", in: "0134685997", wantErr: ErrInvalid},
+		{name: "letters", in: "978013468599X", wantErr: ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromBarcode(tt.in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("FromBarcode(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("FromBarcode(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTo10(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr error
+	}{
+		{name: "isbn-13", in: "978-0-13-468599-1", want: "0134685997"},
+		{name: "check digit x", in: "9780804429573", want: "080442957X"},
+		{name: "isbn-10 is cleaned", in: "0-13-468599-7", want: "0134685997"},
+		{name: "979 has no isbn-10", in: "9791090636071", wantErr: ErrNoISBN10Form},
+		{name: "invalid", in: "12345", wantErr: ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := To10(tt.in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("To10(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("To10(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEqual(t *testing.T) {
+	if !Equal("0134685997", "978-0134685991") {
+		t.Error("Equal() = false for the ISBN-10 and ISBN-13 of one book")
+	}
+	if Equal("9780134685991", "9780132350884") {
+		t.Error("Equal() = true for different books")
+	}
+	if Equal("invalid", "invalid") {
+		t.Error("Equal() = true for invalid ISBNs")
+	}
+}
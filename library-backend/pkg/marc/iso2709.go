@@ -0,0 +1,260 @@
+package marc
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"unicode/utf8"
+)
+
+// the delimiters of ISO 2709
+const (
+	subfieldDelimiter = 0x1F
+	fieldTerminator   = 0x1E
+	recordTerminator  = 0x1D
+)
+
+const (
+	directoryEntryLength = 12
+	maxRecordLength      = 99999
+	maxFieldLength       = 9999
+)
+
+// Marshal encodes a record in ISO 2709. The record length, base address of data, character coding
+// (UTF-8) and entry map of the leader are filled in, the other leader positions are kept.
+func Marshal(r Record) ([]byte, error) {
+	var directory, data bytes.Buffer
+
+	addField := func(tag string, value []byte) error {
+		if len(tag) != 3 {
+			return fmt.Errorf("%w: tag %q is not 3 characters", ErrInvalidRecord, tag)
+		}
+		if len(value) > maxFieldLength {
+			return fmt.Errorf("%w: field %s is longer than %d bytes", ErrRecordTooLong, tag, maxFieldLength)
+		}
+
+		fmt.Fprintf(&directory, "%s%04d%05d", tag, len(value), data.Len())
+		data.Write(value)
+
+		return nil
+	}
+
+	for _, f := range r.ControlFields {
+		if err := checkValue(f.Tag, f.Value); err != nil {
+			return nil, err
+		}
+
+		if err := addField(f.Tag, append([]byte(f.Value), fieldTerminator)); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, f := range r.DataFields {
+		value := []byte{indicator(f.Ind1), indicator(f.Ind2)}
+		for _, s := range f.Subfields {
+			if err := checkValue(f.Tag, s.Value); err != nil {
+				return nil, err
+			}
+			value = append(value, subfieldDelimiter, s.Code)
+			value = append(value, s.Value...)
+		}
+
+		if err := addField(f.Tag, append(value, fieldTerminator)); err != nil {
+			return nil, err
+		}
+	}
+
+	directory.WriteByte(fieldTerminator)
+
+	base := LeaderLength + directory.Len()
+	length := base + data.Len() + 1
+	if length > maxRecordLength {
+		return nil, ErrRecordTooLong
+	}
+
+	leader := r.leader()
+	copy(leader[0:5], fmt.Sprintf("%05d", length))
+	leader[9] = 'a'
+	leader[10] = '2'
+	leader[11] = '2'
+	copy(leader[12:17], fmt.Sprintf("%05d", base))
+	copy(leader[20:24], "4500")
+
+	out := make([]byte, 0, length)
+	out = append(out, leader...)
+	out = append(out, directory.Bytes()...)
+	out = append(out, data.Bytes()...)
+	out = append(out, recordTerminator)
+
+	return out, nil
+}
+
+// Unmarshal decodes a single ISO 2709 record.
+func Unmarshal(raw []byte) (Record, error) {
+	if len(raw) < LeaderLength+2 || raw[len(raw)-1] != recordTerminator {
+		return Record{}, fmt.Errorf("%w: missing record terminator", ErrInvalidRecord)
+	}
+
+	if !utf8.Valid(raw) {
+		return Record{}, fmt.Errorf("%w: not UTF-8, MARC-8 records are not supported", ErrInvalidRecord)
+	}
+
+	leader := raw[:LeaderLength]
+
+	length, err := strconv.Atoi(string(leader[0:5]))
+	if err != nil || length != len(raw) {
+		return Record{}, fmt.Errorf("%w: record length %q does not match", ErrInvalidRecord, leader[0:5])
+	}
+
+	base, err := strconv.Atoi(string(leader[12:17]))
+	if err != nil || base <= LeaderLength || base > len(raw) || raw[base-1] != fieldTerminator {
+		return Record{}, fmt.Errorf("%w: invalid base address of data %q", ErrInvalidRecord, leader[12:17])
+	}
+
+	directory := raw[LeaderLength : base-1]
+	if len(directory)%directoryEntryLength != 0 {
+		return Record{}, fmt.Errorf("%w: directory is not a whole number of entries", ErrInvalidRecord)
+	}
+
+	record := Record{Leader: string(leader)}
+	data := raw[base : len(raw)-1]
+
+	for i := 0; i < len(directory); i += directoryEntryLength {
+		entry := directory[i : i+directoryEntryLength]
+		tag := string(entry[0:3])
+
+		fieldLength, err := strconv.Atoi(string(entry[3:7]))
+		if err != nil {
+			return Record{}, fmt.Errorf("%w: field %s has an invalid length", ErrInvalidRecord, tag)
+		}
+
+		start, err := strconv.Atoi(string(entry[7:12]))
+		if err != nil || start < 0 || fieldLength < 1 || fieldLength > maxFieldLength ||
+			start+fieldLength > len(data) || data[start+fieldLength-1] != fieldTerminator {
+			return Record{}, fmt.Errorf("%w: field %s is out of place", ErrInvalidRecord, tag)
+		}
+
+		value := data[start : start+fieldLength-1]
+
+		if IsControlTag(tag) {
+			record.ControlFields = append(record.ControlFields, ControlField{Tag: tag, Value: string(value)})
+			continue
+		}
+
+		field, err := decodeDataField(tag, value)
+		if err != nil {
+			return Record{}, err
+		}
+		record.DataFields = append(record.DataFields, field)
+	}
+
+	return record, nil
+}
+
+func decodeDataField(tag string, value []byte) (DataField, error) {
+	if len(value) < 2 {
+		return DataField{}, fmt.Errorf("%w: field %s has no indicators", ErrInvalidRecord, tag)
+	}
+
+	field := DataField{Tag: tag, Ind1: value[0], Ind2: value[1]}
+
+	parts := bytes.Split(value[2:], []byte{subfieldDelimiter})
+	if len(parts[0]) > 0 {
+		return DataField{}, fmt.Errorf("%w: field %s has data before its first subfield", ErrInvalidRecord, tag)
+	}
+
+	for _, part := range parts[1:] {
+		if len(part) == 0 {
+			continue
+		}
+		field.Subfields = append(field.Subfields, Subfield{Code: part[0], Value: string(part[1:])})
+	}
+
+	return field, nil
+}
+
+// checkValue refuses values that would break the record structure.
+func checkValue(tag, value string) error {
+	if bytes.ContainsAny([]byte(value), "\x1d\x1e\x1f") {
+		return fmt.Errorf("%w: field %s contains a MARC delimiter", ErrInvalidRecord, tag)
+	}
+
+	return nil
+}
+
+// indicator writes an unset indicator as a blank.
+func indicator(b byte) byte {
+	if b == 0 {
+		return ' '
+	}
+
+	return b
+}
+
+// Reader reads ISO 2709 records one after the other.
+type Reader struct {
+	r *bufio.Reader
+}
+
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// Read returns the next record, or io.EOF after the last one. Line breaks between records, which
+// some systems add, are skipped.
+func (rd *Reader) Read() (Record, error) {
+	for {
+		b, err := rd.r.ReadByte()
+		if err != nil {
+			return Record{}, err
+		}
+
+		if b != '\n' && b != '\r' {
+			_ = rd.r.UnreadByte()
+			break
+		}
+	}
+
+	head := make([]byte, 5)
+	if _, err := io.ReadFull(rd.r, head); err != nil {
+		return Record{}, fmt.Errorf("%w: truncated record", ErrInvalidRecord)
+	}
+
+	length, err := strconv.Atoi(string(head))
+	if err != nil || length < LeaderLength+2 {
+		return Record{}, fmt.Errorf("%w: invalid record length %q", ErrInvalidRecord, head)
+	}
+
+	raw := make([]byte, length)
+	copy(raw, head)
+	if _, err := io.ReadFull(rd.r, raw[5:]); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+			return Record{}, fmt.Errorf("%w: truncated record", ErrInvalidRecord)
+		}
+		return Record{}, err
+	}
+
+	return Unmarshal(raw)
+}
+
+// Writer writes ISO 2709 records one after the other.
+type Writer struct {
+	w io.Writer
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+func (wr *Writer) Write(r Record) error {
+	raw, err := Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	_, err = wr.w.Write(raw)
+	return err
+}
@@ -0,0 +1,122 @@
+// Package marc reads and writes MARC 21 bibliographic records, in the ISO 2709 exchange format and
+// as MARCXML.
+//
+// A Record is kept as the fields it was read with, the meaning of the tags is left to the caller.
+// Records are expected to be UTF-8 (leader position 09 set to "a"), MARC-8 records are refused
+// rather than read with garbled diacritics.
+package marc
+
+import (
+	"errors"
+	"strings"
+)
+
+// LeaderLength is the length of the leader that starts every record.
+const LeaderLength = 24
+
+var (
+	ErrInvalidRecord = errors.New("invalid MARC record")
+	ErrRecordTooLong = errors.New("MARC record is longer than 99999 bytes")
+)
+
+// Record is a MARC record, a leader followed by control fields (tags 001 to 009) and data fields.
+type Record struct {
+	Leader        string
+	ControlFields []ControlField
+	DataFields    []DataField
+}
+
+type ControlField struct {
+	Tag   string
+	Value string
+}
+
+// DataField is a variable data field with two indicators and its subfields in order.
+type DataField struct {
+	Tag       string
+	Ind1      byte
+	Ind2      byte
+	Subfields []Subfield
+}
+
+type Subfield struct {
+	Code  byte
+	Value string
+}
+
+// IsControlTag reports whether tag is the tag of a control field, 001 to 009.
+func IsControlTag(tag string) bool {
+	return strings.HasPrefix(tag, "00")
+}
+
+// ControlField returns the value of the first control field with the tag.
+func (r Record) ControlField(tag string) (string, bool) {
+	for _, f := range r.ControlFields {
+		if f.Tag == tag {
+			return f.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// Fields returns the data fields with the tag, in record order.
+func (r Record) Fields(tag string) []DataField {
+	var fields []DataField
+	for _, f := range r.DataFields {
+		if f.Tag == tag {
+			fields = append(fields, f)
+		}
+	}
+
+	return fields
+}
+
+// Field returns the first data field with the tag.
+func (r Record) Field(tag string) (DataField, bool) {
+	for _, f := range r.DataFields {
+		if f.Tag == tag {
+			return f, true
+		}
+	}
+
+	return DataField{}, false
+}
+
+// Subfield returns the value of the first subfield with the code, or an empty string.
+func (f DataField) Subfield(code byte) string {
+	for _, s := range f.Subfields {
+		if s.Code == code {
+			return s.Value
+		}
+	}
+
+	return ""
+}
+
+// SubfieldValues returns the values of every subfield with the code.
+func (f DataField) SubfieldValues(code byte) []string {
+	var values []string
+	for _, s := range f.Subfields {
+		if s.Code == code {
+			values = append(values, s.Value)
+		}
+	}
+
+	return values
+}
+
+// leader returns the record leader padded or cut to LeaderLength, so a record built by hand with a
+// short or empty leader can still be written.
+func (r Record) leader() []byte {
+	leader := []byte(r.Leader)
+	if len(leader) > LeaderLength {
+		leader = leader[:LeaderLength]
+	}
+
+	for len(leader) < LeaderLength {
+		leader = append(leader, ' ')
+	}
+
+	return leader
+}
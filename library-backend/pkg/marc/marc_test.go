@@ -0,0 +1,219 @@
+package marc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func testRecord() Record {
+	return Record{
+		Leader: "00000nam a2200000 i 4500",
+		ControlFields: []ControlField{
+			{Tag: "001", Value: "b1"},
+			{Tag: "008", Value: "250824s2017    xx            000 0 und d"},
+		},
+		DataFields: []DataField{
+			{Tag: "020", Ind1: ' ', Ind2: ' ', Subfields: []Subfield{{Code: 'a', Value: "9780134685991"}}},
+			{Tag: "100", Ind1: '1', Ind2: ' ', Subfields: []Subfield{{Code: 'a', Value: "Bloch, Joshua"}}},
+			{Tag: "245", Ind1: '1', Ind2: '0', Subfields: []Subfield{
+				{Code: 'a', Value: "Effective Java /"},
+				{Code: 'c', Value: "Joshua Bloch."},
+			}},
+			{Tag: "264", Ind1: ' ', Ind2: '1', Subfields: []Subfield{
+				{Code: 'b', Value: "Addison-Wesley & Sons,"},
+				{Code: 'c', Value: "2017."},
+			}},
+			{Tag: "500", Ind1: ' ', Ind2: ' ', Subfields: []Subfield{{Code: 'a', Value: "Édition révisée — 3ᵉ"}}},
+		},
+	}
+}
+
+func TestMarshalUnmarshal(t *testing.T) {
+	record := testRecord()
+
+	raw, err := Marshal(record)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	if !strings.HasPrefix(string(raw), "00") || raw[len(raw)-1] != recordTerminator {
+		t.Fatalf("Marshal() = %q", raw)
+	}
+
+	got, err := Unmarshal(raw)
+	if err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if got.Leader[5:9] != "nam " || got.Leader[9] != 'a' || got.Leader[12:17] == "00000" {
+		t.Errorf("Unmarshal() leader = %q", got.Leader)
+	}
+
+	got.Leader = record.Leader
+	if !reflect.DeepEqual(got.ControlFields, record.ControlFields) || !reflect.DeepEqual(got.DataFields, record.DataFields) {
+		t.Errorf("Unmarshal() = %+v, want %+v", got, record)
+	}
+}
+
+func TestMarshal_errors(t *testing.T) {
+	t.Run("delimiter in a value", func(t *testing.T) {
+		record := Record{DataFields: []DataField{{Tag: "245", Subfields: []Subfield{{Code: 'a', Value: "a\x1fb"}}}}}
+		if _, err := Marshal(record); !errors.Is(err, ErrInvalidRecord) {
+			t.Errorf("Marshal() error = %v, want %v", err, ErrInvalidRecord)
+		}
+	})
+
+	t.Run("field too long", func(t *testing.T) {
+		record := Record{DataFields: []DataField{{Tag: "500", Subfields: []Subfield{{Code: 'a', Value: strings.Repeat("x", 10000)}}}}}
+		if _, err := Marshal(record); !errors.Is(err, ErrRecordTooLong) {
+			t.Errorf("Marshal() error = %v, want %v", err, ErrRecordTooLong)
+		}
+	})
+}
+
+func TestUnmarshal_errors(t *testing.T) {
+	raw, err := Marshal(testRecord())
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	firstLength, err := strconv.Atoi(string(raw[LeaderLength+3 : LeaderLength+7]))
+	if err != nil {
+		t.Fatalf("first field length error = %v", err)
+	}
+
+	// withEntry replaces the length and start of the first directory entry
+	withEntry := func(lengthAndStart string) []byte {
+		crafted := bytes.Clone(raw)
+		copy(crafted[LeaderLength+3:], lengthAndStart)
+		return crafted
+	}
+
+	tests := []struct {
+		name string
+		raw  []byte
+	}{
+		{name: "truncated", raw: raw[:len(raw)-10]},
+		// the field ends on the terminator of the first field, so only the start is wrong
+		{name: "negative field start", raw: withEntry(fmt.Sprintf("%04d-0001", firstLength+1))},
+		{name: "negative field length", raw: withEntry("-00100000")},
+		{name: "field past the data", raw: withEntry("000299999")},
+		{name: "wrong length", raw: append([]byte("00010"), raw[5:]...)},
+		{name: "not utf-8", raw: bytes.Replace(raw, []byte("Bloch"), []byte("Bl\xe2ch"), 1)},
+		{name: "empty", raw: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Unmarshal(tt.raw); !errors.Is(err, ErrInvalidRecord) {
+				t.Errorf("Unmarshal() error = %v, want %v", err, ErrInvalidRecord)
+			}
+		})
+	}
+}
+
+func TestReaderWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	for i := 0; i < 2; i++ {
+		if err := w.Write(testRecord()); err != nil {
+			t.Fatalf("Writer.Write() error = %v", err)
+		}
+		// some systems end every record with a line break
+		buf.WriteString("\n")
+	}
+
+	r := NewReader(&buf)
+	for i := 0; i < 2; i++ {
+		record, err := r.Read()
+		if err != nil {
+			t.Fatalf("Reader.Read() record %d error = %v", i+1, err)
+		}
+		if f, _ := record.Field("245"); f.Subfield('a') != "Effective Java /" {
+			t.Errorf("Reader.Read() 245 = %+v", f)
+		}
+	}
+
+	if _, err := r.Read(); err != io.EOF {
+		t.Errorf("Reader.Read() after the last record error = %v, want io.EOF", err)
+	}
+}
+
+func TestXMLWriterReader(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewXMLWriter(&buf)
+	if err := w.Write(testRecord()); err != nil {
+		t.Fatalf("XMLWriter.Write() error = %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("XMLWriter.Close() error = %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, `<collection xmlns="`+Namespace+`">`) || !strings.Contains(out, `Addison-Wesley &amp; Sons,`) {
+		t.Fatalf("XMLWriter output = %s", out)
+	}
+
+	r := NewXMLReader(strings.NewReader(out))
+	record, err := r.Read()
+	if err != nil {
+		t.Fatalf("XMLReader.Read() error = %v", err)
+	}
+
+	want := testRecord()
+	if !reflect.DeepEqual(record.ControlFields, want.ControlFields) || !reflect.DeepEqual(record.DataFields, want.DataFields) {
+		t.Errorf("XMLReader.Read() = %+v, want %+v", record, want)
+	}
+
+	if _, err := r.Read(); err != io.EOF {
+		t.Errorf("XMLReader.Read() after the last record error = %v, want io.EOF", err)
+	}
+}
+
+func TestXMLReader_prefixedRecord(t *testing.T) {
+	doc := `<?xml version="1.0"?>
+<marc:record xmlns:marc="http://www.loc.gov/MARC21/slim">
+  <marc:leader>00000nam a2200000 i 4500</marc:leader>
+  <marc:datafield tag="245" ind1="0" ind2="0"><marc:subfield code="a">Dune</marc:subfield></marc:datafield>
+</marc:record>`
+
+	record, err := NewXMLReader(strings.NewReader(doc)).Read()
+	if err != nil {
+		t.Fatalf("XMLReader.Read() error = %v", err)
+	}
+
+	if f, ok := record.Field("245"); !ok || f.Subfield('a') != "Dune" || f.Ind1 != '0' {
+		t.Errorf("XMLReader.Read() 245 = %+v", f)
+	}
+}
+
+func TestMarshalXML(t *testing.T) {
+	raw, err := MarshalXML(testRecord())
+	if err != nil {
+		t.Fatalf("MarshalXML() error = %v", err)
+	}
+
+	if !strings.HasPrefix(string(raw), `<record xmlns="`+Namespace+`"><leader>`) {
+		t.Errorf("MarshalXML() = %s", raw)
+	}
+
+	// the element reads back as a record of its own
+	record, err := NewXMLReader(bytes.NewReader(raw)).Read()
+	if err != nil || !reflect.DeepEqual(record.DataFields, testRecord().DataFields) {
+		t.Errorf("XMLReader.Read() = %+v, error %v", record, err)
+	}
+}
+
+func TestXMLReader_invalid(t *testing.T) {
+	doc := `<collection><record><datafield tag="245"><subfield code="ab">x</subfield></datafield></record></collection>`
+
+	if _, err := NewXMLReader(strings.NewReader(doc)).Read(); !errors.Is(err, ErrInvalidRecord) {
+		t.Errorf("XMLReader.Read() error = %v, want %v", err, ErrInvalidRecord)
+	}
+}
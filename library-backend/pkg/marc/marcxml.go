@@ -0,0 +1,203 @@
+package marc
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Namespace is the MARCXML namespace, MARC 21 slim.
+const Namespace = "http://www.loc.gov/MARC21/slim"
+
+// the MARCXML elements of a record. Elements are matched by local name, so records are read with or
+// without the namespace and with any prefix.
+type xmlRecord struct {
+	XMLName       xml.Name          `xml:"record"`
+	Leader        string            `xml:"leader"`
+	ControlFields []xmlControlField `xml:"controlfield"`
+	DataFields    []xmlDataField    `xml:"datafield"`
+}
+
+type xmlControlField struct {
+	Tag   string `xml:"tag,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlDataField struct {
+	Tag       string        `xml:"tag,attr"`
+	Ind1      string        `xml:"ind1,attr"`
+	Ind2      string        `xml:"ind2,attr"`
+	Subfields []xmlSubfield `xml:"subfield"`
+}
+
+type xmlSubfield struct {
+	Code  string `xml:"code,attr"`
+	Value string `xml:",chardata"`
+}
+
+// XMLWriter writes records as a MARCXML collection. Close must be called after the last record to
+// end the collection.
+type XMLWriter struct {
+	w       io.Writer
+	encoder *xml.Encoder
+	started bool
+	written int
+}
+
+func NewXMLWriter(w io.Writer) *XMLWriter {
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("  ", "  ")
+
+	return &XMLWriter{w: w, encoder: encoder}
+}
+
+func (x *XMLWriter) Write(r Record) error {
+	if err := x.start(); err != nil {
+		return err
+	}
+
+	if err := x.encoder.Encode(toXMLRecord(r)); err != nil {
+		return err
+	}
+
+	x.written++
+
+	return x.encoder.Flush()
+}
+
+// Close ends the collection, an empty one if no record was written.
+func (x *XMLWriter) Close() error {
+	if err := x.start(); err != nil {
+		return err
+	}
+
+	// the encoder starts every record but the first on a new line, the last one is ended here
+	closing := "</collection>\n"
+	if x.written > 0 {
+		closing = "\n" + closing
+	}
+
+	_, err := io.WriteString(x.w, closing)
+	return err
+}
+
+func (x *XMLWriter) start() error {
+	if x.started {
+		return nil
+	}
+	x.started = true
+
+	_, err := io.WriteString(x.w, xml.Header+`<collection xmlns="`+Namespace+`">`+"\n")
+	return err
+}
+
+// MarshalXML returns a record as a MARCXML record element of its own, for embedding in other
+// documents.
+func MarshalXML(r Record) ([]byte, error) {
+	var buf bytes.Buffer
+
+	start := xml.StartElement{
+		Name: xml.Name{Local: "record"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: Namespace}},
+	}
+	if err := xml.NewEncoder(&buf).EncodeElement(toXMLRecord(r), start); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func toXMLRecord(r Record) xmlRecord {
+	leader := r.leader()
+	leader[9] = 'a'
+	copy(leader[10:12], "22")
+	copy(leader[20:24], "4500")
+
+	record := xmlRecord{Leader: string(leader)}
+	for _, f := range r.ControlFields {
+		record.ControlFields = append(record.ControlFields, xmlControlField{Tag: f.Tag, Value: f.Value})
+	}
+
+	for _, f := range r.DataFields {
+		field := xmlDataField{Tag: f.Tag, Ind1: string(indicator(f.Ind1)), Ind2: string(indicator(f.Ind2))}
+		for _, s := range f.Subfields {
+			field.Subfields = append(field.Subfields, xmlSubfield{Code: string(s.Code), Value: s.Value})
+		}
+		record.DataFields = append(record.DataFields, field)
+	}
+
+	return record
+}
+
+// XMLReader reads the records of a MARCXML document one after the other, either a collection or a
+// single record element.
+type XMLReader struct {
+	decoder *xml.Decoder
+}
+
+func NewXMLReader(r io.Reader) *XMLReader {
+	return &XMLReader{decoder: xml.NewDecoder(r)}
+}
+
+// Read returns the next record, or io.EOF after the last one.
+func (x *XMLReader) Read() (Record, error) {
+	for {
+		token, err := x.decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return Record{}, io.EOF
+		}
+		if err != nil {
+			return Record{}, fmt.Errorf("%w: %v", ErrInvalidRecord, err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "record" {
+			continue
+		}
+
+		var record xmlRecord
+		if err := x.decoder.DecodeElement(&record, &start); err != nil {
+			return Record{}, fmt.Errorf("%w: %v", ErrInvalidRecord, err)
+		}
+
+		return record.toRecord()
+	}
+}
+
+func (r xmlRecord) toRecord() (Record, error) {
+	record := Record{Leader: r.Leader}
+
+	for _, f := range r.ControlFields {
+		if len(f.Tag) != 3 {
+			return Record{}, fmt.Errorf("%w: tag %q is not 3 characters", ErrInvalidRecord, f.Tag)
+		}
+		record.ControlFields = append(record.ControlFields, ControlField{Tag: f.Tag, Value: f.Value})
+	}
+
+	for _, f := range r.DataFields {
+		if len(f.Tag) != 3 {
+			return Record{}, fmt.Errorf("%w: tag %q is not 3 characters", ErrInvalidRecord, f.Tag)
+		}
+
+		field := DataField{Tag: f.Tag, Ind1: xmlIndicator(f.Ind1), Ind2: xmlIndicator(f.Ind2)}
+		for _, s := range f.Subfields {
+			if len(s.Code) != 1 {
+				return Record{}, fmt.Errorf("%w: field %s has a subfield code %q", ErrInvalidRecord, f.Tag, s.Code)
+			}
+			field.Subfields = append(field.Subfields, Subfield{Code: s.Code[0], Value: s.Value})
+		}
+		record.DataFields = append(record.DataFields, field)
+	}
+
+	return record, nil
+}
+
+func xmlIndicator(value string) byte {
+	if value == "" {
+		return ' '
+	}
+
+	return value[0]
+}
@@ -0,0 +1,160 @@
+// Package opds renders OPDS 1.2 catalog feeds, the Atom feeds e-reader apps browse, and the
+// OpenSearch description they search with.
+//
+// Feeds are plain values, filled in by the caller and written with Marshal. Links may be relative,
+// clients resolve them against the feed URL.
+package opds
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// the namespaces of a feed
+const (
+	NamespaceAtom       = "http://www.w3.org/2005/Atom"
+	NamespaceDC         = "http://purl.org/dc/terms/"
+	NamespaceOPDS       = "http://opds-spec.org/2010/catalog"
+	NamespaceOpenSearch = "http://a9.com/-/spec/opensearch/1.1/"
+)
+
+// the media types of OPDS documents
+const (
+	TypeNavigation  = "application/atom+xml;profile=opds-catalog;kind=navigation"
+	TypeAcquisition = "application/atom+xml;profile=opds-catalog;kind=acquisition"
+	TypeEntry       = "application/atom+xml;type=entry;profile=opds-catalog"
+	TypeOpenSearch  = "application/opensearchdescription+xml"
+)
+
+// the link relations of OPDS and Atom
+const (
+	RelSelf              = "self"
+	RelStart             = "start"
+	RelUp                = "up"
+	RelAlternate         = "alternate"
+	RelSearch            = "search"
+	RelFirst             = "first"
+	RelPrevious          = "previous"
+	RelNext              = "next"
+	RelLast              = "last"
+	RelSubsection        = "subsection"
+	RelImage             = "http://opds-spec.org/image"
+	RelThumbnail         = "http://opds-spec.org/image/thumbnail"
+	RelSortNew           = "http://opds-spec.org/sort/new"
+	RelAcquisitionBorrow = "http://opds-spec.org/acquisition/borrow"
+)
+
+// Feed is an Atom feed, a navigation feed when its entries lead to other feeds or an acquisition
+// feed when they are publications.
+type Feed struct {
+	XMLName         xml.Name  `xml:"feed"`
+	Xmlns           string    `xml:"xmlns,attr"`
+	XmlnsDC         string    `xml:"xmlns:dc,attr"`
+	XmlnsOPDS       string    `xml:"xmlns:opds,attr"`
+	XmlnsOpenSearch string    `xml:"xmlns:opensearch,attr"`
+	ID              string    `xml:"id"`
+	Title           string    `xml:"title"`
+	Updated         time.Time `xml:"updated"`
+	Author          *Person   `xml:"author,omitempty"`
+	TotalResults    *int      `xml:"opensearch:totalResults,omitempty"`
+	ItemsPerPage    *int      `xml:"opensearch:itemsPerPage,omitempty"`
+	StartIndex      *int      `xml:"opensearch:startIndex,omitempty"`
+	Links           []Link    `xml:"link"`
+	Entries         []Entry   `xml:"entry"`
+}
+
+// Entry is a publication of an acquisition feed, or a link to another feed in a navigation feed.
+type Entry struct {
+	ID         string     `xml:"id"`
+	Title      string     `xml:"title"`
+	Updated    time.Time  `xml:"updated"`
+	Authors    []Person   `xml:"author,omitempty"`
+	Identifier string     `xml:"dc:identifier,omitempty"`
+	Publisher  string     `xml:"dc:publisher,omitempty"`
+	Issued     string     `xml:"dc:issued,omitempty"`
+	Categories []Category `xml:"category,omitempty"`
+	Content    *Content   `xml:"content,omitempty"`
+	Links      []Link     `xml:"link"`
+}
+
+type Person struct {
+	Name string `xml:"name"`
+}
+
+type Category struct {
+	Term   string `xml:"term,attr"`
+	Label  string `xml:"label,attr,omitempty"`
+	Scheme string `xml:"scheme,attr,omitempty"`
+}
+
+type Content struct {
+	Type string `xml:"type,attr"`
+	Text string `xml:",chardata"`
+}
+
+type Link struct {
+	Rel   string `xml:"rel,attr"`
+	Href  string `xml:"href,attr"`
+	Type  string `xml:"type,attr,omitempty"`
+	Title string `xml:"title,attr,omitempty"`
+	// IndirectAcquisitions describe what an acquisition link ends in when it does not lead to the
+	// publication itself
+	IndirectAcquisitions []IndirectAcquisition `xml:"opds:indirectAcquisition,omitempty"`
+}
+
+// IndirectAcquisition is the type of what is acquired through an acquisition link, nested when it
+// takes several steps.
+type IndirectAcquisition struct {
+	Type                 string                `xml:"type,attr"`
+	IndirectAcquisitions []IndirectAcquisition `xml:"opds:indirectAcquisition,omitempty"`
+}
+
+// TextContent is plain text entry content.
+func TextContent(text string) *Content {
+	return &Content{Type: "text", Text: text}
+}
+
+// Marshal writes the feed as an XML document, with the namespaces it uses declared on the root.
+func Marshal(feed Feed) ([]byte, error) {
+	feed.Xmlns = NamespaceAtom
+	feed.XmlnsDC = NamespaceDC
+	feed.XmlnsOPDS = NamespaceOPDS
+	feed.XmlnsOpenSearch = NamespaceOpenSearch
+
+	return marshal(feed)
+}
+
+// OpenSearchDescription tells a client how to search the catalog. The template of its URL has a
+// {searchTerms} placeholder, and may use {startPage} and {count}.
+type OpenSearchDescription struct {
+	XMLName        xml.Name      `xml:"OpenSearchDescription"`
+	Xmlns          string        `xml:"xmlns,attr"`
+	ShortName      string        `xml:"ShortName"`
+	Description    string        `xml:"Description"`
+	InputEncoding  string        `xml:"InputEncoding"`
+	OutputEncoding string        `xml:"OutputEncoding"`
+	URL            OpenSearchURL `xml:"Url"`
+}
+
+type OpenSearchURL struct {
+	Type     string `xml:"type,attr"`
+	Template string `xml:"template,attr"`
+}
+
+// MarshalOpenSearch writes the description as an XML document.
+func MarshalOpenSearch(description OpenSearchDescription) ([]byte, error) {
+	description.Xmlns = NamespaceOpenSearch
+	description.InputEncoding = "UTF-8"
+	description.OutputEncoding = "UTF-8"
+
+	return marshal(description)
+}
+
+func marshal(v any) ([]byte, error) {
+	raw, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), append(raw, '\n')...), nil
+}
@@ -0,0 +1,95 @@
+package opds
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMarshal(t *testing.T) {
+	total := 1
+	feed := Feed{
+		ID:           "urn:library:opds:books",
+		Title:        "Books",
+		Updated:      time.Date(2025, 8, 24, 8, 0, 0, 0, time.UTC),
+		TotalResults: &total,
+		Links:        []Link{{Rel: RelSelf, Href: "/v1/opds/books?page=1", Type: TypeAcquisition}},
+		Entries: []Entry{{
+			ID:         "urn:uuid:1",
+			Title:      "Pride & Prejudice",
+			Updated:    time.Date(2025, 8, 24, 8, 0, 0, 0, time.UTC),
+			Authors:    []Person{{Name: "Jane Austen"}},
+			Identifier: "urn:isbn:9780141439518",
+			Publisher:  "Penguin",
+			Categories: []Category{{Term: "romance", Label: "Romance"}},
+			Content:    TextContent("2 of 3 copies available"),
+			Links: []Link{
+				{Rel: RelImage, Href: "https://example.com/cover.jpg", Type: "image/jpeg"},
+				{Rel: RelAcquisitionBorrow, Href: "/v1/books/1/copies", Type: "application/json", IndirectAcquisitions: []IndirectAcquisition{{Type: "application/vnd.library.physical-copy"}}},
+			},
+		}},
+	}
+
+	raw, err := Marshal(feed)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	out := string(raw)
+	for _, want := range []string{
+		xml.Header,
+		`<feed xmlns="http://www.w3.org/2005/Atom" xmlns:dc="http://purl.org/dc/terms/"`,
+		`<updated>2025-08-24T08:00:00Z</updated>`,
+		`<opensearch:totalResults>1</opensearch:totalResults>`,
+		`<title>Pride &amp; Prejudice</title>`,
+		`<dc:identifier>urn:isbn:9780141439518</dc:identifier>`,
+		`<link rel="http://opds-spec.org/image" href="https://example.com/cover.jpg" type="image/jpeg"></link>`,
+		`<link rel="http://opds-spec.org/acquisition/borrow" href="/v1/books/1/copies" type="application/json">`,
+		`<opds:indirectAcquisition type="application/vnd.library.physical-copy"></opds:indirectAcquisition>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Marshal() = %s, want it to contain %s", out, want)
+		}
+	}
+
+	// optional elements that were not set are left out
+	for _, unwanted := range []string{"itemsPerPage", "dc:issued", "<author>\n    <name></name>"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("Marshal() = %s, want no %s", out, unwanted)
+		}
+	}
+
+	// the document reads back as Atom
+	var parsed struct {
+		XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+		Entries []struct {
+			Title string `xml:"http://www.w3.org/2005/Atom title"`
+		} `xml:"http://www.w3.org/2005/Atom entry"`
+	}
+	if err := xml.Unmarshal(raw, &parsed); err != nil || len(parsed.Entries) != 1 || parsed.Entries[0].Title != "Pride & Prejudice" {
+		t.Errorf("xml.Unmarshal() = %+v, error %v", parsed, err)
+	}
+}
+
+func TestMarshalOpenSearch(t *testing.T) {
+	raw, err := MarshalOpenSearch(OpenSearchDescription{
+		ShortName:   "Library",
+		Description: "Search the catalog",
+		URL:         OpenSearchURL{Type: TypeAcquisition, Template: "/v1/opds/books?q={searchTerms}&page={startPage?}"},
+	})
+	if err != nil {
+		t.Fatalf("MarshalOpenSearch() error = %v", err)
+	}
+
+	out := string(raw)
+	for _, want := range []string{
+		`<OpenSearchDescription xmlns="http://a9.com/-/spec/opensearch/1.1/">`,
+		`<InputEncoding>UTF-8</InputEncoding>`,
+		`template="/v1/opds/books?q={searchTerms}&amp;page={startPage?}"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("MarshalOpenSearch() = %s, want it to contain %s", out, want)
+		}
+	}
+}
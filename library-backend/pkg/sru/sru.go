@@ -0,0 +1,330 @@
+// Package sru writes the responses of SRU 2.0, Search/Retrieve via URL, the protocol library systems
+// search each other's catalogs with.
+//
+// A server answers three operations: searchRetrieve runs a CQL query and returns records, scan
+// browses the terms of an index and explain describes the server. Errors are reported as diagnostics
+// inside the responses rather than by HTTP status.
+package sru
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// Version is the SRU version of the responses.
+const Version = "2.0"
+
+// ContentType is the media type of SRU responses.
+const ContentType = "application/sru+xml"
+
+// the namespaces of the responses
+const (
+	NamespaceResponse   = "http://docs.oasis-open.org/ns/search-ws/sruResponse"
+	NamespaceScan       = "http://docs.oasis-open.org/ns/search-ws/scan"
+	NamespaceDiagnostic = "http://docs.oasis-open.org/ns/search-ws/diagnostic"
+	NamespaceExplain    = "http://explain.z3950.org/dtd/2.0/"
+	NamespaceDC         = "info:srw/schema/1/dc-schema"
+	NamespaceDCElements = "http://purl.org/dc/elements/1.1/"
+)
+
+// the identifiers of record schemas
+const (
+	SchemaDC      = "info:srw/schema/1/dc-v1.1"
+	SchemaMARCXML = "info:srw/schema/1/marcxml-v1.1"
+	SchemaExplain = NamespaceExplain
+)
+
+// the values of recordXMLEscaping, how a record is embedded in a response
+const (
+	EscapingXML    = "xml"
+	EscapingString = "string"
+)
+
+// the diagnostics of the SRU diagnostic list used by this package's callers
+const (
+	DiagGeneralSystemError          = 1
+	DiagUnsupportedOperation        = 4
+	DiagUnsupportedVersion          = 5
+	DiagUnsupportedParameterValue   = 6
+	DiagMandatoryParameterMissing   = 7
+	DiagQuerySyntaxError            = 10
+	DiagUnsupportedIndex            = 16
+	DiagUnsupportedRelation         = 19
+	DiagUnsupportedRelationModifier = 20
+	DiagEmptyTermUnsupported        = 27
+	DiagMaskingNotSupported         = 28
+	DiagInvalidTerm                 = 36
+	DiagUnsupportedBoolean          = 37
+	DiagUnsupportedBooleanModifier  = 46
+	DiagFirstRecordOutOfRange       = 61
+	DiagUnknownSchema               = 66
+	DiagUnsupportedXMLEscaping      = 71
+	DiagUnsupportedSortPath         = 88
+	DiagResponsePositionOutOfRange  = 120
+)
+
+var diagnosticMessages = map[int]string{
+	DiagGeneralSystemError:          "General system error",
+	DiagUnsupportedOperation:        "Unsupported operation",
+	DiagUnsupportedVersion:          "Unsupported version",
+	DiagUnsupportedParameterValue:   "Unsupported parameter value",
+	DiagMandatoryParameterMissing:   "Mandatory parameter not supplied",
+	DiagQuerySyntaxError:            "Query syntax error",
+	DiagUnsupportedIndex:            "Unsupported index",
+	DiagUnsupportedRelation:         "Unsupported relation",
+	DiagUnsupportedRelationModifier: "Unsupported relation modifier",
+	DiagEmptyTermUnsupported:        "Empty term unsupported",
+	DiagMaskingNotSupported:         "Masking character not supported",
+	DiagInvalidTerm:                 "Term in invalid format for index or relation",
+	DiagUnsupportedBoolean:          "Unsupported boolean operator",
+	DiagUnsupportedBooleanModifier:  "Unsupported boolean modifier",
+	DiagFirstRecordOutOfRange:       "First record position out of range",
+	DiagUnknownSchema:               "Unknown schema for retrieval",
+	DiagUnsupportedXMLEscaping:      "Unsupported recordXMLEscaping value",
+	DiagUnsupportedSortPath:         "Unsupported path for sort",
+	DiagResponsePositionOutOfRange:  "Response position out of range",
+}
+
+// Diagnostic is an error or warning of an operation.
+type Diagnostic struct {
+	XMLName xml.Name `xml:"http://docs.oasis-open.org/ns/search-ws/diagnostic diagnostic"`
+	URI     string   `xml:"uri"`
+	Details string   `xml:"details,omitempty"`
+	Message string   `xml:"message,omitempty"`
+}
+
+// NewDiagnostic returns the diagnostic of the SRU diagnostic list with the code, details name what
+// caused it, such as the parameter or index.
+func NewDiagnostic(code int, details string) Diagnostic {
+	return Diagnostic{
+		URI:     fmt.Sprintf("info:srw/diagnostic/1/%d", code),
+		Details: details,
+		Message: diagnosticMessages[code],
+	}
+}
+
+// Record is a record in a response. Data is the record itself, an XML document.
+type Record struct {
+	Schema      string     `xml:"recordSchema"`
+	XMLEscaping string     `xml:"recordXMLEscaping"`
+	Data        recordData `xml:"recordData"`
+	// Position is the 1-based position of the record in the result set, 0 when it has none
+	Position int `xml:"recordPosition,omitempty"`
+}
+
+type recordData struct {
+	XML  string `xml:",innerxml"`
+	Text string `xml:",chardata"`
+}
+
+// NewRecord embeds a record in a response, as XML or, with EscapingString, as an escaped string.
+func NewRecord(schema string, data []byte, escaping string, position int) Record {
+	record := Record{Schema: schema, XMLEscaping: escaping, Position: position}
+
+	if escaping == EscapingString {
+		record.Data.Text = string(data)
+	} else {
+		record.XMLEscaping = EscapingXML
+		record.Data.XML = string(data)
+	}
+
+	return record
+}
+
+// SearchRetrieveResponse is the response of a searchRetrieve operation.
+type SearchRetrieveResponse struct {
+	NumberOfRecords int
+	Records         []Record
+	// NextRecordPosition is the position to ask for the next page from, 0 on the last page
+	NextRecordPosition int
+	Diagnostics        []Diagnostic
+}
+
+// ScanResponse is the response of a scan operation.
+type ScanResponse struct {
+	Terms       []Term
+	Diagnostics []Diagnostic
+}
+
+// Term is a term of a scanned index with the number of records it is found in.
+type Term struct {
+	Value           string `xml:"value"`
+	NumberOfRecords int    `xml:"numberOfRecords"`
+	DisplayTerm     string `xml:"displayTerm,omitempty"`
+}
+
+// ExplainResponse is the response of an explain operation, its record is an Explain document.
+type ExplainResponse struct {
+	Record      Record
+	Diagnostics []Diagnostic
+}
+
+// the responses as written, in the element order of the SRU schemas and without the lists that
+// are empty
+type (
+	xmlSearchRetrieveResponse struct {
+		XMLName            xml.Name        `xml:"http://docs.oasis-open.org/ns/search-ws/sruResponse searchRetrieveResponse"`
+		Version            string          `xml:"version"`
+		NumberOfRecords    int             `xml:"numberOfRecords"`
+		Records            *xmlRecords     `xml:"records,omitempty"`
+		NextRecordPosition int             `xml:"nextRecordPosition,omitempty"`
+		Diagnostics        *xmlDiagnostics `xml:"diagnostics,omitempty"`
+	}
+
+	xmlScanResponse struct {
+		XMLName     xml.Name        `xml:"http://docs.oasis-open.org/ns/search-ws/scan scanResponse"`
+		Terms       *xmlTerms       `xml:"terms,omitempty"`
+		Diagnostics *xmlDiagnostics `xml:"diagnostics,omitempty"`
+	}
+
+	xmlExplainResponse struct {
+		XMLName     xml.Name        `xml:"http://docs.oasis-open.org/ns/search-ws/sruResponse explainResponse"`
+		Version     string          `xml:"version"`
+		Record      Record          `xml:"record"`
+		Diagnostics *xmlDiagnostics `xml:"diagnostics,omitempty"`
+	}
+
+	xmlRecords struct {
+		Records []Record `xml:"record"`
+	}
+
+	xmlTerms struct {
+		Terms []Term `xml:"term"`
+	}
+
+	xmlDiagnostics struct {
+		Diagnostics []Diagnostic `xml:"diagnostic"`
+	}
+)
+
+func (r SearchRetrieveResponse) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	response := xmlSearchRetrieveResponse{
+		Version:            Version,
+		NumberOfRecords:    r.NumberOfRecords,
+		NextRecordPosition: r.NextRecordPosition,
+		Diagnostics:        diagnosticList(r.Diagnostics),
+	}
+	if len(r.Records) > 0 {
+		response.Records = &xmlRecords{Records: r.Records}
+	}
+
+	return e.Encode(response)
+}
+
+func (r ScanResponse) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	response := xmlScanResponse{Diagnostics: diagnosticList(r.Diagnostics)}
+	if len(r.Terms) > 0 {
+		response.Terms = &xmlTerms{Terms: r.Terms}
+	}
+
+	return e.Encode(response)
+}
+
+func (r ExplainResponse) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	return e.Encode(xmlExplainResponse{Version: Version, Record: r.Record, Diagnostics: diagnosticList(r.Diagnostics)})
+}
+
+func diagnosticList(diagnostics []Diagnostic) *xmlDiagnostics {
+	if len(diagnostics) == 0 {
+		return nil
+	}
+
+	return &xmlDiagnostics{Diagnostics: diagnostics}
+}
+
+// Explain is a ZeeRex 2.0 description of a server: where it is, what it holds, the indexes it
+// searches and the record schemas it returns.
+type Explain struct {
+	XMLName      xml.Name     `xml:"http://explain.z3950.org/dtd/2.0/ explain"`
+	ServerInfo   ServerInfo   `xml:"serverInfo"`
+	DatabaseInfo DatabaseInfo `xml:"databaseInfo"`
+	IndexInfo    IndexInfo    `xml:"indexInfo"`
+	Schemas      []Schema     `xml:"schemaInfo>schema"`
+	ConfigInfo   ConfigInfo   `xml:"configInfo"`
+}
+
+type ServerInfo struct {
+	Protocol  string `xml:"protocol,attr"`
+	Version   string `xml:"version,attr"`
+	Transport string `xml:"transport,attr"`
+	Host      string `xml:"host"`
+	Port      int    `xml:"port"`
+	Database  string `xml:"database"`
+}
+
+type DatabaseInfo struct {
+	Title       string `xml:"title"`
+	Description string `xml:"description,omitempty"`
+}
+
+type IndexInfo struct {
+	Sets    []ContextSet `xml:"set"`
+	Indexes []Index      `xml:"index"`
+}
+
+// ContextSet is a set of indexes and modifiers, the prefix of their names.
+type ContextSet struct {
+	Name       string `xml:"name,attr"`
+	Identifier string `xml:"identifier,attr"`
+}
+
+// Index is a searchable index with the names a query can give it.
+type Index struct {
+	Title string      `xml:"title"`
+	Names []IndexName `xml:"map>name"`
+}
+
+type IndexName struct {
+	Set  string `xml:"set,attr"`
+	Name string `xml:",chardata"`
+}
+
+type Schema struct {
+	Identifier string `xml:"identifier,attr"`
+	Name       string `xml:"name,attr"`
+	Title      string `xml:"title"`
+}
+
+// ConfigInfo lists the defaults, limits and supported features of the server.
+type ConfigInfo struct {
+	Defaults []Setting `xml:"default"`
+	Settings []Setting `xml:"setting"`
+	Supports []Setting `xml:"supports"`
+}
+
+type Setting struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// DublinCore is a record in the SRU Dublin Core schema.
+type DublinCore struct {
+	XMLName     xml.Name `xml:"srw_dc:dc"`
+	XmlnsSRWDC  string   `xml:"xmlns:srw_dc,attr"`
+	XmlnsDC     string   `xml:"xmlns:dc,attr"`
+	Title       string   `xml:"dc:title,omitempty"`
+	Creators    []string `xml:"dc:creator"`
+	Publisher   string   `xml:"dc:publisher,omitempty"`
+	Date        string   `xml:"dc:date,omitempty"`
+	Subjects    []string `xml:"dc:subject"`
+	Type        string   `xml:"dc:type,omitempty"`
+	Identifiers []string `xml:"dc:identifier"`
+}
+
+// MarshalDublinCore returns the XML of a Dublin Core record, with its namespaces declared.
+func MarshalDublinCore(dc DublinCore) ([]byte, error) {
+	dc.XmlnsSRWDC = NamespaceDC
+	dc.XmlnsDC = NamespaceDCElements
+
+	return xml.Marshal(dc)
+}
+
+// Marshal returns the XML document of a response.
+func Marshal(response any) ([]byte, error) {
+	raw, err := xml.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), append(raw, '\n')...), nil
+}
@@ -0,0 +1,111 @@
+package sru
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestMarshal_searchRetrieveResponse(t *testing.T) {
+	dc, err := MarshalDublinCore(DublinCore{Title: "Pride & Prejudice", Creators: []string{"Jane Austen"}, Date: "1813"})
+	if err != nil {
+		t.Fatalf("MarshalDublinCore() error = %v", err)
+	}
+
+	raw, err := Marshal(SearchRetrieveResponse{
+		NumberOfRecords:    3,
+		Records:            []Record{NewRecord(SchemaDC, dc, EscapingXML, 1), NewRecord(SchemaDC, dc, EscapingString, 2)},
+		NextRecordPosition: 3,
+	})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	out := string(raw)
+	for _, want := range []string{
+		xml.Header,
+		`<searchRetrieveResponse xmlns="` + NamespaceResponse + `">`,
+		`<version>2.0</version>`,
+		`<recordData><srw_dc:dc xmlns:srw_dc="info:srw/schema/1/dc-schema" xmlns:dc="http://purl.org/dc/elements/1.1/"><dc:title>Pride &amp; Prejudice</dc:title>`,
+		`<recordXMLEscaping>string</recordXMLEscaping>`,
+		`<recordData>&lt;srw_dc:dc`,
+		`<nextRecordPosition>3</nextRecordPosition>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Marshal() = %s, want it to contain %s", out, want)
+		}
+	}
+
+	if strings.Contains(out, "diagnostics") {
+		t.Errorf("Marshal() = %s, want no diagnostics", out)
+	}
+
+	// the embedded record is part of the document, the escaped one is text
+	var parsed struct {
+		Records []struct {
+			Data struct {
+				Inner string `xml:",innerxml"`
+			} `xml:"recordData"`
+		} `xml:"records>record"`
+	}
+	if err := xml.Unmarshal(raw, &parsed); err != nil || len(parsed.Records) != 2 {
+		t.Fatalf("xml.Unmarshal() = %+v, error %v", parsed, err)
+	}
+}
+
+func TestMarshal_diagnostics(t *testing.T) {
+	raw, err := Marshal(ScanResponse{Diagnostics: []Diagnostic{NewDiagnostic(DiagUnsupportedIndex, "dc.author")}})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	out := string(raw)
+	for _, want := range []string{
+		`<scanResponse xmlns="` + NamespaceScan + `">`,
+		`<diagnostic xmlns="` + NamespaceDiagnostic + `">`,
+		`<uri>info:srw/diagnostic/1/16</uri>`,
+		`<details>dc.author</details>`,
+		`<message>Unsupported index</message>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Marshal() = %s, want it to contain %s", out, want)
+		}
+	}
+
+	if strings.Contains(out, "<terms>") {
+		t.Errorf("Marshal() = %s, want no terms", out)
+	}
+}
+
+func TestMarshal_explainResponse(t *testing.T) {
+	explain, err := xml.Marshal(Explain{
+		ServerInfo: ServerInfo{Protocol: "SRU", Version: Version, Transport: "http", Host: "localhost", Port: 8080, Database: "v1/sru"},
+		IndexInfo: IndexInfo{
+			Sets:    []ContextSet{{Name: "dc", Identifier: "info:srw/cql-context-set/1/dc-v1.1"}},
+			Indexes: []Index{{Title: "Title", Names: []IndexName{{Set: "dc", Name: "title"}}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("xml.Marshal() error = %v", err)
+	}
+
+	raw, err := Marshal(ExplainResponse{Record: NewRecord(SchemaExplain, explain, EscapingXML, 0)})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	out := string(raw)
+	for _, want := range []string{
+		`<explainResponse xmlns="` + NamespaceResponse + `">`,
+		`<explain xmlns="` + NamespaceExplain + `"><serverInfo protocol="SRU" version="2.0" transport="http">`,
+		`<index><title>Title</title><map><name set="dc">title</name></map></index>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Marshal() = %s, want it to contain %s", out, want)
+		}
+	}
+
+	if strings.Contains(out, "recordPosition") {
+		t.Errorf("Marshal() = %s, want no record position", out)
+	}
+}